@@ -19,6 +19,13 @@ import (
 type Alignment interface {
 	SeqBag
 	AddGaps(rate, lenprop float64)
+	// Stockholm like annotations of the alignment (#=GF, #=GS, #=GC, #=GR), nil if none
+	Annotations() *Annotations
+	// Aligns each sequence of the given seqbag against the profile of this alignment,
+	// and adds it to the alignment (default pairwise alignment scores).
+	// If keeplength is true, then insertions relative to this alignment are removed
+	// from the new sequences, and the alignment length does not change.
+	AddUnaligned(seqs SeqBag, keeplength bool) error
 	Append(Alignment) error // Appends alignment sequences to this alignment
 	AvgAllelesPerSite() float64
 	BuildBootstrap(frac float64) Alignment // Bootstrap alignment
@@ -30,6 +37,9 @@ type Alignment interface {
 	Compress() []int
 	// concatenates the given alignment with this alignment
 	Concat(Alignment) error
+	// concatenates the given alignment with this alignment, sequences missing
+	// from one of the alignments are filled with the given character
+	ConcatFill(c Alignment, fill uint8) error
 	// Computes the majority consensus of the given alignemnt
 	// To do so, it takes the majority character at each alignment site
 	// if ignoreGaps is true, then gaps are not taken into account for majority computation (except if only Gaps)
@@ -92,6 +102,8 @@ type Alignment interface {
 	// returns the number of characters in each sequence that are unique in their alignment site (gaps or others)
 	// It does not take into account 'N' and '-' as unique mutations
 	NumMutationsUniquePerSequence(profile *CountProfile) (numuniques []int, numnew []int, nummuts []int, err error)
+	// Partitions of the alignment sites (e.g. NEXUS CHARSET definitions), nil if none
+	Partitions() *PartitionSet
 	Pssm(log bool, pseudocount float64, normalization int) (pssm map[uint8][]float64, err error) // Normalization: PSSM_NORM_NONE, PSSM_NORM_UNIF, PSSM_NORM_DATA
 	Rarefy(nb int, counts map[string]int) (Alignment, error)                                     // Take a new rarefied sample taking into accounts weights
 	RandSubAlign(length int, consecutive bool) (Alignment, error)                                // Extract a random subalignment with given length from this alignment
@@ -100,6 +112,10 @@ type Alignment interface {
 	RefCoordinates(name string, refstart, refend int) (alistart, aliend int, err error)
 	// converts sites on the given sequence to coordinates on the alignment
 	RefSites(name string, sites []int) (refsites []int, err error)
+	SetAnnotations(an *Annotations)
+	SetPartitions(ps *PartitionSet)
+	// Variants of the sequences compared to the given reference sequence, in reference coordinates
+	Variants(refname string) (variants []Variant, err error)
 	// Removes sequences having >= cutoff gaps, returns number of removed sequences
 	RemoveGapSeqs(cutoff float64, ignoreNs bool) int
 	// Removes sequences having >= cutoff character, returns number of removed sequences
@@ -116,6 +132,8 @@ type Alignment interface {
 	ShuffleSites(rate float64, roguerate float64, randroguefirst bool) []string
 	SimulateRogue(prop float64, proplen float64) ([]string, []string) // add "rogue" sequences
 	SiteConservation(position int) (int, error)                       // If the site is conserved:
+	// Computes several conservation metrics for each site of the alignment, in one pass (see SiteScore)
+	SiteScores() []SiteScore
 	Split(part *PartitionSet) ([]Alignment, error)                    //Splits the alignment given the paritions in argument
 	SubAlign(start, length int) (Alignment, error)                    // Extract a subalignment from this alignment
 	SelectSites(sites []int) (Alignment, error)                       // Extract givens sites from the alignment
@@ -129,7 +147,9 @@ type Alignment interface {
 
 type align struct {
 	seqbag
-	length int // Length of alignment
+	length      int           // Length of alignment
+	annotations *Annotations  // Stockholm like annotations (may be nil)
+	partitions  *PartitionSet // Partitions of the sites, e.g. from NEXUS CHARSETs (may be nil)
 }
 
 // AlignChannel is used for iterating over alignments
@@ -155,6 +175,8 @@ func NewAlign(alphabet int) *align {
 			IGNORE_NONE,
 			alphabet},
 		-1,
+		nil,
+		nil,
 	}
 }
 
@@ -177,6 +199,17 @@ func (a *align) AddSequence(name string, sequence string, comment string) error
 	return err
 }
 
+// AddSequenceQuality adds a sequence with the Phred quality score of each of its
+// characters (see seqbag.AddSequenceQuality), checking the alignment length.
+func (a *align) AddSequenceQuality(name string, sequence []uint8, quality []uint8, comment string) (err error) {
+	var s *seq
+	if s, err = addSequenceQuality(a, name, sequence, quality, comment); err != nil || s == nil {
+		return
+	}
+	s.quality = quality
+	return
+}
+
 // AddSequenceChar adds a sequence from its uint8 representation.
 // If a.ignoreidentical is true, then it won't add the sequence if
 // a sequence with the same name AND same sequence
@@ -797,6 +830,25 @@ func (a *align) Append(al Alignment) (err error) {
 	return
 }
 
+// AddUnaligned aligns each sequence of seqs against the profile of the alignment
+// and adds it to the alignment. See MultipleAligner.Add
+func (a *align) AddUnaligned(seqs SeqBag, keeplength bool) (err error) {
+	var res Alignment
+
+	if res, err = NewMultipleAligner().Add(a, seqs, keeplength); err != nil {
+		return
+	}
+	// The content of the alignment is replaced only once the whole result is built:
+	// it is left unchanged in case of error
+	tmp := NewAlign(a.Alphabet())
+	tmp.IgnoreIdentical(a.ignoreidentical)
+	if err = tmp.Append(res); err != nil {
+		return
+	}
+	a.seqmap, a.seqs, a.length = tmp.seqmap, tmp.seqs, tmp.length
+	return
+}
+
 // Add substitutions uniformly to the alignment
 // if rate < 0 : does nothing
 // if rate > 1 : rate=1
@@ -889,8 +941,14 @@ func (a *align) TrimSequences(trimsize int, fromStart bool) error {
 	for _, seq := range a.seqs {
 		if fromStart {
 			seq.sequence = seq.sequence[trimsize:len(seq.sequence)]
+			if seq.quality != nil {
+				seq.quality = seq.quality[trimsize:]
+			}
 		} else {
 			seq.sequence = seq.sequence[0 : len(seq.sequence)-trimsize]
+			if seq.quality != nil {
+				seq.quality = seq.quality[:len(seq.quality)-trimsize]
+			}
 		}
 	}
 	a.length = a.length - trimsize
@@ -1221,12 +1279,14 @@ func RandomAlignment(alphabet, length, nbseq int) (al Alignment, err error) {
 func (a *align) Clone() (c Alignment, err error) {
 	c = NewAlign(a.Alphabet())
 	c.IgnoreIdentical(a.ignoreidentical)
-	a.IterateAll(func(name string, sequence []uint8, comment string) bool {
-		newseq := make([]uint8, 0, len(sequence))
-		newseq = append(newseq, sequence...)
-		err = c.AddSequenceChar(name, newseq, comment)
-		return err != nil
-	})
+	for _, s := range a.seqs {
+		cs := s.Clone()
+		if err = c.AddSequenceQuality(cs.Name(), cs.SequenceChar(), cs.Quality(), cs.Comment()); err != nil {
+			return
+		}
+	}
+	c.SetAnnotations(a.annotations.Clone())
+	c.SetPartitions(a.partitions.Clone())
 	return
 }
 
@@ -1452,8 +1512,6 @@ func (a *align) Pssm(log bool, pseudocount float64, normalization int) (pssm map
 	pssm = make(map[uint8][]float64)
 	var alphabet []uint8
 	var normfactors map[uint8]float64
-	/* Entropy at each position */
-	var entropy []float64
 	alphabet = a.AlphabetCharacters()
 	for _, c := range alphabet {
 		if _, ok := pssm[c]; !ok {
@@ -1462,6 +1520,31 @@ func (a *align) Pssm(log bool, pseudocount float64, normalization int) (pssm map
 	}
 
 	/* We compute normalization factors (takes into account pseudo counts) */
+	if normfactors, err = pssmNormFactors(alphabet, a.NbSequences(), pseudocount, normalization, a.CharStats); err != nil {
+		return
+	}
+
+	/* We count nt/aa occurences at each site */
+	for site := 0; site < a.Length(); site++ {
+		for seq := 0; seq < a.NbSequences(); seq++ {
+			s := a.seqs[seq].sequence[site]
+			s = uint8(unicode.ToUpper(rune(s)))
+			if _, ok := normfactors[s]; ok {
+				if _, ok := pssm[s]; ok {
+					pssm[s][site] += 1.0
+				}
+			}
+		}
+	}
+
+	normalizePssm(pssm, normfactors, len(alphabet), a.Length(), log, pseudocount, normalization)
+
+	return
+}
+
+// pssmNormFactors computes PSSM normalization factors of each character of the alphabet
+// (takes into account pseudo counts). charstats is only called for PSSM_NORM_DATA.
+func pssmNormFactors(alphabet []uint8, nbseqs int, pseudocount float64, normalization int, charstats func() map[uint8]int64) (normfactors map[uint8]float64, err error) {
 	normfactors = make(map[uint8]float64)
 	switch normalization {
 	case PSSM_NORM_NONE:
@@ -1470,18 +1553,18 @@ func (a *align) Pssm(log bool, pseudocount float64, normalization int) (pssm map
 		}
 	case PSSM_NORM_UNIF:
 		for _, c := range alphabet {
-			normfactors[c] = 1.0 / (float64(a.NbSequences()) + (float64(len(pssm)) * pseudocount)) / (1.0 / float64(len(alphabet)))
+			normfactors[c] = 1.0 / (float64(nbseqs) + (float64(len(alphabet)) * pseudocount)) / (1.0 / float64(len(alphabet)))
 		}
 	case PSSM_NORM_FREQ:
 		for _, c := range alphabet {
-			normfactors[c] = 1.0 / (float64(a.NbSequences()) + (float64(len(pssm)) * pseudocount))
+			normfactors[c] = 1.0 / (float64(nbseqs) + (float64(len(alphabet)) * pseudocount))
 		}
 	case PSSM_NORM_LOGO:
 		for _, c := range alphabet {
-			normfactors[c] = 1.0 / float64(a.NbSequences())
+			normfactors[c] = 1.0 / float64(nbseqs)
 		}
 	case PSSM_NORM_DATA:
-		stats := a.CharStats()
+		stats := charstats()
 		total := 0.0
 		for _, c := range alphabet {
 			if s, ok := stats[c]; !ok {
@@ -1493,25 +1576,20 @@ func (a *align) Pssm(log bool, pseudocount float64, normalization int) (pssm map
 		}
 		for _, c := range alphabet {
 			s := stats[c]
-			normfactors[c] = 1.0 / (float64(a.NbSequences()) + (float64(len(pssm)) * pseudocount)) / (float64(s) / total)
+			normfactors[c] = 1.0 / (float64(nbseqs) + (float64(len(alphabet)) * pseudocount)) / (float64(s) / total)
 		}
 	default:
 		err = errors.New("unknown normalization option")
 		return
 	}
+	return
+}
 
-	/* We count nt/aa occurences at each site */
-	for site := 0; site < a.Length(); site++ {
-		for seq := 0; seq < a.NbSequences(); seq++ {
-			s := a.seqs[seq].sequence[site]
-			s = uint8(unicode.ToUpper(rune(s)))
-			if _, ok := normfactors[s]; ok {
-				if _, ok := pssm[s]; ok {
-					pssm[s][site] += 1.0
-				}
-			}
-		}
-	}
+// normalizePssm adds pseudo counts to raw counts of the pssm, applies normalization
+// factors, and computes the logo or the log2 transform.
+func normalizePssm(pssm map[uint8][]float64, normfactors map[uint8]float64, alphabetsize, length int, log bool, pseudocount float64, normalization int) {
+	/* Entropy at each position */
+	var entropy []float64
 
 	/* We add pseudo counts */
 	if pseudocount > 0 {
@@ -1523,7 +1601,7 @@ func (a *align) Pssm(log bool, pseudocount float64, normalization int) (pssm map
 	}
 
 	/* Initialize entropy if NORM_LOGO*/
-	entropy = make([]float64, a.Length())
+	entropy = make([]float64, length)
 	/* Applying normalization factors */
 	for k, v := range pssm {
 		for i := range v {
@@ -1538,7 +1616,7 @@ func (a *align) Pssm(log bool, pseudocount float64, normalization int) (pssm map
 	if normalization == PSSM_NORM_LOGO {
 		for _, v := range pssm {
 			for i := range v {
-				v[i] = v[i] * (math.Log(float64(len(alphabet)))/math.Log(2) - entropy[i])
+				v[i] = v[i] * (math.Log(float64(alphabetsize))/math.Log(2) - entropy[i])
 			}
 		}
 	} else {
@@ -1551,8 +1629,6 @@ func (a *align) Pssm(log bool, pseudocount float64, normalization int) (pssm map
 			}
 		}
 	}
-
-	return
 }
 
 // Extract a subalignment from this alignment
@@ -1747,15 +1823,25 @@ to a full gap sequence.
 Returns an error if the sequences do not have the same alphabet.
 */
 func (a *align) Concat(c Alignment) (err error) {
+	return a.ConcatFill(c, GAP)
+}
+
+/*
+Concatenates both alignments, like Concat, but sequences missing from one
+of the alignments are filled with the given character (e.g. GAP or '?')
+instead of gaps.
+*/
+func (a *align) ConcatFill(c Alignment, fill uint8) (err error) {
 	if a.Alphabet() != c.Alphabet() {
 		return errors.New("alignments do not have the same alphabet")
 	}
+	partitions := concatPartitions(a.partitions, a.Length(), c.Partitions(), c.Length())
 	a.IterateAll(func(name string, sequence []uint8, comment string) bool {
 		_, ok := c.GetSequenceChar(name)
 		if !ok {
 			// This sequence is present in a but not in c
 			// So we append full gap sequence to a
-			err = a.appendToSequence(name, []uint8(strings.Repeat(string(GAP), c.Length())))
+			err = a.appendToSequence(name, []uint8(strings.Repeat(string(fill), c.Length())))
 		}
 		return err != nil
 	})
@@ -1767,7 +1853,7 @@ func (a *align) Concat(c Alignment) (err error) {
 		if !ok {
 			// This sequence is present in c but not in a
 			// So we add it to a, with gaps only
-			err = a.AddSequence(name, strings.Repeat(string(GAP), a.Length()), comment)
+			err = a.AddSequence(name, strings.Repeat(string(fill), a.Length()), comment)
 		}
 		// Then we append the c sequence to a
 		err = a.appendToSequence(name, sequence)
@@ -1789,6 +1875,7 @@ func (a *align) Concat(c Alignment) (err error) {
 		return err != nil
 	})
 	a.length = leng
+	a.partitions = partitions
 
 	return err
 }
@@ -894,6 +894,69 @@ func TestConcat(t *testing.T) {
 	})
 }
 
+func TestConcatPartitions(t *testing.T) {
+	a, _ := RandomAlignment(NUCLEOTIDS, 10, 5)
+	a2, _ := RandomAlignment(NUCLEOTIDS, 6, 5)
+	a3, _ := RandomAlignment(NUCLEOTIDS, 4, 5)
+
+	ps := NewPartitionSet(10)
+	ps.AddRange("p1", "DNA", 0, 9, 1)
+	a.SetPartitions(ps)
+	ps2 := NewPartitionSet(6)
+	ps2.AddRange("p2", "DNA", 0, 2, 1)
+	ps2.AddRange("p1", "DNA", 3, 5, 1)
+	a2.SetPartitions(ps2)
+
+	if err := a.Concat(a2); err != nil {
+		t.Error(err)
+		return
+	}
+	if a.Partitions() == nil || a.Partitions().AliLength() != 16 || a.Partitions().NPartitions() != 2 {
+		t.Errorf("Partitions of concatenated alignment are not correct")
+		return
+	}
+	exp := []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 0, 0, 0}
+	for i, p := range exp {
+		if a.Partitions().Partition(i) != p {
+			t.Errorf("Site %d should be in partition %d (%d)", i, p, a.Partitions().Partition(i))
+		}
+	}
+
+	// a3 has no partitions: they are not defined anymore
+	if err := a.Concat(a3); err != nil {
+		t.Error(err)
+		return
+	}
+	if a.Partitions() != nil {
+		t.Errorf("Concatenated alignment should not have partitions")
+	}
+}
+
+func TestConcatFill(t *testing.T) {
+	a := NewAlign(NUCLEOTIDS)
+	a.AddSequence("A", "ACGT", "")
+	a.AddSequence("B", "ACGG", "")
+
+	a2 := NewAlign(NUCLEOTIDS)
+	a2.AddSequence("B", "TT", "")
+	a2.AddSequence("C", "GG", "")
+
+	if err := a.ConcatFill(a2, '?'); err != nil {
+		t.Error(err)
+		return
+	}
+
+	exp := map[string]string{"A": "ACGT??", "B": "ACGGTT", "C": "????GG"}
+	if a.NbSequences() != len(exp) {
+		t.Errorf("Concatenated alignment should have %d sequences and not %d", len(exp), a.NbSequences())
+	}
+	for name, seq := range exp {
+		if s, ok := a.GetSequence(name); !ok || s != seq {
+			t.Errorf("Concatenated sequence %s should be %s and not %s", name, seq, s)
+		}
+	}
+}
+
 func TestAppend(t *testing.T) {
 	var err error
 	var a, a2, a3 Alignment
@@ -2363,3 +2426,33 @@ func Test_align_InformativeSites(t *testing.T) {
 		})
 	}
 }
+
+func Test_CountProfile_AddSequence(t *testing.T) {
+	var a Alignment
+	var p, exp *CountProfile
+	var err error
+
+	a = NewAlign(NUCLEOTIDS)
+	a.AddSequence("A", "ACGACGA-GACC", "")
+	a.AddSequence("B", "AT-TT-T-TTTC", "")
+	a.AddSequence("C", "ATCTT-TTT--T", "")
+
+	exp = NewCountProfileFromAlignment(a)
+	p = NewCountProfile()
+	a.IterateChar(func(name string, sequence []uint8) bool {
+		err = p.AddSequence(sequence)
+		return err != nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(exp, p) {
+		t.Error(fmt.Errorf("Profile is not what is expected, have %v, want %v", p, exp))
+	}
+	if c, _ := p.Count('T', 4); c != 2 {
+		t.Error(fmt.Errorf("Count of T at site 4 should be 2, have %d", c))
+	}
+	if err = p.AddSequence([]uint8("ACGT")); err == nil {
+		t.Error(fmt.Errorf("Adding a sequence with a different length should return an error"))
+	}
+}
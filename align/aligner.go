@@ -2,6 +2,7 @@ package align
 
 import (
 	"fmt"
+	"math"
 	"unicode"
 )
 
@@ -30,6 +31,8 @@ const (
 
 	ALIGN_ALGO_SW = iota
 	ALIGN_ALGO_ATG
+	ALIGN_ALGO_NW         // Global alignment (Needleman-Wunsch/Gotoh)
+	ALIGN_ALGO_SEMIGLOBAL // Global alignment with free end gaps (glocal)
 )
 
 type pwaligner struct {
@@ -38,6 +41,12 @@ type pwaligner struct {
 	trace  [][]int     // trace matrix
 	maxa   []float64   // keep track of best gap opened
 
+	// Global & semi-global alignments only (affine gaps, Gotoh):
+	// matrix/trace store the state ending with a (mis)match, and
+	// the two following store the states ending with a gap
+	matrixup, matrixleft [][]float64 // gap in seq2 / gap in seq1
+	traceup, traceleft   [][]int     // trace matrices for gap states
+
 	maxscore         float64 // Maximum score of the matrix
 	nbmatches        int     // number of matches
 	nbmismatches     int     // number of mismatches
@@ -153,6 +162,8 @@ func (a *pwaligner) fillMatrix() (err error) {
 		a.seq1.Reverse()
 		a.seq2.Reverse()
 		err = a.fillMatrix_SW()
+	case ALIGN_ALGO_NW, ALIGN_ALGO_SEMIGLOBAL:
+		err = a.fillMatrix_NW()
 	default:
 		err = a.fillMatrix_SW()
 	}
@@ -354,6 +365,8 @@ func (a *pwaligner) backTrack() {
 		Reverse(a.seq2ali)
 		a.start1, a.end1 = a.seq1.Length()-a.end1-1, a.seq1.Length()-a.start1-1
 		a.start2, a.end2 = a.seq2.Length()-a.end2-1, a.seq2.Length()-a.start2-1
+	case ALIGN_ALGO_NW, ALIGN_ALGO_SEMIGLOBAL:
+		a.backTrack_NW()
 	default:
 		a.backTrack_SW()
 	}
@@ -440,6 +453,256 @@ func (a *pwaligner) backTrack_SW() {
 	a.alistr = alistr
 }
 
+// Matrices for global and semi-global alignments have
+// one more row and one more column than the sequence lengths:
+// row 0 and column 0 correspond to empty prefixes.
+func (a *pwaligner) initMatrix_NW(l1, l2 int) {
+	var i int
+
+	a.matrix = make([][]float64, l1+1)
+	a.matrixup = make([][]float64, l1+1)
+	a.matrixleft = make([][]float64, l1+1)
+	a.trace = make([][]int, l1+1)
+	a.traceup = make([][]int, l1+1)
+	a.traceleft = make([][]int, l1+1)
+	for i = range a.matrix {
+		a.matrix[i] = make([]float64, l2+1)
+		a.matrixup[i] = make([]float64, l2+1)
+		a.matrixleft[i] = make([]float64, l2+1)
+		a.trace[i] = make([]int, l2+1)
+		a.traceup[i] = make([]int, l2+1)
+		a.traceleft[i] = make([]int, l2+1)
+	}
+}
+
+// Global alignment with affine gap scores (Gotoh).
+//
+// If algo is ALIGN_ALGO_SEMIGLOBAL, then gaps at the
+// beginning and at the end of both sequences are not penalized.
+//
+// A gap of length k costs gapopen+(k-1)*gapextend
+func (a *pwaligner) fillMatrix_NW() (err error) {
+	var l1, l2 int
+	var c1, c2 uint8
+	var indexseq1, indexseq2 []int // convert characters to subst matrix positions
+	var best float64
+	var from int
+	var freeends bool = (a.algo == ALIGN_ALGO_SEMIGLOBAL)
+	var inf float64 = math.Inf(-1)
+
+	// We convert characters to indices in subst matrices
+	// once for all
+	if indexseq1, err = a.seqToindices(a.seq1); err != nil {
+		return
+	}
+	if indexseq2, err = a.seqToindices(a.seq2); err != nil {
+		return
+	}
+
+	l1 = a.seq1.Length()
+	l2 = a.seq2.Length()
+	a.initMatrix_NW(l1, l2)
+
+	a.matrix[0][0] = .0
+	a.matrixup[0][0] = inf
+	a.matrixleft[0][0] = inf
+	a.trace[0][0] = ALIGN_STOP
+
+	// First column: only gaps in seq2
+	for i := 1; i <= l1; i++ {
+		a.matrix[i][0] = inf
+		a.matrixleft[i][0] = inf
+		a.matrixup[i][0] = a.gapopen + float64(i-1)*a.gapextend
+		if freeends {
+			a.matrixup[i][0] = .0
+		}
+		a.traceup[i][0] = ALIGN_UP
+		if i == 1 {
+			a.traceup[i][0] = ALIGN_DIAG
+		}
+	}
+
+	// First row: only gaps in seq1
+	for j := 1; j <= l2; j++ {
+		a.matrix[0][j] = inf
+		a.matrixup[0][j] = inf
+		a.matrixleft[0][j] = a.gapopen + float64(j-1)*a.gapextend
+		if freeends {
+			a.matrixleft[0][j] = .0
+		}
+		a.traceleft[0][j] = ALIGN_LEFT
+		if j == 1 {
+			a.traceleft[0][j] = ALIGN_DIAG
+		}
+	}
+
+	for i := 1; i <= l1; i++ {
+		c1 = a.seq1.CharAt(i - 1)
+		for j := 1; j <= l2; j++ {
+			c2 = a.seq2.CharAt(j - 1)
+
+			// (Mis)Match state
+			best, from = a.matrix[i-1][j-1], ALIGN_DIAG
+			if a.matrixup[i-1][j-1] > best {
+				best, from = a.matrixup[i-1][j-1], ALIGN_UP
+			}
+			if a.matrixleft[i-1][j-1] > best {
+				best, from = a.matrixleft[i-1][j-1], ALIGN_LEFT
+			}
+			a.matrix[i][j] = best + a.matchScore(c1, c2, indexseq1[i-1], indexseq2[j-1])
+			a.trace[i][j] = from
+
+			// Gap in seq2 state
+			best, from = a.matrix[i-1][j]+a.gapopen, ALIGN_DIAG
+			if a.matrixup[i-1][j]+a.gapextend > best {
+				best, from = a.matrixup[i-1][j]+a.gapextend, ALIGN_UP
+			}
+			if a.matrixleft[i-1][j]+a.gapopen > best {
+				best, from = a.matrixleft[i-1][j]+a.gapopen, ALIGN_LEFT
+			}
+			a.matrixup[i][j] = best
+			a.traceup[i][j] = from
+
+			// Gap in seq1 state
+			best, from = a.matrix[i][j-1]+a.gapopen, ALIGN_DIAG
+			if a.matrixleft[i][j-1]+a.gapextend > best {
+				best, from = a.matrixleft[i][j-1]+a.gapextend, ALIGN_LEFT
+			}
+			if a.matrixup[i][j-1]+a.gapopen > best {
+				best, from = a.matrixup[i][j-1]+a.gapopen, ALIGN_UP
+			}
+			a.matrixleft[i][j] = best
+			a.traceleft[i][j] = from
+		}
+	}
+
+	// End of the alignment
+	a.maxi = l1
+	a.maxj = l2
+	a.maxscore, _ = a.bestState_NW(l1, l2)
+	if freeends {
+		// Trailing gaps are free: the alignment may end
+		// anywhere on the last row or on the last column
+		for j := 0; j <= l2; j++ {
+			if best, _ = a.bestState_NW(l1, j); best > a.maxscore {
+				a.maxscore, a.maxi, a.maxj = best, l1, j
+			}
+		}
+		for i := 0; i <= l1; i++ {
+			if best, _ = a.bestState_NW(i, l2); best > a.maxscore {
+				a.maxscore, a.maxi, a.maxj = best, i, l2
+			}
+		}
+	}
+	return
+}
+
+// Returns the best score and the corresponding state (ALIGN_DIAG, ALIGN_UP or ALIGN_LEFT)
+// of cell i,j of the global alignment matrices
+func (a *pwaligner) bestState_NW(i, j int) (score float64, state int) {
+	score, state = a.matrix[i][j], ALIGN_DIAG
+	if a.matrixup[i][j] > score {
+		score, state = a.matrixup[i][j], ALIGN_UP
+	}
+	if a.matrixleft[i][j] > score {
+		score, state = a.matrixleft[i][j], ALIGN_LEFT
+	}
+	return
+}
+
+func (a *pwaligner) backTrack_NW() {
+	var i, j, state, pos1, pos2 int
+	var seq1, seq2, alistr []uint8
+	var c1, c2 uint8
+	var first bool
+
+	i = a.maxi
+	j = a.maxj
+	_, state = a.bestState_NW(i, j)
+
+	seq1 = make([]uint8, 0, a.seq1.Length()+a.seq2.Length())
+	seq2 = make([]uint8, 0, a.seq1.Length()+a.seq2.Length())
+	alistr = make([]uint8, 0, a.seq1.Length()+a.seq2.Length())
+
+	// Free trailing gaps (semi-global only)
+	for k := a.seq1.Length() - 1; k >= i; k-- {
+		seq1 = append(seq1, a.seq1.CharAt(k))
+		seq2 = append(seq2, '-')
+		alistr = append(alistr, ' ')
+	}
+	for k := a.seq2.Length() - 1; k >= j; k-- {
+		seq1 = append(seq1, '-')
+		seq2 = append(seq2, a.seq2.CharAt(k))
+		alistr = append(alistr, ' ')
+	}
+
+	for i > 0 || j > 0 {
+		switch state {
+		case ALIGN_DIAG:
+			seq1 = append(seq1, a.seq1.CharAt(i-1))
+			seq2 = append(seq2, a.seq2.CharAt(j-1))
+			if a.seq1.CharAt(i-1) == a.seq2.CharAt(j-1) {
+				alistr = append(alistr, '|')
+			} else {
+				alistr = append(alistr, '.')
+			}
+			state = a.trace[i][j]
+			i--
+			j--
+		case ALIGN_UP:
+			seq1 = append(seq1, a.seq1.CharAt(i-1))
+			seq2 = append(seq2, '-')
+			alistr = append(alistr, ' ')
+			state = a.traceup[i][j]
+			i--
+		case ALIGN_LEFT:
+			seq1 = append(seq1, '-')
+			seq2 = append(seq2, a.seq2.CharAt(j-1))
+			alistr = append(alistr, ' ')
+			state = a.traceleft[i][j]
+			j--
+		}
+	}
+
+	Reverse(seq1)
+	Reverse(seq2)
+	Reverse(alistr)
+
+	a.seq1ali = seq1
+	a.seq2ali = seq2
+	a.alistr = alistr
+
+	// Statistics, and start/end of the aligned region,
+	// i.e. first and last positions where no sequence has a gap
+	a.start1, a.start2 = 0, 0
+	a.end1, a.end2 = a.seq1.Length()-1, a.seq2.Length()-1
+	first = true
+	for k := range seq1 {
+		c1, c2 = seq1[k], seq2[k]
+		a.length++
+		if c1 == '-' || c2 == '-' {
+			a.nbgaps++
+		} else {
+			if c1 == c2 {
+				a.nbmatches++
+			} else {
+				a.nbmismatches++
+			}
+			if first {
+				a.start1, a.start2 = pos1, pos2
+				first = false
+			}
+			a.end1, a.end2 = pos1, pos2
+		}
+		if c1 != '-' {
+			pos1++
+		}
+		if c2 != '-' {
+			pos2++
+		}
+	}
+}
+
 func (a *pwaligner) AlignmentStr() string {
 	alistr := make([]uint8, 0, len(a.seq1ali)+len(a.alistr)+len(a.seq2ali)+3)
 	alistr = append(alistr, a.seq1ali...)
@@ -0,0 +1,100 @@
+package align
+
+import (
+	"testing"
+)
+
+func Test_pwaligner_Global(t *testing.T) {
+	tests := []struct {
+		name          string
+		algo          int
+		seq1, seq2    string
+		want1, want2  string
+		wantScore     float64
+		wantStart1    int
+		wantStart2    int
+		wantEnd1      int
+		wantEnd2      int
+		wantNbMatches int
+		wantNbGaps    int
+		wantAliLength int
+	}{
+		{name: "global",
+			algo:          ALIGN_ALGO_NW,
+			seq1:          "GGGGGACGTACGTTTGACCATTTTT",
+			seq2:          "ACGTACGTTTGACCA",
+			want1:         "GGGGGACGTACGTTTGACCATTTTT",
+			want2:         "-----ACGTACGTTTGACCA-----",
+			wantScore:     51.0,
+			wantStart1:    5,
+			wantStart2:    0,
+			wantEnd1:      19,
+			wantEnd2:      14,
+			wantNbMatches: 15,
+			wantNbGaps:    10,
+			wantAliLength: 25,
+		},
+		{name: "semiglobal",
+			algo:          ALIGN_ALGO_SEMIGLOBAL,
+			seq1:          "GGGGGACGTACGTTTGACCATTTTT",
+			seq2:          "ACGTACGTTTGACCA",
+			want1:         "GGGGGACGTACGTTTGACCATTTTT",
+			want2:         "-----ACGTACGTTTGACCA-----",
+			wantScore:     75.0,
+			wantStart1:    5,
+			wantStart2:    0,
+			wantEnd1:      19,
+			wantEnd2:      14,
+			wantNbMatches: 15,
+			wantNbGaps:    10,
+			wantAliLength: 25,
+		},
+		{name: "semiglobal overlap",
+			algo:          ALIGN_ALGO_SEMIGLOBAL,
+			seq1:          "TTTTTACGTACGT",
+			seq2:          "ACGTACGTGGGGG",
+			want1:         "TTTTTACGTACGT-----",
+			want2:         "-----ACGTACGTGGGGG",
+			wantScore:     40.0,
+			wantStart1:    5,
+			wantStart2:    0,
+			wantEnd1:      12,
+			wantEnd2:      7,
+			wantNbMatches: 8,
+			wantNbGaps:    10,
+			wantAliLength: 18,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewPwAligner(NewSequence("s1", []uint8(tt.seq1), ""), NewSequence("s2", []uint8(tt.seq2), ""), tt.algo)
+			if _, err := a.Alignment(); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(a.Seq1Ali()); got != tt.want1 {
+				t.Errorf("Seq1Ali() = %v, want %v", got, tt.want1)
+			}
+			if got := string(a.Seq2Ali()); got != tt.want2 {
+				t.Errorf("Seq2Ali() = %v, want %v", got, tt.want2)
+			}
+			if got := a.MaxScore(); got != tt.wantScore {
+				t.Errorf("MaxScore() = %v, want %v", got, tt.wantScore)
+			}
+			if s1, s2 := a.AlignStarts(); s1 != tt.wantStart1 || s2 != tt.wantStart2 {
+				t.Errorf("AlignStarts() = %d,%d, want %d,%d", s1, s2, tt.wantStart1, tt.wantStart2)
+			}
+			if e1, e2 := a.AlignEnds(); e1 != tt.wantEnd1 || e2 != tt.wantEnd2 {
+				t.Errorf("AlignEnds() = %d,%d, want %d,%d", e1, e2, tt.wantEnd1, tt.wantEnd2)
+			}
+			if got := a.NbMatches(); got != tt.wantNbMatches {
+				t.Errorf("NbMatches() = %v, want %v", got, tt.wantNbMatches)
+			}
+			if got := a.NbGaps(); got != tt.wantNbGaps {
+				t.Errorf("NbGaps() = %v, want %v", got, tt.wantNbGaps)
+			}
+			if got := a.Length(); got != tt.wantAliLength {
+				t.Errorf("Length() = %v, want %v", got, tt.wantAliLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package align
+
+// Annotation is a markup line associated to an alignment,
+// as found in Stockholm files
+type Annotation struct {
+	Name    string // Sequence name (only for #=GS and #=GR annotations)
+	Feature string // Feature tag (e.g. AC, DE, SS, ...)
+	Text    string // Free text (#=GF, #=GS) or one character per alignment column (#=GC, #=GR)
+}
+
+// Annotations of an alignment, as found in Stockholm files.
+// The order of the annotations in each slice is the order of the input file.
+type Annotations struct {
+	GF []Annotation // Per file annotations: #=GF <feature> <text>
+	GS []Annotation // Per sequence annotations: #=GS <seqname> <feature> <text>
+	GC []Annotation // Per column annotations: #=GC <feature> <per column annotation>
+	GR []Annotation // Per residue annotations: #=GR <seqname> <feature> <per residue annotation>
+}
+
+// NewAnnotations returns an empty set of annotations
+func NewAnnotations() *Annotations {
+	return &Annotations{
+		GF: make([]Annotation, 0),
+		GS: make([]Annotation, 0),
+		GC: make([]Annotation, 0),
+		GR: make([]Annotation, 0),
+	}
+}
+
+// Clone returns a deep copy of the annotations
+func (an *Annotations) Clone() *Annotations {
+	if an == nil {
+		return nil
+	}
+	c := NewAnnotations()
+	c.GF = append(c.GF, an.GF...)
+	c.GS = append(c.GS, an.GS...)
+	c.GC = append(c.GC, an.GC...)
+	c.GR = append(c.GR, an.GR...)
+	return c
+}
+
+// Empty returns true if there is no annotation at all
+func (an *Annotations) Empty() bool {
+	return an == nil || (len(an.GF) == 0 && len(an.GS) == 0 && len(an.GC) == 0 && len(an.GR) == 0)
+}
+
+// Annotations returns the annotations of the alignment (nil if none)
+func (a *align) Annotations() *Annotations {
+	return a.annotations
+}
+
+// SetAnnotations sets the annotations of the alignment
+func (a *align) SetAnnotations(an *Annotations) {
+	a.annotations = an
+}
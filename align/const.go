@@ -2,6 +2,7 @@ package align
 
 import (
 	"fmt"
+	"sort"
 	"unicode"
 )
 
@@ -24,19 +25,45 @@ const (
 	PSSM_NORM_UNIF = 3 // Normalization by uniform frequency
 	PSSM_NORM_LOGO = 4 // Normalization like LOGO : v(site)=freq*(log2(alphabet)-H(site)-pseudocount
 
-	FORMAT_FASTA   = 0
-	FORMAT_PHYLIP  = 1
-	FORMAT_NEXUS   = 2
-	FORMAT_CLUSTAL = 3
+	FORMAT_FASTA     = 0
+	FORMAT_PHYLIP    = 1
+	FORMAT_NEXUS     = 2
+	FORMAT_CLUSTAL   = 3
+	FORMAT_STOCKHOLM = 4
 
 	POSITION_IDENTICAL      = 0 // All characters in a position are the same
 	POSITION_CONSERVED      = 1 // Same strong group
 	POSITION_SEMI_CONSERVED = 2 // Same weak group
 	POSITION_NOT_CONSERVED  = 3 // None of the above values
 
-	GENETIC_CODE_STANDARD         = 0 // Standard genetic code
-	GENETIC_CODE_VETEBRATE_MITO   = 1 // Vertebrate mitochondrial genetic code
-	GENETIC_CODE_INVETEBRATE_MITO = 2 // Invertebrate mitochondrial genetic code
+	// Genetic codes (NCBI translation table id in parenthesis)
+	GENETIC_CODE_STANDARD                = 0  // Standard genetic code (1)
+	GENETIC_CODE_VETEBRATE_MITO          = 1  // Vertebrate mitochondrial genetic code (2)
+	GENETIC_CODE_INVETEBRATE_MITO        = 2  // Invertebrate mitochondrial genetic code (5)
+	GENETIC_CODE_YEAST_MITO              = 3  // Yeast mitochondrial code (3)
+	GENETIC_CODE_MOLD_MITO               = 4  // Mold, protozoan, coelenterate mitochondrial and Mycoplasma/Spiroplasma code (4)
+	GENETIC_CODE_CILIATE                 = 5  // Ciliate, dasycladacean and hexamita nuclear code (6)
+	GENETIC_CODE_ECHINODERM_MITO         = 6  // Echinoderm and flatworm mitochondrial code (9)
+	GENETIC_CODE_EUPLOTID                = 7  // Euplotid nuclear code (10)
+	GENETIC_CODE_BACTERIAL_PLASTID       = 8  // Bacterial, archaeal and plant plastid code (11)
+	GENETIC_CODE_ALT_YEAST               = 9  // Alternative yeast nuclear code (12)
+	GENETIC_CODE_ASCIDIAN_MITO           = 10 // Ascidian mitochondrial code (13)
+	GENETIC_CODE_ALT_FLATWORM_MITO       = 11 // Alternative flatworm mitochondrial code (14)
+	GENETIC_CODE_BLEPHARISMA             = 12 // Blepharisma nuclear code (15)
+	GENETIC_CODE_CHLOROPHYCEAN_MITO      = 13 // Chlorophycean mitochondrial code (16)
+	GENETIC_CODE_TREMATODE_MITO          = 14 // Trematode mitochondrial code (21)
+	GENETIC_CODE_SCENEDESMUS_MITO        = 15 // Scenedesmus obliquus mitochondrial code (22)
+	GENETIC_CODE_THRAUSTOCHYTRIUM_MITO   = 16 // Thraustochytrium mitochondrial code (23)
+	GENETIC_CODE_RHABDOPLEURIDAE_MITO    = 17 // Rhabdopleuridae mitochondrial code (24)
+	GENETIC_CODE_SR1_GRACILIBACTERIA     = 18 // Candidate division SR1 and gracilibacteria code (25)
+	GENETIC_CODE_PACHYSOLEN              = 19 // Pachysolen tannophilus nuclear code (26)
+	GENETIC_CODE_KARYORELICT             = 20 // Karyorelict nuclear code (27)
+	GENETIC_CODE_CONDYLOSTOMA            = 21 // Condylostoma nuclear code (28)
+	GENETIC_CODE_MESODINIUM              = 22 // Mesodinium nuclear code (29)
+	GENETIC_CODE_PERITRICH               = 23 // Peritrich nuclear code (30)
+	GENETIC_CODE_BLASTOCRITHIDIA         = 24 // Blastocrithidia nuclear code (31)
+	GENETIC_CODE_BALANOPHORACEAE_PLASTID = 25 // Balanophoraceae plastid code (32)
+	GENETIC_CODE_CEPHALODISCIDAE_MITO    = 26 // Cephalodiscidae mitochondrial code (33)
 
 	IGNORE_NONE     = 0
 	IGNORE_NAME     = 1
@@ -64,92 +91,139 @@ const (
 var stdaminoacid = []uint8{'A', 'R', 'N', 'D', 'C', 'Q', 'E', 'G', 'H', 'I', 'L', 'K', 'M', 'F', 'P', 'S', 'T', 'W', 'Y', 'V'}
 var stdnucleotides = []uint8{'A', 'C', 'G', 'T'}
 
-var standardcode = map[string]uint8{
-	"---": '-',
-	"GCT": 'A', "GCC": 'A', "GCA": 'A', "GCG": 'A',
-	"TTA": 'L', "TTG": 'L', "CTT": 'L', "CTC": 'L', "CTA": 'L', "CTG": 'L',
-	"CGT": 'R', "CGC": 'R', "CGA": 'R', "CGG": 'R', "AGA": 'R', "AGG": 'R',
-	"AAA": 'K', "AAG": 'K',
-	"AAT": 'N', "AAC": 'N',
-	"ATG": 'M',
-	"GAT": 'D', "GAC": 'D',
-	"TTT": 'F', "TTC": 'F',
-	"TGT": 'C', "TGC": 'C',
-	"CCT": 'P', "CCC": 'P', "CCA": 'P', "CCG": 'P',
-	"CAA": 'Q', "CAG": 'Q',
-	"TCT": 'S', "TCC": 'S', "TCA": 'S', "TCG": 'S', "AGT": 'S', "AGC": 'S',
-	"GAA": 'E', "GAG": 'E',
-	"ACT": 'T', "ACC": 'T', "ACA": 'T', "ACG": 'T',
-	"GGT": 'G', "GGC": 'G', "GGA": 'G', "GGG": 'G',
-	"TGG": 'W',
-	"CAT": 'H', "CAC": 'H',
-	"TAT": 'Y', "TAC": 'Y',
-	"ATT": 'I', "ATC": 'I', "ATA": 'I',
-	"GTT": 'V', "GTC": 'V', "GTA": 'V', "GTG": 'V',
-	"TAA": '*', "TGA": '*', "TAG": '*',
+// NCBI translation tables, as given in NCBI gc.prt file:
+//   - aas: amino acid of each codon, codons being ordered as
+//     TTT TTC TTA TTG TCT ... GGG (bases ordered as T, C, A, G);
+//   - starts: M for each codon that may be used as start codon.
+//
+// Index in this slice corresponds to GENETIC_CODE_* constants.
+var ncbiGeneticCodes = []struct {
+	id     int
+	aas    string
+	starts string
+}{
+	{1, "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "---M---------------M---------------M----------------------------"},
+	{2, "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSS**VVVVAAAADDEEGGGG", "--------------------------------MMMM---------------M------------"},
+	{5, "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSSSSVVVVAAAADDEEGGGG", "---M----------------------------MMMM---------------M------------"},
+	{3, "FFLLSSSSYY**CCWWTTTTPPPPHHQQRRRRIIMMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "----------------------------------MM----------------------------"},
+	{4, "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "--MM---------------M------------MMMM---------------M------------"},
+	{6, "FFLLSSSSYYQQCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-----------------------------------M----------------------------"},
+	{9, "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNNKSSSSVVVVAAAADDEEGGGG", "-----------------------------------M---------------M------------"},
+	{10, "FFLLSSSSYY**CCCWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-----------------------------------M----------------------------"},
+	{11, "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "---M---------------M------------MMMM---------------M------------"},
+	{12, "FFLLSSSSYY**CC*WLLLSPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-------------------M---------------M----------------------------"},
+	{13, "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSSGGVVVVAAAADDEEGGGG", "---M------------------------------MM---------------M------------"},
+	{14, "FFLLSSSSYYY*CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNNKSSSSVVVVAAAADDEEGGGG", "-----------------------------------M----------------------------"},
+	{15, "FFLLSSSSYY*QCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-----------------------------------M----------------------------"},
+	{16, "FFLLSSSSYY*LCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-----------------------------------M----------------------------"},
+	{21, "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNNKSSSSVVVVAAAADDEEGGGG", "-----------------------------------M---------------M------------"},
+	{22, "FFLLSS*SYY*LCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-----------------------------------M----------------------------"},
+	{23, "FF*LSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "--------------------------------M--M---------------M------------"},
+	{24, "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSSKVVVVAAAADDEEGGGG", "---M---------------M---------------M---------------M------------"},
+	{25, "FFLLSSSSYY**CCGWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "---M-------------------------------M---------------M------------"},
+	{26, "FFLLSSSSYY**CC*WLLLAPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-------------------M---------------M----------------------------"},
+	{27, "FFLLSSSSYYQQCCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-----------------------------------M----------------------------"},
+	{28, "FFLLSSSSYYQQCCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-----------------------------------M----------------------------"},
+	{29, "FFLLSSSSYYYYCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-----------------------------------M----------------------------"},
+	{30, "FFLLSSSSYYEECC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-----------------------------------M----------------------------"},
+	{31, "FFLLSSSSYYEECCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "-----------------------------------M----------------------------"},
+	{32, "FFLLSSSSYY*WCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG", "---M---------------M------------MMMM---------------M------------"},
+	{33, "FFLLSSSSYYY*CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSSKVVVVAAAADDEEGGGG", "---M---------------M---------------M---------------M------------"},
 }
 
-var vertebratemitocode = map[string]uint8{
-	"---": '-',
-	"GCT": 'A', "GCC": 'A', "GCA": 'A', "GCG": 'A',
-	"TTA": 'L', "TTG": 'L', "CTT": 'L', "CTC": 'L', "CTA": 'L', "CTG": 'L',
-	"CGT": 'R', "CGC": 'R', "CGA": 'R', "CGG": 'R',
-	"AAA": 'K', "AAG": 'K',
-	"AAT": 'N', "AAC": 'N',
-	"ATG": 'M', "ATA": 'M',
-	"GAT": 'D', "GAC": 'D',
-	"TTT": 'F', "TTC": 'F',
-	"TGT": 'C', "TGC": 'C',
-	"CCT": 'P', "CCC": 'P', "CCA": 'P', "CCG": 'P',
-	"CAA": 'Q', "CAG": 'Q',
-	"TCT": 'S', "TCC": 'S', "TCA": 'S', "TCG": 'S', "AGT": 'S', "AGC": 'S',
-	"GAA": 'E', "GAG": 'E',
-	"ACT": 'T', "ACC": 'T', "ACA": 'T', "ACG": 'T',
-	"GGT": 'G', "GGC": 'G', "GGA": 'G', "GGG": 'G',
-	"TGG": 'W', "TGA": 'W',
-	"CAT": 'H', "CAC": 'H',
-	"TAT": 'Y', "TAC": 'Y',
-	"ATT": 'I', "ATC": 'I',
-	"GTT": 'V', "GTC": 'V', "GTA": 'V', "GTG": 'V',
-	"AGA": '*', "AGG": '*', "TAA": '*', "TAG": '*',
-}
+// Codon => amino acid maps, one per genetic code (same order as ncbiGeneticCodes)
+var geneticCodes = buildGeneticCodes()
 
-var invertebratemitocode = map[string]uint8{
-	"---": '-',
-	"GCT": 'A', "GCC": 'A', "GCA": 'A', "GCG": 'A',
-	"TTA": 'L', "TTG": 'L', "CTT": 'L', "CTC": 'L', "CTA": 'L', "CTG": 'L',
-	"CGT": 'R', "CGC": 'R', "CGA": 'R', "CGG": 'R',
-	"AAA": 'K', "AAG": 'K',
-	"AAT": 'N', "AAC": 'N',
-	"ATG": 'M', "ATA": 'M',
-	"GAT": 'D', "GAC": 'D',
-	"TTT": 'F', "TTC": 'F',
-	"TGT": 'C', "TGC": 'C',
-	"CCT": 'P', "CCC": 'P', "CCA": 'P', "CCG": 'P',
-	"CAA": 'Q', "CAG": 'Q',
-	"AGA": 'S', "AGG": 'S', "TCT": 'S', "TCC": 'S', "TCA": 'S', "TCG": 'S', "AGT": 'S', "AGC": 'S',
-	"GAA": 'E', "GAG": 'E',
-	"ACT": 'T', "ACC": 'T', "ACA": 'T', "ACG": 'T',
-	"GGT": 'G', "GGC": 'G', "GGA": 'G', "GGG": 'G',
-	"TGG": 'W', "TGA": 'W',
-	"CAT": 'H', "CAC": 'H',
-	"TAT": 'Y', "TAC": 'Y',
-	"ATT": 'I', "ATC": 'I',
-	"GTT": 'V', "GTC": 'V', "GTA": 'V', "GTG": 'V',
-	"TAA": '*', "TAG": '*',
+func buildGeneticCodes() (codes []map[string]uint8) {
+	var bases = "TCAG"
+	var codon string
+
+	codes = make([]map[string]uint8, len(ncbiGeneticCodes))
+	for i, gc := range ncbiGeneticCodes {
+		codes[i] = map[string]uint8{"---": '-'}
+		for j := 0; j < 64; j++ {
+			codon = string([]byte{bases[j/16], bases[(j/4)%4], bases[j%4]})
+			codes[i][codon] = gc.aas[j]
+		}
+	}
+	return
 }
 
 func geneticCode(code int) (gencode map[string]uint8, err error) {
-	switch code {
-	case GENETIC_CODE_STANDARD:
-		gencode = standardcode
-	case GENETIC_CODE_VETEBRATE_MITO:
-		gencode = vertebratemitocode
-	case GENETIC_CODE_INVETEBRATE_MITO:
-		gencode = invertebratemitocode
-	default:
-		err = fmt.Errorf("this genetic code does not exis")
+	if code < 0 || code >= len(geneticCodes) {
+		err = fmt.Errorf("this genetic code does not exist")
+		return
+	}
+	gencode = geneticCodes[code]
+	return
+}
+
+// GeneticCode returns a copy of the codon => amino acid map of the given
+// genetic code (GENETIC_CODE_* constants). Codons are upper case, and
+// stop codons are translated into '*'.
+func GeneticCode(code int) (gencode map[string]uint8, err error) {
+	var gc map[string]uint8
+
+	if gc, err = geneticCode(code); err != nil {
+		return
+	}
+	gencode = make(map[string]uint8, len(gc))
+	for codon, aa := range gc {
+		gencode[codon] = aa
+	}
+	return
+}
+
+// Returns the start codons of the given genetic code.
+// If altstarts is false, then only ATG is considered as start codon
+// Otherwise, all start codons given in the NCBI translation table are returned.
+func startCodons(code int, altstarts bool) (starts []string, err error) {
+	var bases = "TCAG"
+
+	if code < 0 || code >= len(ncbiGeneticCodes) {
+		err = fmt.Errorf("this genetic code does not exist")
+		return
+	}
+	if !altstarts {
+		starts = []string{"ATG"}
+		return
+	}
+	starts = make([]string, 0, 5)
+	for j, c := range ncbiGeneticCodes[code].starts {
+		if c == 'M' {
+			starts = append(starts, string([]byte{bases[j/16], bases[(j/4)%4], bases[j%4]}))
+		}
+	}
+	return
+}
+
+// Returns the stop codons of the given genetic code
+func stopCodons(code int) (stops []string, err error) {
+	var gencode map[string]uint8
+
+	if gencode, err = geneticCode(code); err != nil {
+		return
+	}
+	stops = make([]string, 0, 3)
+	for codon, aa := range gencode {
+		if aa == '*' {
+			stops = append(stops, codon)
+		}
+	}
+	sort.Strings(stops)
+	return
+}
+
+// GeneticCodeFromNCBI returns the genetic code (GENETIC_CODE_* constants)
+// corresponding to the given NCBI translation table id (1 to 33)
+func GeneticCodeFromNCBI(id int) (code int, err error) {
+	for i, gc := range ncbiGeneticCodes {
+		if gc.id == id {
+			code = i
+			return
+		}
 	}
+	err = fmt.Errorf("ncbi genetic code %d does not exist", id)
 	return
 }
 
@@ -0,0 +1,676 @@
+package align
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	MSA_DIST_KMER     = iota // Guide tree built from k-mer distances
+	MSA_DIST_PAIRWISE        // Guide tree built from pairwise (global) alignment distances
+)
+
+// MultipleAligner aligns a set of unaligned sequences progressively:
+//
+//  1. Computes distances between all pairs of sequences, either from
+//     shared k-mers (MSA_DIST_KMER) or from pairwise global alignments (MSA_DIST_PAIRWISE);
+//  2. Builds a UPGMA guide tree from these distances;
+//  3. Follows the guide tree from the leaves to the root, and aligns
+//     profiles together (global profile-profile alignment, affine gaps).
+//     Column scores are the average substitution scores (dnafull or blosum62, or
+//     match/mismatch scores if given with SetScore) over all pairs of
+//     characters of the two profiles.
+//
+// It does not modify the input sequences, and sequences of the output
+// alignment are in the same order as the input sequences.
+//
+// Add aligns each sequence of seqs against the profile of the alignment al
+// (character frequencies computed from its CountProfile), and returns a new alignment
+// made of al sequences followed by the new aligned sequences. New sequences are
+// aligned independently from each other. If keeplength is true, then
+// characters of new sequences that are inserted relative to al are removed, and
+// the columns of al are kept as is (like mafft --keeplength). Otherwise,
+// insertions are kept, and gap columns are added to al sequences accordingly.
+type MultipleAligner interface {
+	Align(seqs SeqBag) (Alignment, error)
+	Add(al Alignment, seqs SeqBag, keeplength bool) (Alignment, error)
+	SetGapOpenScore(open float64)
+	SetGapExtendScore(extend float64)
+	SetScore(match, mismatch float64)
+	SetGuideDistance(dist int) error
+	SetCpus(cpus int)
+}
+
+type msaligner struct {
+	gapopen       float64
+	gapextend     float64
+	changedscores bool
+	match         float64
+	mismatch      float64
+	guidedist     int
+	cpus          int
+
+	submatrix [][]float64   // substitution matrix
+	chartopos map[uint8]int // char to position in subst matrix
+}
+
+// Aligned profile: set of aligned sequences
+// at a given node of the guide tree
+type msaProfile struct {
+	ids  []int     // Indices of the sequences in the input seqbag
+	rows [][]uint8 // Aligned sequences
+}
+
+func NewMultipleAligner() MultipleAligner {
+	return &msaligner{
+		gapopen:       -10.0,
+		gapextend:     -0.5,
+		changedscores: false,
+		match:         1.0,
+		mismatch:      -1.0,
+		guidedist:     MSA_DIST_KMER,
+		cpus:          1,
+	}
+}
+
+func (m *msaligner) SetGapOpenScore(open float64) {
+	m.gapopen = open
+}
+
+func (m *msaligner) SetGapExtendScore(extend float64) {
+	m.gapextend = extend
+}
+
+// Sets manually match and mismatch scores
+// Substitution matrix is not used any more
+func (m *msaligner) SetScore(match, mismatch float64) {
+	m.match = match
+	m.mismatch = mismatch
+	m.changedscores = true
+}
+
+func (m *msaligner) SetGuideDistance(dist int) (err error) {
+	switch dist {
+	case MSA_DIST_KMER, MSA_DIST_PAIRWISE:
+		m.guidedist = dist
+	default:
+		err = fmt.Errorf("unknown guide tree distance: %d", dist)
+	}
+	return
+}
+
+func (m *msaligner) SetCpus(cpus int) {
+	m.cpus = cpus
+}
+
+func (m *msaligner) Align(seqs SeqBag) (al Alignment, err error) {
+	var unal SeqBag
+	var dists [][]float64
+	var profiles []*msaProfile
+	var i, j, k, l int
+	var s Sequence
+
+	unal = seqs.Unalign()
+	if unal.Alphabet() == UNKNOWN || unal.Alphabet() == BOTH {
+		unal.AutoAlphabet()
+	}
+	if err = m.initSubstMatrix(unal.Alphabet()); err != nil {
+		return
+	}
+
+	if unal.NbSequences() == 0 {
+		err = fmt.Errorf("no sequence to align")
+		return
+	}
+
+	profiles = make([]*msaProfile, unal.NbSequences())
+	for i, s = range unal.Sequences() {
+		if err = m.checkCharacters(s.SequenceChar()); err != nil {
+			return
+		}
+		profiles[i] = &msaProfile{
+			ids:  []int{i},
+			rows: [][]uint8{s.SequenceChar()},
+		}
+	}
+
+	if dists, err = m.guideDistances(unal); err != nil {
+		return
+	}
+
+	// UPGMA guide tree: we merge the two closest
+	// profiles until there is only one left
+	sizes := make([]float64, len(profiles))
+	for i = range sizes {
+		sizes[i] = 1.0
+	}
+	for len(profiles) > 1 {
+		mini, minj := 0, 1
+		for i = 0; i < len(profiles); i++ {
+			for j = i + 1; j < len(profiles); j++ {
+				if dists[i][j] < dists[mini][minj] {
+					mini, minj = i, j
+				}
+			}
+		}
+		profiles[mini] = m.alignProfiles(profiles[mini], profiles[minj])
+
+		// Average distances to the new cluster
+		for k = range profiles {
+			if k != mini && k != minj {
+				d := (dists[mini][k]*sizes[mini] + dists[minj][k]*sizes[minj]) / (sizes[mini] + sizes[minj])
+				dists[mini][k] = d
+				dists[k][mini] = d
+			}
+		}
+		sizes[mini] += sizes[minj]
+
+		// We remove cluster minj
+		profiles = append(profiles[:minj], profiles[minj+1:]...)
+		sizes = append(sizes[:minj], sizes[minj+1:]...)
+		dists = append(dists[:minj], dists[minj+1:]...)
+		for k = range dists {
+			dists[k] = append(dists[k][:minj], dists[k][minj+1:]...)
+		}
+	}
+
+	// Output alignment in the input order
+	rows := make([][]uint8, unal.NbSequences())
+	for l, i = range profiles[0].ids {
+		rows[i] = profiles[0].rows[l]
+	}
+	al = NewAlign(unal.Alphabet())
+	for i, s = range unal.Sequences() {
+		if err = al.AddSequenceChar(s.Name(), rows[i], s.Comment()); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (m *msaligner) Add(al Alignment, seqs SeqBag, keeplength bool) (res Alignment, err error) {
+	var unal SeqBag
+	var cp *CountProfile
+	var f1 [][]float64
+	var ops []int
+	var counts []int
+	var name uint8
+	var idx, i, j, k int
+	var ok bool
+	var cols [][]uint8  // For each new sequence: its character at each column of al
+	var ins [][][]uint8 // For each new sequence: its insertions before each column of al (and after the last one)
+	var maxins []int    // Maximum insertion length before each column of al
+	var newseqs []Sequence
+
+	if al.NbSequences() == 0 {
+		err = fmt.Errorf("cannot add sequences to an empty alignment")
+		return
+	}
+	if err = m.initSubstMatrix(al.Alphabet()); err != nil {
+		return
+	}
+
+	// Frequencies of each character at each site of al
+	cp = NewCountProfileFromAlignment(al)
+	f1 = make([][]float64, al.Length())
+	for i = range f1 {
+		f1[i] = make([]float64, len(m.submatrix))
+	}
+	for i = 0; i < cp.NbCharacters(); i++ {
+		name, _ = cp.NameAt(i)
+		if idx, ok = m.chartopos[uint8(unicode.ToUpper(rune(name)))]; !ok || name == GAP {
+			continue
+		}
+		counts, _ = cp.CountsAt(i)
+		for j = range counts {
+			f1[j][idx] += float64(counts[j]) / float64(al.NbSequences())
+		}
+	}
+
+	unal = seqs.Unalign()
+	newseqs = unal.Sequences()
+	cols = make([][]uint8, len(newseqs))
+	ins = make([][][]uint8, len(newseqs))
+	maxins = make([]int, al.Length()+1)
+	for i, s := range newseqs {
+		if err = m.checkCharacters(s.SequenceChar()); err != nil {
+			return
+		}
+		ops = m.alignFrequencies(f1, m.profileFrequencies(&msaProfile{rows: [][]uint8{s.SequenceChar()}}))
+		cols[i] = make([]uint8, 0, al.Length())
+		ins[i] = make([][]uint8, al.Length()+1)
+		j = 0
+		for _, op := range ops {
+			switch op {
+			case ALIGN_DIAG:
+				cols[i] = append(cols[i], s.SequenceChar()[j])
+				j++
+			case ALIGN_UP:
+				cols[i] = append(cols[i], GAP)
+			case ALIGN_LEFT:
+				k = len(cols[i])
+				ins[i][k] = append(ins[i][k], s.SequenceChar()[j])
+				if len(ins[i][k]) > maxins[k] {
+					maxins[k] = len(ins[i][k])
+				}
+				j++
+			}
+		}
+	}
+
+	res = NewAlign(al.Alphabet())
+	if keeplength {
+		al.IterateAll(func(name string, sequence []uint8, comment string) bool {
+			err = res.AddSequenceChar(name, sequence, comment)
+			return err != nil
+		})
+		if err != nil {
+			return
+		}
+		for i, s := range newseqs {
+			if err = res.AddSequenceChar(s.Name(), cols[i], s.Comment()); err != nil {
+				return
+			}
+		}
+		return
+	}
+
+	// Insertions are added before each column of al, gaps are
+	// added to other sequences to fill the inserted columns
+	al.IterateAll(func(name string, sequence []uint8, comment string) bool {
+		row := make([]uint8, 0, len(sequence)+len(maxins))
+		for k = 0; k <= len(sequence); k++ {
+			row = append(row, []uint8(strings.Repeat(string(GAP), maxins[k]))...)
+			if k < len(sequence) {
+				row = append(row, sequence[k])
+			}
+		}
+		err = res.AddSequenceChar(name, row, comment)
+		return err != nil
+	})
+	if err != nil {
+		return
+	}
+	for i, s := range newseqs {
+		row := make([]uint8, 0, len(cols[i])+len(maxins))
+		for k = 0; k <= len(cols[i]); k++ {
+			row = append(row, ins[i][k]...)
+			row = append(row, []uint8(strings.Repeat(string(GAP), maxins[k]-len(ins[i][k])))...)
+			if k < len(cols[i]) {
+				row = append(row, cols[i][k])
+			}
+		}
+		if err = res.AddSequenceChar(s.Name(), row, s.Comment()); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Initializes the substitution matrix depending on the given alphabet
+// (dnafull or blosum62), or using match/mismatch scores
+func (m *msaligner) initSubstMatrix(alphabet int) (err error) {
+	switch alphabet {
+	case NUCLEOTIDS, BOTH:
+		m.submatrix = dnafull_subst_matrix
+		m.chartopos = dna_to_matrix_pos
+	case AMINOACIDS:
+		m.submatrix = blosum62_subst_matrix
+		m.chartopos = prot_to_matrix_pos
+	default:
+		err = fmt.Errorf("unknown alphabet for multiple sequence alignment")
+		return
+	}
+	if m.changedscores {
+		m.submatrix = identitySubstMatrix(len(m.submatrix), m.match, m.mismatch)
+	}
+	return
+}
+
+// Checks that all characters of the sequence are part of the substitution matrix
+func (m *msaligner) checkCharacters(seq []uint8) (err error) {
+	for _, c := range seq {
+		if _, ok := m.chartopos[uint8(unicode.ToUpper(rune(c)))]; !ok {
+			err = fmt.Errorf("character not part of alphabet : %c", c)
+			return
+		}
+	}
+	return
+}
+
+// Computes all pairwise distances between the input
+// sequences (in parallel, using cpus threads)
+func (m *msaligner) guideDistances(seqs SeqBag) (dists [][]float64, err error) {
+	var kmers []map[string]int
+	var k int = 2
+	var seqlist []Sequence = seqs.Sequences()
+	var wg sync.WaitGroup
+	var errmut sync.Mutex
+
+	dists = make([][]float64, len(seqlist))
+	for i := range dists {
+		dists[i] = make([]float64, len(seqlist))
+	}
+
+	if m.guidedist == MSA_DIST_KMER {
+		if seqs.Alphabet() != AMINOACIDS {
+			k = 4
+		}
+		kmers = make([]map[string]int, len(seqlist))
+		for i, s := range seqlist {
+			kmers[i] = countKmers(s.SequenceChar(), k)
+		}
+	}
+
+	rows := make(chan int, 100)
+	go func() {
+		for i := range seqlist {
+			rows <- i
+		}
+		close(rows)
+	}()
+
+	for cpu := 0; cpu < m.cpus; cpu++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var d float64
+			var inerr error
+			for i := range rows {
+				for j := i + 1; j < len(seqlist); j++ {
+					if m.guidedist == MSA_DIST_KMER {
+						d = kmerDistance(kmers[i], kmers[j], seqlist[i].Length(), seqlist[j].Length(), k)
+					} else if d, inerr = m.pairwiseDistance(seqlist[i], seqlist[j]); inerr != nil {
+						errmut.Lock()
+						err = inerr
+						errmut.Unlock()
+					}
+					dists[i][j] = d
+					dists[j][i] = d
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return
+}
+
+// Distance between two sequences, computed as 1 - identity
+// of their global alignment (gaps not taken into account)
+func (m *msaligner) pairwiseDistance(s1, s2 Sequence) (d float64, err error) {
+	var aligner *pwaligner
+
+	aligner = NewPwAligner(s1, s2, ALIGN_ALGO_NW)
+	aligner.SetGapOpenScore(m.gapopen)
+	aligner.SetGapExtendScore(m.gapextend)
+	if m.changedscores {
+		aligner.SetScore(m.match, m.mismatch)
+	}
+	if _, err = aligner.Alignment(); err != nil {
+		return
+	}
+	d = 1.0
+	if aligner.NbMatches()+aligner.NbMisMatches() > 0 {
+		d = 1.0 - float64(aligner.NbMatches())/float64(aligner.NbMatches()+aligner.NbMisMatches())
+	}
+	return
+}
+
+// Counts all the k-mers of the given sequence
+func countKmers(seq []uint8, k int) (kmers map[string]int) {
+	var up string = strings.ToUpper(string(seq))
+
+	kmers = make(map[string]int)
+	for i := 0; i+k <= len(up); i++ {
+		kmers[up[i:i+k]]++
+	}
+	return
+}
+
+// Fractional common k-mer distance (Edgar, 2004):
+// 1 - (number of shared k-mers)/(number of k-mers in the shortest sequence)
+func kmerDistance(kmers1, kmers2 map[string]int, l1, l2, k int) float64 {
+	var shared int
+	var nb int = l1
+	if l2 < nb {
+		nb = l2
+	}
+	nb = nb - k + 1
+	if nb <= 0 {
+		return 1.0
+	}
+	for kmer, c1 := range kmers1 {
+		if c2, ok := kmers2[kmer]; ok {
+			if c2 < c1 {
+				c1 = c2
+			}
+			shared += c1
+		}
+	}
+	return 1.0 - float64(shared)/float64(nb)
+}
+
+// Substitution matrix with match scores on the diagonal
+// and mismatch scores elsewhere
+func identitySubstMatrix(size int, match, mismatch float64) (mat [][]float64) {
+	mat = make([][]float64, size)
+	for i := range mat {
+		mat[i] = make([]float64, size)
+		for j := range mat[i] {
+			mat[i][j] = mismatch
+			if i == j {
+				mat[i][j] = match
+			}
+		}
+	}
+	return
+}
+
+// Frequencies of each character (indices in the substitution matrix)
+// at each column of the profile. Gaps are not counted, so frequencies
+// of a column containing gaps do not sum to 1.
+func (m *msaligner) profileFrequencies(p *msaProfile) (freqs [][]float64) {
+	var length int
+	var nbseqs float64 = float64(len(p.rows))
+
+	if len(p.rows) > 0 {
+		length = len(p.rows[0])
+	}
+	freqs = make([][]float64, length)
+	for i := range freqs {
+		freqs[i] = make([]float64, len(m.submatrix))
+		for _, r := range p.rows {
+			if r[i] != GAP {
+				freqs[i][m.chartopos[uint8(unicode.ToUpper(rune(r[i])))]] += 1.0 / nbseqs
+			}
+		}
+	}
+	return
+}
+
+// Global profile-profile alignment of p1 and p2
+func (m *msaligner) alignProfiles(p1, p2 *msaProfile) (p *msaProfile) {
+	var i, j int
+	var ops []int
+
+	ops = m.alignFrequencies(m.profileFrequencies(p1), m.profileFrequencies(p2))
+
+	// Builds the new profile
+	p = &msaProfile{
+		ids:  append(append([]int{}, p1.ids...), p2.ids...),
+		rows: make([][]uint8, len(p1.rows)+len(p2.rows)),
+	}
+	for r := range p.rows {
+		p.rows[r] = make([]uint8, 0, len(ops))
+	}
+	for _, op := range ops {
+		for r, row := range p1.rows {
+			if op == ALIGN_LEFT {
+				p.rows[r] = append(p.rows[r], GAP)
+			} else {
+				p.rows[r] = append(p.rows[r], row[i])
+			}
+		}
+		for r, row := range p2.rows {
+			if op == ALIGN_UP {
+				p.rows[len(p1.rows)+r] = append(p.rows[len(p1.rows)+r], GAP)
+			} else {
+				p.rows[len(p1.rows)+r] = append(p.rows[len(p1.rows)+r], row[j])
+			}
+		}
+		if op != ALIGN_LEFT {
+			i++
+		}
+		if op != ALIGN_UP {
+			j++
+		}
+	}
+	return
+}
+
+// Global alignment of two profiles given as character frequencies at each column
+// (affine gaps, Gotoh), same scheme as pwaligner with ALIGN_ALGO_NW: a gap of
+// length k costs gapopen+(k-1)*gapextend.
+//
+// Returns the alignment path, from the first to the last column:
+// - ALIGN_DIAG: a column of f1 aligned with a column of f2;
+// - ALIGN_UP: a column of f1 aligned with a gap;
+// - ALIGN_LEFT: a gap aligned with a column of f2.
+func (m *msaligner) alignFrequencies(f1, f2 [][]float64) (ops []int) {
+	var l1, l2, i, j, state, cur int
+	var sub1 [][]float64
+	var nz2 [][]int
+	var best, sc float64
+	var from uint8
+	var prevm, prevup, prevleft, curm, curup, curleft []float64
+	var tracem, traceup, traceleft []uint8
+	var inf float64 = math.Inf(-1)
+
+	l1 = len(f1)
+	l2 = len(f2)
+
+	// Precomputes, for each column of p1, its average substitution
+	// score against each character, and the non zero frequency characters
+	// of each column of p2
+	sub1 = make([][]float64, l1)
+	for i = range f1 {
+		sub1[i] = make([]float64, len(m.submatrix))
+		for x, fx := range f1[i] {
+			if fx > 0 {
+				for y := range m.submatrix[x] {
+					sub1[i][y] += fx * m.submatrix[x][y]
+				}
+			}
+		}
+	}
+	nz2 = make([][]int, l2)
+	for j = range f2 {
+		for y, fy := range f2[j] {
+			if fy > 0 {
+				nz2[j] = append(nz2[j], y)
+			}
+		}
+	}
+
+	tracem = make([]uint8, (l1+1)*(l2+1))
+	traceup = make([]uint8, (l1+1)*(l2+1))
+	traceleft = make([]uint8, (l1+1)*(l2+1))
+	prevm, prevup, prevleft = make([]float64, l2+1), make([]float64, l2+1), make([]float64, l2+1)
+	curm, curup, curleft = make([]float64, l2+1), make([]float64, l2+1), make([]float64, l2+1)
+
+	// First row
+	prevm[0], prevup[0], prevleft[0] = .0, inf, inf
+	for j = 1; j <= l2; j++ {
+		prevm[j], prevup[j] = inf, inf
+		prevleft[j] = m.gapopen + float64(j-1)*m.gapextend
+		traceleft[j] = ALIGN_LEFT
+		if j == 1 {
+			traceleft[j] = ALIGN_DIAG
+		}
+	}
+
+	for i = 1; i <= l1; i++ {
+		cur = i * (l2 + 1)
+		// First column
+		curm[0], curleft[0] = inf, inf
+		curup[0] = m.gapopen + float64(i-1)*m.gapextend
+		traceup[cur] = ALIGN_UP
+		if i == 1 {
+			traceup[cur] = ALIGN_DIAG
+		}
+		for j = 1; j <= l2; j++ {
+			sc = .0
+			for _, y := range nz2[j-1] {
+				sc += sub1[i-1][y] * f2[j-1][y]
+			}
+
+			best, from = prevm[j-1], ALIGN_DIAG
+			if prevup[j-1] > best {
+				best, from = prevup[j-1], ALIGN_UP
+			}
+			if prevleft[j-1] > best {
+				best, from = prevleft[j-1], ALIGN_LEFT
+			}
+			curm[j] = best + sc
+			tracem[cur+j] = from
+
+			best, from = prevm[j]+m.gapopen, ALIGN_DIAG
+			if prevup[j]+m.gapextend > best {
+				best, from = prevup[j]+m.gapextend, ALIGN_UP
+			}
+			if prevleft[j]+m.gapopen > best {
+				best, from = prevleft[j]+m.gapopen, ALIGN_LEFT
+			}
+			curup[j] = best
+			traceup[cur+j] = from
+
+			best, from = curm[j-1]+m.gapopen, ALIGN_DIAG
+			if curleft[j-1]+m.gapextend > best {
+				best, from = curleft[j-1]+m.gapextend, ALIGN_LEFT
+			}
+			if curup[j-1]+m.gapopen > best {
+				best, from = curup[j-1]+m.gapopen, ALIGN_UP
+			}
+			curleft[j] = best
+			traceleft[cur+j] = from
+		}
+		prevm, curm = curm, prevm
+		prevup, curup = curup, prevup
+		prevleft, curleft = curleft, prevleft
+	}
+
+	// Backtrack from the last cell
+	state = ALIGN_DIAG
+	if prevup[l2] > prevm[l2] {
+		state = ALIGN_UP
+	}
+	if prevleft[l2] > prevm[l2] && prevleft[l2] > prevup[l2] {
+		state = ALIGN_LEFT
+	}
+	i, j = l1, l2
+	ops = make([]int, 0, l1+l2)
+	for i > 0 || j > 0 {
+		ops = append(ops, state)
+		switch state {
+		case ALIGN_DIAG:
+			state = int(tracem[i*(l2+1)+j])
+			i--
+			j--
+		case ALIGN_UP:
+			state = int(traceup[i*(l2+1)+j])
+			i--
+		case ALIGN_LEFT:
+			state = int(traceleft[i*(l2+1)+j])
+			j--
+		}
+	}
+	for i, j = 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return
+}
@@ -0,0 +1,150 @@
+package align
+
+import (
+	"testing"
+)
+
+func Test_msaligner_Align(t *testing.T) {
+	tests := []struct {
+		name  string
+		guide int
+		seqs  [][]string
+		want  [][]string
+	}{
+		{name: "kmer",
+			guide: MSA_DIST_KMER,
+			seqs: [][]string{
+				{"s1", "ACGTACGTTTGACCATTGACAGT"},
+				{"s2", "ACGTCGTTTGACCATTGACAGT"},
+				{"s3", "ACGTACGTTTGACCATGACAGT"},
+				{"s4", "GGACGTACGTTTGACCATTGACAGTAA"},
+				{"s5", "ACGTACGTTTAACCATTGACAGT"},
+			},
+			want: [][]string{
+				{"s1", "--ACGTACGTTTGACCATTGACAGT--"},
+				{"s2", "--ACGT-CGTTTGACCATTGACAGT--"},
+				{"s3", "--ACGTACGTTTGACCA-TGACAGT--"},
+				{"s4", "GGACGTACGTTTGACCATTGACAGTAA"},
+				{"s5", "--ACGTACGTTTAACCATTGACAGT--"},
+			},
+		},
+		{name: "pairwise",
+			guide: MSA_DIST_PAIRWISE,
+			seqs: [][]string{
+				{"p1", "MKVLAAGIVGLLLA"},
+				{"p2", "MKVLAGIVGLLLA"},
+				{"p3", "MKVLAAGIVGLA"},
+			},
+			want: [][]string{
+				{"p1", "MKVLAAGIVGLLLA"},
+				{"p2", "MKVL-AGIVGLLLA"},
+				{"p3", "MKVLAAGIVG--LA"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSeqBag(UNKNOWN)
+			for _, s := range tt.seqs {
+				sb.AddSequence(s[0], s[1], "")
+			}
+			sb.AutoAlphabet()
+			exp := NewAlign(UNKNOWN)
+			for _, s := range tt.want {
+				exp.AddSequence(s[0], s[1], "")
+			}
+			exp.AutoAlphabet()
+
+			m := NewMultipleAligner()
+			if err := m.SetGuideDistance(tt.guide); err != nil {
+				t.Fatal(err)
+			}
+			al, err := m.Align(sb)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !al.Identical(exp) {
+				t.Errorf("Align() = %v, want %v", al.String(), exp.String())
+			}
+		})
+	}
+}
+
+func Test_align_AddUnaligned(t *testing.T) {
+	tests := []struct {
+		name       string
+		keeplength bool
+		want       [][]string
+	}{
+		{name: "insertions",
+			keeplength: false,
+			want: [][]string{
+				{"r1", "ACGTACGTTT-----GACC-ATTGACAGT"},
+				{"r2", "ACGT-CGTTT-----GACC-ATTGACAGT"},
+				{"r3", "ACGTACGTTT-----GACCAATTGACAGT"},
+				{"n1", "ACGTACGTTTGGGGGGACC-ATTGACAGT"},
+				{"n2", "-----CGTTT-----GACC-ATTGA----"},
+			},
+		},
+		{name: "keeplength",
+			keeplength: true,
+			want: [][]string{
+				{"r1", "ACGTACGTTTGACC-ATTGACAGT"},
+				{"r2", "ACGT-CGTTTGACC-ATTGACAGT"},
+				{"r3", "ACGTACGTTTGACCAATTGACAGT"},
+				{"n1", "ACGTACGTTTGACC-ATTGACAGT"},
+				{"n2", "-----CGTTTGACC-ATTGA----"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			al := NewAlign(UNKNOWN)
+			al.AddSequence("r1", "ACGTACGTTTGACC-ATTGACAGT", "")
+			al.AddSequence("r2", "ACGT-CGTTTGACC-ATTGACAGT", "")
+			al.AddSequence("r3", "ACGTACGTTTGACCAATTGACAGT", "")
+			al.AutoAlphabet()
+			sb := NewSeqBag(UNKNOWN)
+			sb.AddSequence("n1", "ACGTACGTTTGGGGGGACCATTGACAGT", "")
+			sb.AddSequence("n2", "CGTTTGACCATTGA", "")
+			sb.AutoAlphabet()
+
+			exp := NewAlign(UNKNOWN)
+			for _, s := range tt.want {
+				exp.AddSequence(s[0], s[1], "")
+			}
+			exp.AutoAlphabet()
+
+			if err := al.AddUnaligned(sb, tt.keeplength); err != nil {
+				t.Fatal(err)
+			}
+			if !al.Identical(exp) {
+				t.Errorf("AddUnaligned() = %v, want %v", al.String(), exp.String())
+			}
+			if al.Length() != exp.Length() {
+				t.Errorf("AddUnaligned() length = %d, want %d", al.Length(), exp.Length())
+			}
+		})
+	}
+}
+
+func Test_align_AddUnaligned_Error(t *testing.T) {
+	al := NewAlign(UNKNOWN)
+	al.AddSequence("r1", "ACGTACGTTTGACC-ATTGACAGT", "")
+	al.AddSequence("r2", "ACGT-CGTTTGACC-ATTGACAGT", "")
+	al.AutoAlphabet()
+	exp, err := al.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb := NewSeqBag(UNKNOWN)
+	sb.AddSequence("n1", "ACGTACGTTTGACCATTGACAGT", "")
+	sb.AddSequence("n2", "ACGTAC!TTTGACCATTGACAGT", "")
+
+	if err = al.AddUnaligned(sb, false); err == nil {
+		t.Fatal("AddUnaligned() should fail with a sequence having unknown characters")
+	}
+	if !al.Identical(exp) || al.Length() != exp.Length() {
+		t.Errorf("AddUnaligned() should leave the alignment unchanged on error: %v, want %v", al.String(), exp.String())
+	}
+}
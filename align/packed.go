@@ -0,0 +1,466 @@
+package align
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Number of sequences stored in each block of a PackedAlign
+const packedBlockSize = 256
+
+// packedCodes gives the 4 bits code of each character that may be stored
+// in a PackedAlign: NT_* IUPAC bitmasks for nucleotides, and NT_OTHER for gaps.
+// Other characters are not encodable (-1).
+var packedCodes [256]int8
+
+// packedChars gives the character corresponding to each 4 bits code
+var packedChars [16]uint8
+
+// Order in which characters are examined in MaxCharStats (ties are resolved by this order)
+var packedCharOrder []uint8
+
+// packedRanks transforms a byte of two packed characters such that comparing
+// transformed bytes gives the lexicographic order of the characters
+// (used to sort patterns in Compress)
+var packedRanks [256]uint8
+
+func init() {
+	for i := range packedCodes {
+		packedCodes[i] = -1
+	}
+	for _, c := range []uint8("ACGTRYSWKMBDHVN") {
+		packedCodes[c] = int8(iupacToInt[c])
+		packedChars[iupacToInt[c]] = c
+	}
+	packedCodes[GAP] = NT_OTHER
+	packedChars[NT_OTHER] = GAP
+
+	packedCharOrder = make([]uint8, 16)
+	copy(packedCharOrder, packedChars[:])
+	sort.Slice(packedCharOrder, func(i, j int) bool { return packedCharOrder[i] < packedCharOrder[j] })
+
+	var ranks [16]uint8
+	for r, c := range packedCharOrder {
+		ranks[packedCodes[c]] = uint8(r)
+	}
+	for v := range packedRanks {
+		// First sequence is in the low half of the byte
+		packedRanks[v] = ranks[v&0x0F]<<4 | ranks[v>>4]
+	}
+}
+
+// SiteAlignment is the subset of Alignment methods implemented by PackedAlign.
+// It allows site-wise operations to be run indifferently on a row-major
+// Alignment or on a PackedAlign.
+type SiteAlignment interface {
+	NbSequences() int
+	Length() int
+	Alphabet() int
+	AlphabetCharacters() []uint8
+	GetSequenceNameById(ith int) (string, bool)
+	IterateChar(it func(name string, sequence []uint8) bool)
+	CharStats() map[uint8]int64
+	Entropy(site int, removegaps bool) (float64, error)
+	MaxCharStats(ignoreGaps, ignoreNs bool) (out []uint8, occur []int, total []int)
+	RemoveGapSites(cutoff float64, ends bool) (first, last int, kept []int)
+	Compress() (weights []int)
+	Pssm(log bool, pseudocount float64, normalization int) (pssm map[uint8][]float64, err error)
+}
+
+var _ SiteAlignment = Alignment(nil)
+var _ SiteAlignment = (*PackedAlign)(nil)
+
+// PackedAlign is a memory compact, column-major, nucleotide alignment.
+//
+// Each character is stored as its 4 bits IUPAC code (NT_A, NT_C, ..., NT_N,
+// and NT_OTHER for gaps), i.e. two sequences per byte. Sequences are stored
+// by blocks of at most packedBlockSize sequences, and inside each block, characters of
+// the same site are contiguous. It takes half the memory of the row-major
+// Alignment, and site-wise operations (Compress, Entropy, RemoveGapSites,
+// MaxCharStats, Pssm) only scan contiguous memory.
+//
+// The last block is sized by its number of sequences, and grows when
+// sequences are added, so that alignments with few long sequences
+// do not take more memory than needed.
+//
+// Only upper case IUPAC nucleotides and gaps can be stored: AddSequence
+// returns an error otherwise, and the caller may fall back to a row-major
+// Alignment (see Unpack).
+type PackedAlign struct {
+	names  []string
+	length int
+	blocks []*packedBlock
+}
+
+// packedBlock stores the characters of up to packedBlockSize sequences:
+// stride bytes per site, i.e. 2*stride sequences
+type packedBlock struct {
+	stride int
+	data   []uint8 // length * stride bytes
+}
+
+// grow doubles the number of sequences the block may store
+// (at most packedBlockSize), keeping its content
+func (b *packedBlock) grow(length int) {
+	stride := b.stride * 2
+	if stride > packedBlockSize/2 {
+		stride = packedBlockSize / 2
+	}
+	data := make([]uint8, length*stride)
+	for site := 0; site < length; site++ {
+		copy(data[site*stride:], b.data[site*b.stride:(site+1)*b.stride])
+	}
+	b.stride = stride
+	b.data = data
+}
+
+// NewPackedAlign initializes a new empty PackedAlign
+func NewPackedAlign() *PackedAlign {
+	return &PackedAlign{
+		names:  make([]string, 0, 100),
+		length: 0,
+		blocks: make([]*packedBlock, 0, 10),
+	}
+}
+
+// PackAlign stores the given alignment in a new PackedAlign.
+// Returns an error if the alignment contains characters that can not be packed.
+func PackAlign(al Alignment) (p *PackedAlign, err error) {
+	p = NewPackedAlign()
+	al.IterateChar(func(name string, sequence []uint8) bool {
+		err = p.AddSequence(name, sequence)
+		return err != nil
+	})
+	return
+}
+
+// AddSequence appends a sequence to the PackedAlign.
+//
+// Returns an error if the sequence does not have the same length as the
+// previous ones, or if it contains characters that can not be packed.
+// In that case, the PackedAlign is not modified.
+func (p *PackedAlign) AddSequence(name string, sequence []uint8) (err error) {
+	var block *packedBlock
+
+	if len(p.names) > 0 && p.length != len(sequence) {
+		err = fmt.Errorf("sequence %s does not have same length as other sequences", name)
+		return
+	}
+	for _, c := range sequence {
+		if packedCodes[c] < 0 {
+			err = fmt.Errorf("character '%c' of sequence %s can not be packed", c, name)
+			return
+		}
+	}
+
+	p.length = len(sequence)
+	b, r := len(p.names)/packedBlockSize, len(p.names)%packedBlockSize
+	if b == len(p.blocks) {
+		p.blocks = append(p.blocks, &packedBlock{stride: 1, data: make([]uint8, p.length)})
+	}
+	block = p.blocks[b]
+	if r/2 >= block.stride {
+		block.grow(p.length)
+	}
+	for site, c := range sequence {
+		idx := site*block.stride + r/2
+		if r%2 == 0 {
+			block.data[idx] = (block.data[idx] & 0xF0) | uint8(packedCodes[c])
+		} else {
+			block.data[idx] = (block.data[idx] & 0x0F) | uint8(packedCodes[c])<<4
+		}
+	}
+	p.names = append(p.names, name)
+	return
+}
+
+// NbSequences returns the number of sequences of the alignment
+func (p *PackedAlign) NbSequences() int {
+	return len(p.names)
+}
+
+// Length returns the length of the alignment
+func (p *PackedAlign) Length() int {
+	return p.length
+}
+
+// Alphabet of a PackedAlign is always NUCLEOTIDS
+func (p *PackedAlign) Alphabet() int {
+	return NUCLEOTIDS
+}
+
+// AlphabetCharacters returns the standard nucleotides
+func (p *PackedAlign) AlphabetCharacters() []uint8 {
+	return stdnucleotides
+}
+
+// GetSequenceNameById returns the name of the ith sequence
+func (p *PackedAlign) GetSequenceNameById(ith int) (string, bool) {
+	if ith < 0 || ith >= len(p.names) {
+		return "", false
+	}
+	return p.names[ith], true
+}
+
+// CharAt returns the character of the given sequence at the given site
+func (p *PackedAlign) CharAt(seq, site int) uint8 {
+	return packedChars[p.code(seq, site)]
+}
+
+func (p *PackedAlign) code(seq, site int) uint8 {
+	r := seq % packedBlockSize
+	block := p.blocks[seq/packedBlockSize]
+	v := block.data[site*block.stride+r/2]
+	if r%2 == 0 {
+		return v & 0x0F
+	}
+	return v >> 4
+}
+
+// column returns, for each block, the bytes of the given site
+// that contain sequences of the alignment
+func (p *PackedAlign) column(site int, it func(bytes []uint8)) {
+	for b, block := range p.blocks {
+		n := len(p.names) - b*packedBlockSize
+		if n > packedBlockSize {
+			n = packedBlockSize
+		}
+		start := site * block.stride
+		it(block.data[start : start+(n+1)/2])
+	}
+}
+
+// siteCounts returns the number of occurences of each code at the given site
+func (p *PackedAlign) siteCounts(site int) (counts [16]int) {
+	p.column(site, func(bytes []uint8) {
+		for _, v := range bytes {
+			counts[v&0x0F]++
+			counts[v>>4]++
+		}
+	})
+	// Unused high half of the last byte is 0 (NT_OTHER)
+	if len(p.names)%2 == 1 {
+		counts[NT_OTHER]--
+	}
+	return
+}
+
+// IterateChar iterates over the sequences of the alignment, in the
+// order they have been added. Sequences are decoded in a new slice
+// at each iteration.
+// If the function returns true, the iteration stops.
+func (p *PackedAlign) IterateChar(it func(name string, sequence []uint8) bool) {
+	for i, name := range p.names {
+		seq := make([]uint8, p.length)
+		for site := range seq {
+			seq[site] = packedChars[p.code(i, site)]
+		}
+		if it(name, seq) {
+			return
+		}
+	}
+}
+
+// Unpack returns a row-major Alignment containing the sequences of
+// the PackedAlign.
+func (p *PackedAlign) Unpack() (al Alignment, err error) {
+	al = NewAlign(NUCLEOTIDS)
+	p.IterateChar(func(name string, sequence []uint8) bool {
+		err = al.AddSequenceChar(name, sequence, "")
+		return err != nil
+	})
+	return
+}
+
+// CharStats returns the number of occurences of each character in the alignment
+func (p *PackedAlign) CharStats() (chars map[uint8]int64) {
+	var counts [16]int64
+	chars = make(map[uint8]int64)
+	for site := 0; site < p.length; site++ {
+		for c, n := range p.siteCounts(site) {
+			counts[c] += int64(n)
+		}
+	}
+	for c, n := range counts {
+		if n > 0 {
+			chars[packedChars[c]] = n
+		}
+	}
+	return
+}
+
+// Entropy of the given site. If the site number is < 0 or >= length -> returns an error
+// if removegaps is true, do not take into account gap characters
+func (p *PackedAlign) Entropy(site int, removegaps bool) (float64, error) {
+	if site < 0 || site >= p.length {
+		return 1.0, errors.New("site position is outside alignment")
+	}
+	counts := p.siteCounts(site)
+	if removegaps {
+		counts[NT_OTHER] = 0
+	}
+	total := 0
+	for _, v := range counts {
+		total += v
+	}
+	if total == 0 {
+		return math.NaN(), nil
+	}
+	entropy := 0.0
+	for _, v := range counts {
+		if v > 0 {
+			proba := float64(v) / float64(total)
+			entropy -= proba * math.Log(proba)
+		}
+	}
+	return entropy, nil
+}
+
+// MaxCharStats returns the most frequent character at each site, like Alignment.MaxCharStats.
+// Contrary to Alignment.MaxCharStats, ties are resolved deterministically, by
+// taking the first character in lexicographic order.
+func (p *PackedAlign) MaxCharStats(ignoreGaps, ignoreNs bool) (out []uint8, occur []int, total []int) {
+	out = make([]uint8, p.length)
+	occur = make([]int, p.length)
+	total = make([]int, p.length)
+
+	for site := 0; site < p.length; site++ {
+		counts := p.siteCounts(site)
+		max := 0
+		out[site] = p.CharAt(0, site)
+		occur[site] = len(p.names)
+		for _, c := range packedCharOrder {
+			v := counts[packedCodes[c]]
+			if v == 0 || (ignoreGaps && c == GAP) || (ignoreNs && c == ALL_NUCLE) {
+				continue
+			}
+			total[site] += v
+			if v > max {
+				out[site] = c
+				occur[site] = v
+				max = v
+			}
+		}
+	}
+	return
+}
+
+// RemoveGapSites removes positions constituted of [cutoff*100%,100%] Gaps,
+// exactly like Alignment.RemoveGapSites.
+//
+// Returns the number of consecutive removed sites at start and end of alignment and the indexes of
+// the remaining positions
+func (p *PackedAlign) RemoveGapSites(cutoff float64, ends bool) (first, last int, kept []int) {
+	kept = make([]int, 0)
+	if len(p.names) == 0 {
+		return
+	}
+
+	if cutoff < 0 || cutoff > 1 {
+		cutoff = 0
+	}
+
+	length := p.length
+	toremove := make([]bool, length)
+	firstcontinuous := -1
+	lastcontinuous := length
+	for site := 0; site < length; site++ {
+		nbgaps := p.siteCounts(site)[NT_OTHER]
+		if (cutoff > 0.0 && float64(nbgaps) >= cutoff*float64(len(p.names))) || (cutoff == 0 && nbgaps > 0) {
+			toremove[site] = true
+			if site == firstcontinuous+1 {
+				firstcontinuous++
+			}
+			if lastcontinuous == length {
+				lastcontinuous = site
+			}
+		} else {
+			lastcontinuous = length
+		}
+	}
+
+	for site := 0; site < length; site++ {
+		if !toremove[site] || (ends && site < lastcontinuous && site > firstcontinuous) {
+			kept = append(kept, site)
+		}
+	}
+	p.keepSites(kept)
+
+	return firstcontinuous + 1, length - lastcontinuous, kept
+}
+
+// keepSites keeps only the given sites (in the given order) in the alignment
+func (p *PackedAlign) keepSites(sites []int) {
+	for _, block := range p.blocks {
+		stride := block.stride
+		data := make([]uint8, len(sites)*stride)
+		for i, site := range sites {
+			copy(data[i*stride:(i+1)*stride], block.data[site*stride:(site+1)*stride])
+		}
+		block.data = data
+	}
+	p.length = len(sites)
+}
+
+// Compress removes identical patterns/sites and returns the number of occurences
+// of each pattern. Patterns are sorted in the same order as Alignment.Compress.
+func (p *PackedAlign) Compress() (weights []int) {
+	var key []uint8
+	// Number of occurences and first site of each pattern
+	patterns := make(map[string]*struct{ count, site int })
+
+	for site := 0; site < p.length; site++ {
+		// Keys are sorted like the character patterns (see packedRanks)
+		key = key[:0]
+		p.column(site, func(bytes []uint8) {
+			for _, v := range bytes {
+				key = append(key, packedRanks[v])
+			}
+		})
+		pat, ok := patterns[string(key)]
+		if !ok {
+			pat = &struct{ count, site int }{0, site}
+			patterns[string(key)] = pat
+		}
+		pat.count++
+	}
+
+	keys := make([]string, 0, len(patterns))
+	for k := range patterns {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	weights = make([]int, len(keys))
+	sites := make([]int, len(keys))
+	for i, k := range keys {
+		weights[i] = patterns[k].count
+		sites[i] = patterns[k].site
+	}
+	p.keepSites(sites)
+	return
+}
+
+// Pssm returns the Position-Specific Scoring Matrix of the alignment,
+// exactly like Alignment.Pssm.
+func (p *PackedAlign) Pssm(log bool, pseudocount float64, normalization int) (pssm map[uint8][]float64, err error) {
+	var normfactors map[uint8]float64
+
+	pssm = make(map[uint8][]float64)
+	for _, c := range stdnucleotides {
+		pssm[c] = make([]float64, p.length)
+	}
+	if normfactors, err = pssmNormFactors(stdnucleotides, len(p.names), pseudocount, normalization, p.CharStats); err != nil {
+		return
+	}
+	for site := 0; site < p.length; site++ {
+		counts := p.siteCounts(site)
+		for _, c := range stdnucleotides {
+			pssm[c][site] = float64(counts[packedCodes[c]])
+		}
+	}
+	normalizePssm(pssm, normfactors, len(stdnucleotides), p.length, log, pseudocount, normalization)
+	return
+}
@@ -0,0 +1,196 @@
+package align
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// randomPackableAlign generates a random nucleotide alignment with
+// gaps and a few IUPAC characters
+func randomPackableAlign(nbseqs, length int) Alignment {
+	chars := []uint8("ACGTACGTACGT--RN")
+	al := NewAlign(NUCLEOTIDS)
+	for i := 0; i < nbseqs; i++ {
+		seq := make([]uint8, length)
+		for j := range seq {
+			// A few conserved columns to have identical patterns
+			if j%5 == 0 {
+				seq[j] = chars[j%len(chars)]
+			} else {
+				seq[j] = chars[rand.Intn(len(chars))]
+			}
+		}
+		al.AddSequenceChar(fmt.Sprintf("Seq%04d", i), seq, "")
+	}
+	return al
+}
+
+func packTestAlign(t *testing.T, al Alignment) *PackedAlign {
+	p, err := PackAlign(al)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func checkUnpacked(t *testing.T, p *PackedAlign, exp Alignment) {
+	unpacked, err := p.Unpack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !unpacked.Identical(exp) {
+		t.Errorf("Packed alignment is different from expected \n %s \n vs. \n %s", unpacked.String(), exp.String())
+	}
+}
+
+func TestPackedAlign_Unpack(t *testing.T) {
+	rand.Seed(10)
+	for _, nbseqs := range []int{1, 3, 256, 301} {
+		al := randomPackableAlign(nbseqs, 50)
+		p := packTestAlign(t, al)
+		if p.NbSequences() != nbseqs || p.Length() != 50 {
+			t.Errorf("Packed alignment should have %d sequences and length 50, not %d and %d", nbseqs, p.NbSequences(), p.Length())
+		}
+		checkUnpacked(t, p, al)
+		if !reflect.DeepEqual(p.CharStats(), al.CharStats()) {
+			t.Errorf("Char stats are different: %v vs. %v", p.CharStats(), al.CharStats())
+		}
+	}
+}
+
+func TestPackedAlign_AddSequence(t *testing.T) {
+	p := NewPackedAlign()
+	if err := p.AddSequence("s1", []uint8("ACGT-N")); err != nil {
+		t.Error(err)
+	}
+	if err := p.AddSequence("s2", []uint8("ACGT")); err == nil {
+		t.Errorf("Adding a sequence with a different length should return an error")
+	}
+	if err := p.AddSequence("s2", []uint8("ACgT-N")); err == nil {
+		t.Errorf("Adding a sequence with a lower case character should return an error")
+	}
+	if err := p.AddSequence("s2", []uint8("ACXT-N")); err == nil {
+		t.Errorf("Adding a sequence with a non nucleotide character should return an error")
+	}
+	if err := p.AddSequence("s2", []uint8("TTTT-N")); err != nil {
+		t.Error(err)
+	}
+	if p.NbSequences() != 2 || p.CharAt(1, 0) != 'T' || p.CharAt(0, 5) != 'N' {
+		t.Errorf("Packed alignment was modified by failed AddSequence")
+	}
+}
+
+// Number of bytes used to store the characters of the alignment
+func packedDataSize(p *PackedAlign) (size int) {
+	for _, block := range p.blocks {
+		size += len(block.data)
+	}
+	return
+}
+
+func TestPackedAlign_Size(t *testing.T) {
+	rand.Seed(10)
+	// Few long sequences: the block is sized by its number of sequences
+	al := randomPackableAlign(2, 100000)
+	p := packTestAlign(t, al)
+	if size := packedDataSize(p); size != 100000 {
+		t.Errorf("2 packed sequences of length 100000 should take 100000 bytes, not %d", size)
+	}
+	checkUnpacked(t, p, al)
+
+	for _, c := range []struct{ nbseqs, size int }{{1, 50}, {3, 100}, {5, 200}, {256, 6400}, {257, 6450}, {301, 8000}} {
+		al = randomPackableAlign(c.nbseqs, 50)
+		p = packTestAlign(t, al)
+		if size := packedDataSize(p); size != c.size {
+			t.Errorf("%d packed sequences of length 50 should take %d bytes, not %d", c.nbseqs, c.size, size)
+		}
+		checkUnpacked(t, p, al)
+	}
+}
+
+func TestPackedAlign_Compress(t *testing.T) {
+	rand.Seed(10)
+	for _, nbseqs := range []int{1, 3, 301} {
+		al := randomPackableAlign(nbseqs, 200)
+		p := packTestAlign(t, al)
+
+		expw := al.Compress()
+		w := p.Compress()
+		if !reflect.DeepEqual(w, expw) {
+			t.Errorf("Weights are different: %v vs. %v", w, expw)
+		}
+		checkUnpacked(t, p, al)
+	}
+}
+
+func TestPackedAlign_RemoveGapSites(t *testing.T) {
+	rand.Seed(10)
+	for _, cutoff := range []float64{0, 0.1, 0.5, 1} {
+		for _, ends := range []bool{false, true} {
+			al := randomPackableAlign(301, 100)
+			// Gap only columns at start and end
+			al.IterateChar(func(name string, sequence []uint8) bool {
+				sequence[0], sequence[1], sequence[99] = GAP, GAP, GAP
+				return false
+			})
+			p := packTestAlign(t, al)
+
+			expfirst, explast, expkept := al.RemoveGapSites(cutoff, ends)
+			first, last, kept := p.RemoveGapSites(cutoff, ends)
+			if first != expfirst || last != explast || !reflect.DeepEqual(kept, expkept) {
+				t.Errorf("cutoff=%f, ends=%t: removed sites are different: %d,%d,%v vs. %d,%d,%v", cutoff, ends, first, last, kept, expfirst, explast, expkept)
+			}
+			checkUnpacked(t, p, al)
+		}
+	}
+}
+
+func TestPackedAlign_SiteStats(t *testing.T) {
+	rand.Seed(10)
+	al := randomPackableAlign(301, 100)
+	p := packTestAlign(t, al)
+
+	for site := 0; site < al.Length(); site++ {
+		for _, removegaps := range []bool{false, true} {
+			exp, _ := al.Entropy(site, removegaps)
+			e, _ := p.Entropy(site, removegaps)
+			if math.Abs(e-exp) > 1e-10 {
+				t.Errorf("Entropy of site %d is different: %f vs. %f", site, e, exp)
+			}
+		}
+	}
+	if _, err := p.Entropy(100, false); err == nil {
+		t.Errorf("Entropy outside the alignment should return an error")
+	}
+
+	for _, ignoreGaps := range []bool{false, true} {
+		for _, ignoreNs := range []bool{false, true} {
+			_, expoccur, exptotal := al.MaxCharStats(ignoreGaps, ignoreNs)
+			_, occur, total := p.MaxCharStats(ignoreGaps, ignoreNs)
+			if !reflect.DeepEqual(occur, expoccur) || !reflect.DeepEqual(total, exptotal) {
+				t.Errorf("Max char stats are different: %v,%v vs. %v,%v", occur, total, expoccur, exptotal)
+			}
+		}
+	}
+
+	for _, norm := range []int{PSSM_NORM_NONE, PSSM_NORM_FREQ, PSSM_NORM_DATA, PSSM_NORM_UNIF, PSSM_NORM_LOGO} {
+		exp, err := al.Pssm(true, 1.0, norm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pssm, err := p.Pssm(true, 1.0, norm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for c, v := range exp {
+			for site := range v {
+				if math.Abs(pssm[c][site]-v[site]) > 1e-10 {
+					t.Errorf("Pssm (norm %d) of %c at site %d is different: %f vs. %f", norm, c, site, pssm[c][site], v[site])
+				}
+			}
+		}
+	}
+}
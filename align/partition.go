@@ -26,17 +26,27 @@ func NewPartitionSet(alignmentLength int) (ps *PartitionSet) {
 	return
 }
 
+// AddRange adds the sites start to end (0-based, inclusive), taking one site every
+// modulo sites, to the partition partName. If the partition does not exist yet,
+// it is created with the model modelName.
+//
+// Returns an error if the range is outside the alignment, or if a site
+// is already in a partition. Sites are given 1-based in error messages.
 func (ps *PartitionSet) AddRange(partName, modelName string, start, end, modulo int) (err error) {
 	if start < 0 {
-		err = fmt.Errorf("start of partition is outside of alignment: %d", start)
+		err = fmt.Errorf("partition %s: start of range (%d) is outside of alignment", partName, start+1)
 		return
 	}
 	if end >= ps.length {
-		err = fmt.Errorf("end of partition is outside of alignment: %d", end)
+		err = fmt.Errorf("partition %s: end of range (%d) is outside of alignment (length %d)", partName, end+1, ps.length)
+		return
+	}
+	if start > end {
+		err = fmt.Errorf("partition %s: start of range (%d) is after its end (%d)", partName, start+1, end+1)
 		return
 	}
 	if modulo <= 0 {
-		err = fmt.Errorf("'modulo' value is not authorized: %d", modulo)
+		err = fmt.Errorf("partition %s: 'modulo' value is not authorized: %d", partName, modulo)
 		return
 	}
 
@@ -47,6 +57,14 @@ func (ps *PartitionSet) AddRange(partName, modelName string, start, end, modulo
 			break
 		}
 	}
+
+	for i := start; i <= end; i += modulo {
+		if ps.partitions[i] != -1 {
+			err = fmt.Errorf("partition %s: site %d is already in partition %s", partName, i+1, ps.names[ps.partitions[i]])
+			return
+		}
+	}
+
 	// New partition name
 	if partitionIndex == -1 {
 		ps.names = append(ps.names, partName)
@@ -55,10 +73,6 @@ func (ps *PartitionSet) AddRange(partName, modelName string, start, end, modulo
 	}
 
 	for i := start; i <= end; i += modulo {
-		if ps.partitions[i] != -1 {
-			err = fmt.Errorf("several partitions are defined for site %d ", i)
-			return
-		}
 		ps.partitions[i] = partitionIndex
 	}
 	return
@@ -165,3 +179,69 @@ func (ps *PartitionSet) ModeleName(code int) string {
 func (ps *PartitionSet) AliLength() int {
 	return ps.length
 }
+
+// DefaultPartitionModel returns the model name given to partitions
+// when no model is specified: "DNA" for nucleotides and "PROT" for amino acids
+func DefaultPartitionModel(alphabet int) string {
+	if alphabet == AMINOACIDS {
+		return "PROT"
+	}
+	return "DNA"
+}
+
+// Clone returns a deep copy of the partition set
+func (ps *PartitionSet) Clone() *PartitionSet {
+	if ps == nil {
+		return nil
+	}
+	c := &PartitionSet{
+		names:      make([]string, len(ps.names)),
+		partitions: make([]int, len(ps.partitions)),
+		models:     make([]string, len(ps.models)),
+		length:     ps.length,
+	}
+	copy(c.names, ps.names)
+	copy(c.partitions, ps.partitions)
+	copy(c.models, ps.models)
+	return c
+}
+
+// Partitions returns the partitions of the alignment sites (nil if none)
+func (a *align) Partitions() *PartitionSet {
+	return a.partitions
+}
+
+// SetPartitions sets the partitions of the alignment sites
+func (a *align) SetPartitions(ps *PartitionSet) {
+	a.partitions = ps
+}
+
+// Partition set of the concatenation of two alignments of length l1 and l2,
+// with partition sets ps1 and ps2.
+//
+// If the first alignment is empty (l1<=0), returns a copy of ps2.
+// Otherwise, if one of the partition sets is nil, returns nil.
+// Partitions having the same name in both sets are merged.
+func concatPartitions(ps1 *PartitionSet, l1 int, ps2 *PartitionSet, l2 int) (ps *PartitionSet) {
+	if l1 <= 0 {
+		return ps2.Clone()
+	}
+	if ps1 == nil || ps2 == nil {
+		return nil
+	}
+	ps = NewPartitionSet(l1 + l2)
+	for _, part := range []struct {
+		ps     *PartitionSet
+		offset int
+	}{{ps1, 0}, {ps2, l1}} {
+		for i, p := range part.ps.partitions {
+			if p == -1 {
+				continue
+			}
+			if err := ps.AddRange(part.ps.names[p], part.ps.models[p], i+part.offset, i+part.offset, 1); err != nil {
+				return nil
+			}
+		}
+	}
+	return
+}
@@ -2,6 +2,7 @@ package align
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 )
 
@@ -50,9 +51,11 @@ type Phaser interface {
 	SetCutEnd(cutend bool)
 	SetCpus(cpus int)
 	SetTranslate(translate bool, geneticcode int) (err error)
+	SetAltStarts(altstarts bool)
 	SetAlignScores(match, mismatch float64)
 	SetGapOpen(float64)
 	SetGapExtend(float64)
+	SetAlignAlgo(algo int) error
 }
 
 type phaser struct {
@@ -71,6 +74,9 @@ type phaser struct {
 	// as is
 	translate   bool
 	geneticcode int
+	// Alternative start codons of the genetic code
+	// are considered as starts (otherwise only ATG)
+	altstarts bool
 	//
 	// For Pairwise alignment
 	changedscores bool
@@ -78,6 +84,9 @@ type phaser struct {
 	mismatchscore float64
 	gapopen       float64
 	gapextend     float64
+	// Pairwise alignment algorithm
+	// (ALIGN_ALGO_ATG, ALIGN_ALGO_NW or ALIGN_ALGO_SEMIGLOBAL)
+	algo int
 }
 
 type PhasedSequence struct {
@@ -107,11 +116,13 @@ func NewPhaser() Phaser {
 		cpus:          1,
 		translate:     true,
 		geneticcode:   GENETIC_CODE_STANDARD,
+		altstarts:     false,
 		changedscores: false,
 		matchscore:    -1,
 		mismatchscore: -1,
 		gapopen:       -10,
 		gapextend:     -0.5,
+		algo:          ALIGN_ALGO_ATG,
 	}
 }
 
@@ -138,6 +149,10 @@ func (p *phaser) SetTranslate(translate bool, geneticcode int) (err error) {
 	p.geneticcode = geneticcode
 	return
 }
+func (p *phaser) SetAltStarts(altstarts bool) {
+	p.altstarts = altstarts
+}
+
 func (p *phaser) SetAlignScores(match, mismatch float64) {
 	p.matchscore = match
 	p.mismatchscore = mismatch
@@ -152,6 +167,21 @@ func (p *phaser) SetGapExtend(gapextend float64) {
 	p.gapextend = gapextend
 }
 
+// Sets the pairwise alignment algorithm used to align sequences
+// against reference orfs:
+// - ALIGN_ALGO_ATG (default): local alignment starting at the start of the orf
+// - ALIGN_ALGO_NW: global alignment
+// - ALIGN_ALGO_SEMIGLOBAL: global alignment without end gap penalties
+func (p *phaser) SetAlignAlgo(algo int) (err error) {
+	switch algo {
+	case ALIGN_ALGO_ATG, ALIGN_ALGO_NW, ALIGN_ALGO_SEMIGLOBAL:
+		p.algo = algo
+	default:
+		err = fmt.Errorf("unsupported alignment algorithm for phase: %d", algo)
+	}
+	return
+}
+
 // orfs: Reference sequences/ORFs to phase sequences with
 // seqs: Sequences to phase
 func (p *phaser) Phase(orfs, seqs SeqBag) (phased chan PhasedSequence, err error) {
@@ -170,7 +200,7 @@ func (p *phaser) Phase(orfs, seqs SeqBag) (phased chan PhasedSequence, err error
 
 	// If no orf given, then we find the longest among the sequences
 	if orfs == nil {
-		if orf, err = seqs.LongestORF(p.reverse); err != nil {
+		if orf, err = seqs.LongestORF(p.reverse, p.geneticcode, p.altstarts); err != nil {
 			return
 		}
 		orfs = NewSeqBag(UNKNOWN)
@@ -187,6 +217,24 @@ func (p *phaser) Phase(orfs, seqs SeqBag) (phased chan PhasedSequence, err error
 		if err = orfsaa.Translate(0, p.geneticcode); err != nil {
 			return
 		}
+		// Alternative start codons are translated as M
+		if p.altstarts {
+			var starts []string
+			if starts, err = startCodons(p.geneticcode, true); err != nil {
+				return
+			}
+			for i, o := range orfs.Sequences() {
+				if o.Length() < 3 {
+					continue
+				}
+				codon := strings.Replace(strings.ToUpper(string(o.SequenceChar()[0:3])), "U", "T", -1)
+				for _, start := range starts {
+					if codon == start {
+						orfsaa.SetSequenceChar(i, 0, 'M')
+					}
+				}
+			}
+		}
 	}
 
 	// Now we align all sequences against this longest orf aa sequence with Modified Smith/Waterman
@@ -279,7 +327,7 @@ func (p *phaser) alignAgainstRefsAA(seq Sequence, orfsaa []Sequence) (ph PhasedS
 				ph = PhasedSequence{Err: fmt.Errorf("error while translating %s : %v", seq.Name(), err)}
 				return
 			}
-			aligner = NewPwAligner(orfaa, seqaa, ALIGN_ALGO_ATG)
+			aligner = NewPwAligner(orfaa, seqaa, p.algo)
 			aligner.SetGapOpenScore(p.gapopen)
 			aligner.SetGapExtendScore(p.gapextend)
 			if p.changedscores {
@@ -293,7 +341,8 @@ func (p *phaser) alignAgainstRefsAA(seq Sequence, orfsaa []Sequence) (ph PhasedS
 			_, seqstart := aligner.AlignStarts()
 			_, seqend := aligner.AlignEnds()
 
-			if aligner.MaxScore() > bestscore {
+			// With global alignments, best score may be negative
+			if bestseq == nil || aligner.MaxScore() > bestscore {
 				bestscore = aligner.MaxScore()
 				// Alignment start in nucleotidic sequence
 				beststart = (phase % 3) + (seqstart * 3)
@@ -371,7 +420,7 @@ func (p *phaser) alignAgainstRefsNT(seq Sequence, orfs []Sequence) (ph PhasedSeq
 			} else {
 				tmpseq = revcomp
 			}
-			aligner = NewPwAligner(orf, tmpseq, ALIGN_ALGO_ATG)
+			aligner = NewPwAligner(orf, tmpseq, p.algo)
 			aligner.SetGapOpenScore(p.gapopen)
 			aligner.SetGapExtendScore(p.gapextend)
 			if p.changedscores {
@@ -384,7 +433,8 @@ func (p *phaser) alignAgainstRefsNT(seq Sequence, orfs []Sequence) (ph PhasedSeq
 			_, seqstart := aligner.AlignStarts()
 			_, seqend := aligner.AlignEnds()
 
-			if aligner.MaxScore() > bestscore {
+			// With global alignments, best score may be negative
+			if bestseq == nil || aligner.MaxScore() > bestscore {
 				bestscore = aligner.MaxScore()
 				// Alignment start in nucleotidic sequence
 				beststart = seqstart
@@ -21,21 +21,36 @@ func NewCountProfileFromAlignment(al Alignment) (p *CountProfile) {
 	p.counts = make([][]int, 0, 100)
 
 	al.IterateChar(func(name string, seq []uint8) bool {
-		for i, r := range seq {
-			idx := p.names[int(r)]
-			if idx < 0 {
-				idx = len(p.header)
-				p.names[int(r)] = idx
-				p.header = append(p.header, r)
-				p.counts = append(p.counts, make([]int, al.Length()))
-			}
-			p.counts[idx][i]++
-		}
+		p.AddSequence(seq)
 		return false
 	})
 	return
 }
 
+// AddSequence adds the characters of the given sequence to the counts
+// of the profile. It allows to build a profile without loading the whole
+// alignment in memory.
+//
+// If the length of the sequence is different from the number of sites
+// of the profile, returns an error.
+func (p *CountProfile) AddSequence(seq []uint8) (err error) {
+	if len(p.counts) > 0 && len(p.counts[0]) != len(seq) {
+		err = fmt.Errorf("sequence length (%d) is different from profile length (%d)", len(seq), len(p.counts[0]))
+		return
+	}
+	for i, r := range seq {
+		idx := p.names[int(r)]
+		if idx < 0 {
+			idx = len(p.header)
+			p.names[int(r)] = idx
+			p.header = append(p.header, r)
+			p.counts = append(p.counts, make([]int, len(seq)))
+		}
+		p.counts[idx][i]++
+	}
+	return
+}
+
 // NewCountProfile initializes a new Profile with nil attributes
 func NewCountProfile() (p *CountProfile) {
 	p = &CountProfile{
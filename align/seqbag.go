@@ -19,6 +19,8 @@ import (
 type SeqBag interface {
 	AddSequence(name string, sequence string, comment string) error
 	AddSequenceChar(name string, sequence []uint8, comment string) error
+	// Adds a sequence with the Phred quality score of each character (nil: no quality)
+	AddSequenceQuality(name string, sequence []uint8, quality []uint8, comment string) error
 	AppendSeqIdentifier(identifier string, right bool)
 	Alphabet() int
 	AlphabetStr() string
@@ -39,6 +41,7 @@ type SeqBag interface {
 	GetSequenceCharById(ith int) ([]uint8, bool)
 	GetSequenceNameById(ith int) (string, bool)
 	GetSequenceByName(name string) (Sequence, bool)
+	HasQualities() bool // true if all the sequences have quality scores (e.g. from fastq)
 	SetSequenceChar(ithAlign, ithSite int, char uint8) error
 	// IgnoreIdentical sets the behavior when duplicate names are encountered while building the alignment
 	// If ignore is IGNORE_NONE: Does not ignore anything
@@ -55,7 +58,7 @@ type SeqBag interface {
 	IterateAll(it func(name string, sequence []uint8, comment string) bool)
 	Sequences() []Sequence
 	SequencesChan() chan Sequence
-	LongestORF(reverse bool) (orf Sequence, err error)
+	LongestORF(reverse bool, geneticcode int, altstarts bool) (orf Sequence, err error)
 	MaxNameLength() int // maximum sequence name length
 	NbSequences() int
 	RarefySeqBag(nb int, counts map[string]int) (SeqBag, error) // Take a new rarefied sample taking into accounts weights
@@ -174,6 +177,46 @@ func (sb *seqbag) AddSequenceChar(name string, sequence []uint8, comment string)
 	return nil
 }
 
+// AddSequenceQuality adds a sequence, and the Phred quality score of each of its characters
+// (see AddSequenceChar). If quality is nil, then the sequence has no quality scores.
+// If the quality length is not the sequence length, then returns an error.
+func (sb *seqbag) AddSequenceQuality(name string, sequence []uint8, quality []uint8, comment string) (err error) {
+	var s *seq
+	if s, err = addSequenceQuality(sb, name, sequence, quality, comment); err != nil || s == nil {
+		return
+	}
+	s.quality = quality
+	return
+}
+
+// addSequenceQuality adds the sequence using sb.AddSequenceChar, and returns the new
+// sequence (nil if it has been ignored, see IgnoreIdentical)
+func addSequenceQuality(sb SeqBag, name string, sequence []uint8, quality []uint8, comment string) (s *seq, err error) {
+	if quality != nil && len(quality) != len(sequence) {
+		err = fmt.Errorf("quality length (%d) is different from sequence length (%d) for sequence %s", len(quality), len(sequence), name)
+		return
+	}
+	nb := sb.NbSequences()
+	if err = sb.AddSequenceChar(name, sequence, comment); err != nil || sb.NbSequences() == nb {
+		return
+	}
+	var last Sequence
+	last, _ = sb.Sequence(nb)
+	s = last.(*seq)
+	return
+}
+
+// HasQualities returns true if there is at least one sequence, and if all
+// the sequences have quality scores
+func (sb *seqbag) HasQualities() bool {
+	for _, seq := range sb.seqs {
+		if seq.quality == nil {
+			return false
+		}
+	}
+	return len(sb.seqs) > 0
+}
+
 // Append a string to all sequence names of the alignment
 // If right is true, then append it to the right of each names,
 // otherwise, appends it to the left
@@ -259,12 +302,12 @@ func (sb *seqbag) CloneSeqBag() (SeqBag, error) {
 	c := NewSeqBag(sb.Alphabet())
 	c.IgnoreIdentical(sb.ignoreidentical)
 	var err error
-	sb.IterateAll(func(name string, sequence []uint8, comment string) bool {
-		newseq := make([]uint8, 0, len(sequence))
-		newseq = append(newseq, sequence...)
-		err = c.AddSequenceChar(name, newseq, comment)
-		return err != nil
-	})
+	for _, s := range sb.seqs {
+		cs := s.Clone()
+		if err = c.AddSequenceQuality(cs.Name(), cs.SequenceChar(), cs.Quality(), cs.Comment()); err != nil {
+			break
+		}
+	}
 	return c, err
 }
 
@@ -288,7 +331,7 @@ func (sb *seqbag) Deduplicate() (identical [][]string, err error) {
 		s := string(seq.sequence)
 		// If the group does not exist
 		if i, ok := seqs[s]; !ok {
-			if err = sb.AddSequence(seq.name, s, seq.comment); err != nil {
+			if err = sb.AddSequenceQuality(seq.name, seq.sequence, seq.quality, seq.comment); err != nil {
 				return
 			}
 			identical = append(identical, []string{seq.name})
@@ -311,7 +354,7 @@ func (sb *seqbag) FilterLength(minlength, maxlength int) (err error) {
 	sb.Clear()
 	for _, seq := range oldseqs {
 		if (minlength >= 0 && seq.Length() >= minlength) || (maxlength > 0 && seq.Length() <= maxlength) {
-			if err = sb.AddSequenceChar(seq.name, seq.sequence, seq.comment); err != nil {
+			if err = sb.AddSequenceQuality(seq.name, seq.sequence, seq.quality, seq.comment); err != nil {
 				return
 			}
 		}
@@ -869,15 +912,18 @@ func (sb *seqbag) ReverseComplement() (err error) {
 		if err = Complement(seq.sequence); err != nil {
 			return
 		}
-		Reverse(seq.sequence)
+		seq.Reverse()
 	}
 
 	return
 }
 
 // Translate sequences in 3 phases (or 6 phases if reverse strand is true)
-// And return the longest orf found
-func (sb *seqbag) LongestORF(reverse bool) (orf Sequence, err error) {
+// And return the longest orf found.
+//
+// Start and stop codons are taken from the given genetic code. If altstarts
+// is false, then only ATG is considered as start codon.
+func (sb *seqbag) LongestORF(reverse bool, geneticcode int, altstarts bool) (orf Sequence, err error) {
 	var beststart, bestend int
 	var start, end int
 	var bestseq Sequence
@@ -887,7 +933,9 @@ func (sb *seqbag) LongestORF(reverse bool) (orf Sequence, err error) {
 	found = false
 	// Search for the longest orf in all sequences
 	for _, seq := range sb.seqs {
-		start, end = seq.LongestORF()
+		if start, end, err = seq.LongestORF(geneticcode, altstarts); err != nil {
+			return
+		}
 		if start != -1 && end-start > bestend-beststart {
 			beststart, bestend = start, end
 			bestseq = seq
@@ -898,7 +946,9 @@ func (sb *seqbag) LongestORF(reverse bool) (orf Sequence, err error) {
 			rev := seq.Clone()
 			rev.Reverse()
 			rev.Complement()
-			start, end = rev.LongestORF()
+			if start, end, err = rev.LongestORF(geneticcode, altstarts); err != nil {
+				return
+			}
 			if start != -1 && end-start > bestend-beststart {
 				beststart, bestend = start, end
 				bestseq = rev
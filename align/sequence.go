@@ -19,8 +19,13 @@ type Sequence interface {
 	Name() string
 	SetName(name string)
 	Comment() string
+	// Phred quality score of each character (e.g. from a fastq file), nil if none
+	Quality() []uint8
+	SetQuality(quality []uint8) error // Returns an error if the quality length is not the sequence length
 	Length() int
-	LongestORF() (start, end int) // Detects the longest ORF in forward strand only
+	// Detects the longest ORF in forward strand only, using start and stop codons of the given genetic code
+	// (alternative start codons if altstarts is true, only ATG otherwise)
+	LongestORF(geneticcode int, altstarts bool) (start, end int, err error)
 	Reverse()
 	Complement() error                                      // Returns an error if not nucleotide sequence
 	Translate(phase int, geneticcode int) (Sequence, error) // Translates the sequence using the given code
@@ -42,6 +47,7 @@ type seq struct {
 	name     string  // Name of the sequence
 	sequence []uint8 // Sequence of nucleotides/aa
 	comment  string  // Comment if any
+	quality  []uint8 // Phred quality score of each character, if any
 }
 
 func NewSequence(name string, sequence []uint8, comment string) *seq {
@@ -49,6 +55,7 @@ func NewSequence(name string, sequence []uint8, comment string) *seq {
 		name,
 		sequence,
 		comment,
+		nil,
 	}
 }
 
@@ -92,18 +99,49 @@ func (s *seq) Comment() string {
 	return s.comment
 }
 
+func (s *seq) Quality() []uint8 {
+	return s.quality
+}
+
+func (s *seq) SetQuality(quality []uint8) error {
+	if quality != nil && len(quality) != len(s.sequence) {
+		return fmt.Errorf("quality length (%d) is different from sequence length (%d) for sequence %s", len(quality), len(s.sequence), s.name)
+	}
+	s.quality = quality
+	return nil
+}
+
 func (s *seq) Length() int {
 	return len(s.sequence)
 }
 
-// Detects the position of ATG giving the longest ORF
+// Detects the position of the start codon giving the longest ORF
 // Search is done in the forward strand only
 //
-// returns -1 is no ATG...STOP has been found
-func (s *seq) LongestORF() (start, end int) {
+// Start and stop codons are taken from the given genetic code. If altstarts
+// is false, then only ATG is considered as start codon.
+//
+// returns -1 is no START...STOP has been found
+func (s *seq) LongestORF(geneticcode int, altstarts bool) (start, end int, err error) {
+	var starts, stops []string
+	var re *regexp.Regexp
+
 	start = -1
 	end = -1
-	re, _ := regexp.Compile("(ATG)(.{3})*?(TAA|TGA|TAG)")
+	if starts, err = startCodons(geneticcode, altstarts); err != nil {
+		return
+	}
+	if stops, err = stopCodons(geneticcode); err != nil {
+		return
+	}
+	// Some genetic codes (e.g. NCBI 27, 28, 31) only have context dependent stops
+	if len(stops) == 0 {
+		err = fmt.Errorf("genetic code has no stop codon, cannot detect orfs")
+		return
+	}
+	if re, err = regexp.Compile("(" + strings.Join(starts, "|") + ")(.{3})*?(" + strings.Join(stops, "|") + ")"); err != nil {
+		return
+	}
 	//re.Longest()
 	idx := re.FindAllStringIndex(
 		strings.Replace(
@@ -116,7 +154,7 @@ func (s *seq) LongestORF() (start, end int) {
 			start = pos[0]
 		}
 	}
-	return start, end
+	return
 }
 
 func RandomSequence(alphabet, length int) ([]uint8, error) {
@@ -144,6 +182,7 @@ func Reverse(seq []uint8) {
 // Reverse sequence order
 func (s *seq) Reverse() {
 	Reverse(s.sequence)
+	Reverse(s.quality)
 }
 
 // Complement sequence
@@ -364,7 +403,12 @@ func bufferTranslate(s *seq, phase int, code map[string]uint8, buffer *bytes.Buf
 func (s *seq) Clone() Sequence {
 	seq2 := make([]uint8, len(s.sequence))
 	copy(seq2, s.sequence)
-	return NewSequence(s.name, seq2, s.comment)
+	c := NewSequence(s.name, seq2, s.comment)
+	if s.quality != nil {
+		c.quality = make([]uint8, len(s.quality))
+		copy(c.quality, s.quality)
+	}
+	return c
 }
 
 // GenAllPossibleCodons generates all possible codons given the 3 nucleotides in arguments
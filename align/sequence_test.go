@@ -55,6 +55,9 @@ func Test_seq_Translate(t *testing.T) {
 		{name: "Seq1", fields: fields{name: "seq1", sequence: []uint8{'G', 'A', 'Y', 'A', 'A', 'R', 'U', 'A', 'Y', 'C', 'A', 'Y', 'R', 'A', 'Y', 'U', 'A', 'G'}}, args: args{phase: 0, geneticcode: 0}, wantTr: &seq{name: "seq1", sequence: []uint8{'D', 'K', 'Y', 'H', 'X', '*'}}, wantErr: false},
 		{name: "Seq2", fields: fields{name: "seq1", sequence: []uint8{'G', 'A', 'Y', 'A', 'A', 'R', 'U', 'A', 'Y', 'C', 'A', 'Y', 'A', 'A', 'Y', 'U', 'A', 'G'}}, args: args{phase: 0, geneticcode: 0}, wantTr: &seq{name: "seq1", sequence: []uint8{'D', 'K', 'Y', 'H', 'N', '*'}}, wantErr: false},
 		{name: "Seq3", fields: fields{name: "seq1", sequence: []uint8{'-', 'A', 'Y', 'A', 'A', 'R', 'U', 'A', 'Y', 'C', 'A', 'Y', 'A', 'A', 'Y', 'U', 'A', 'G'}}, args: args{phase: 0, geneticcode: 0}, wantTr: &seq{name: "seq1", sequence: []uint8{'X', 'K', 'Y', 'H', 'N', '*'}}, wantErr: false},
+		{name: "Mold", fields: fields{name: "seq1", sequence: []uint8("ATGTGATAA")}, args: args{phase: 0, geneticcode: GENETIC_CODE_MOLD_MITO}, wantTr: &seq{name: "seq1", sequence: []uint8("MW*")}, wantErr: false},
+		{name: "Ciliate", fields: fields{name: "seq1", sequence: []uint8("ATGTAATAGTGA")}, args: args{phase: 0, geneticcode: GENETIC_CODE_CILIATE}, wantTr: &seq{name: "seq1", sequence: []uint8("MQQ*")}, wantErr: false},
+		{name: "Unknown code", fields: fields{name: "seq1", sequence: []uint8("ATGTAA")}, args: args{phase: 0, geneticcode: 100}, wantTr: nil, wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -75,6 +78,69 @@ func Test_seq_Translate(t *testing.T) {
 	}
 }
 
+func Test_seq_LongestORF(t *testing.T) {
+	type args struct {
+		geneticcode int
+		altstarts   bool
+	}
+	tests := []struct {
+		name      string
+		sequence  string
+		args      args
+		wantStart int
+		wantEnd   int
+		wantErr   bool
+	}{
+		{name: "ATG", sequence: "CCTTGAAACCCGGGTAAATGAAATAA", args: args{geneticcode: GENETIC_CODE_STANDARD, altstarts: false}, wantStart: 17, wantEnd: 26, wantErr: false},
+		{name: "Alt starts", sequence: "CCTTGAAACCCGGGTAAATGAAATAA", args: args{geneticcode: GENETIC_CODE_STANDARD, altstarts: true}, wantStart: 2, wantEnd: 17, wantErr: false},
+		{name: "No stop", sequence: "CCTTGAAACCCGGGTAAATGAAATGA", args: args{geneticcode: GENETIC_CODE_VETEBRATE_MITO, altstarts: false}, wantStart: -1, wantEnd: -1, wantErr: false},
+		{name: "Context stops", sequence: "ATGAAATAA", args: args{geneticcode: GENETIC_CODE_KARYORELICT, altstarts: false}, wantStart: -1, wantEnd: -1, wantErr: true},
+		{name: "Unknown code", sequence: "ATGAAATAA", args: args{geneticcode: 100, altstarts: false}, wantStart: -1, wantEnd: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSequence("s1", []uint8(tt.sequence), "")
+			gotStart, gotEnd, err := s.LongestORF(tt.args.geneticcode, tt.args.altstarts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("seq.LongestORF() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotStart != tt.wantStart || gotEnd != tt.wantEnd {
+				t.Errorf("seq.LongestORF() = (%v, %v), want (%v, %v)", gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestGeneticCodeFromNCBI(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       int
+		wantCode int
+		wantErr  bool
+	}{
+		{name: "Standard", id: 1, wantCode: GENETIC_CODE_STANDARD, wantErr: false},
+		{name: "Vertebrate mito", id: 2, wantCode: GENETIC_CODE_VETEBRATE_MITO, wantErr: false},
+		{name: "Invertebrate mito", id: 5, wantCode: GENETIC_CODE_INVETEBRATE_MITO, wantErr: false},
+		{name: "Bacterial", id: 11, wantCode: GENETIC_CODE_BACTERIAL_PLASTID, wantErr: false},
+		{name: "Cephalodiscidae", id: 33, wantCode: GENETIC_CODE_CEPHALODISCIDAE_MITO, wantErr: false},
+		{name: "Unassigned 7", id: 7, wantCode: 0, wantErr: true},
+		{name: "Unknown", id: 34, wantCode: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCode, err := GeneticCodeFromNCBI(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GeneticCodeFromNCBI() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotCode != tt.wantCode {
+				t.Errorf("GeneticCodeFromNCBI() = %v, want %v", gotCode, tt.wantCode)
+			}
+		})
+	}
+}
+
 func Test_seq_NumGapsFromEnd(t *testing.T) {
 	type fields struct {
 		name     string
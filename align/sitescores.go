@@ -0,0 +1,184 @@
+package align
+
+import (
+	"math"
+)
+
+// Pseudo count added to character frequencies for the Jensen-Shannon divergence
+const jsdPseudoCount = 1e-6
+
+// SiteScore gathers conservation metrics of an alignment site
+type SiteScore struct {
+	Site int // Position of the site on the alignment (0-based)
+	// Shannon entropy of the site (natural log), gaps and '*' not taken
+	// into account. NaN if the site only contains such characters
+	Entropy float64
+	// Jensen-Shannon divergence between the character distribution of the site and
+	// the background distribution of the alignment (Capra & Singh 2007), log2,
+	// multiplied by the fraction of non gap characters. Higher is more conserved.
+	JSD         float64
+	GapFraction float64 // Fraction of gaps at the site
+	Informative bool    // Parsimony informative site (see InformativeSites)
+	// Clustal conservation class (see SiteConservation): POSITION_IDENTICAL,
+	// POSITION_CONSERVED, POSITION_SEMI_CONSERVED or POSITION_NOT_CONSERVED
+	Conservation int
+}
+
+// SiteScores computes several conservation metrics for each site of the
+// alignment (see SiteScore). Characters of each site are counted once, and all
+// metrics are computed from these counts. Entropy, informative sites and
+// conservation classes are the same as Entropy(site, true), InformativeSites() and
+// SiteConservation(site).
+func (a *align) SiteScores() (scores []SiteScore) {
+	alphabet := a.AlphabetCharacters()
+	var index [256]int
+	for i := range index {
+		index[i] = -1
+	}
+	for i, c := range alphabet {
+		index[c] = i
+	}
+
+	all := uint8('.')
+	if a.Alphabet() == AMINOACIDS {
+		all = ALL_AMINO
+	} else if a.Alphabet() == NUCLEOTIDS {
+		all = ALL_NUCLE
+	}
+
+	// Background distribution
+	background := make([]float64, len(alphabet))
+	total := 0.0
+	for _, seq := range a.seqs {
+		for _, c := range seq.sequence {
+			if i := index[upperByte(c)]; i >= 0 {
+				background[i]++
+				total++
+			}
+		}
+	}
+	for i := range background {
+		background[i] = (background[i] + jsdPseudoCount) / (total + float64(len(alphabet))*jsdPseudoCount)
+	}
+
+	scores = make([]SiteScore, a.Length())
+	var occur [256]int
+	alphacounts := make([]float64, len(alphabet))
+	for site := range scores {
+		for i := range occur {
+			occur[i] = 0
+		}
+		for _, seq := range a.seqs {
+			occur[seq.sequence[site]]++
+		}
+		scores[site] = a.siteScore(site, occur[:], all, index[:], background, alphacounts)
+	}
+	return
+}
+
+// siteScore computes the metrics of a site given the number of occurences of each character
+func (a *align) siteScore(site int, occur []int, all uint8, index []int, background, alphacounts []float64) (score SiteScore) {
+	var upper, informative [256]int
+	nbseqs := a.NbSequences()
+	score.Site = site
+	if nbseqs == 0 {
+		score.Entropy = math.NaN()
+		return
+	}
+	score.GapFraction = float64(occur[GAP]) / float64(nbseqs)
+	for c, nb := range occur {
+		u := upperByte(uint8(c))
+		upper[u] += nb
+		if c != GAP && c != POINT && c != int(all) {
+			informative[u] += nb
+		}
+	}
+
+	// Entropy
+	total := 0
+	for c, nb := range occur {
+		if nb > 0 && c != OTHER && c != POINT && c != GAP {
+			total += nb
+		}
+	}
+	if total == 0 {
+		score.Entropy = math.NaN()
+	} else {
+		for c, nb := range occur {
+			if nb > 0 && c != OTHER && c != POINT && c != GAP {
+				proba := float64(nb) / float64(total)
+				score.Entropy -= proba * math.Log(proba)
+			}
+		}
+	}
+
+	// Informative
+	nbinformative := 0
+	for _, nb := range informative {
+		if nb >= 2 {
+			nbinformative++
+		}
+	}
+	score.Informative = nbinformative >= 2
+
+	// Conservation
+	score.Conservation = POSITION_NOT_CONSERVED
+	distinct := 0
+	for _, nb := range occur {
+		if nb > 0 {
+			distinct++
+		}
+	}
+	if distinct == 1 && occur[GAP] == 0 {
+		score.Conservation = POSITION_IDENTICAL
+	} else if a.Alphabet() == AMINOACIDS {
+		if groupsContain(strongGroups, upper[:], nbseqs) {
+			score.Conservation = POSITION_CONSERVED
+		} else if groupsContain(weakGroups, upper[:], nbseqs) {
+			score.Conservation = POSITION_SEMI_CONSERVED
+		}
+	}
+
+	// Jensen-Shannon divergence
+	nbchars := 0.0
+	for i := range alphacounts {
+		alphacounts[i] = 0
+	}
+	for c, nb := range upper {
+		if i := index[c]; nb > 0 && i >= 0 {
+			alphacounts[i] += float64(nb)
+			nbchars += float64(nb)
+		}
+	}
+	jsd := 0.0
+	for i, q := range background {
+		p := (alphacounts[i] + jsdPseudoCount) / (nbchars + float64(len(alphacounts))*jsdPseudoCount)
+		r := 0.5*p + 0.5*q
+		jsd += 0.5*p*math.Log2(p/r) + 0.5*q*math.Log2(q/r)
+	}
+	score.JSD = jsd * (1. - score.GapFraction)
+	return
+}
+
+// groupsContain returns true if one of the groups contains the
+// characters of all the sequences
+func groupsContain(groups [][]uint8, occur []int, nbseqs int) bool {
+	for _, g := range groups {
+		nb := 0
+		for _, aa := range g {
+			nb += occur[aa]
+		}
+		if nb == nbseqs {
+			return true
+		}
+	}
+	return false
+}
+
+// upperByte returns the upper case of ASCII letters, and c otherwise
+func upperByte(c uint8) uint8 {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
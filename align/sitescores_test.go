@@ -0,0 +1,82 @@
+package align
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// randomSiteAlign generates a random alignment with few different characters per
+// site, gaps, and lower case characters
+func randomSiteAlign(alphabet, nbseqs, length int) Alignment {
+	chars := stdnucleotides
+	if alphabet == AMINOACIDS {
+		chars = []uint8("STANEQKMILFHY")
+	}
+	al := NewAlign(alphabet)
+	for i := 0; i < nbseqs; i++ {
+		seq := make([]uint8, length)
+		for j := range seq {
+			switch r := rand.Intn(20); {
+			case r == 0:
+				seq[j] = GAP
+			case r == 1:
+				seq[j] = chars[j%len(chars)] + 'a' - 'A'
+			case r < 12:
+				seq[j] = chars[j%len(chars)]
+			default:
+				seq[j] = chars[(j+rand.Intn(3))%len(chars)]
+			}
+		}
+		al.AddSequenceChar(string(rune('A'+i)), seq, "")
+	}
+	return al
+}
+
+func TestSiteScores(t *testing.T) {
+	rand.Seed(10)
+	for _, alphabet := range []int{NUCLEOTIDS, AMINOACIDS} {
+		for _, nbseqs := range []int{2, 5, 20} {
+			al := randomSiteAlign(alphabet, nbseqs, 200)
+			scores := al.SiteScores()
+			informative := make(map[int]bool)
+			for _, s := range al.InformativeSites() {
+				informative[s] = true
+			}
+			for site, s := range scores {
+				if s.Site != site {
+					t.Errorf("Wrong site index %d vs. %d", s.Site, site)
+				}
+				e, _ := al.Entropy(site, true)
+				if math.Abs(e-s.Entropy) > 1e-10 {
+					t.Errorf("Entropy of site %d should be %f, not %f", site, e, s.Entropy)
+				}
+				if s.Informative != informative[site] {
+					t.Errorf("Site %d informative should be %t", site, informative[site])
+				}
+				c, _ := al.SiteConservation(site)
+				if s.Conservation != c {
+					t.Errorf("Conservation of site %d should be %d, not %d", site, c, s.Conservation)
+				}
+			}
+		}
+	}
+}
+
+func TestSiteScoresJSD(t *testing.T) {
+	al := NewAlign(NUCLEOTIDS)
+	al.AddSequence("s1", "AAC-", "")
+	al.AddSequence("s2", "AGT-", "")
+	al.AddSequence("s3", "ACG-", "")
+	al.AddSequence("s4", "A-A-", "")
+	scores := al.SiteScores()
+	if !(scores[0].JSD > scores[1].JSD && scores[0].JSD > scores[2].JSD) {
+		t.Errorf("Conserved site should have a higher JSD: %v", scores)
+	}
+	if scores[1].GapFraction != 0.25 || scores[3].GapFraction != 1 {
+		t.Errorf("Wrong gap fractions: %v", scores)
+	}
+	if scores[3].JSD != 0 || !math.IsNaN(scores[3].Entropy) {
+		t.Errorf("Gap only site should have a null JSD and a NaN entropy: %v", scores[3])
+	}
+}
@@ -0,0 +1,208 @@
+package align
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Variant describes a difference between the sequences of an alignment and
+// a reference sequence of the same alignment, in reference coordinates.
+type Variant struct {
+	Pos  int      // 1-based position on the reference sequence (without gaps)
+	Ref  string   // Reference allele
+	Alts []string // Alternative alleles
+	// For each sequence of the alignment (reference excluded, in alignment order):
+	// indices of its alleles (0: Ref, i>0: Alts[i-1]). Ambiguous nucleotides (IUPAC) give
+	// several alleles. nil means missing genotype.
+	Genotypes [][]int
+}
+
+// Variants compares all sequences of the alignment to the reference sequence
+// having the given name, and returns the variants, in reference coordinates:
+//
+//   - Each reference position where at least one sequence differs gives a variant;
+//   - Insertions (gaps in the reference) are attached to the previous reference position;
+//   - Deletions (gaps in a sequence) are anchored on the previous reference position
+//     (or on the next one if the deletion starts at the first position);
+//   - If a sequence has one ambiguous nucleotide (IUPAC) in its allele, then its genotype
+//     contains all the possible alleles (e.g. R gives A and G). If it has several ambiguous
+//     nucleotides, they are replaced by N;
+//   - Alleles made only of N are considered missing;
+//   - Ambiguous nucleotides (IUPAC) of the reference are replaced by N, as VCF
+//     only allows A, C, G, T and N in REF.
+//
+// Alignment must be nucleotidic.
+func (a *align) Variants(refname string) (variants []Variant, err error) {
+	var refseq []uint8
+	var exists bool
+	var refcols []int
+	var others [][]uint8
+	var nreg, s, e, i, start, end int
+
+	if a.Alphabet() != NUCLEOTIDS {
+		err = fmt.Errorf("variants can only be computed on nucleotide alignments")
+		return
+	}
+	if refseq, exists = a.GetSequenceChar(refname); !exists {
+		err = fmt.Errorf("sequence %s does not exist in the alignment", refname)
+		return
+	}
+
+	refcols = make([]int, 0, len(refseq))
+	for i = range refseq {
+		if refseq[i] != GAP {
+			refcols = append(refcols, i)
+		}
+	}
+	if len(refcols) == 0 {
+		err = fmt.Errorf("reference sequence %s contains only gaps", refname)
+		return
+	}
+
+	others = make([][]uint8, 0, a.NbSequences()-1)
+	a.IterateChar(func(name string, sequence []uint8) bool {
+		if name != refname {
+			others = append(others, sequence)
+		}
+		return false
+	})
+
+	// Reference position k covers alignment columns [regionStart(k), regionStart(k+1)[
+	// i.e. its own column and the following insertion columns.
+	// Columns before the first reference character are attached to the first position.
+	nreg = len(refcols)
+	regionStart := func(k int) int {
+		if k == 0 {
+			return 0
+		}
+		if k == nreg {
+			return a.Length()
+		}
+		return refcols[k]
+	}
+	// True if at least one sequence has a gap at reference position k
+	deletion := func(k int) bool {
+		for _, o := range others {
+			if o[refcols[k]] == GAP {
+				return true
+			}
+		}
+		return false
+	}
+
+	variants = make([]Variant, 0)
+	for s = 0; s < nreg; s = e + 1 {
+		e = s
+		// A deletion at the first position is anchored on the next base
+		if s == 0 {
+			for e+1 < nreg && deletion(e) {
+				e++
+			}
+		}
+		// Deletions at next positions are anchored on this one
+		for e+1 < nreg && deletion(e+1) {
+			e++
+		}
+		start, end = regionStart(s), regionStart(e+1)
+
+		ref := nAmbiguous(vcfAllele(refseq[start:end]))
+		alleles := map[string]int{ref: 0}
+		v := Variant{Pos: s + 1, Ref: ref, Alts: make([]string, 0), Genotypes: make([][]int, len(others))}
+		variable := false
+		for i, o := range others {
+			v.Genotypes[i] = v.addGenotype(alleles, vcfAllele(o[start:end]))
+			for _, g := range v.Genotypes[i] {
+				if g != 0 {
+					variable = true
+				}
+			}
+		}
+		if variable {
+			variants = append(variants, v)
+		}
+	}
+	return
+}
+
+// Returns the genotype corresponding to the given allele, and adds
+// the new alternative alleles to the variant.
+func (v *Variant) addGenotype(alleles map[string]int, allele string) (genotype []int) {
+	var nambig, ambigpos int
+	var possibles []uint8
+
+	if strings.Trim(allele, "N") == "" {
+		return nil
+	}
+
+	for i := 0; i < len(allele); i++ {
+		switch allele[i] {
+		case 'A', 'C', 'G', 'T', 'N':
+		default:
+			nambig++
+			ambigpos = i
+		}
+	}
+
+	if nambig == 1 {
+		possibles = IupacCode[allele[ambigpos]]
+		genotype = make([]int, 0, len(possibles))
+		for _, nt := range possibles {
+			genotype = append(genotype, v.alleleIndex(alleles, allele[:ambigpos]+string(nt)+allele[ambigpos+1:]))
+		}
+		sort.Ints(genotype)
+		return
+	}
+
+	if nambig > 1 {
+		allele = nAmbiguous(allele)
+	}
+	return []int{v.alleleIndex(alleles, allele)}
+}
+
+// Replaces the ambiguous nucleotides of the allele by N
+func nAmbiguous(allele string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case 'A', 'C', 'G', 'T':
+			return r
+		default:
+			return 'N'
+		}
+	}, allele)
+}
+
+// Returns the index of the given allele, adding it to the alternative
+// alleles if it does not exist yet
+func (v *Variant) alleleIndex(alleles map[string]int, allele string) (idx int) {
+	var ok bool
+	if idx, ok = alleles[allele]; !ok {
+		v.Alts = append(v.Alts, allele)
+		idx = len(v.Alts)
+		alleles[allele] = idx
+	}
+	return
+}
+
+// Returns the allele corresponding to the given alignment columns:
+// Gaps are removed, characters are upper cased, and characters that
+// are not nucleotides (IUPAC) are replaced by N.
+func vcfAllele(columns []uint8) string {
+	var sb strings.Builder
+	var c uint8
+	var ok bool
+
+	for _, c = range columns {
+		if c == GAP {
+			continue
+		}
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if _, ok = IupacCode[c]; !ok {
+			c = ALL_NUCLE
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
@@ -0,0 +1,90 @@
+package align
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_align_Variants(t *testing.T) {
+	in := NewAlign(NUCLEOTIDS)
+	in.AddSequence("ref", "ACGT--ACGTACGT", "")
+	in.AddSequence("s1", "ACGTTTACGTACGT", "")
+	in.AddSequence("s2", "ACRT--AC--ACGT", "")
+	in.AddSequence("s3", "-CGT--ACGTNCGA", "")
+	in.AddSequence("s4", "ACGT--ACGTACGY", "")
+
+	exp := []Variant{
+		{Pos: 1, Ref: "AC", Alts: []string{"C"}, Genotypes: [][]int{{0}, {0}, {1}, {0}}},
+		{Pos: 3, Ref: "G", Alts: []string{"A"}, Genotypes: [][]int{{0}, {0, 1}, {0}, {0}}},
+		{Pos: 4, Ref: "T", Alts: []string{"TTT"}, Genotypes: [][]int{{1}, {0}, {0}, {0}}},
+		{Pos: 6, Ref: "CGT", Alts: []string{"C"}, Genotypes: [][]int{{0}, {1}, {0}, {0}}},
+		{Pos: 12, Ref: "T", Alts: []string{"A", "C"}, Genotypes: [][]int{{0}, {0}, {1}, {0, 2}}},
+	}
+
+	variants, err := in.Variants("ref")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(variants, exp) {
+		t.Errorf("Variants() = %v, want %v", variants, exp)
+	}
+}
+
+func Test_align_VariantsMissing(t *testing.T) {
+	in := NewAlign(NUCLEOTIDS)
+	in.AddSequence("ref", "ACGTACGT", "")
+	in.AddSequence("s1", "ACGTNCGT", "")
+	in.AddSequence("s2", "ACGTTCGT", "")
+	in.AddSequence("s3", "ACGTACG-", "")
+
+	exp := []Variant{
+		{Pos: 5, Ref: "A", Alts: []string{"T"}, Genotypes: [][]int{nil, {1}, {0}}},
+		{Pos: 7, Ref: "GT", Alts: []string{"G"}, Genotypes: [][]int{{0}, {0}, {1}}},
+	}
+
+	variants, err := in.Variants("ref")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(variants, exp) {
+		t.Errorf("Variants() = %v, want %v", variants, exp)
+	}
+}
+
+func Test_align_VariantsAmbiguousRef(t *testing.T) {
+	in := NewAlign(NUCLEOTIDS)
+	in.AddSequence("ref", "ACRTAC-T", "")
+	in.AddSequence("s1", "ACATAC-T", "")
+	in.AddSequence("s2", "ACNTACGT", "")
+	in.AddSequence("s3", "ACRTAC-Y", "")
+
+	exp := []Variant{
+		{Pos: 3, Ref: "N", Alts: []string{"A", "G"}, Genotypes: [][]int{{1}, nil, {1, 2}}},
+		{Pos: 6, Ref: "C", Alts: []string{"CG"}, Genotypes: [][]int{{0}, {1}, {0}}},
+		{Pos: 7, Ref: "T", Alts: []string{"C"}, Genotypes: [][]int{{0}, {0}, {0, 1}}},
+	}
+
+	variants, err := in.Variants("ref")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(variants, exp) {
+		t.Errorf("Variants() = %v, want %v", variants, exp)
+	}
+}
+
+func Test_align_VariantsErrors(t *testing.T) {
+	in := NewAlign(NUCLEOTIDS)
+	in.AddSequence("ref", "ACGT", "")
+	in.AddSequence("s1", "ACGT", "")
+	if _, err := in.Variants("unknown"); err == nil {
+		t.Errorf("Variants() should return an error with unknown reference")
+	}
+
+	prot := NewAlign(AMINOACIDS)
+	prot.AddSequence("ref", "MWKL", "")
+	prot.AddSequence("s1", "MWKI", "")
+	if _, err := prot.Variants("ref"); err == nil {
+		t.Errorf("Variants() should return an error with protein alignment")
+	}
+}
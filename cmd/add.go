@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/io"
+	"github.com/spf13/cobra"
+)
+
+var addOutput string
+var addSequences string
+var addKeepLength bool
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Adds unaligned sequences to an existing alignment",
+	Long: `Adds unaligned sequences to an existing alignment.
+
+Each new sequence (given with -s, fasta format) is aligned independently against
+the profile of the input alignment (frequencies of characters at each site), using
+a global alignment with affine gap scores, and is then added to the alignment.
+
+If --keep-length is given, then characters of the new sequences that are inserted
+relative to the input alignment are removed, and the columns of the input alignment
+are kept as is (similar to mafft --keeplength). Otherwise, insertions are kept and
+gap columns are added to the sequences of the input alignment.
+
+If neither --match nor --mismatch are specified, then match and mismatch scores
+are taken from blosum62 or dnafull substitution matrices (taken from EMBOSS WATER)
+depending on the input alignment alphabet.
+
+Score for opening a gap is specified by --gap-open option and score for extending a gap is
+specified by --gap-extend option (they should be negative).
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var aligns *align.AlignChannel
+		var seqs align.SeqBag
+		var res align.Alignment
+		var f *os.File
+
+		if aligns, err = readalign(infile); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		if seqs, err = readsequences(addSequences); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		if f, err = openWriteFile(addOutput); err != nil {
+			io.LogError(err)
+			return
+		}
+		defer closeWriteFile(f, addOutput)
+
+		aligner := align.NewMultipleAligner()
+		aligner.SetGapOpenScore(gapopen)
+		aligner.SetGapExtendScore(gapextend)
+		if cmd.Flags().Changed("mismatch") || cmd.Flags().Changed("match") {
+			aligner.SetScore(match, mismatch)
+		}
+
+		for al := range aligns.Achan {
+			if res, err = aligner.Add(al, seqs, addKeepLength); err != nil {
+				io.LogError(err)
+				return
+			}
+			if err = writeAlign(res, f); err != nil {
+				io.LogError(err)
+				return
+			}
+		}
+
+		if aligns.Err != nil {
+			err = aligns.Err
+			io.LogError(err)
+		}
+		return
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(addCmd)
+	addCmd.PersistentFlags().StringVarP(&addOutput, "output", "o", "stdout", "Alignment output file")
+	addCmd.PersistentFlags().StringVarP(&addSequences, "sequences", "s", "none", "Unaligned sequences to add to the input alignment (fasta)")
+	addCmd.PersistentFlags().BoolVar(&addKeepLength, "keep-length", false, "Removes insertions relative to the input alignment, so that its length does not change")
+	addCmd.PersistentFlags().Float64Var(&gapopen, "gap-open", -10.0, "Score for opening a gap ")
+	addCmd.PersistentFlags().Float64Var(&gapextend, "gap-extend", -0.5, "Score for extending a gap ")
+	addCmd.PersistentFlags().Float64Var(&match, "match", 1.0, "Score for a match (if omitted, then take substitution matrix)")
+	addCmd.PersistentFlags().Float64Var(&mismatch, "mismatch", -1.0, "Score for a mismatch (if omitted, then take substitution matrix)")
+}
@@ -35,7 +35,10 @@ goalign mutate gaps -i align.fa -n 0.5 -r 0.5
 
 		for al := range aligns.Achan {
 			al.AddGaps(mutateRate, gapnbseqs)
-			writeAlign(al, f)
+			if err = writeAlign(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 
 		if aligns.Err != nil {
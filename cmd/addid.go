@@ -29,7 +29,15 @@ The string may be added to the left or to the right of each sequence name.
 		}
 		defer closeWriteFile(f, addIdOutput)
 
-		if unaligned {
+		if streamable() {
+			err = readsequencestream(infile, !unaligned, func(al align.Alignment) error {
+				al.AppendSeqIdentifier(addIdName, addIdRight)
+				return writeStreamAlign(al, f)
+			})
+			if err != nil {
+				io.LogError(err)
+			}
+		} else if unaligned {
 			var seqs align.SeqBag
 
 			if seqs, err = readsequences(infile); err != nil {
@@ -37,7 +45,10 @@ The string may be added to the left or to the right of each sequence name.
 				return
 			}
 			seqs.AppendSeqIdentifier(addIdName, addIdRight)
-			writeSequences(seqs, f)
+			if err = writeSequences(seqs, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		} else {
 
 			var aligns *align.AlignChannel
@@ -47,7 +58,10 @@ The string may be added to the left or to the right of each sequence name.
 			}
 			for al := range aligns.Achan {
 				al.AppendSeqIdentifier(addIdName, addIdRight)
-				writeAlign(al, f)
+				if err = writeAlign(al, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 
 			if aligns.Err != nil {
@@ -80,7 +80,10 @@ goalign append -i align.fasta others*.fasta
 			io.LogError(err)
 			return
 		}
-		writeAlign(refAlign, f)
+		if err = writeAlign(refAlign, f); err != nil {
+			io.LogError(err)
+			return
+		}
 		closeWriteFile(f, appendout)
 
 		return
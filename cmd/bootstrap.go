@@ -85,7 +85,7 @@ goalign build seqboot -i align.phylip -p -n 500 -o boot_
 
 		// If a partition file is given, then we parse it
 		if bootstrappartitionstr != "none" {
-			if inputpartition, err = parsePartition(bootstrappartitionstr, al.Length()); err != nil {
+			if inputpartition, err = parsePartition(bootstrappartitionstr, al); err != nil {
 				io.LogError(err)
 				return
 			}
@@ -147,7 +147,10 @@ goalign build seqboot -i align.phylip -p -n 500 -o boot_
 				boot.ShuffleSequences()
 			}
 
-			bootstring = writeAlignString(boot)
+			if bootstring, err = writeAlignString(boot); err != nil {
+				io.LogError(err)
+				return
+			}
 
 			// Output
 			if bootstraptar {
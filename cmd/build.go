@@ -17,7 +17,9 @@ var buildCmd = &cobra.Command{
     - k2p  : Kimura 2 Parameters
     - f81  : Felsenstein 81
     - f84  : Felsenstein 84
+    - tn84 : Tajima and Nei 1984 (tn82 is accepted as an alias)
     - tn93 : Tamura and Nei 1993
+    - logdet : LogDet/paralinear (also for amino acids)
 `,
 }
 
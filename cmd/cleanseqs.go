@@ -56,7 +56,10 @@ will be removed.`,
 				al.RemoveCharacterSeqs(c[0], cleanCutoff, cleanIgnoreCase, cleanIgnoreGaps, cleanIgnoreNs)
 			}
 			after := al.NbSequences()
-			writeAlign(al, f)
+			if err = writeAlign(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
 			if !cleanQuiet {
 				io.PrintMessage(fmt.Sprintf("Alignment (%d) #seqs before cleaning=%d", i, before))
 				io.PrintMessage(fmt.Sprintf("Alignment (%d) #seqs after cleaning=%d", i, after))
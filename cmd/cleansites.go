@@ -39,10 +39,6 @@ will be removed.`,
 		var kept []int
 		var f, sitesposout *os.File
 
-		if aligns, err = readalign(infile); err != nil {
-			io.LogError(err)
-			return
-		}
 		if f, err = openWriteFile(cleanOutput); err != nil {
 			io.LogError(err)
 			return
@@ -55,18 +51,41 @@ will be removed.`,
 		}
 		defer closeWriteFile(f, cleanOutput)
 
+		gaps := cleanChar == string(align.GAP) || cleanChar == "GAP"
+		if gaps && cleanIgnoreGaps {
+			err = fmt.Errorf("--ignore-gaps should not be given with --char GAP")
+			io.LogError(err)
+			return
+		}
+
+		if gaps && streamable() {
+			var sa align.SiteAlignment
+			if sa, err = readsitealign(infile); err != nil {
+				io.LogError(err)
+				return
+			}
+			beforelength := sa.Length()
+			nbstart, nbend, kept = sa.RemoveGapSites(cleanCutoff, cleanEnds)
+			if err = writeSiteAlign(sa, f); err != nil {
+				io.LogError(err)
+				return
+			}
+			printCleanSitesStats(sitesposout, 0, "gaps", beforelength, sa.Length(), nbstart, nbend, kept)
+			return
+		}
+
+		if aligns, err = readalign(infile); err != nil {
+			io.LogError(err)
+			return
+		}
+
 		i := 0
 		char := ""
 
 		for al := range aligns.Achan {
 			beforelength := al.Length()
 
-			if cleanChar == string(align.GAP) || cleanChar == "GAP" {
-				if cleanIgnoreGaps {
-					err = fmt.Errorf("--ignore-gaps should not be given with --char GAP")
-					io.LogError(err)
-					return
-				}
+			if gaps {
 				char = "gaps"
 				nbstart, nbend, kept = al.RemoveGapSites(cleanCutoff, cleanEnds)
 			} else if cleanChar == "MAJ" {
@@ -89,19 +108,11 @@ will be removed.`,
 				nbstart, nbend, kept = al.RemoveCharacterSites(c[0], cleanCutoff, cleanEnds, cleanIgnoreCase, cleanIgnoreGaps, cleanIgnoreNs)
 			}
 			afterlength := al.Length()
-			writeAlign(al, f)
-
-			for _, p := range kept {
-				fmt.Fprintf(sitesposout, "%d\n", p)
-			}
-
-			if !cleanQuiet {
-				io.PrintSimpleMessage(fmt.Sprintf("Alignment (%d) length before cleaning=%d", i, beforelength))
-				io.PrintSimpleMessage(fmt.Sprintf("Alignment (%d) length after cleaning=%d", i, afterlength))
-				io.PrintSimpleMessage(fmt.Sprintf("Alignment (%d) number of %s=%d", i, char, beforelength-afterlength))
-				io.PrintSimpleMessage(fmt.Sprintf("Alignment (%d) number of start %s=%d", i, char, nbstart))
-				io.PrintSimpleMessage(fmt.Sprintf("Alignment (%d) number of end %s=%d", i, char, nbend))
+			if err = writeAlign(al, f); err != nil {
+				io.LogError(err)
+				return
 			}
+			printCleanSitesStats(sitesposout, i, char, beforelength, afterlength, nbstart, nbend, kept)
 		}
 
 		if aligns.Err != nil {
@@ -112,6 +123,22 @@ will be removed.`,
 	},
 }
 
+// Prints the remaining positions in the positions file, and
+// the number of removed sites on stderr (if not --quiet)
+func printCleanSitesStats(sitesposout *os.File, i int, char string, beforelength, afterlength, nbstart, nbend int, kept []int) {
+	for _, p := range kept {
+		fmt.Fprintf(sitesposout, "%d\n", p)
+	}
+
+	if !cleanQuiet {
+		io.PrintSimpleMessage(fmt.Sprintf("Alignment (%d) length before cleaning=%d", i, beforelength))
+		io.PrintSimpleMessage(fmt.Sprintf("Alignment (%d) length after cleaning=%d", i, afterlength))
+		io.PrintSimpleMessage(fmt.Sprintf("Alignment (%d) number of %s=%d", i, char, beforelength-afterlength))
+		io.PrintSimpleMessage(fmt.Sprintf("Alignment (%d) number of start %s=%d", i, char, nbstart))
+		io.PrintSimpleMessage(fmt.Sprintf("Alignment (%d) number of end %s=%d", i, char, nbend))
+	}
+}
+
 func init() {
 	cleansitesCmd.PersistentFlags().BoolVar(&cleanEnds, "ends", false, "If true, then only remove consecutive gap positions from alignment start and end")
 	cleansitesCmd.PersistentFlags().StringVar(&sitesposoutfile, "positions", "none", "Output file of all remaining positions (0-based, on position per line)")
@@ -44,7 +44,10 @@ goalign reformat clustal -i align.fasta
 		if reformatCleanNames {
 			a.CleanNames(nil)
 		}
-		writeAlignClustal(a, f)
+		if err = writeAlignClustal(a, f); err != nil {
+			io.LogError(err)
+			return
+		}
 
 		return
 	},
@@ -69,7 +69,10 @@ the protein alignment length * 3, returns an error.
 				io.LogError(err)
 				return
 			}
-			writeAlign(codonAl, f)
+			if err = writeAlign(codonAl, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 
 		if aligns.Err != nil {
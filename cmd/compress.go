@@ -39,12 +39,9 @@ and weight file:
 `,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		var aligns *align.AlignChannel
+		var sa align.SiteAlignment
 		var f, wf *os.File
 
-		if aligns, err = readalign(infile); err != nil {
-			io.LogError(err)
-			return
-		}
 		if f, err = openWriteFile(compressOutput); err != nil {
 			io.LogError(err)
 			return
@@ -57,13 +54,34 @@ and weight file:
 		}
 		defer closeWriteFile(f, compressWeightOutput)
 
+		if streamable() {
+			if sa, err = readsitealign(infile); err != nil {
+				io.LogError(err)
+				return
+			}
+			w := sa.Compress()
+			if err = writeSiteAlign(sa, f); err != nil {
+				io.LogError(err)
+				return
+			}
+			writeWeights(w, wf)
+			return
+		}
+
+		if aligns, err = readalign(infile); err != nil {
+			io.LogError(err)
+			return
+		}
 		for al := range aligns.Achan {
 			var w []int
 			if w = al.Compress(); err != nil {
 				io.LogError(err)
 				return
 			} else {
-				writeAlign(al, f)
+				if err = writeAlign(al, f); err != nil {
+					io.LogError(err)
+					return
+				}
 				writeWeights(w, wf)
 			}
 		}
@@ -47,13 +47,33 @@ Nucleotides:
 - k2p     : Kimura 2 Parameters
 - f81     : Felsenstein 81
 - f84     : Felsenstein 84
+- tn84    : Tajima and Nei 1984 (tn82 is accepted as an alias)
 - tn93    : Tamura and Nei 1993
+- logdet  : LogDet/paralinear (Lockhart et al. 1994, Lake 1994)
 Proteins:
 - DAYHOFF
 - JTT
 - MtRev 
 - LG
 - WAG
+- HIVb
+- HIVw
+- cpREV
+- rtREV
+- VT
+- Blosum62
+- FLU
+- mtMAM
+- mtART
+- Any model given as a PAML .dat file (-m model.dat): lower triangle of the
+  exchangeability matrix followed by the 20 amino acid frequencies
+- logdet  : LogDet/paralinear
+
+The logdet distance does not assume stationary composition, and is robust to
+compositional heterogeneity (ex: GC-rich vs. AT-rich lineages). It does not
+support --alpha. For each pair of sequences, only characters present in both
+sequences are taken into account: sites having a character absent from one of
+the sequences are ignored.
 
 For nucleotides, differences are generally counted if nucleotides are incompatible.
 For example R and Y will give a difference; N and A will not give a difference.
@@ -71,7 +91,7 @@ goalign compute distance -m k2p -i align.fa
 
 if -a is given: display only the average distance
 
-In case of a nucleotidic alignment, it is possible to specify sequence ranges to compare. For example, 
+In case of a nucleotidic alignment (or of the logdet distance), it is possible to specify sequence ranges to compare. For example, 
 goalign compute distance -m pdist -i align.ph -p --range1 0:9 --range2 10:19
 will compute distance only between sequences [0 to 9] and sequences [10 to 19].
 Output matrix will be formatted the same way as usual, except that it will be made of 0 except for
@@ -82,7 +102,7 @@ the comparisons 0 vs. 10; 0 .vs 11; ...; 9 vs. 19.
 		var f *os.File
 		var model dna.DistModel
 		var aligns *align.AlignChannel
-		var protmodel int
+		var protmodel *protein.ProtDistModel
 
 		if f, err = openWriteFile(computedistOutput); err != nil {
 			io.LogError(err)
@@ -95,13 +115,17 @@ the comparisons 0 vs. 10; 0 .vs 11; ...; 9 vs. 19.
 			return
 		}
 
+		if protmodel, err = protDistModel(computedistModel, cmd.Flags().Changed("alpha"), computedistAlpha, computedistRemoveGaps); err != nil {
+			io.LogError(err)
+			return
+		}
+
 		// If prot model
-		if protmodel = pm.ModelStringToInt(computedistModel); protmodel != -1 {
+		if protmodel != nil {
 			var d *mat.Dense
-			m, _ := protein.NewProtDistModel(protmodel, true, cmd.Flags().Changed("alpha"), computedistAlpha, computedistRemoveGaps)
-			m.InitModel(nil, nil)
+			protmodel.InitModel(nil, nil)
 			for align := range aligns.Achan {
-				if _, _, d, err = m.MLDist(align, nil); err != nil {
+				if _, _, d, err = protmodel.MLDist(align, nil); err != nil {
 					io.LogError(err)
 					return
 				}
@@ -169,7 +193,7 @@ the comparisons 0 vs. 10; 0 .vs 11; ...; 9 vs. 19.
 
 			for align := range aligns.Achan {
 				var distMatrix [][]float64
-				distMatrix, err = dna.DistMatrix(align, nil, model, range1min, range1max, range2min, range2max, cmd.Flags().Changed("alpha"), computedistAlpha, rootcpus)
+				distMatrix, err = dna.DistMatrix(align, nil, alignDistModel(model, computedistRemoveGaps, align), range1min, range1max, range2min, range2max, cmd.Flags().Changed("alpha"), computedistAlpha, rootcpus)
 				if err != nil {
 					io.LogError(err)
 					return
@@ -203,8 +227,8 @@ func init() {
 	computedistCmd.PersistentFlags().BoolVar(&computedistRemoveAmbiguous, "rm-ambiguous", false, "if true, ambiguous positions are removed for the normalisation by the length in case of non different positions. Only available for pdist (nt)")
 	computedistCmd.PersistentFlags().BoolVarP(&computedistAverage, "average", "a", false, "Compute only the average distance between all pairs of sequences")
 	computedistCmd.PersistentFlags().Float64Var(&computedistAlpha, "alpha", 0.0, "Gamma alpha parameter, if not given : no gamma")
-	computedistCmd.PersistentFlags().StringVar(&computedistRange1, "range1", "", "If set, then will restrict distance computation to the given seq range compared to range 2 (0-based, ex --range1 0:100 means [0,100]), only for nucleotide models so far")
-	computedistCmd.PersistentFlags().StringVar(&computedistRange2, "range2", "", "If set, then will restrict distance computation to the given seq range compared to range 1 (0-based, ex --range2 0:100 means [0:100]), only for nucleotide models so far")
+	computedistCmd.PersistentFlags().StringVar(&computedistRange1, "range1", "", "If set, then will restrict distance computation to the given seq range compared to range 2 (0-based, ex --range1 0:100 means [0,100]), only for nucleotide and logdet models so far")
+	computedistCmd.PersistentFlags().StringVar(&computedistRange2, "range2", "", "If set, then will restrict distance computation to the given seq range compared to range 1 (0-based, ex --range2 0:100 means [0:100]), only for nucleotide and logdet models so far")
 }
 
 func writeDistMatrix(al align.Alignment, matrix [][]float64, f *os.File) (err error) {
@@ -244,6 +268,39 @@ func writeDistAverage(al align.Alignment, matrix [][]float64, f *os.File) {
 	f.WriteString(fmt.Sprintf("%.12f\n", sum))
 }
 
+// Returns the protein distance model with the given name (see protein.ModelStringToInt),
+// or read from the given PAML .dat file. Returns a nil model if it is not a protein model.
+func protDistModel(model string, gamma bool, alpha float64, rmgaps bool) (m *protein.ProtDistModel, err error) {
+	var dmat *mat.Dense
+	var pi []float64
+	var mod *pm.ProtModel
+
+	if code := pm.ModelStringToInt(model); code != -1 {
+		return protein.NewProtDistModel(code, true, gamma, alpha, rmgaps)
+	}
+	if _, err = dna.Model(model, rmgaps); err == nil || !regularFile(model) {
+		err = nil
+		return
+	}
+	if dmat, pi, err = pm.ReadPAMLFile(model); err != nil {
+		return
+	}
+	if mod, err = pm.NewProtModelFromMats(dmat, pi, gamma, alpha); err != nil {
+		return
+	}
+	m = protein.NewProtDistModelFromProtModel(mod, true, rmgaps)
+	return
+}
+
+// Returns the distance model to use for the given alignment: on amino acid
+// alignments, the logdet distance is computed by protein.LogDetModel
+func alignDistModel(model dna.DistModel, rmgaps bool, al align.Alignment) dna.DistModel {
+	if _, ok := model.(*dna.LogDetModel); ok && al.Alphabet() == align.AMINOACIDS {
+		return protein.NewLogDetModel(rmgaps)
+	}
+	return model
+}
+
 func denseToSlice(m *mat.Dense) (s [][]float64) {
 	r, c := m.Dims()
 	s = make([][]float64, r)
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/distance/codon"
+	"github.com/evolbioinfo/goalign/io"
+)
+
+var computedndsOutput string
+var computedndsDnOutput string
+var computedndsDsOutput string
+var computedndsMethod string
+var computedndsGeneticCode string
+
+// computedndsCmd represents the compute dnds command
+var computedndsCmd = &cobra.Command{
+	Use:   "dnds",
+	Short: "Compute pairwise dN, dS and dN/dS from a codon alignment",
+	Long: `Compute pairwise dN, dS and dN/dS from a codon alignment
+
+The input alignment must be a nucleotide codon alignment (for example
+given by goalign codonalign), whose length is a multiple of 3.
+
+For each pair of sequences, it computes the number of non synonymous
+substitutions per non synonymous site (dN), the number of synonymous
+substitutions per synonymous site (dS), and omega=dN/dS.
+
+Available methods (-m):
+- ng86  : Nei & Gojobori 1986: equal substitution rates, Jukes-Cantor correction
+- lwl85 : Li, Wu & Luo 1985: sites classified as 0, 2 or 4 fold degenerate,
+          Kimura 2 parameters correction in each class
+- yn00  : Yang & Nielsen 2000: sites and differences weighted by the
+          transition/transversion ratio and the codon frequencies (F3x4),
+          Kimura 2 parameters correction. It does not use nucleotide
+          frequencies in the correction, so values may differ slightly from PAML
+
+Codons containing gaps or ambiguous nucleotides, and stop codons are not
+taken into account (pairwise deletion). Pathways between codons going through
+stop codons are ignored. The genetic code may be given with --genetic-code.
+
+The omega matrix is written in the output file (-o), and dN and dS matrices
+may be written in --dn-output and --ds-output files. All matrices have the same
+format as goalign compute distance. Values that can not be computed (saturation,
+no synonymous difference, etc.) are NaN or +Inf.
+
+If the input alignment contains several alignments, will compute matrices
+for all of them.
+
+For example:
+
+goalign compute dnds -m ng86 -i codons.fa --dn-output dn.txt --ds-output ds.txt -o omega.txt
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var f, fdn, fds *os.File
+		var aligns *align.AlignChannel
+		var method, geneticcode int
+		var dn, ds, omega [][]float64
+
+		if method, err = codon.Method(computedndsMethod); err != nil {
+			io.LogError(err)
+			return
+		}
+		if geneticcode, err = geneticCodeFromString(computedndsGeneticCode); err != nil {
+			io.LogError(err)
+			return
+		}
+		if f, err = openWriteFile(computedndsOutput); err != nil {
+			io.LogError(err)
+			return
+		}
+		defer closeWriteFile(f, computedndsOutput)
+
+		if computedndsDnOutput != "none" {
+			if fdn, err = openWriteFile(computedndsDnOutput); err != nil {
+				io.LogError(err)
+				return
+			}
+			defer closeWriteFile(fdn, computedndsDnOutput)
+		}
+		if computedndsDsOutput != "none" {
+			if fds, err = openWriteFile(computedndsDsOutput); err != nil {
+				io.LogError(err)
+				return
+			}
+			defer closeWriteFile(fds, computedndsDsOutput)
+		}
+
+		if aligns, err = readalign(infile); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		for al := range aligns.Achan {
+			if dn, ds, omega, err = codon.DnDsMatrix(al, method, geneticcode, rootcpus); err != nil {
+				io.LogError(err)
+				return
+			}
+			if err = writeDistMatrix(al, omega, f); err != nil {
+				io.LogError(err)
+				return
+			}
+			if fdn != nil {
+				if err = writeDistMatrix(al, dn, fdn); err != nil {
+					io.LogError(err)
+					return
+				}
+			}
+			if fds != nil {
+				if err = writeDistMatrix(al, ds, fds); err != nil {
+					io.LogError(err)
+					return
+				}
+			}
+		}
+
+		if aligns.Err != nil {
+			err = aligns.Err
+			io.LogError(err)
+		}
+		return
+	},
+}
+
+func init() {
+	computeCmd.AddCommand(computedndsCmd)
+	computedndsCmd.PersistentFlags().StringVarP(&computedndsOutput, "output", "o", "stdout", "dN/dS matrix output file")
+	computedndsCmd.PersistentFlags().StringVar(&computedndsDnOutput, "dn-output", "none", "dN matrix output file")
+	computedndsCmd.PersistentFlags().StringVar(&computedndsDsOutput, "ds-output", "none", "dS matrix output file")
+	computedndsCmd.PersistentFlags().StringVarP(&computedndsMethod, "method", "m", "ng86", "dN/dS estimation method: ng86, lwl85 or yn00")
+	computedndsCmd.PersistentFlags().StringVar(&computedndsGeneticCode, "genetic-code", "standard", geneticCodeUsage)
+}
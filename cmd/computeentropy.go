@@ -40,39 +40,30 @@ and it will not be taken into account in the average.
 `,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		var aligns *align.AlignChannel
-		var e float64
+		var sa align.SiteAlignment
+
+		if streamable() {
+			if sa, err = readsitealign(infile); err != nil {
+				io.LogError(err)
+				return
+			}
+			printEntropyHeader()
+			if err = printEntropy(sa, 0); err != nil {
+				io.LogError(err)
+			}
+			return
+		}
 
 		if aligns, err = readalign(infile); err != nil {
 			io.LogError(err)
 			return
 		}
-
+		printEntropyHeader()
 		nb := 0
-		if entropyAverage {
-			fmt.Println("Alignment\tAvgEntropy")
-		} else {
-			fmt.Println("Alignment\tSite\tEntropy")
-		}
-		for align := range aligns.Achan {
-			avg := 0.0
-			total := 0
-			for i := 0; i < align.Length(); i++ {
-				if e, err = align.Entropy(i, entropyRemoveGaps); err != nil {
-					io.LogError(err)
-					return
-				} else {
-					if entropyAverage {
-						if !math.IsNaN(e) {
-							avg += e
-							total++
-						}
-					} else {
-						fmt.Printf("%d\t%d\t%.3f\n", nb, i, e)
-					}
-				}
-			}
-			if entropyAverage {
-				fmt.Printf("%d\t%.3f\n", nb, avg/float64(total))
+		for al := range aligns.Achan {
+			if err = printEntropy(al, nb); err != nil {
+				io.LogError(err)
+				return
 			}
 			nb++
 		}
@@ -85,6 +76,38 @@ and it will not be taken into account in the average.
 	},
 }
 
+func printEntropyHeader() {
+	if entropyAverage {
+		fmt.Println("Alignment\tAvgEntropy")
+	} else {
+		fmt.Println("Alignment\tSite\tEntropy")
+	}
+}
+
+// Prints the entropy of each site of the nbth alignment, or its average entropy
+func printEntropy(al align.SiteAlignment, nb int) (err error) {
+	var e float64
+	avg := 0.0
+	total := 0
+	for i := 0; i < al.Length(); i++ {
+		if e, err = al.Entropy(i, entropyRemoveGaps); err != nil {
+			return
+		}
+		if entropyAverage {
+			if !math.IsNaN(e) {
+				avg += e
+				total++
+			}
+		} else {
+			fmt.Printf("%d\t%d\t%.3f\n", nb, i, e)
+		}
+	}
+	if entropyAverage {
+		fmt.Printf("%d\t%.3f\n", nb, avg/float64(total))
+	}
+	return
+}
+
 func init() {
 	computeCmd.AddCommand(entropyCmd)
 	entropyCmd.PersistentFlags().BoolVarP(&entropyAverage, "average", "a", false, "Compute only the average entropy of input alignment")
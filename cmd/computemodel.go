@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/distance/dna"
+	"github.com/evolbioinfo/goalign/io"
+	mdna "github.com/evolbioinfo/goalign/models/dna"
+)
+
+var computemodelOutput string
+var computemodelGamma bool
+var computemodelNCat int
+var computemodelRemoveGaps bool
+var computemodelMaxPairs int
+
+// computemodelCmd represents the compute model command
+var computemodelCmd = &cobra.Command{
+	Use:   "model",
+	Short: "Estimates GTR model parameters from a nucleotide alignment",
+	Long: `Estimates GTR model parameters from a nucleotide alignment.
+
+Base frequencies are the empirical frequencies of the alignment.
+Relative rates (AC, AG, AT, CG, CT, relative to GT=1) and, if --gamma is given,
+the shape parameter (alpha) of a discrete gamma rate heterogeneity with --ncat categories,
+are estimated by maximum likelihood.
+
+The likelihood is the pairwise composite likelihood: each pair of sequences
+is considered independently, with its own ML distance, and all pairs share
+the model parameters. If the alignment has more than --max-pairs pairs of sequences,
+--max-pairs random pairs are used (see --seed).
+
+Only sites with unambiguous nucleotides in both sequences of a pair are taken
+into account. If --rm-gaps is given, sites having at least one gap are removed.
+
+If the input file contains several alignments, parameters are estimated for each of them.
+
+Output is tab separated, one line per alignment:
+Alignment  lnL  AC  AG  AT  CG  CT  GT  piA  piC  piG  piT  alpha  model
+
+The last column is the model in RAxML-NG format (e.g. GTR{1.2/4.1/0.8/1.1/5.2/1}+FU{0.3/0.2/0.2/0.3}+G4{0.52}),
+which may be given as is to goalign simulate -m.
+
+goalign compute distance does not accept such a model: only the estimated alpha
+may be reused there (goalign compute distance -m tn93 --alpha <alpha>). The relative
+rates and base frequencies are only usable with goalign simulate -m.
+
+Examples:
+goalign compute model -i align.fa --gamma
+goalign simulate --tree tree.nw -l 1000 -m "$(goalign compute model -i align.fa --gamma | tail -n 1 | cut -f 14)"
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var f *os.File
+		var aligns *align.AlignChannel
+		var params mdna.GTRParams
+		var ncat int
+
+		if aligns, err = readalign(infile); err != nil {
+			io.LogError(err)
+			return
+		}
+		if f, err = openWriteFile(computemodelOutput); err != nil {
+			io.LogError(err)
+			return
+		}
+		defer closeWriteFile(f, computemodelOutput)
+
+		if computemodelGamma {
+			if computemodelNCat < 2 {
+				err = fmt.Errorf("number of gamma categories must be >= 2")
+				io.LogError(err)
+				return
+			}
+			ncat = computemodelNCat
+		}
+
+		if _, err = f.WriteString("Alignment\tlnL\tAC\tAG\tAT\tCG\tCT\tGT\tpiA\tpiC\tpiG\tpiT\talpha\tmodel\n"); err != nil {
+			io.LogError(err)
+			return
+		}
+		nb := 0
+		for al := range aligns.Achan {
+			if params, err = dna.EstimateGTR(al, nil, computemodelRemoveGaps, ncat, computemodelMaxPairs); err != nil {
+				io.LogError(err)
+				return
+			}
+			line := fmt.Sprintf("%d\t%.4f", nb, params.LnL)
+			for _, r := range params.Rates {
+				line += fmt.Sprintf("\t%.6f", r)
+			}
+			for _, p := range params.Pi {
+				line += fmt.Sprintf("\t%.6f", p)
+			}
+			if params.NCat > 0 {
+				line += fmt.Sprintf("\t%.6f", params.Alpha)
+			} else {
+				line += "\tNA"
+			}
+			if _, err = f.WriteString(fmt.Sprintf("%s\t%s\n", line, params.String())); err != nil {
+				io.LogError(err)
+				return
+			}
+			nb++
+		}
+
+		if aligns.Err != nil {
+			err = aligns.Err
+			io.LogError(err)
+		}
+		return
+	},
+}
+
+func init() {
+	computeCmd.AddCommand(computemodelCmd)
+	computemodelCmd.PersistentFlags().StringVarP(&computemodelOutput, "output", "o", "stdout", "Model parameters output file")
+	computemodelCmd.PersistentFlags().BoolVar(&computemodelGamma, "gamma", false, "Also estimates the gamma shape parameter (alpha)")
+	computemodelCmd.PersistentFlags().IntVar(&computemodelNCat, "ncat", 4, "Number of gamma categories (only with --gamma)")
+	computemodelCmd.PersistentFlags().BoolVarP(&computemodelRemoveGaps, "rm-gaps", "r", false, "Do not take into account positions containing >=1 gaps")
+	computemodelCmd.PersistentFlags().IntVar(&computemodelMaxPairs, "max-pairs", 1000, "Maximum number of pairs of sequences used for the estimation (<=0: all pairs)")
+}
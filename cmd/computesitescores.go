@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/io"
+)
+
+var sitescoresOutput string
+var sitescoresRefSeq string
+var sitescoresBed bool
+var sitescoresCodon bool
+
+// sitescoresCmd represents the compute sitescores command
+var sitescoresCmd = &cobra.Command{
+	Use:   "sitescores",
+	Short: "Computes several conservation metrics for each alignment site",
+	Long: `Computes several conservation metrics for each alignment site
+
+It prints one line per alignment site, in a tab separated form, with the
+following columns:
+- Alignment    : index of the alignment in the input file
+- Site         : position of the site on the alignment (0-based)
+- RefSite      : position of the site on the reference sequence given with
+                 --ref-seq (0-based, without gaps), "NA" if the reference has
+                 a gap at this site (only with --ref-seq)
+- Entropy      : Shannon entropy (natural log), gaps not taken into account
+                 (same as goalign compute entropy -g)
+- JSD          : Jensen-Shannon divergence between the site distribution and the
+                 background distribution of the alignment (Capra & Singh 2007),
+                 multiplied by the fraction of non gaps. Higher is more conserved
+- GapFraction  : fraction of gaps
+- Informative  : 1 if the site is parsimony informative (at least two characters
+                 occuring at least twice each, gaps and N/X excluded), 0 otherwise
+- Conservation : clustal conservation class: identical, conserved (same strong
+                 group), semi-conserved (same weak group) or not-conserved
+- CodonPos     : position of the site in its codon (1, 2 or 3), for codon
+                 alignments (only with --codon)
+
+If --bed is given (requires --ref-seq), then output is BED-like: the three first
+columns are the reference sequence name, and the start (0-based) and end positions
+of the site on the reference sequence, followed by the metrics. Sites where the
+reference sequence has a gap are not written.
+
+Examples:
+goalign compute sitescores -i alignment.fa
+goalign compute sitescores -i codons.fa --ref-seq seq1 --bed --codon
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var f *os.File
+		var aligns *align.AlignChannel
+
+		if sitescoresBed && sitescoresRefSeq == "none" {
+			err = fmt.Errorf("--bed requires a reference sequence (--ref-seq)")
+			io.LogError(err)
+			return
+		}
+
+		if f, err = openWriteFile(sitescoresOutput); err != nil {
+			io.LogError(err)
+			return
+		}
+		defer closeWriteFile(f, sitescoresOutput)
+
+		if aligns, err = readalign(infile); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		printSiteScoresHeader(f)
+		nb := 0
+		for al := range aligns.Achan {
+			if err = printSiteScores(f, al, nb); err != nil {
+				io.LogError(err)
+				return
+			}
+			nb++
+		}
+
+		if aligns.Err != nil {
+			err = aligns.Err
+			io.LogError(err)
+		}
+		return
+	},
+}
+
+func init() {
+	computeCmd.AddCommand(sitescoresCmd)
+	sitescoresCmd.PersistentFlags().StringVarP(&sitescoresOutput, "output", "o", "stdout", "Site scores output file")
+	sitescoresCmd.PersistentFlags().StringVar(&sitescoresRefSeq, "ref-seq", "none", "Reference sequence giving site coordinates")
+	sitescoresCmd.PersistentFlags().BoolVar(&sitescoresBed, "bed", false, "BED-like output, in reference sequence coordinates (requires --ref-seq)")
+	sitescoresCmd.PersistentFlags().BoolVar(&sitescoresCodon, "codon", false, "Adds the codon position of each site (codon alignment)")
+}
+
+func printSiteScoresHeader(f *os.File) {
+	if sitescoresBed {
+		f.WriteString("#Chrom\tStart\tEnd")
+	} else {
+		f.WriteString("Alignment\tSite")
+		if sitescoresRefSeq != "none" {
+			f.WriteString("\tRefSite")
+		}
+	}
+	f.WriteString("\tEntropy\tJSD\tGapFraction\tInformative\tConservation")
+	if sitescoresCodon {
+		f.WriteString("\tCodonPos")
+	}
+	f.WriteString("\n")
+}
+
+// Prints the scores of each site of the nbth alignment
+func printSiteScores(f *os.File, al align.Alignment, nb int) (err error) {
+	var refsites []int
+
+	if sitescoresRefSeq != "none" {
+		if refsites, err = siteRefCoordinates(al, sitescoresRefSeq); err != nil {
+			return
+		}
+	}
+
+	for _, s := range al.SiteScores() {
+		if sitescoresBed {
+			if refsites[s.Site] < 0 {
+				continue
+			}
+			f.WriteString(fmt.Sprintf("%s\t%d\t%d", sitescoresRefSeq, refsites[s.Site], refsites[s.Site]+1))
+		} else {
+			f.WriteString(fmt.Sprintf("%d\t%d", nb, s.Site))
+			if refsites != nil {
+				if refsites[s.Site] < 0 {
+					f.WriteString("\tNA")
+				} else {
+					f.WriteString(fmt.Sprintf("\t%d", refsites[s.Site]))
+				}
+			}
+		}
+		informative := 0
+		if s.Informative {
+			informative = 1
+		}
+		f.WriteString(fmt.Sprintf("\t%.3f\t%.3f\t%.3f\t%d\t%s", s.Entropy, s.JSD, s.GapFraction, informative, conservationName(s.Conservation)))
+		if sitescoresCodon {
+			f.WriteString(fmt.Sprintf("\t%d", s.Site%3+1))
+		}
+		f.WriteString("\n")
+	}
+	return
+}
+
+// siteRefCoordinates returns, for each site of the alignment, its position on the
+// given reference sequence (without gaps), or -1 if the reference has a gap.
+func siteRefCoordinates(al align.Alignment, refname string) (refcoords []int, err error) {
+	var seq []uint8
+	var exists bool
+	var alisites []int
+
+	if seq, exists = al.GetSequenceChar(refname); !exists {
+		err = fmt.Errorf("reference sequence %s does not exist in the alignment", refname)
+		return
+	}
+	refsites := make([]int, 0, len(seq))
+	for _, c := range seq {
+		if c != align.GAP {
+			refsites = append(refsites, len(refsites))
+		}
+	}
+	if alisites, err = al.RefSites(refname, refsites); err != nil {
+		return
+	}
+	refcoords = make([]int, al.Length())
+	for i := range refcoords {
+		refcoords[i] = -1
+	}
+	for refsite, alisite := range alisites {
+		refcoords[alisite] = refsite
+	}
+	return
+}
+
+func conservationName(conservation int) string {
+	switch conservation {
+	case align.POSITION_IDENTICAL:
+		return "identical"
+	case align.POSITION_CONSERVED:
+		return "conserved"
+	case align.POSITION_SEMI_CONSERVED:
+		return "semi-conserved"
+	default:
+		return "not-conserved"
+	}
+}
@@ -1,14 +1,29 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/evolbioinfo/goalign/align"
 	"github.com/evolbioinfo/goalign/io"
+	"github.com/evolbioinfo/goalign/io/nexus"
+	"github.com/evolbioinfo/goalign/io/partition"
 	"github.com/spf13/cobra"
 )
 
 var concatout string
+var concatoutpartition string
+var concatoutcharsets string
+var concatmodels []string
+var concatfill string
+
+// Part of the concatenated alignment corresponding to an input alignment
+type concatPart struct {
+	name       string
+	start, end int // 0-based, inclusive
+}
 
 // concatCmd represents the concat command
 var concatCmd = &cobra.Command{
@@ -17,7 +32,7 @@ var concatCmd = &cobra.Command{
 	Long: `Concatenates a set of alignments.
 For example:
 
-If format is phylip, it may contain several alignments in one file. 
+If format is phylip, it may contain several alignments in one file.
 Then we can concatenate all of them:
 goalign concat -i align.phy
 
@@ -28,68 +43,180 @@ It is possible to give only otherfiles, without -i, by giving -i none
    goalign concat -i none align*.fasta
 or goalign concat -i none -p align*.phy
 
+If all input alignments are in nexus format and define charsets, the output
+nexus alignment keeps them (in a SETS block).
+
+Sequences missing from an input alignment are filled with gaps, or with the
+character given with --fill-char (e.g. '?').
+
+The positions of each input alignment in the concatenated alignment may be written:
+- In a RAxML/IQ-TREE partition file (--out-partition): model,name=start-end
+- In a nexus SETS block (--out-charsets): charset name = start-end;
+Partition names are the input file names without extension (followed by _<index> if
+the file contains several alignments). Model names are given with --model, either
+once for all input alignments, or once per input alignment (in order).
 `,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
-		var aligns *align.AlignChannel
-		var alchan *align.AlignChannel
+		var ps *align.PartitionSet
 		var align align.Alignment = nil
+		var parts []concatPart
+		var fileparts []concatPart
+		var files []string
 		var f *os.File
 
+		if len(concatfill) != 1 {
+			err = fmt.Errorf("fill character must be a single character: %q", concatfill)
+			io.LogError(err)
+			return
+		}
+
 		if infile != "none" {
-			if aligns, err = readalign(infile); err != nil {
+			files = append(files, infile)
+		}
+		files = append(files, args...)
+
+		for _, file := range files {
+			if align, fileparts, err = concatFile(align, file); err != nil {
 				io.LogError(err)
 				return
 			}
-			for al := range aligns.Achan {
-				if align == nil {
-					align = al
-				} else {
-					if err = align.Concat(al); err != nil {
-						io.LogError(err)
-						return
-					}
-				}
-			}
-			if aligns.Err != nil {
-				err = aligns.Err
+			parts = append(parts, fileparts...)
+		}
+
+		if align == nil {
+			err = fmt.Errorf("no alignment to concatenate")
+			io.LogError(err)
+			return
+		}
+
+		if f, err = openWriteFile(concatout); err != nil {
+			io.LogError(err)
+			return
+		}
+		if err = writeAlign(align, f); err != nil {
+			io.LogError(err)
+			return
+		}
+		closeWriteFile(f, concatout)
+
+		if concatoutpartition != "none" || concatoutcharsets != "none" {
+			if ps, err = concatPartitionSet(align, parts); err != nil {
 				io.LogError(err)
 				return
 			}
 		}
-		for _, otherfile := range args {
-			if alchan, err = readalign(otherfile); err != nil {
+		if concatoutpartition != "none" {
+			if f, err = openWriteFile(concatoutpartition); err != nil {
 				io.LogError(err)
 				return
 			}
-			for al := range alchan.Achan {
-				if align == nil {
-					align = al
-				} else {
-					if err = align.Concat(al); err != nil {
-						io.LogError(err)
-						return
-					}
-				}
-			}
-			if alchan.Err != nil {
-				err = alchan.Err
+			f.WriteString(ps.String())
+			closeWriteFile(f, concatoutpartition)
+		}
+		if concatoutcharsets != "none" {
+			if f, err = openWriteFile(concatoutcharsets); err != nil {
 				io.LogError(err)
 				return
 			}
+			f.WriteString("#NEXUS\n")
+			nexus.NewWriter(f).WriteSets(ps)
+			closeWriteFile(f, concatoutcharsets)
 		}
 
-		if f, err = openWriteFile(concatout); err != nil {
-			io.LogError(err)
-			return
+		return
+	},
+}
+
+// Concatenates all the alignments of the given file to the given alignment (may be nil),
+// and returns the new alignment and the parts corresponding to the alignments of the file
+func concatFile(al align.Alignment, file string) (outal align.Alignment, parts []concatPart, err error) {
+	var aligns *align.AlignChannel
+	var start int
+	var name string
+
+	outal = al
+	if aligns, err = readalign(file); err != nil {
+		return
+	}
+	for a := range aligns.Achan {
+		if err != nil {
+			// We empty the channel
+			continue
 		}
-		writeAlign(align, f)
-		closeWriteFile(f, concatout)
+		start = 0
+		if outal == nil {
+			outal = a
+		} else {
+			start = outal.Length()
+			err = outal.ConcatFill(a, concatfill[0])
+		}
+		parts = append(parts, concatPart{start: start, end: outal.Length() - 1})
+	}
+	if err != nil {
+		return
+	}
+	if aligns.Err != nil {
+		err = aligns.Err
+		return
+	}
 
+	name = filepath.Base(file)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	for i := range parts {
+		parts[i].name = name
+		if len(parts) > 1 {
+			parts[i].name = fmt.Sprintf("%s_%d", name, i+1)
+		}
+	}
+	return
+}
+
+// Builds the partition set of the concatenated alignment:
+// one partition per input alignment, with models given by --model
+func concatPartitionSet(al align.Alignment, parts []concatPart) (ps *align.PartitionSet, err error) {
+	var model, name string
+	var names map[string]int = make(map[string]int)
+
+	if len(concatmodels) > 1 && len(concatmodels) != len(parts) {
+		err = fmt.Errorf("number of models (%d) is different from the number of input alignments (%d)", len(concatmodels), len(parts))
 		return
-	},
+	}
+	for _, m := range concatmodels {
+		if _, err = partition.ParseModel(m); err != nil {
+			return
+		}
+	}
+
+	ps = align.NewPartitionSet(al.Length())
+	for i, p := range parts {
+		if p.end < p.start {
+			// Empty alignment
+			continue
+		}
+		model = align.DefaultPartitionModel(al.Alphabet())
+		if len(concatmodels) == 1 {
+			model = concatmodels[0]
+		} else if len(concatmodels) > 1 {
+			model = concatmodels[i]
+		}
+		// Same file names in different directories
+		name = p.name
+		if names[p.name] > 0 {
+			name = fmt.Sprintf("%s_%d", p.name, names[p.name]+1)
+		}
+		names[p.name]++
+		if err = ps.AddRange(name, model, p.start, p.end, 1); err != nil {
+			return
+		}
+	}
+	return
 }
 
 func init() {
 	RootCmd.AddCommand(concatCmd)
 	concatCmd.PersistentFlags().StringVarP(&concatout, "output", "o", "stdout", "Alignment output file")
+	concatCmd.PersistentFlags().StringVar(&concatoutpartition, "out-partition", "none", "Output partition file (RAxML/IQ-TREE format), one partition per input alignment")
+	concatCmd.PersistentFlags().StringVar(&concatoutcharsets, "out-charsets", "none", "Output nexus file with a SETS block, one charset per input alignment")
+	concatCmd.PersistentFlags().StringSliceVar(&concatmodels, "model", []string{}, "Model of the partitions in output partition file: once for all, or once per input alignment (default DNA or PROT)")
+	concatCmd.PersistentFlags().StringVar(&concatfill, "fill-char", "-", "Character used to fill sequences missing from an input alignment (e.g. - or ?)")
 }
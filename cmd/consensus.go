@@ -48,7 +48,10 @@ then will output several consensus sequences.
 
 		for al := range aligns.Achan {
 			cons := al.Consensus(consensusIgnoreGaps, consensusIgnoreNs)
-			writeAlign(cons, f)
+			if err = writeAlign(cons, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 
 		if aligns.Err != nil {
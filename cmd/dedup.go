@@ -70,7 +70,10 @@ This means that seq1 is identical to seq2 and seq3 is identical to seq4.
 				io.LogError(err)
 				return
 			} else {
-				writeSequences(seqs, f)
+				if err = writeSequences(seqs, f); err != nil {
+					io.LogError(err)
+					return
+				}
 				writeIdentical(id, l)
 			}
 		} else {
@@ -86,7 +89,10 @@ This means that seq1 is identical to seq2 and seq3 is identical to seq4.
 					io.LogError(err)
 					return
 				} else {
-					writeAlign(al, f)
+					if err = writeAlign(al, f); err != nil {
+						io.LogError(err)
+						return
+					}
 					writeIdentical(id, l)
 				}
 			}
@@ -60,7 +60,10 @@ If option --reverse is given, then replaces . with the characters on the first s
 				} else {
 					al.DiffWithFirst()
 				}
-				writeAlign(al, f)
+				if err = writeAlign(al, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 		}
 
@@ -10,7 +10,6 @@ import (
 	"github.com/evolbioinfo/goalign/distance/dna"
 	"github.com/evolbioinfo/goalign/distance/protein"
 	"github.com/evolbioinfo/goalign/io"
-	pm "github.com/evolbioinfo/goalign/models/protein"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -37,13 +36,26 @@ Available Distances:
 - k2p  : Kimura 2 Parameters
 - f81  : Felsenstein 81
 - f84  : Felsenstein 84
+- tn84 : Tajima and Nei 1984 (tn82 is accepted as an alias)
 - tn93 : Tamura and Nei 1993
+- logdet : LogDet/paralinear
 Proteins:
 - DAYHOFF
 - JTT
 - MtRev 
 - LG
 - WAG
+- HIVb
+- HIVw
+- cpREV
+- rtREV
+- VT
+- Blosum62
+- FLU
+- mtMAM
+- mtART
+- Any model given as a PAML .dat file (-m model.dat)
+- logdet : LogDet/paralinear
 
 For example:
 
@@ -62,7 +74,6 @@ original alignment.
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		var dnamodel dna.DistModel
 		var protmodel *protein.ProtDistModel
-		var protmodelI int
 		var d *mat.Dense
 		var aligns *align.AlignChannel
 		var f *os.File
@@ -84,8 +95,11 @@ original alignment.
 			io.LogError(err)
 			return
 		}
-		if protmodelI = pm.ModelStringToInt(distbootmodel); protmodelI != -1 {
-			protmodel, _ = protein.NewProtDistModel(protmodelI, true, cmd.Flags().Changed("alpha"), distbootAlpha, distbootRemoveGaps)
+		if protmodel, err = protDistModel(distbootmodel, cmd.Flags().Changed("alpha"), distbootAlpha, distbootRemoveGaps); err != nil {
+			io.LogError(err)
+			return
+		}
+		if protmodel != nil {
 			protmodel.InitModel(nil, nil)
 			for i := 0; i < distbootnb; i++ {
 				if distbootcontinuous {
@@ -109,6 +123,7 @@ original alignment.
 				io.LogError(err)
 				return
 			}
+			dnamodel = alignDistModel(dnamodel, distbootRemoveGaps, align)
 			for i := 0; i < distbootnb; i++ {
 				var weights []float64 = nil
 				var distMatrix [][]float64
@@ -66,7 +66,10 @@ gotree divide -i align.ph -p -o out
 					io.LogError(err)
 					return
 				}
-				writeSequences(seqs, f)
+				if err = writeSequences(seqs, f); err != nil {
+					io.LogError(err)
+					return
+				}
 				f.Close()
 				i++
 			} else {
@@ -80,18 +83,27 @@ gotree divide -i align.ph -p -o out
 							io.LogError(err)
 							return true
 						}
-						writeSequences(tmpSeqs, f)
+						if err = writeSequences(tmpSeqs, f); err != nil {
+							io.LogError(err)
+							return true
+						}
 						f.Close()
 						tmpSeqs = align.NewSeqBag(seqs.Alphabet())
 						i++
 					}
 					return false
 				})
+				if err != nil {
+					return
+				}
 				if nb%divideNbSeqs > 0 {
 					if f, err = openWriteFile(fmt.Sprintf("%s_%03d%s", divideOutput, i, ext)); err != nil {
 						io.LogError(err)
 					}
-					writeSequences(tmpSeqs, f)
+					if err = writeSequences(tmpSeqs, f); err != nil {
+						io.LogError(err)
+						return
+					}
 					f.Close()
 					i++
 				}
@@ -109,9 +121,15 @@ gotree divide -i align.ph -p -o out
 						return
 					}
 					if divideoutputFasta {
-						writeAlignFasta(al, f)
+						if err = writeAlignFasta(al, f); err != nil {
+							io.LogError(err)
+							return
+						}
 					} else {
-						writeAlign(al, f)
+						if err = writeAlign(al, f); err != nil {
+							io.LogError(err)
+							return
+						}
 					}
 					f.Close()
 					i++
@@ -127,9 +145,15 @@ gotree divide -i align.ph -p -o out
 								return true
 							}
 							if divideoutputFasta {
-								writeAlignFasta(tmpAlign, f)
+								if err = writeAlignFasta(tmpAlign, f); err != nil {
+									io.LogError(err)
+									return true
+								}
 							} else {
-								writeAlign(tmpAlign, f)
+								if err = writeAlign(tmpAlign, f); err != nil {
+									io.LogError(err)
+									return true
+								}
 							}
 							f.Close()
 							i++
@@ -137,14 +161,23 @@ gotree divide -i align.ph -p -o out
 						}
 						return false
 					})
+					if err != nil {
+						return
+					}
 					if nb%divideNbSeqs > 0 {
 						if f, err = openWriteFile(fmt.Sprintf("%s_%03d%s", divideOutput, i, ext)); err != nil {
 							io.LogError(err)
 						}
 						if divideoutputFasta {
-							writeAlignFasta(tmpAlign, f)
+							if err = writeAlignFasta(tmpAlign, f); err != nil {
+								io.LogError(err)
+								return
+							}
 						} else {
-							writeAlign(tmpAlign, f)
+							if err = writeAlign(tmpAlign, f); err != nil {
+								io.LogError(err)
+								return
+							}
 						}
 						f.Close()
 						i++
@@ -1,10 +1,26 @@
 package cmd
 
 import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+
 	"github.com/spf13/cobra"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/draw"
+	"github.com/evolbioinfo/goalign/io"
 )
 
 var drawOutput string
+var drawScheme string
+var drawStart int
+var drawLength int
+var drawNoTracks bool
+var drawPixelSize int
+var drawRefSeq string
+var drawNoErrorBars bool
 
 // drawCmd represents the draw command
 var drawCmd = &cobra.Command{
@@ -17,3 +33,84 @@ func init() {
 	RootCmd.AddCommand(drawCmd)
 	drawCmd.PersistentFlags().StringVarP(&drawOutput, "output", "o", "stdout", "Alignment draw output file")
 }
+
+// addDrawFlags adds the flags shared by terminal, svg, png and logo subcommands.
+// The number of sites per block has a default value specific to each subcommand.
+func addDrawFlags(cmd *cobra.Command, width *int, defwidth int) {
+	cmd.PersistentFlags().StringVar(&drawScheme, "scheme", draw.SCHEME_AUTO, "Color scheme: auto (nucleotide or clustal), clustal, zappo, nucleotide, or none")
+	cmd.PersistentFlags().IntVarP(&drawStart, "start", "s", 0, "Start position of the region to draw (0-based inclusive)")
+	cmd.PersistentFlags().IntVarP(&drawLength, "length", "l", -1, "Length of the region to draw (-1: until the end of the alignment)")
+	cmd.PersistentFlags().IntVar(width, "width", defwidth, "Number of sites per block (0: one single block)")
+}
+
+// addDrawTracksFlag adds the flag disabling consensus and conservation tracks
+func addDrawTracksFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&drawNoTracks, "no-tracks", false, "Do not draw consensus and conservation tracks")
+}
+
+func drawOptions(width int) (options draw.Options) {
+	options = draw.DefaultOptions()
+	options.Scheme = drawScheme
+	options.Start = drawStart
+	options.Length = drawLength
+	options.Width = width
+	options.Tracks = !drawNoTracks
+	options.PixelSize = drawPixelSize
+	if drawRefSeq != "none" {
+		options.RefSeq = drawRefSeq
+	}
+	options.ErrorBars = !drawNoErrorBars
+	return
+}
+
+// drawFileName returns the output file name of the nalign th alignment:
+// an index is added to the file name if there are several alignments
+func drawFileName(fname string, nalign int) string {
+	if nalign == 0 || fname == "stdout" || fname == "-" {
+		return fname
+	}
+	ext := filepath.Ext(fname)
+	return fmt.Sprintf("%s_%d%s", fname[0:len(fname)-len(ext)], nalign, ext)
+}
+
+// drawAlignFiles draws each input alignment in its own output file, using the
+// layout returned by newLayout
+func drawAlignFiles(width int, newLayout func(w *bufio.Writer, options draw.Options) draw.AlignLayout) (err error) {
+	var aligns *align.AlignChannel
+
+	if aligns, err = readalign(infile); err != nil {
+		io.LogError(err)
+		return
+	}
+
+	nalign := 0
+	for al := range aligns.Achan {
+		if err = drawAlignFile(al, drawFileName(drawOutput, nalign), width, newLayout); err != nil {
+			io.LogError(err)
+			return
+		}
+		nalign++
+	}
+
+	if aligns.Err != nil {
+		err = aligns.Err
+		io.LogError(err)
+	}
+	return
+}
+
+// drawAlignFile draws the alignment in the given output file
+func drawAlignFile(al align.Alignment, fname string, width int, newLayout func(w *bufio.Writer, options draw.Options) draw.AlignLayout) (err error) {
+	var f *os.File
+
+	if f, err = openWriteFile(fname); err != nil {
+		return
+	}
+	defer closeWriteFile(f, fname)
+
+	w := bufio.NewWriter(f)
+	if err = newLayout(w, drawOptions(width)).DrawAlign(al); err != nil {
+		return
+	}
+	return w.Flush()
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/evolbioinfo/goalign/draw"
+)
+
+var drawLogoWidth int
+
+// logoCmd represents the draw logo command
+var logoCmd = &cobra.Command{
+	Use:   "logo",
+	Short: "Draw sequence logos in svg images",
+	Long: `Draw sequence logos in svg images
+
+At each site, letters are stacked by increasing height, and the height of the
+stack is the information content of the site, in bits (Schneider & Stephens 1990):
+  R = log2(s) - (H + e(n))
+with s the alphabet size (4 or 20), H the Shannon entropy (log2) of the site,
+and e(n) = (s-1)/(2 ln(2) n) the small sample correction, n being the number of
+characters at the site, gaps and ambiguous characters excluded. The height of
+each letter is its frequency times R (frequencies are the same as given by
+goalign compute pssm -n 1, but computed without gaps).
+
+Error bars of +/- 2 standard deviations of R are drawn on top of each stack,
+unless --no-errorbars is given. The variance of R is approximated by
+  (sum(q log2(q)^2) - (sum(q log2(q)))^2)/n
+with q the frequencies of the characters with one pseudo count per character.
+They are mostly visible for small sample sizes.
+
+Letters are colored according to the color scheme (--scheme, see goalign draw svg).
+
+A region of the alignment may be selected with --start and --length. If --ref-seq
+is given, --start and --length are given on this reference sequence (0-based,
+without gaps), and positions are labelled with their (1-based) coordinates on this
+reference sequence ("-" where it has a gap). Otherwise, positions are labelled with
+their (1-based) coordinates on the alignment.
+
+The logo is split in lines of --width sites (0: one single line).
+
+If the input file contains several alignments, it will write several output files.
+
+Example:
+goalign draw logo -i alignment.fa --ref-seq seq1 -s 10 -l 30 -o logo.svg
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		return drawAlignFiles(drawLogoWidth, draw.NewLogoLayout)
+	},
+}
+
+func init() {
+	drawCmd.AddCommand(logoCmd)
+	addDrawFlags(logoCmd, &drawLogoWidth, 50)
+	logoCmd.PersistentFlags().StringVar(&drawRefSeq, "ref-seq", "none", "Reference sequence giving the coordinates of --start and --length, and the position labels")
+	logoCmd.PersistentFlags().BoolVar(&drawNoErrorBars, "no-errorbars", false, "Do not draw error bars")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/evolbioinfo/goalign/draw"
+)
+
+var drawPNGWidth int
+
+// pngCmd represents the draw png command
+var pngCmd = &cobra.Command{
+	Use:   "png",
+	Short: "Draw alignments in png images",
+	Long: `Draw alignments in png images
+
+Each site of each sequence is drawn as a square of --pixel-size pixels,
+colored according to the color scheme (--scheme, see goalign draw svg).
+Characters absent from the color scheme are light gray, and gaps are white.
+No text is written: it gives an overview of the alignment. The ruler is a line
+of ticks every 5 (gray) and 10 (black) sites.
+
+The alignment is drawn in blocks of --width sites (0: one single block), each
+block ending with the consensus track and the conservation bar plot (see goalign
+draw svg), unless --no-tracks is given. A region of the alignment may be selected
+with --start and --length.
+
+If the input file contains several alignments, it will write several output files.
+
+Example:
+goalign draw png -i alignment.fa --width 0 --pixel-size 2 -o alignment.png
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		return drawAlignFiles(drawPNGWidth, draw.NewPNGLayout)
+	},
+}
+
+func init() {
+	drawCmd.AddCommand(pngCmd)
+	addDrawFlags(pngCmd, &drawPNGWidth, 0)
+	addDrawTracksFlag(pngCmd)
+	pngCmd.PersistentFlags().IntVar(&drawPixelSize, "pixel-size", 4, "Size of a site, in pixels")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/evolbioinfo/goalign/draw"
+)
+
+var drawSVGWidth int
+
+// svgCmd represents the draw svg command
+var svgCmd = &cobra.Command{
+	Use:   "svg",
+	Short: "Draw alignments in svg images",
+	Long: `Draw alignments in svg images
+
+The alignment is drawn in blocks of --width sites. Each block starts with
+a ruler (1-based coordinates), and ends with a consensus track and a
+conservation track, unless --no-tracks is given. The conservation track is a
+bar plot of the proportion of sequences having the consensus character, colored
+according to the clustal conservation class of the site (darker is more conserved).
+
+Characters are colored according to the color scheme (--scheme):
+- auto       : nucleotide for nucleotide alignments, clustal for amino acids
+- clustal    : Clustal X residue colors
+- zappo      : Zappo colors (physico-chemical properties)
+- nucleotide : A, C, G and T/U colors
+- none       : no color
+
+A region of the alignment may be selected with --start and --length.
+
+The svg image is self-contained: it does not need any external resource.
+If the input file contains several alignments, it will write several output files.
+
+Example:
+goalign draw svg -i alignment.fa --scheme zappo -s 100 -l 200 -o alignment.svg
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		return drawAlignFiles(drawSVGWidth, draw.NewSVGLayout)
+	},
+}
+
+func init() {
+	drawCmd.AddCommand(svgCmd)
+	addDrawFlags(svgCmd, &drawSVGWidth, 100)
+	addDrawTracksFlag(svgCmd)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/draw"
+	"github.com/evolbioinfo/goalign/io"
+)
+
+var drawTerminalWidth int
+
+// terminalCmd represents the draw terminal command
+var terminalCmd = &cobra.Command{
+	Use:   "terminal",
+	Short: "Draw alignments in the terminal, with colors",
+	Long: `Draw alignments in the terminal, with colors
+
+The alignment is displayed in pages (blocks) of --width sites. Each page
+starts with a column ruler (1-based coordinates, a '+' every 5 sites and a '|'
+every 10 sites), and ends with consensus and conservation (clustal symbols: '*'
+identical, ':' conserved, '.' semi-conserved) tracks, unless --no-tracks is given.
+
+Characters are colored with 24 bits ANSI escape codes, according to the color
+scheme (--scheme):
+- auto       : nucleotide for nucleotide alignments, clustal for amino acids
+- clustal    : Clustal X residue colors
+- zappo      : Zappo colors (physico-chemical properties)
+- nucleotide : A, C, G and T/U colors
+- none       : no color (no escape code)
+
+A region of the alignment may be selected with --start and --length.
+
+Long alignments may be browsed with a pager, for example:
+goalign draw terminal -i alignment.fa | less -R
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var aligns *align.AlignChannel
+		var f *os.File
+
+		if f, err = openWriteFile(drawOutput); err != nil {
+			io.LogError(err)
+			return
+		}
+		defer closeWriteFile(f, drawOutput)
+
+		if aligns, err = readalign(infile); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		w := bufio.NewWriter(f)
+		l := draw.NewTerminalLayout(w, drawOptions(drawTerminalWidth))
+		nalign := 0
+		for al := range aligns.Achan {
+			if nalign > 0 {
+				if _, err = w.WriteString("\n"); err != nil {
+					io.LogError(err)
+					return
+				}
+			}
+			if err = l.DrawAlign(al); err != nil {
+				io.LogError(err)
+				return
+			}
+			nalign++
+		}
+
+		if aligns.Err != nil {
+			err = aligns.Err
+			io.LogError(err)
+			return
+		}
+		if err = w.Flush(); err != nil {
+			io.LogError(err)
+		}
+		return
+	},
+}
+
+func init() {
+	drawCmd.AddCommand(terminalCmd)
+	addDrawFlags(terminalCmd, &drawTerminalWidth, 60)
+	addDrawTracksFlag(terminalCmd)
+}
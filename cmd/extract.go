@@ -5,25 +5,30 @@ import (
 	"compress/gzip"
 	"errors"
 	"fmt"
+	goio "io"
 	"os"
 	"strconv"
 	"strings"
 
 	"github.com/evolbioinfo/goalign/align"
 	"github.com/evolbioinfo/goalign/io"
+	"github.com/evolbioinfo/goalign/io/feature"
 
 	"github.com/evolbioinfo/goalign/io/utils"
 	"github.com/spf13/cobra"
 )
 
 type extractSubSequence struct {
-	starts []int
-	ends   []int
-	name   string
+	starts  []int
+	ends    []int
+	reverse []bool // Blocks to reverse complement (reverse strand features)
+	name    string
 }
 
 var extractrefseq string
 var extractcoordfile string
+var extractfeaturefile string
+var extractfeaturetypes string
 var extractoutput string
 var extracttranslate int
 
@@ -81,6 +86,19 @@ var extractCmd = &cobra.Command{
 	For example:
 	goalign extract -i alignment.fasta -f annotations.txt
 	
+	Instead of a coordinate file, sub-alignments may be defined by the features of a GenBank,
+	EMBL or GFF3 annotation file of the reference sequence (--features, format detected
+	automatically), usually with --ref-seq. Only features of the types given by --feature-types
+	(default CDS) are extracted:
+	- Each feature is extracted in a file named after its gene, locus_tag, protein_id or label
+	  qualifier (GenBank/EMBL) or its Name, gene, locus_tag or ID attribute (GFF3), or named
+	  <type>_<index> if it has none. Duplicate names are suffixed with _<n>;
+	- Joined features (or GFF3 lines sharing the same ID) are extracted as multi-block sub-alignments;
+	- Features on the reverse strand are reverse complemented.
+	
+	For example:
+	goalign extract -i genomes.fasta --features ref.gb --ref-seq ref -o genes
+	
 	If the input file contains several alignments, only the first one is considered.
 `,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
@@ -89,8 +107,9 @@ var extractCmd = &cobra.Command{
 		var subcoords []extractSubSequence
 		var subalign, subaligntmp align.Alignment
 
-		if extractcoordfile == "none" {
-			err = fmt.Errorf("subsequence coordinate file should be specified")
+		if (extractcoordfile == "none") == (extractfeaturefile == "none") {
+			err = fmt.Errorf("either a subsequence coordinate file or a feature file should be specified")
+			io.LogError(err)
 			return
 		}
 
@@ -99,7 +118,12 @@ var extractCmd = &cobra.Command{
 			return
 		}
 
-		if subcoords, err = parseCoordinateFile(extractcoordfile); err != nil {
+		if extractfeaturefile != "none" {
+			subcoords, err = parseFeatureFile(extractfeaturefile, extractfeaturetypes)
+		} else {
+			subcoords, err = parseCoordinateFile(extractcoordfile)
+		}
+		if err != nil {
 			io.LogError(err)
 			return
 		}
@@ -140,6 +164,12 @@ var extractCmd = &cobra.Command{
 					io.LogError(err)
 					return
 				}
+				if subseq.reverse[i] {
+					if err = subaligntmp.ReverseComplement(); err != nil {
+						io.LogError(err)
+						return
+					}
+				}
 
 				if subalign == nil {
 					subalign = subaligntmp
@@ -157,7 +187,10 @@ var extractCmd = &cobra.Command{
 				io.LogError(err)
 				return
 			}
-			writeAlign(subalign, f)
+			if err = writeAlign(subalign, f); err != nil {
+				io.LogError(err)
+				return
+			}
 			f.Close()
 		}
 
@@ -171,6 +204,8 @@ func init() {
 	extractCmd.PersistentFlags().IntVar(&extracttranslate, "translate", -1, "Wether the extracted sequence will be translated (only if input alignment is nucleotide). <0: No translation, 0: Std code, 1: Vertebrate mito, 2: Invertebrate mito")
 	extractCmd.PersistentFlags().StringVarP(&extractoutput, "output", "o", ".", "Output folder")
 	extractCmd.PersistentFlags().StringVar(&extractcoordfile, "coordinates", "none", "File with all coordinates of the sequences to extract")
+	extractCmd.PersistentFlags().StringVar(&extractfeaturefile, "features", "none", "GenBank, EMBL or GFF3 annotation file of the reference sequence, defining the sequences to extract (instead of --coordinates)")
+	extractCmd.PersistentFlags().StringVar(&extractfeaturetypes, "feature-types", "CDS", "Coma separated types of the features to extract (only with --features)")
 }
 
 func parseCoordinateFile(file string) (coords []extractSubSequence, err error) {
@@ -205,9 +240,10 @@ func parseCoordinateFile(file string) (coords []extractSubSequence, err error) {
 			return
 		}
 		subseq := extractSubSequence{
-			starts: make([]int, 0),
-			ends:   make([]int, 0),
-			name:   cols[2],
+			starts:  make([]int, 0),
+			ends:    make([]int, 0),
+			reverse: make([]bool, 0),
+			name:    cols[2],
 		}
 
 		startstr := strings.Split(cols[0], ",")
@@ -227,6 +263,7 @@ func parseCoordinateFile(file string) (coords []extractSubSequence, err error) {
 				return
 			}
 			subseq.ends = append(subseq.ends, si)
+			subseq.reverse = append(subseq.reverse, false)
 		}
 		coords = append(coords, subseq)
 		l, e = utils.Readln(r)
@@ -234,3 +271,57 @@ func parseCoordinateFile(file string) (coords []extractSubSequence, err error) {
 
 	return
 }
+
+// parseFeatureFile parses the features of the given types (coma separated) from a
+// GenBank, EMBL or GFF3 file, and returns their coordinates
+func parseFeatureFile(file string, types string) (coords []extractSubSequence, err error) {
+	var fi goio.Closer
+	var r *bufio.Reader
+	var features []feature.Feature
+
+	if fi, r, err = utils.GetReader(file); err != nil {
+		return
+	}
+	defer fi.Close()
+
+	if features, err = feature.Parse(r); err != nil {
+		return
+	}
+
+	keep := make(map[string]bool)
+	for _, t := range strings.Split(types, ",") {
+		keep[strings.TrimSpace(t)] = true
+	}
+	names := make(map[string]int)
+	coords = make([]extractSubSequence, 0)
+	for _, f := range features {
+		if !keep[f.Type] {
+			continue
+		}
+		name := f.Name
+		if name == "" {
+			name = fmt.Sprintf("%s_%d", f.Type, len(coords)+1)
+		}
+		// Names are used as file names
+		name = strings.Map(func(r rune) rune {
+			if r == os.PathSeparator || r == ' ' || r == '\t' {
+				return '_'
+			}
+			return r
+		}, name)
+		if names[name]++; names[name] > 1 {
+			name = fmt.Sprintf("%s_%d", name, names[name])
+		}
+		subseq := extractSubSequence{name: name}
+		for _, b := range f.Blocks {
+			subseq.starts = append(subseq.starts, b.Start)
+			subseq.ends = append(subseq.ends, b.End)
+			subseq.reverse = append(subseq.reverse, b.Reverse)
+		}
+		coords = append(coords, subseq)
+	}
+	if len(coords) == 0 {
+		err = fmt.Errorf("no feature of type %s in the annotation file", types)
+	}
+	return
+}
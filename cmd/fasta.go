@@ -43,7 +43,10 @@ goalign reformat fasta -i align.fasta
 			if reformatCleanNames {
 				seqs.CleanNames(nil)
 			}
-			writeSequences(seqs, f)
+			if err = writeSequences(seqs, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		} else {
 			var aligns *align.AlignChannel
 
@@ -61,7 +64,10 @@ goalign reformat fasta -i align.fasta
 			if reformatCleanNames {
 				a.CleanNames(nil)
 			}
-			writeAlignFasta(a, f)
+			if err = writeAlignFasta(a, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 		return
 	},
@@ -99,7 +99,10 @@ The output format is the same than input format.
 					return
 				}
 			}
-			writeAlign(al, f)
+			if err = writeAlign(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 		f.Close()
 
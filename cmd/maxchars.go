@@ -28,8 +28,18 @@ goalign stats maxchar -i align.fasta
 `,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		var aligns *align.AlignChannel
+		var sa align.SiteAlignment
 		maxCharIgnoreGaps = maxCharIgnoreGaps || maxCharExcludeGaps
 
+		if streamable() {
+			if sa, err = readsitealign(infile); err != nil {
+				io.LogError(err)
+				return
+			}
+			printMaxCharStats(sa, maxCharIgnoreGaps, maxCharIgnoreNs)
+			return
+		}
+
 		if aligns, err = readalign(infile); err != nil {
 			io.LogError(err)
 			return
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/io"
+	"github.com/spf13/cobra"
+)
+
+var msaOutput string
+var msaGuide string
+
+// msaCmd represents the align command
+var msaCmd = &cobra.Command{
+	Use:   "align",
+	Short: "Aligns a set of sequences (progressive multiple sequence alignment)",
+	Long: `Aligns a set of sequences (progressive multiple sequence alignment).
+
+Input : Fasta file (unaligned sequences, gaps are removed)
+Output: Aligned file (format depending on format options)
+
+To do so, it will:
+1. Compute distances between all pairs of sequences, depending on --guide:
+   - kmer (default): fraction of shared k-mers (k=4 for nucleotides, k=2 for amino acids);
+   - pairwise: 1-identity of global pairwise alignments;
+2. Build a UPGMA guide tree from these distances;
+3. Align sequences progressively along the guide tree, using global
+   profile-profile alignments.
+
+If neither --match nor --mismatch are specified, then match and mismatch scores
+are taken from blosum62 or dnafull substitution matrices (taken from EMBOSS WATER)
+depending on the input sequences alphabets.
+
+Score for opening a gap is specified by --gap-open option and score for extending a gap is
+specified by --gap-extend option (they should be negative).
+
+Distances are computed using --threads threads.
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var seqs align.SeqBag
+		var al align.Alignment
+		var f *os.File
+		var guide int
+
+		switch msaGuide {
+		case "kmer":
+			guide = align.MSA_DIST_KMER
+		case "pairwise":
+			guide = align.MSA_DIST_PAIRWISE
+		default:
+			err = fmt.Errorf("unknown guide tree distance : %s", msaGuide)
+			io.LogError(err)
+			return
+		}
+
+		if f, err = openWriteFile(msaOutput); err != nil {
+			io.LogError(err)
+			return
+		}
+		defer closeWriteFile(f, msaOutput)
+
+		if seqs, err = readsequences(infile); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		aligner := align.NewMultipleAligner()
+		aligner.SetGapOpenScore(gapopen)
+		aligner.SetGapExtendScore(gapextend)
+		aligner.SetCpus(rootcpus)
+		if err = aligner.SetGuideDistance(guide); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		if cmd.Flags().Changed("mismatch") || cmd.Flags().Changed("match") {
+			aligner.SetScore(match, mismatch)
+		}
+
+		if al, err = aligner.Align(seqs); err != nil {
+			io.LogError(err)
+			return
+		}
+		if err = writeAlign(al, f); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		return
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(msaCmd)
+	msaCmd.PersistentFlags().StringVarP(&msaOutput, "output", "o", "stdout", "Alignment output file")
+	msaCmd.PersistentFlags().StringVar(&msaGuide, "guide", "kmer", "Distance used to build the guide tree: kmer or pairwise")
+	msaCmd.PersistentFlags().Float64Var(&gapopen, "gap-open", -10.0, "Score for opening a gap ")
+	msaCmd.PersistentFlags().Float64Var(&gapextend, "gap-extend", -0.5, "Score for extending a gap ")
+	msaCmd.PersistentFlags().Float64Var(&match, "match", 1.0, "Score for a match (if omitted, then take substitution matrix)")
+	msaCmd.PersistentFlags().Float64Var(&mismatch, "mismatch", -1.0, "Score for a mismatch (if omitted, then take substitution matrix)")
+}
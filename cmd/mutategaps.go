@@ -33,7 +33,10 @@ var gapsCmd = &cobra.Command{
 
 		for al := range aligns.Achan {
 			al.Mutate(mutateRate)
-			writeAlign(al, f)
+			if err = writeAlign(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 
 		if aligns.Err != nil {
@@ -62,7 +62,10 @@ Id -a is given, then names are generated with the pattern "S000<i>".
 					return
 				}
 			}
-			writeSequences(seqs, f)
+			if err = writeSequences(seqs, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		} else {
 			if aligns, err = readalign(infile); err != nil {
 				io.LogError(err)
@@ -86,7 +89,10 @@ Id -a is given, then names are generated with the pattern "S000<i>".
 						return
 					}
 				}
-				writeAlign(al, f)
+				if err = writeAlign(al, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 
 			if aligns.Err != nil {
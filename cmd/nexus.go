@@ -41,7 +41,10 @@ goalign reformat nexus -i align.fasta
 			if reformatCleanNames {
 				al.CleanNames(nil)
 			}
-			writeAlignNexus(al, f)
+			if err = writeAlignNexus(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 
 		if aligns.Err != nil {
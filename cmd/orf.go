@@ -10,6 +10,8 @@ import (
 
 var orfOutput string
 var orfreverse bool
+var orfGeneticCode string
+var orfAltStarts bool
 
 // translateCmd represents the addid command
 var orfCmd = &cobra.Command{
@@ -17,6 +19,11 @@ var orfCmd = &cobra.Command{
 	Short: "Find the longest orf in all given sequences in forward strand",
 	Long: `Find the longest orf in all given sequences in forward strand.
 
+Start and stop codons are taken from the genetic code given with --genetic-code
+(standard by default). Only ATG is considered as start codon, unless --alt-starts
+is given: in that case, all start codons of the genetic code (NCBI translation table)
+are considered.
+
 If input sequences are not nucleotidic, then returns an error.
 If input sequences are aligned (contain '-'), then they are unaligned first.
 
@@ -27,6 +34,7 @@ Output is in fasta format.
 		var reforf align.SeqBag
 		var inseqs align.SeqBag
 		var orf align.Sequence
+		var geneticcode int
 
 		if f, err = openWriteFile(orfOutput); err != nil {
 			io.LogError(err)
@@ -41,14 +49,22 @@ Output is in fasta format.
 
 		inseqs = inseqs.Unalign()
 
-		if orf, err = inseqs.LongestORF(orfreverse); err != nil {
+		if geneticcode, err = geneticCodeFromString(orfGeneticCode); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		if orf, err = inseqs.LongestORF(orfreverse, geneticcode, orfAltStarts); err != nil {
 			io.LogError(err)
 			return
 		}
 		reforf = align.NewSeqBag(align.UNKNOWN)
 		reforf.AddSequenceChar(orf.Name(), orf.SequenceChar(), orf.Comment())
 		reforf.AutoAlphabet()
-		writeSequences(reforf, f)
+		if err = writeSequences(reforf, f); err != nil {
+			io.LogError(err)
+			return
+		}
 
 		return
 	},
@@ -58,4 +74,6 @@ func init() {
 	RootCmd.AddCommand(orfCmd)
 	orfCmd.PersistentFlags().StringVarP(&orfOutput, "output", "o", "stdout", "ORF Output Fasta File")
 	orfCmd.PersistentFlags().BoolVar(&orfreverse, "reverse", false, "Search for the longest ORF ALSO in the reverse strand")
+	orfCmd.PersistentFlags().StringVar(&orfGeneticCode, "genetic-code", "standard", geneticCodeUsage)
+	orfCmd.PersistentFlags().BoolVar(&orfAltStarts, "alt-starts", false, "Considers alternative start codons of the genetic code (otherwise only ATG)")
 }
@@ -40,7 +40,10 @@ goalign reformat paml -i align.fasta
 			if reformatCleanNames {
 				al.CleanNames(nil)
 			}
-			writeAlignPaml(al, f)
+			if err = writeAlignPaml(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 
 		if aligns.Err != nil {
@@ -13,12 +13,14 @@ import (
 var phaseOutput string
 var phaseAAOutput string
 var phaseGeneticCode string
+var phaseAltStarts bool
 var phaseLogOutput string
 var orfsequence string
 var lencutoff float64
 var matchcutoff float64
 var phasereverse bool
 var phasecutend bool
+var phaseAlignMode string
 
 // translateCmd represents the addid command
 var phaseCmd = &cobra.Command{
@@ -61,6 +63,7 @@ Output file is an unaligned set of sequences in fasta.
 		var reforf align.SeqBag
 		var orf align.Sequence
 		var geneticcode int
+		var algo int
 
 		if f, err = openWriteFile(phaseOutput); err != nil {
 			io.LogError(err)
@@ -89,6 +92,11 @@ Output file is an unaligned set of sequences in fasta.
 			inseqs = (<-aligns.Achan).Unalign()
 		}
 
+		if geneticcode, err = geneticCodeFromString(phaseGeneticCode); err != nil {
+			io.LogError(err)
+			return
+		}
+
 		if orfsequence != "none" {
 			if reforf, err = readsequences(orfsequence); err != nil {
 				io.LogError(err)
@@ -101,7 +109,7 @@ Output file is an unaligned set of sequences in fasta.
 			}
 		} else {
 			// We detect the orf
-			if orf, err = inseqs.LongestORF(phasereverse); err != nil {
+			if orf, err = inseqs.LongestORF(phasereverse, geneticcode, phaseAltStarts); err != nil {
 				io.LogError(err)
 				return
 			}
@@ -111,15 +119,8 @@ Output file is an unaligned set of sequences in fasta.
 			reforf.AutoAlphabet()
 		}
 
-		switch phaseGeneticCode {
-		case "standard":
-			geneticcode = align.GENETIC_CODE_STANDARD
-		case "mitov":
-			geneticcode = align.GENETIC_CODE_VETEBRATE_MITO
-		case "mitoi":
-			geneticcode = align.GENETIC_CODE_INVETEBRATE_MITO
-		default:
-			err = fmt.Errorf("unknown genetic code : %s", phaseGeneticCode)
+		if algo, err = alignAlgo(phaseAlignMode, align.ALIGN_ALGO_ATG); err != nil {
+			io.LogError(err)
 			return
 		}
 
@@ -129,9 +130,14 @@ Output file is an unaligned set of sequences in fasta.
 		phaser.SetReverse(phasereverse)
 		phaser.SetCutEnd(phasecutend)
 		phaser.SetCpus(rootcpus)
+		phaser.SetAltStarts(phaseAltStarts)
 		phaser.SetTranslate(true, geneticcode)
 		phaser.SetGapOpen(gapopen)
 		phaser.SetGapExtend(gapextend)
+		if err = phaser.SetAlignAlgo(algo); err != nil {
+			io.LogError(err)
+			return
+		}
 
 		if cmd.Flags().Changed("mismatch") || cmd.Flags().Changed("match") {
 			phaser.SetAlignScores(match, mismatch)
@@ -167,8 +173,14 @@ Output file is an unaligned set of sequences in fasta.
 			}
 		}
 
-		writeSequences(phasedseqs, f)
-		writeSequences(phasedseqsaa, aaf)
+		if err = writeSequences(phasedseqs, f); err != nil {
+			io.LogError(err)
+			return
+		}
+		if err = writeSequences(phasedseqsaa, aaf); err != nil {
+			io.LogError(err)
+			return
+		}
 
 		return
 	},
@@ -177,7 +189,7 @@ Output file is an unaligned set of sequences in fasta.
 func init() {
 	RootCmd.AddCommand(phaseCmd)
 	phaseCmd.PersistentFlags().StringVarP(&phaseOutput, "output", "o", "stdout", "Output \"phased\" FASTA file")
-	phaseCmd.PersistentFlags().StringVar(&phaseGeneticCode, "genetic-code", "standard", "Genetic Code: standard, mitoi (invertebrate mitochondrial) or mitov (vertebrate mitochondrial)")
+	phaseCmd.PersistentFlags().StringVar(&phaseGeneticCode, "genetic-code", "standard", geneticCodeUsage)
 	phaseCmd.PersistentFlags().StringVar(&phaseAAOutput, "aa-output", "none", "Output Met \"phased\" aa FASTA file")
 	phaseCmd.PersistentFlags().StringVarP(&phaseLogOutput, "log", "l", "none", "Output log: positions of the considered Start for each sequence")
 	phaseCmd.PersistentFlags().Float64Var(&lencutoff, "len-cutoff", -1.0, "Length cutoff, over orf length, to consider sequence hits (-1==No cutoff)")
@@ -190,4 +202,6 @@ func init() {
 	phaseCmd.PersistentFlags().BoolVar(&phasereverse, "reverse", false, "Search ALSO in the reverse strand (in addition to the forward strand)")
 	phaseCmd.PersistentFlags().BoolVar(&phasecutend, "cut-end", false, "Iftrue, then also remove the end of sequences that do not align with orf")
 	phaseCmd.PersistentFlags().StringVar(&orfsequence, "ref-orf", "none", "Reference ORF to phase against (if none is given, then will try to get the longest orf in the input data)")
+	phaseCmd.PersistentFlags().BoolVar(&phaseAltStarts, "alt-starts", false, "Considers alternative start codons of the genetic code when searching the longest ORF (otherwise only ATG)")
+	phaseCmd.PersistentFlags().StringVar(&phaseAlignMode, "align-mode", "local", "Pairwise alignment mode: local (starting at the start of the orf), global or semiglobal (no end gap penalty)")
 }
@@ -53,6 +53,7 @@ Output file is an unaligned set of sequences in fasta.
 		var reforf align.SeqBag
 		var orf align.Sequence
 		var geneticcode int
+		var algo int
 
 		if f, err = openWriteFile(phaseOutput); err != nil {
 			io.LogError(err)
@@ -93,6 +94,11 @@ Output file is an unaligned set of sequences in fasta.
 			inseqs = (<-aligns.Achan).Unalign()
 		}
 
+		if geneticcode, err = geneticCodeFromString(phaseGeneticCode); err != nil {
+			io.LogError(err)
+			return
+		}
+
 		if orfsequence != "none" {
 			if reforf, err = readsequences(orfsequence); err != nil {
 				io.LogError(err)
@@ -105,7 +111,7 @@ Output file is an unaligned set of sequences in fasta.
 			}
 		} else {
 			// We detect the orf
-			if orf, err = inseqs.LongestORF(phasereverse); err != nil {
+			if orf, err = inseqs.LongestORF(phasereverse, geneticcode, phaseAltStarts); err != nil {
 				io.LogError(err)
 				return
 			}
@@ -115,15 +121,8 @@ Output file is an unaligned set of sequences in fasta.
 			reforf.AutoAlphabet()
 		}
 
-		switch phaseGeneticCode {
-		case "standard":
-			geneticcode = align.GENETIC_CODE_STANDARD
-		case "mitov":
-			geneticcode = align.GENETIC_CODE_VETEBRATE_MITO
-		case "mitoi":
-			geneticcode = align.GENETIC_CODE_INVETEBRATE_MITO
-		default:
-			err = fmt.Errorf("unknown genetic code : %s", phaseGeneticCode)
+		if algo, err = alignAlgo(phaseAlignMode, align.ALIGN_ALGO_ATG); err != nil {
+			io.LogError(err)
 			return
 		}
 
@@ -133,9 +132,14 @@ Output file is an unaligned set of sequences in fasta.
 		phaser.SetReverse(phasereverse)
 		phaser.SetCutEnd(phasecutend)
 		phaser.SetCpus(rootcpus)
+		phaser.SetAltStarts(phaseAltStarts)
 		phaser.SetTranslate(false, geneticcode)
 		phaser.SetGapOpen(gapopen)
 		phaser.SetGapExtend(gapextend)
+		if err = phaser.SetAlignAlgo(algo); err != nil {
+			io.LogError(err)
+			return
+		}
 
 		if cmd.Flags().Changed("mismatch") || cmd.Flags().Changed("match") {
 			phaser.SetAlignScores(match, mismatch)
@@ -188,9 +192,18 @@ Output file is an unaligned set of sequences in fasta.
 			}
 		}
 
-		writeSequences(phasedseqs, f)
-		writeSequences(phasedseqsaa, aaf)
-		writeSequences(phasedcodonseqs, codonf)
+		if err = writeSequences(phasedseqs, f); err != nil {
+			io.LogError(err)
+			return
+		}
+		if err = writeSequences(phasedseqsaa, aaf); err != nil {
+			io.LogError(err)
+			return
+		}
+		if err = writeSequences(phasedcodonseqs, codonf); err != nil {
+			io.LogError(err)
+			return
+		}
 
 		return
 	},
@@ -200,7 +213,7 @@ func init() {
 	RootCmd.AddCommand(phasentCmd)
 	phasentCmd.PersistentFlags().StringVarP(&phaseOutput, "output", "o", "stdout", "Output ATG \"phased\" FASTA file")
 	phasentCmd.PersistentFlags().StringVar(&phaseCodonOutput, "nt-output", "none", "Output ATG \"phased\" FASTA file + first nts not in ref phase removed (nt corresponding to aa-output sequence)")
-	phasentCmd.PersistentFlags().StringVar(&phaseGeneticCode, "genetic-code", "standard", "Genetic Code: standard, mitoi (invertebrate mitochondrial) or mitov (vertebrate mitochondrial)")
+	phasentCmd.PersistentFlags().StringVar(&phaseGeneticCode, "genetic-code", "standard", geneticCodeUsage)
 	phasentCmd.PersistentFlags().StringVar(&phaseAAOutput, "aa-output", "none", "Output translated sequences FASTA file")
 	phasentCmd.PersistentFlags().StringVarP(&phaseLogOutput, "log", "l", "none", "Output log: positions of the considered ATG for each sequence")
 	phasentCmd.PersistentFlags().Float64Var(&lencutoff, "len-cutoff", -1.0, "Length cutoff, over orf length, to consider sequence hits (-1==No cutoff)")
@@ -213,4 +226,6 @@ func init() {
 	phasentCmd.PersistentFlags().BoolVar(&phasereverse, "reverse", false, "Search ALSO in the reverse strand (in addition to the forward strand)")
 	phasentCmd.PersistentFlags().BoolVar(&phasecutend, "cut-end", false, "If true, then also remove the end of sequences that do not align with orf")
 	phasentCmd.PersistentFlags().StringVar(&orfsequence, "ref-orf", "none", "Reference ORF to phase against (if none is given, then will try to get the longest orf in the input data)")
+	phasentCmd.PersistentFlags().BoolVar(&phaseAltStarts, "alt-starts", false, "Considers alternative start codons of the genetic code when searching the longest ORF (otherwise only ATG)")
+	phasentCmd.PersistentFlags().StringVar(&phaseAlignMode, "align-mode", "local", "Pairwise alignment mode: local (starting at the start of the orf), global or semiglobal (no end gap penalty)")
 }
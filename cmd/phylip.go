@@ -41,7 +41,10 @@ goalign reformat phylip -i align.fasta
 			if reformatCleanNames {
 				al.CleanNames(nil)
 			}
-			writeAlignPhylip(al, f)
+			if err = writeAlignPhylip(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 
 		if aligns.Err != nil {
@@ -35,16 +35,33 @@ Possible to log2 transform the (normalized) value with --log (-l). Not taken int
 `,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		var aligns *align.AlignChannel
+		var sa align.SiteAlignment
 		var pssm map[uint8][]float64
 		var pssmstring string
 
-		if aligns, err = readalign(infile); err != nil {
-			io.LogError(err)
-			return
-		}
-
 		switch pssmnorm {
 		case align.PSSM_NORM_UNIF, align.PSSM_NORM_NONE, align.PSSM_NORM_FREQ, align.PSSM_NORM_DATA, align.PSSM_NORM_LOGO:
+			if streamable() {
+				if sa, err = readsitealign(infile); err != nil {
+					io.LogError(err)
+					return
+				}
+				if pssm, err = sa.Pssm(pssmlog, pssmpseudocount, pssmnorm); err != nil {
+					io.LogError(err)
+					return
+				}
+				if pssmstring, err = printPSSM(sa, pssm); err != nil {
+					io.LogError(err)
+					return
+				}
+				fmt.Fprint(os.Stdout, pssmstring)
+				return
+			}
+
+			if aligns, err = readalign(infile); err != nil {
+				io.LogError(err)
+				return
+			}
 			for al := range aligns.Achan {
 				if pssm, err = al.Pssm(pssmlog, pssmpseudocount, pssmnorm); err != nil {
 					io.LogError(err)
@@ -79,7 +96,7 @@ func init() {
 	pssmCmd.PersistentFlags().IntVarP(&pssmnorm, "normalization", "n", 0, "Counts normalization")
 }
 
-func printPSSM(a align.Alignment, pssm map[uint8][]float64) (pssmstring string, err error) {
+func printPSSM(a align.SiteAlignment, pssm map[uint8][]float64) (pssmstring string, err error) {
 	var buffer bytes.Buffer
 	size := -1
 	for _, c := range a.AlphabetCharacters() {
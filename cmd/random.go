@@ -39,7 +39,10 @@ var randomCmd = &cobra.Command{
 				return
 			}
 		}
-		writeAlign(a, f)
+		if err = writeAlign(a, f); err != nil {
+			io.LogError(err)
+			return
+		}
 
 		return
 	},
@@ -72,7 +72,10 @@ Output: An alignment (phylip or fasta).
 					io.LogError(err)
 					return
 				}
-				writeSequences(sample, f)
+				if err = writeSequences(sample, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 		} else {
 			var aligns *align.AlignChannel
@@ -92,7 +95,10 @@ Output: An alignment (phylip or fasta).
 						io.LogError(err)
 						return
 					}
-					writeAlign(sample, f)
+					if err = writeAlign(sample, f); err != nil {
+						io.LogError(err)
+						return
+					}
 				}
 			}
 
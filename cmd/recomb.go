@@ -54,7 +54,10 @@ goalign shuffle recomb -i align.fasta -r 0.5 -n 1 -l 0.5
 
 		for al := range aligns.Achan {
 			al.Recombine(recombNb, recombProp)
-			writeAlign(al, f)
+			if err = writeAlign(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 
 		if aligns.Err != nil {
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/io"
+	"github.com/evolbioinfo/goalign/recombination"
+)
+
+var recombdetectOutput string
+var recombdetectScanOutput string
+var recombdetectPhiPerms int
+var recombdetectMaxChiPerms int
+var recombdetectPhiWindow int
+var recombdetectScanWindow int
+
+// recombdetectCmd represents the recombdetect command
+var recombdetectCmd = &cobra.Command{
+	Use:   "recombdetect",
+	Short: "Detects recombination in a nucleotide alignment",
+	Long: `Detects recombination in a nucleotide alignment
+
+It runs two tests:
+1. The pairwise homoplasy index test (PHI, Bruen, Philippe & Bryant 2006):
+   mean refined incompatibility between informative sites separated by at most
+   --phi-window informative sites. The p-value is computed with --perms random
+   permutations of the informative sites (a low PHI means that incompatible
+   sites are close to each other, which is expected under recombination);
+2. The maximum chi-squared test (Maynard Smith 1992): for each pair of sequences
+   and each breakpoint, compares the proportion of differences on both sides of
+   the breakpoint. The p-value is computed with --maxchi-perms random permutations
+   of the polymorphic sites.
+
+Gaps and ambiguous nucleotides are not taken into account.
+
+It prints one line per input alignment, in a tab separated form, with the
+following columns:
+- Alignment        : index of the alignment in the input file
+- InformativeSites : number of informative sites used by the PHI test
+- Phi              : observed PHI
+- PhiMean          : mean of PHI over permutations
+- PhiVar           : variance of PHI over permutations
+- PhiPValue        : PHI test p-value
+- MaxChi2          : maximum chi2 over all pairs of sequences and breakpoints
+- MaxChi2Seq1      : first sequence of the pair giving the maximum chi2
+- MaxChi2Seq2      : second sequence of the pair giving the maximum chi2
+- MaxChi2Site      : alignment site (0-based) right after the best breakpoint
+- MaxChi2PValue    : maximum chi2 test p-value
+
+If --scan-output is given, it also writes the results of a sliding window scan:
+for each breakpoint between two polymorphic sites, the maximum chi2 between the
+--scan-window polymorphic sites on its left and the --scan-window polymorphic sites
+on its right, with columns: Alignment, Site (0-based alignment site right after
+the breakpoint), MaxChi2, Seq1 and Seq2.
+
+Permutations are computed in parallel using -t threads. Results are
+reproducible with a given --seed.
+
+Example:
+goalign recombdetect -i alignment.fa --perms 1000 --scan-output scan.txt --seed 123
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var f, fscan *os.File
+		var aligns *align.AlignChannel
+
+		if f, err = openWriteFile(recombdetectOutput); err != nil {
+			io.LogError(err)
+			return
+		}
+		defer closeWriteFile(f, recombdetectOutput)
+
+		if recombdetectScanOutput != "none" {
+			if fscan, err = openWriteFile(recombdetectScanOutput); err != nil {
+				io.LogError(err)
+				return
+			}
+			defer closeWriteFile(fscan, recombdetectScanOutput)
+			fscan.WriteString("Alignment\tSite\tMaxChi2\tSeq1\tSeq2\n")
+		}
+
+		if aligns, err = readalign(infile); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		f.WriteString("Alignment\tInformativeSites\tPhi\tPhiMean\tPhiVar\tPhiPValue\tMaxChi2\tMaxChi2Seq1\tMaxChi2Seq2\tMaxChi2Site\tMaxChi2PValue\n")
+		nb := 0
+		for al := range aligns.Achan {
+			var phi recombination.PhiResult
+			var maxchi recombination.MaxChi2Result
+			var breakpoints []recombination.Breakpoint
+
+			if phi, err = recombination.Phi(al, recombdetectPhiWindow, recombdetectPhiPerms, rootcpus); err != nil {
+				io.LogError(err)
+				return
+			}
+			if maxchi, err = recombination.MaxChi2(al, recombdetectMaxChiPerms, rootcpus); err != nil {
+				io.LogError(err)
+				return
+			}
+			f.WriteString(fmt.Sprintf("%d\t%d\t%.4f\t%.4f\t%.4g\t%.4g\t%.4f\t%s\t%s\t%s\t%.4g\n",
+				nb, phi.NbSites, phi.Phi, phi.PermMean, phi.PermVar, phi.PValue,
+				maxchi.MaxChi2, recombSeqName(al, maxchi.Seq1), recombSeqName(al, maxchi.Seq2),
+				recombSite(maxchi.Site), maxchi.PValue))
+
+			if fscan != nil {
+				if breakpoints, err = recombination.MaxChi2Scan(al, recombdetectScanWindow, rootcpus); err != nil {
+					io.LogError(err)
+					return
+				}
+				for _, b := range breakpoints {
+					fscan.WriteString(fmt.Sprintf("%d\t%d\t%.4f\t%s\t%s\n", nb, b.Site, b.Chi2,
+						recombSeqName(al, b.Seq1), recombSeqName(al, b.Seq2)))
+				}
+			}
+			nb++
+		}
+
+		if aligns.Err != nil {
+			err = aligns.Err
+			io.LogError(err)
+		}
+		return
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(recombdetectCmd)
+	recombdetectCmd.PersistentFlags().StringVarP(&recombdetectOutput, "output", "o", "stdout", "Test results output file")
+	recombdetectCmd.PersistentFlags().StringVar(&recombdetectScanOutput, "scan-output", "none", "Sliding window max chi2 scan output file")
+	recombdetectCmd.PersistentFlags().IntVar(&recombdetectPhiPerms, "perms", 1000, "Number of permutations for the PHI test")
+	recombdetectCmd.PersistentFlags().IntVar(&recombdetectMaxChiPerms, "maxchi-perms", 100, "Number of permutations for the max chi2 test")
+	recombdetectCmd.PersistentFlags().IntVar(&recombdetectPhiWindow, "phi-window", 100, "PHI window size (in informative sites)")
+	recombdetectCmd.PersistentFlags().IntVar(&recombdetectScanWindow, "scan-window", 20, "Half window size of the max chi2 scan (in polymorphic sites)")
+}
+
+// Name of the ith sequence of the alignment, "NA" if i<0
+func recombSeqName(al align.Alignment, i int) string {
+	if i < 0 {
+		return "NA"
+	}
+	name, _ := al.GetSequenceNameById(i)
+	return name
+}
+
+// Site index, "NA" if site<0
+func recombSite(site int) string {
+	if site < 0 {
+		return "NA"
+	}
+	return fmt.Sprintf("%d", site)
+}
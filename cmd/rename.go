@@ -83,7 +83,25 @@ while ignoring formatting options (phylip, etc.).
 			namemap = make(map[string]string)
 		}
 
-		if unaligned {
+		if streamable() {
+			err = readsequencestream(infile, !unaligned, func(al align.Alignment) (err error) {
+				if renameCleanNames {
+					al.CleanNames(namemap)
+				} else if setregex {
+					if err = al.RenameRegexp(renameRegexp, renameReplace, namemap); err != nil {
+						return
+					}
+				} else {
+					al.Rename(namemap)
+				}
+				err = writeStreamAlign(al, f)
+				return
+			})
+			if err != nil {
+				io.LogError(err)
+				return
+			}
+		} else if unaligned {
 			var seqs align.SeqBag
 
 			if seqs, err = readsequences(infile); err != nil {
@@ -100,7 +118,10 @@ while ignoring formatting options (phylip, etc.).
 			} else {
 				seqs.Rename(namemap)
 			}
-			writeSequences(seqs, f)
+			if err = writeSequences(seqs, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		} else {
 			var aligns *align.AlignChannel
 			if aligns, err = readalign(infile); err != nil {
@@ -118,7 +139,10 @@ while ignoring formatting options (phylip, etc.).
 				} else {
 					al.Rename(namemap)
 				}
-				writeAlign(al, f)
+				if err = writeAlign(al, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 			if aligns.Err != nil {
 				err = aligns.Err
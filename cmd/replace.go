@@ -45,7 +45,10 @@ If the replacement changes sequence length, then returns an error.
 				io.LogError(err)
 				return
 			}
-			writeSequences(seqs, f)
+			if err = writeSequences(seqs, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		} else {
 			if aligns, err = readalign(infile); err != nil {
 				io.LogError(err)
@@ -56,7 +59,10 @@ If the replacement changes sequence length, then returns an error.
 					io.LogError(err)
 					return
 				}
-				writeAlign(al, f)
+				if err = writeAlign(al, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 			if aligns.Err != nil {
 				err = aligns.Err
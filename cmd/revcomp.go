@@ -31,7 +31,18 @@ IUPAC codes are taken into account for the reverse complement.
 		}
 		defer closeWriteFile(f, revCompOutput)
 
-		if unaligned {
+		if streamable() {
+			err = readsequencestream(infile, !unaligned, func(al align.Alignment) (err error) {
+				if err = al.ReverseComplement(); err != nil {
+					return
+				}
+				err = writeStreamAlign(al, f)
+				return
+			})
+			if err != nil {
+				io.LogError(err)
+			}
+		} else if unaligned {
 			var seqs align.SeqBag
 
 			if seqs, err = readsequences(infile); err != nil {
@@ -42,7 +53,10 @@ IUPAC codes are taken into account for the reverse complement.
 				io.LogError(err)
 				return
 			}
-			writeSequences(seqs, f)
+			if err = writeSequences(seqs, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		} else {
 			var aligns *align.AlignChannel
 			var al align.Alignment
@@ -56,7 +70,10 @@ IUPAC codes are taken into account for the reverse complement.
 					io.LogError(err)
 					return
 				}
-				writeAlign(al, f)
+				if err = writeAlign(al, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 
 			if aligns.Err != nil {
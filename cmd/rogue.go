@@ -67,7 +67,10 @@ S4 12345678    S4 1234567
 
 		for al := range aligns.Achan {
 			names, _ := al.SimulateRogue(rogueNb, rogueLength)
-			writeAlign(al, f)
+			if err = writeAlign(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
 			for _, n := range names {
 				namefile.WriteString(n)
 				namefile.WriteString("\n")
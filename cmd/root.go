@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"compress/gzip"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	goio "io"
+	"log"
 	"math/rand"
 	"os"
 	"runtime"
@@ -16,10 +19,12 @@ import (
 	"github.com/evolbioinfo/goalign/align"
 	"github.com/evolbioinfo/goalign/io/clustal"
 	"github.com/evolbioinfo/goalign/io/fasta"
+	"github.com/evolbioinfo/goalign/io/fastq"
 	"github.com/evolbioinfo/goalign/io/nexus"
 	"github.com/evolbioinfo/goalign/io/paml"
 	"github.com/evolbioinfo/goalign/io/partition"
 	"github.com/evolbioinfo/goalign/io/phylip"
+	"github.com/evolbioinfo/goalign/io/stockholm"
 	"github.com/evolbioinfo/goalign/io/utils"
 	"github.com/evolbioinfo/goalign/version"
 	"github.com/fredericlemoine/cobrashell"
@@ -30,12 +35,18 @@ var infile string
 var rootphylip bool
 var rootnexus bool
 var rootclustal bool
+var rootstockholm bool
 var rootcpus int
 var rootinputstrict bool = false
 var rootoutputstrict bool = false
 var rootoutputoneline = false
 var rootoutputnoblock = false
+var rootlinewidth int = -1
+var rootblocksize int = -1
 var rootAutoDetectInputFormat bool
+var rootfastq bool
+var rootfastqtrim int
+var rootfastqmask int
 var seed int64 = -1
 var unaligned bool
 var ignoreidentical = align.IGNORE_NONE
@@ -65,11 +76,14 @@ Input alignment file formats:
 2. Phylip (-p option)
 3. Nexus (-x option)
 4. Clustal (-u option)
-4. Auto detect (--auto-detect option). In that case, it will test input formats in the following order:
+5. Stockholm (--stockholm option)
+6. Fastq (--fastq option), only for commands reading unaligned sequences
+7. Auto detect (--auto-detect option). In that case, it will test input formats in the following order:
     1. Fasta
-    2. Nexus
-    3. Phylip
+    2. Stockholm
+    3. Nexus
     4. Clustal
+    5. Phylip
     If none of these formats is recognized, then will exit with an error 
 
 Please note that in --auto-detect mode, phylip format is considered as not strict!
@@ -92,7 +106,8 @@ Please note that in --auto-detect mode, phylip format is considered as not stric
 	},
 }
 
-// Read sequences (possibly not aligned) from a fasta file
+// Read sequences (possibly not aligned) from a fasta file, or
+// from a fastq file if --fastq is given
 func readsequences(file string) (sequences align.SeqBag, err error) {
 	var fi goio.Closer
 	var r *bufio.Reader
@@ -102,6 +117,12 @@ func readsequences(file string) (sequences align.SeqBag, err error) {
 	}
 	defer fi.Close()
 
+	if rootfastq {
+		p := newFastqParser(r)
+		p.IgnoreIdentical(ignoreidentical)
+		return p.ParseUnalign()
+	}
+
 	p := fasta.NewParser(r)
 	p.IgnoreIdentical(ignoreidentical)
 	if sequences, err = p.ParseUnalign(); err != nil {
@@ -111,6 +132,158 @@ func readsequences(file string) (sequences align.SeqBag, err error) {
 	return
 }
 
+// Returns true if the input sequences may be processed one sequence at a time
+// (see readsequencestream): unaligned sequences, or aligned sequences in fasta
+// format (no other input/output format given)
+func streamable() bool {
+	return unaligned || !(rootphylip || rootnexus || rootclustal || rootstockholm || rootAutoDetectInputFormat)
+}
+
+// Returns true if the given file is a regular file, that may be read several times
+// (not stdin, nor a remote file)
+func regularFile(file string) bool {
+	fi, err := os.Stat(file)
+	return err == nil && fi.Mode().IsRegular()
+}
+
+// newFastqParser returns a fastq parser configured with
+// --fastq-trim-qual and --fastq-mask-qual
+func newFastqParser(r goio.Reader) (p *fastq.Parser) {
+	p = fastq.NewParser(r)
+	p.TrimQuality(rootfastqtrim)
+	p.MaskQuality(rootfastqmask)
+	return
+}
+
+// Reads sequences from a fasta (or fastq if --fastq is given) file one at a time, and calls
+// it on an alignment containing only the current sequence. Only sequence names are kept in
+// memory, to handle duplicate names the same way as readalign and readsequences (--ignore-identical).
+//
+// If aligned is true, all sequences must have the same length (fastq sequences are
+// considered as unaligned).
+func readsequencestream(file string, aligned bool, it func(al align.Alignment) error) (err error) {
+	var fi goio.Closer
+	var r *bufio.Reader
+	var s align.Sequence
+	var al align.Alignment
+	var names map[string]uint64 = make(map[string]uint64) // hash of the first sequence having each name
+	var name string
+	var keep bool
+	var length int = -1
+	var next func() (align.Sequence, error)
+
+	if fi, r, err = utils.GetReader(file); err != nil {
+		return
+	}
+	defer fi.Close()
+
+	if rootfastq {
+		next = newFastqParser(r).Next
+		aligned = false
+	} else {
+		next = fasta.NewParser(r).Next
+	}
+	for {
+		if s, err = next(); err == goio.EOF {
+			err = nil
+			return
+		} else if err != nil {
+			return
+		}
+		if name, keep = streamSequenceName(names, s); !keep {
+			continue
+		}
+		if aligned && length != -1 && length != s.Length() {
+			err = errors.New("Sequence " + name + " does not have same length as other sequences")
+			return
+		}
+		length = s.Length()
+
+		al = align.NewAlign(align.UNKNOWN)
+		if err = al.AddSequenceQuality(name, s.SequenceChar(), s.Quality(), s.Comment()); err != nil {
+			return
+		}
+		al.AutoAlphabet()
+		if err = it(al); err != nil {
+			return
+		}
+	}
+}
+
+// Returns the name of the streamed sequence, renamed if the name already exists,
+// and false if it must be ignored (see align.AddSequenceChar)
+func streamSequenceName(names map[string]uint64, s align.Sequence) (name string, keep bool) {
+	var idx int
+	h := fnv.New64a()
+	h.Write(s.SequenceChar())
+
+	name = s.Name()
+	first, ok := names[name]
+	if ok && ignoreidentical == align.IGNORE_NAME {
+		log.Print(fmt.Sprintf("Warning: sequence name \"%s\" already exists in alignment, ignoring", name))
+		return
+	}
+	if ok && ignoreidentical == align.IGNORE_SEQUENCE && first == h.Sum64() {
+		log.Print(fmt.Sprintf("Warning: sequence \"%s\" already exists in alignment with the same sequence, ignoring", name))
+		return
+	}
+	for ok {
+		idx++
+		log.Print(fmt.Sprintf("Warning: sequence \"%s\" already exists in alignment, renamed in \"%s_%04d\"", name, s.Name(), idx))
+		name = fmt.Sprintf("%s_%04d", s.Name(), idx)
+		_, ok = names[name]
+	}
+	names[name] = h.Sum64()
+	keep = true
+	return
+}
+
+// Reads a fasta alignment for site-wise commands (see streamable). Sequences are
+// read one at a time and stored in a memory compact column-major align.PackedAlign.
+// If a sequence contains characters that can not be packed (lower case, amino acids, etc.),
+// then it falls back to a row-major align.Alignment.
+func readsitealign(file string) (sa align.SiteAlignment, err error) {
+	var al align.Alignment
+	p := align.NewPackedAlign()
+
+	err = readsequencestream(file, true, func(s align.Alignment) (err error) {
+		s.IterateAll(func(name string, sequence []uint8, comment string) bool {
+			if al == nil {
+				if err = p.AddSequence(name, sequence); err == nil {
+					return false
+				}
+				if al, err = p.Unpack(); err != nil {
+					return true
+				}
+				p = nil
+			}
+			err = al.AddSequenceChar(name, sequence, comment)
+			return err != nil
+		})
+		return
+	})
+	if err != nil {
+		return
+	}
+	if al != nil {
+		al.AutoAlphabet()
+		sa = al
+	} else {
+		sa = p
+	}
+	return
+}
+
+// Writes an alignment read with readsitealign. Sequences of an align.PackedAlign
+// are written one at a time, without unpacking the whole alignment: output
+// format is necessarily fasta (see streamable).
+func writeSiteAlign(sa align.SiteAlignment, f *os.File) (err error) {
+	if al, ok := sa.(align.Alignment); ok {
+		return writeAlign(al, f)
+	}
+	return newFastaWriter(f).WriteAlignment(sa)
+}
+
 // Read aligned sequences from an input file
 func readalign(file string) (alchan *align.AlignChannel, err error) {
 	var fi goio.Closer
@@ -119,6 +292,10 @@ func readalign(file string) (alchan *align.AlignChannel, err error) {
 
 	alchan = &align.AlignChannel{}
 
+	if rootfastq {
+		err = errors.New("fastq input (--fastq) is only supported for unaligned sequences")
+		return
+	}
 	if fi, r, err = utils.GetReader(file); err != nil {
 		return
 	}
@@ -132,6 +309,8 @@ func readalign(file string) (alchan *align.AlignChannel, err error) {
 			rootnexus = true
 		} else if format == align.FORMAT_CLUSTAL {
 			rootclustal = true
+		} else if format == align.FORMAT_STOCKHOLM {
+			rootstockholm = true
 		}
 	} else {
 		if rootphylip {
@@ -153,6 +332,14 @@ func readalign(file string) (alchan *align.AlignChannel, err error) {
 			alchan.Achan <- al
 			fi.Close()
 			close(alchan.Achan)
+		} else if rootstockholm {
+			alchan.Achan = make(chan align.Alignment, 15)
+			go func() {
+				sp := stockholm.NewParser(r)
+				sp.IgnoreIdentical(ignoreidentical)
+				sp.ParseMultiple(alchan)
+				fi.Close()
+			}()
 		} else if rootclustal {
 			var al align.Alignment
 			cp := clustal.NewParser(r)
@@ -196,6 +383,7 @@ func init() {
 	RootCmd.PersistentFlags().BoolVarP(&rootphylip, "phylip", "p", false, "Alignment is in phylip? default fasta")
 	RootCmd.PersistentFlags().BoolVarP(&rootnexus, "nexus", "x", false, "Alignment is in nexus? default fasta")
 	RootCmd.PersistentFlags().BoolVarP(&rootclustal, "clustal", "u", false, "Alignment is in clustal? default fasta")
+	RootCmd.PersistentFlags().BoolVar(&rootstockholm, "stockholm", false, "Alignment is in stockholm? default fasta")
 	RootCmd.PersistentFlags().IntVarP(&rootcpus, "threads", "t", 1, "Number of threads")
 
 	// If ignore is IGNORE_NONE: Does not ignore anything
@@ -209,8 +397,13 @@ func init() {
 	RootCmd.PersistentFlags().BoolVar(&rootoutputstrict, "output-strict", false, "Strict phylip output format (only used with -p)")
 	RootCmd.PersistentFlags().BoolVar(&rootoutputoneline, "one-line", false, "Write Phylip sequences on 1 line (only used with -p)")
 	RootCmd.PersistentFlags().BoolVar(&rootoutputnoblock, "no-block", false, "Write Phylip sequences without space separated blocks (only used with -p)")
+	RootCmd.PersistentFlags().IntVar(&rootlinewidth, "line-width", -1, "Number of sites per line in output alignments (-1: default of the output format, 0: each sequence on a single line)")
+	RootCmd.PersistentFlags().IntVar(&rootblocksize, "block-size", -1, "Number of sites per space separated block in phylip, nexus and paml output alignments (-1: default of the output format, 0: no blocks)")
 
-	RootCmd.PersistentFlags().BoolVar(&rootAutoDetectInputFormat, "auto-detect", false, "Auto detects input format (overrides -p, -x and -u)")
+	RootCmd.PersistentFlags().BoolVar(&rootfastq, "fastq", false, "Input sequences are in fastq format (only for commands reading unaligned sequences, output is in fastq format if all output sequences have qualities)")
+	RootCmd.PersistentFlags().IntVar(&rootfastqtrim, "fastq-trim-qual", 0, "Removes bases having a quality < this value from both ends of fastq sequences, sequences having no base left are skipped (only used with --fastq, 0: no trimming)")
+	RootCmd.PersistentFlags().IntVar(&rootfastqmask, "fastq-mask-qual", 0, "Replaces bases having a quality < this value by N in fastq sequences, after trimming (only used with --fastq, 0: no masking)")
+	RootCmd.PersistentFlags().BoolVar(&rootAutoDetectInputFormat, "auto-detect", false, "Auto detects input format (overrides -p, -x, -u and --stockholm)")
 
 	RootCmd.SetHelpTemplate(helptemplate)
 }
@@ -220,31 +413,109 @@ func initConfig() {
 
 }
 
-func writeAlign(al align.Alignment, f *os.File) {
+func writeAlign(al align.Alignment, f *os.File) error {
+	return writeAlignTo(al, f)
+}
+
+// writeAlignTo writes the alignment in the output format given by the root
+// flags. The alignment is streamed to w as it is formatted.
+func writeAlignTo(al align.Alignment, w goio.Writer) (err error) {
 	if rootphylip {
-		f.WriteString(phylip.WriteAlignment(al, rootoutputstrict, rootoutputoneline, rootoutputnoblock))
+		err = newPhylipWriter(w).WriteAlignment(al)
 	} else if rootnexus {
-		f.WriteString(nexus.WriteAlignment(al))
+		err = newNexusWriter(w).WriteAlignment(al)
 	} else if rootclustal {
-		f.WriteString(clustal.WriteAlignment(al))
+		err = newClustalWriter(w).WriteAlignment(al)
+	} else if rootstockholm {
+		err = newStockholmWriter(w).WriteAlignment(al)
 	} else {
-		f.WriteString(fasta.WriteAlignment(al))
+		err = newFastaWriter(w).WriteAlignment(al)
 	}
+	return
 }
 
-func writeAlignString(al align.Alignment) (out string) {
-	if rootphylip {
-		out = phylip.WriteAlignment(al, rootoutputstrict, rootoutputoneline, rootoutputnoblock)
-	} else if rootnexus {
-		out = nexus.WriteAlignment(al)
-	} else if rootclustal {
-		out = clustal.WriteAlignment(al)
-	} else {
-		out = fasta.WriteAlignment(al)
+// newFastaWriter returns a fasta writer configured with --line-width
+func newFastaWriter(w goio.Writer) (fw *fasta.Writer) {
+	fw = fasta.NewWriter(w)
+	if rootlinewidth >= 0 {
+		fw.LineWidth = rootlinewidth
 	}
 	return
 }
 
+// newPhylipWriter returns a phylip writer configured with --output-strict,
+// --line-width, --block-size, --one-line and --no-block
+func newPhylipWriter(w goio.Writer) (pw *phylip.Writer) {
+	pw = phylip.NewWriter(w)
+	pw.Strict = rootoutputstrict
+	if rootlinewidth >= 0 {
+		pw.LineWidth = rootlinewidth
+	}
+	if rootblocksize >= 0 {
+		pw.BlockSize = rootblocksize
+	}
+	if rootoutputoneline {
+		pw.LineWidth = 0
+	}
+	if rootoutputnoblock {
+		pw.BlockSize = 0
+	}
+	return
+}
+
+// newNexusWriter returns a nexus writer configured
+// with --line-width and --block-size
+func newNexusWriter(w goio.Writer) (nw *nexus.Writer) {
+	nw = nexus.NewWriter(w)
+	if rootlinewidth >= 0 {
+		nw.LineWidth = rootlinewidth
+	}
+	if rootblocksize >= 0 {
+		nw.BlockSize = rootblocksize
+	}
+	return
+}
+
+// newClustalWriter returns a clustal writer configured with --line-width
+func newClustalWriter(w goio.Writer) (cw *clustal.Writer) {
+	cw = clustal.NewWriter(w)
+	if rootlinewidth >= 0 {
+		cw.LineWidth = rootlinewidth
+	}
+	return
+}
+
+// newStockholmWriter returns a stockholm writer configured with --line-width
+func newStockholmWriter(w goio.Writer) (sw *stockholm.Writer) {
+	sw = stockholm.NewWriter(w)
+	if rootlinewidth >= 0 {
+		sw.LineWidth = rootlinewidth
+	}
+	return
+}
+
+// newPamlWriter returns a paml writer configured
+// with --line-width and --block-size
+func newPamlWriter(w goio.Writer) (pw *paml.Writer) {
+	pw = paml.NewWriter(w)
+	if rootlinewidth >= 0 {
+		pw.LineWidth = rootlinewidth
+	}
+	if rootblocksize >= 0 {
+		pw.BlockSize = rootblocksize
+	}
+	return
+}
+
+func writeAlignString(al align.Alignment) (out string, err error) {
+	var buf bytes.Buffer
+	if err = writeAlignTo(al, &buf); err != nil {
+		return
+	}
+	out = buf.String()
+	return
+}
+
 func alignExtension() (out string) {
 	if rootphylip {
 		out = ".ph"
@@ -252,34 +523,55 @@ func alignExtension() (out string) {
 		out = ".nx"
 	} else if rootclustal {
 		out = ".clustal"
+	} else if rootstockholm {
+		out = ".sto"
 	} else {
 		out = ".fa"
 	}
 	return
 }
 
-func writeSequences(seqs align.SeqBag, f *os.File) {
-	f.WriteString(fasta.WriteAlignment(seqs))
+// Writes an alignment read with readsequencestream: as fastq sequences if
+// they have qualities (gaps are removed), as fasta otherwise. Gaps are kept
+// in fasta output, even if --unaligned is given
+func writeStreamAlign(al align.Alignment, f *os.File) error {
+	if unaligned || al.HasQualities() {
+		return writeSequences(al, f)
+	}
+	return writeAlignFasta(al, f)
+}
+
+// Writes sequences in fastq format if they all have qualities
+// (see --fastq), in fasta format otherwise
+func writeSequences(seqs align.SeqBag, f *os.File) error {
+	if seqs.HasQualities() {
+		return fastq.NewWriter(f).WriteSequences(seqs)
+	}
+	return newFastaWriter(f).WriteAlignment(seqs)
+}
+
+func writeAlignFasta(al align.Alignment, f *os.File) error {
+	return newFastaWriter(f).WriteAlignment(al)
 }
 
-func writeAlignFasta(al align.Alignment, f *os.File) {
-	f.WriteString(fasta.WriteAlignment(al))
+func writeAlignPhylip(al align.Alignment, f *os.File) error {
+	return newPhylipWriter(f).WriteAlignment(al)
 }
 
-func writeAlignPhylip(al align.Alignment, f *os.File) {
-	f.WriteString(phylip.WriteAlignment(al, rootoutputstrict, rootoutputoneline, rootoutputnoblock))
+func writeAlignNexus(al align.Alignment, f *os.File) error {
+	return newNexusWriter(f).WriteAlignment(al)
 }
 
-func writeAlignNexus(al align.Alignment, f *os.File) {
-	f.WriteString(nexus.WriteAlignment(al))
+func writeAlignClustal(al align.Alignment, f *os.File) error {
+	return newClustalWriter(f).WriteAlignment(al)
 }
 
-func writeAlignClustal(al align.Alignment, f *os.File) {
-	f.WriteString(clustal.WriteAlignment(al))
+func writeAlignStockholm(al align.Alignment, f *os.File) error {
+	return newStockholmWriter(f).WriteAlignment(al)
 }
 
-func writeAlignPaml(al align.Alignment, f *os.File) {
-	f.WriteString(paml.WriteAlignment(al))
+func writeAlignPaml(al align.Alignment, f *os.File) error {
+	return newPamlWriter(f).WriteAlignment(al)
 }
 
 func openWriteFile(file string) (f *os.File, err error) {
@@ -341,7 +633,10 @@ func closeWriteFile(f goio.Closer, filename string) {
 	}
 }
 
-func parsePartition(partitionfile string, alilength int) (ps *align.PartitionSet, err error) {
+// Parses the given partition file (RAxML/IQ-TREE, NEXUS or PartitionFinder format)
+// for the given alignment. Partitions without model are given the default
+// model of the alignment alphabet (DNA or PROT).
+func parsePartition(partitionfile string, al align.Alignment) (ps *align.PartitionSet, err error) {
 	var f goio.Closer
 	var r *bufio.Reader
 
@@ -350,7 +645,8 @@ func parsePartition(partitionfile string, alilength int) (ps *align.PartitionSet
 	}
 	defer f.Close()
 	p := partition.NewParser(r)
-	ps, err = p.Parse(alilength)
+	p.DefaultModel(align.DefaultPartitionModel(al.Alphabet()))
+	ps, err = p.Parse(al.Length())
 	return
 }
 
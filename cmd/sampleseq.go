@@ -55,7 +55,10 @@ divide the output file with 'goalign divide' for example.
 					io.LogError(err)
 					return
 				}
-				writeSequences(sample, f)
+				if err = writeSequences(sample, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 		} else {
 			var aligns *align.AlignChannel
@@ -72,7 +75,10 @@ divide the output file with 'goalign divide' for example.
 						io.LogError(err)
 						return
 					}
-					writeAlign(sample, f)
+					if err = writeAlign(sample, f); err != nil {
+						io.LogError(err)
+						return
+					}
 				}
 			}
 
@@ -64,7 +64,10 @@ var samplesitesCmd = &cobra.Command{
 				io.LogError(err)
 				return
 			}
-			writeAlign(subalign, f)
+			if err = writeAlign(subalign, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 		return
 	},
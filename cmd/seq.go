@@ -26,22 +26,40 @@ goalign trim seq -i align.fa -o trimed.fa -s -n 10
 		var aligns *align.AlignChannel
 		var f *os.File
 
-		if aligns, err = readalign(infile); err != nil {
+		if f, err = openWriteFile(trimAlignOut); err != nil {
 			io.LogError(err)
 			return
 		}
-		if f, err = openWriteFile(trimAlignOut); err != nil {
+		defer closeWriteFile(f, trimAlignOut)
+
+		if streamable() {
+			err = readsequencestream(infile, true, func(al align.Alignment) (err error) {
+				if err = al.TrimSequences(trimNb, trimFromStart); err != nil {
+					return
+				}
+				err = writeStreamAlign(al, f)
+				return
+			})
+			if err != nil {
+				io.LogError(err)
+			}
+			return
+		}
+
+		if aligns, err = readalign(infile); err != nil {
 			io.LogError(err)
 			return
 		}
-		defer closeWriteFile(f, trimAlignOut)
 
 		for al := range aligns.Achan {
 			if err = al.TrimSequences(trimNb, trimFromStart); err != nil {
 				io.LogError(err)
 				return
 			} else {
-				writeAlign(al, f)
+				if err = writeAlign(al, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 		}
 
@@ -43,7 +43,10 @@ goalign shuffle seqs -i align.fasta
 				return
 			}
 			seqs.ShuffleSequences()
-			writeSequences(seqs, f)
+			if err = writeSequences(seqs, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		} else {
 			var aligns *align.AlignChannel
 
@@ -54,7 +57,10 @@ goalign shuffle seqs -i align.fasta
 
 			for al := range aligns.Achan {
 				al.ShuffleSequences()
-				writeAlign(al, f)
+				if err = writeAlign(al, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 
 			if aligns.Err != nil {
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	goio "io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/io"
+	"github.com/evolbioinfo/goalign/io/newick"
+	"github.com/evolbioinfo/goalign/io/partition"
+	"github.com/evolbioinfo/goalign/io/utils"
+	"github.com/evolbioinfo/goalign/models"
+	mdna "github.com/evolbioinfo/goalign/models/dna"
+	pm "github.com/evolbioinfo/goalign/models/protein"
+	"github.com/evolbioinfo/goalign/simulation"
+	"github.com/evolbioinfo/goalign/tree"
+)
+
+var simulateTree string
+var simulateModelStr string
+var simulateLength int
+var simulateNbAligns int
+var simulateIndelRate float64
+var simulateIndelLength float64
+var simulateOutput string
+
+// simulateCmd represents the simulate command
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulates the evolution of sequences along a tree",
+	Long: `Simulates the evolution of sequences along a tree.
+
+The root sequence is drawn from the stationary frequencies of the model, and
+evolves along the branches of the input newick tree (branch lengths in expected
+number of substitutions per site). Output alignment contains tip sequences.
+
+The model is given in RAxML-NG format: NAME{params}+F{freqs}+G{alpha}+I{pinv}
+(e.g. the output of goalign compute model). Available models:
+- Nucleotides:
+  - JC
+  - K2P{kappa}
+  - F81
+  - F84{kappa}
+  - TN93{kappa1/kappa2}
+  - GTR{AC/AG/AT/CG/CT/GT}
+  Frequencies are given with +FU{piA/piC/piG/piT} (default: equal frequencies),
+  and cannot be given for JC and K2P.
+- Proteins: DAYHOFF, JTT, MTREV, LG, WAG, HIVB, HIVW, CPREV, RTREV, VT,
+  BLOSUM62, FLU, MTMAM, MTART
+  Frequencies are those of the model, or may be given with +FU{20 frequencies}.
+
+Rate heterogeneity across sites is given with +G4{alpha} (discrete gamma,
+4 categories), and invariant sites with +I{proportion}. Rates are normalized
+such that the mean rate over all sites is 1.
+
+If --indel-rate > 0, insertions and deletions occur along the branches, with
+the given rate relative to the substitution rate, and lengths following a
+geometric distribution of mean --indel-length. The output alignment is the true
+alignment of the simulated sequences.
+
+Example:
+goalign simulate --tree tree.nw -m "GTR{1/2/1/1/2/1}+FU{0.3/0.2/0.2/0.3}+G4{0.5}" -l 1000
+goalign simulate --tree tree.nw -m "LG+G4{1.0}+I{0.1}" -l 300 --indel-rate 0.05 -p
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var f *os.File
+		var t *tree.Tree
+		var m partition.Model
+		var s *simulation.Simulator
+		var al align.Alignment
+
+		if simulateTree == "none" {
+			err = fmt.Errorf("a tree must be given (--tree)")
+			io.LogError(err)
+			return
+		}
+		if t, err = parseTree(simulateTree); err != nil {
+			io.LogError(err)
+			return
+		}
+		if m, err = partition.ParseModel(simulateModelStr); err != nil {
+			io.LogError(err)
+			return
+		}
+		if s, err = simulator(m); err != nil {
+			io.LogError(err)
+			return
+		}
+		if err = s.SetIndels(simulateIndelRate, simulateIndelLength); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		if f, err = openWriteFile(simulateOutput); err != nil {
+			io.LogError(err)
+			return
+		}
+		defer closeWriteFile(f, simulateOutput)
+
+		for i := 0; i < simulateNbAligns; i++ {
+			if al, err = s.Simulate(t, simulateLength); err != nil {
+				io.LogError(err)
+				return
+			}
+			if err = writeAlign(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
+		}
+		return
+	},
+}
+
+// Builds the simulator corresponding to the given model
+func simulator(m partition.Model) (s *simulation.Simulator, err error) {
+	var model models.Model
+	var pi []float64
+	var alphabet int = align.NUCLEOTIDS
+	var ncat int
+	var alpha, pinv float64
+
+	for _, mod := range m.Modifiers {
+		switch {
+		case mod.Name == "FU" || mod.Name == "F":
+			pi = mod.Params
+		case mod.Name == "FE":
+		case mod.Name == "I":
+			if len(mod.Params) != 1 {
+				err = fmt.Errorf("proportion of invariant sites must be given: +I{pinv}")
+				return
+			}
+			pinv = mod.Params[0]
+		case m.GammaCategories() > 0 && strings.HasPrefix(mod.Name, "G"):
+			if len(mod.Params) != 1 {
+				err = fmt.Errorf("gamma shape parameter must be given: +G4{alpha}")
+				return
+			}
+			alpha = mod.Params[0]
+			ncat = m.GammaCategories()
+		default:
+			err = fmt.Errorf("model modifier +%s is not supported", mod.Name)
+			return
+		}
+	}
+
+	switch strings.ToUpper(m.Name) {
+	case "JC", "JC69", "K2P", "K80", "F81", "F84", "TN93", "GTR":
+		if model, pi, err = simulateDNAModel(m, pi); err != nil {
+			return
+		}
+	default:
+		alphabet = align.AMINOACIDS
+		if model, pi, err = simulateProteinModel(m, pi); err != nil {
+			return
+		}
+	}
+
+	if s, err = simulation.NewSimulator(model, pi, alphabet); err != nil {
+		return
+	}
+	if err = s.SetGamma(alpha, ncat); err != nil {
+		return
+	}
+	err = s.SetInvariant(pinv)
+	return
+}
+
+// Nucleotide substitution model and its frequencies
+func simulateDNAModel(m partition.Model, freqs []float64) (model models.Model, pi []float64, err error) {
+	var nparams int
+
+	pi = []float64{0.25, 0.25, 0.25, 0.25}
+	if freqs != nil {
+		if len(freqs) != 4 {
+			err = fmt.Errorf("4 nucleotide frequencies must be given: +FU{piA/piC/piG/piT}")
+			return
+		}
+		pi = freqs
+	}
+
+	switch strings.ToUpper(m.Name) {
+	case "JC", "JC69":
+		mod := mdna.NewJCModel()
+		err = mod.InitModel()
+		model = mod
+	case "K2P", "K80":
+		nparams = 1
+		mod := mdna.NewK2PModel()
+		if len(m.Params) == 1 {
+			err = mod.InitModel(m.Params[0])
+		}
+		model = mod
+	case "F81":
+		mod := mdna.NewF81Model()
+		err = mod.InitModel(pi[0], pi[1], pi[2], pi[3])
+		model = mod
+	case "F84":
+		nparams = 1
+		kappa := 1.0
+		if len(m.Params) == 1 {
+			kappa = m.Params[0]
+		}
+		mod := mdna.NewF84Model()
+		err = mod.InitModel(kappa, pi[0], pi[1], pi[2], pi[3])
+		model = mod
+	case "TN93":
+		nparams = 2
+		kappa1, kappa2 := 1.0, 1.0
+		if len(m.Params) == 2 {
+			kappa1, kappa2 = m.Params[0], m.Params[1]
+		}
+		mod := mdna.NewTN93Model()
+		err = mod.InitModel(kappa1, kappa2, pi[0], pi[1], pi[2], pi[3])
+		model = mod
+	case "GTR":
+		nparams = 6
+		r := []float64{1, 1, 1, 1, 1, 1}
+		if len(m.Params) == 6 {
+			r = m.Params
+		}
+		mod := mdna.NewGTRModel()
+		err = mod.InitModel(r[0], r[1], r[2], r[3], r[4], r[5], pi[0], pi[1], pi[2], pi[3])
+		model = mod
+	}
+	if err != nil {
+		return
+	}
+	if len(m.Params) != 0 && len(m.Params) != nparams {
+		err = fmt.Errorf("model %s takes %d parameters, %d given", m.Name, nparams, len(m.Params))
+		return
+	}
+	if n := strings.ToUpper(m.Name); freqs != nil && (n == "JC" || n == "JC69" || n == "K2P" || n == "K80") {
+		err = fmt.Errorf("nucleotide frequencies cannot be given to model %s", m.Name)
+	}
+	return
+}
+
+// Protein substitution model and its frequencies
+func simulateProteinModel(m partition.Model, freqs []float64) (model models.Model, pi []float64, err error) {
+	var code int
+	var mod *pm.ProtModel
+
+	name := strings.ToLower(m.Name)
+	if name == "dayhoff" {
+		name = "dayoff"
+	}
+	if code = pm.ModelStringToInt(name); code == -1 {
+		err = fmt.Errorf("model %s is not supported", m.Name)
+		return
+	}
+	if len(m.Params) != 0 {
+		err = fmt.Errorf("model %s does not take parameters", m.Name)
+		return
+	}
+	if freqs != nil && len(freqs) != 20 {
+		err = fmt.Errorf("20 amino acid frequencies must be given: +FU{...}")
+		return
+	}
+	if mod, err = pm.NewProtModel(code, false, 0); err != nil {
+		return
+	}
+	if err = mod.InitModel(freqs); err != nil {
+		return
+	}
+	pi = make([]float64, 20)
+	for i := range pi {
+		pi[i] = mod.Pi(i)
+	}
+	model = mod
+	return
+}
+
+// Parses the newick tree of the given file
+func parseTree(treefile string) (t *tree.Tree, err error) {
+	var f goio.Closer
+	var r *bufio.Reader
+
+	if f, r, err = utils.GetReader(treefile); err != nil {
+		return
+	}
+	defer f.Close()
+	t, err = newick.NewParser(r).Parse()
+	return
+}
+
+func init() {
+	RootCmd.AddCommand(simulateCmd)
+	simulateCmd.PersistentFlags().StringVar(&simulateTree, "tree", "none", "Input newick tree file")
+	simulateCmd.PersistentFlags().StringVarP(&simulateModelStr, "model", "m", "JC", "Substitution model, in RAxML-NG format (e.g. GTR{1/2/1/1/2/1}+FU{0.3/0.2/0.2/0.3}+G4{0.5})")
+	simulateCmd.PersistentFlags().IntVarP(&simulateLength, "length", "l", 1000, "Length of the root sequence")
+	simulateCmd.PersistentFlags().IntVarP(&simulateNbAligns, "nb-aligns", "n", 1, "Number of alignments to simulate")
+	simulateCmd.PersistentFlags().Float64Var(&simulateIndelRate, "indel-rate", 0, "Indel rate, relative to the substitution rate (0: no indels)")
+	simulateCmd.PersistentFlags().Float64Var(&simulateIndelLength, "indel-length", 2, "Mean length of indels (geometric distribution)")
+	simulateCmd.PersistentFlags().StringVarP(&simulateOutput, "output", "o", "stdout", "Simulated alignment output file")
+}
@@ -59,7 +59,10 @@ goalign shuffle sites -i align.fasta -r 0.5
 
 		for al := range aligns.Achan {
 			names := al.ShuffleSites(siteRate, siteRogue, stableRogues)
-			writeAlign(al, f)
+			if err = writeAlign(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
 			for _, n := range names {
 				nameFile.WriteString(n)
 				nameFile.WriteString("\n")
@@ -32,7 +32,10 @@ var sortCmd = &cobra.Command{
 				return
 			}
 			seqs.Sort()
-			writeSequences(seqs, f)
+			if err = writeSequences(seqs, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		} else {
 			var aligns *align.AlignChannel
 
@@ -43,7 +46,10 @@ var sortCmd = &cobra.Command{
 
 			for al := range aligns.Achan {
 				al.Sort()
-				writeAlign(al, f)
+				if err = writeAlign(al, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 
 			if aligns.Err != nil {
@@ -23,8 +23,18 @@ var splitCmd = &cobra.Command{
 Output alignment files will be in the same format as input alignment, 
 with file names corresponding to partition names.
 
+The partition file may be:
+- a RAxML/RAxML-NG/IQ-TREE partition file: GTR+G4+FO, gene1 = 1-100, 101-200/3
+- a NEXUS partition file (IQ-TREE): SETS block with CHARSET and CHARPARTITION commands
+  (CHARSETs of an ASSUMPTIONS block are also accepted)
+- a PartitionFinder configuration file: partitions are defined in [data_blocks]
+
+If no partition file is given and the input alignment is in nexus format,
+the CHARSETs defined in its SETS (or ASSUMPTIONS) block are used as partitions.
+
 Example of usage:
 goalign split -i align.phylip --partition partition.txt 
+goalign split -i align.nexus -x
 `,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		var aligns *align.AlignChannel
@@ -45,7 +55,7 @@ goalign split -i align.phylip --partition partition.txt
 		}
 
 		if splitpartitionstr != "none" {
-			if splitpartition, err = parsePartition(splitpartitionstr, align.Length()); err != nil {
+			if splitpartition, err = parsePartition(splitpartitionstr, align); err != nil {
 				io.LogError(err)
 				return
 			}
@@ -53,6 +63,11 @@ goalign split -i align.phylip --partition partition.txt
 				io.LogError(err)
 				return
 			}
+		} else if splitpartition = align.Partitions(); splitpartition != nil {
+			if err = splitpartition.CheckSites(); err != nil {
+				io.LogError(err)
+				return
+			}
 		} else {
 			err = fmt.Errorf("partition file must be provided")
 			io.LogError(err)
@@ -70,7 +85,10 @@ goalign split -i align.phylip --partition partition.txt
 				io.LogError(err)
 				return
 			}
-			writeAlign(a, f)
+			if err = writeAlign(a, f); err != nil {
+				io.LogError(err)
+				return
+			}
 			f.Close()
 		}
 
@@ -82,5 +100,5 @@ func init() {
 	RootCmd.AddCommand(splitCmd)
 
 	splitCmd.PersistentFlags().StringVarP(&splitprefix, "out-prefix", "o", "", "Prefix of output files")
-	splitCmd.PersistentFlags().StringVar(&splitpartitionstr, "partition", "none", "File containing definition of the partitions")
+	splitCmd.PersistentFlags().StringVar(&splitpartitionstr, "partition", "none", "File containing definition of the partitions (default: nexus charsets, if any)")
 }
@@ -29,6 +29,9 @@ var statsCmd = &cobra.Command{
 
 If the input alignment contains several alignments, will process all of them
 
+If --per-sequences is given and the input file is a fasta file (not stdin),
+the file is read twice, one sequence at a time, and is not loaded in memory.
+
 If --per-sequences is given, then it will print the following stats, for each sequence:
 
 1. Number of gaps in the sequence;
@@ -62,6 +65,20 @@ n...
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		var aligns *align.AlignChannel
 
+		if statpersequences && streamable() && regularFile(infile) {
+			var profile *align.CountProfile
+			if statcountprofile != "none" {
+				if profile, err = countprofile.FromFile(statcountprofile); err != nil {
+					io.LogError(err)
+					return
+				}
+			}
+			if err = printAllSequenceStatsStream(infile, statrefsequence, profile); err != nil {
+				io.LogError(err)
+			}
+			return
+		}
+
 		if aligns, err = readalign(infile); err != nil {
 			io.LogError(err)
 			return
@@ -226,6 +243,16 @@ func printSequenceCharStats(sb align.SeqBag, only string) (err error) {
 	return
 }
 
+// Statistics of a sequence that depend on the other sequences of the alignment
+type sequenceStats struct {
+	gapsuniques int // gaps that are unique in the given alignment
+	gapsnew     int // new gaps that are not found in the profile
+	gapsboth    int // gaps that are unique in the given alignment and not found in the profile
+	mutuniques  int // mutations that are unique in the given alignment
+	mutsnew     int // new mutations that are not found in the profile
+	mutsboth    int // mutations that are unique in the given alignment and not found in the profile
+}
+
 func printAllSequenceStats(al align.Alignment, refSequence align.Sequence, countProfile *align.CountProfile) (err error) {
 	var sequencemap map[uint8]int
 
@@ -238,9 +265,6 @@ func printAllSequenceStats(al align.Alignment, refSequence align.Sequence, count
 	var nummutsboth []int // mutations that are unique in the given alignment and not found in the profile
 	var numgapsboth []int // gaps that are unique in the given alignment and not found in the profile
 
-	var nummutations int
-	var gaps int
-	var name string
 	var uniquechars []uint8 = al.UniqueCharacters()
 
 	if numgapsuniques, numnewgaps, numgapsboth, err = al.NumGapsUniquePerSequence(countProfile); err != nil {
@@ -250,6 +274,139 @@ func printAllSequenceStats(al align.Alignment, refSequence align.Sequence, count
 		return
 	}
 
+	printSequenceStatsHeader(uniquechars, refSequence, countProfile)
+	for i, s := range al.Sequences() {
+		if sequencemap, err = al.CharStatsSeq(i); err != nil {
+			return
+		}
+		st := sequenceStats{
+			gapsuniques: numgapsuniques[i],
+			gapsnew:     numnewgaps[i],
+			gapsboth:    numgapsboth[i],
+			mutuniques:  nummutuniques[i],
+			mutsnew:     numnewmuts[i],
+			mutsboth:    nummutsboth[i],
+		}
+		if err = printSequenceStats(s, al.Alphabet(), st, sequencemap, uniquechars, refSequence, countProfile); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Same as printAllSequenceStats, but reads the input fasta file twice, one
+// sequence at a time, so that the whole alignment is never loaded in memory:
+//   - The first pass builds the profile of the alignment (number of occurences
+//     of each character at each site), and looks for the reference sequence;
+//   - The second pass prints the statistics of each sequence, using this profile.
+func printAllSequenceStatsStream(file string, refname string, countProfile *align.CountProfile) (err error) {
+	var profile *align.CountProfile = align.NewCountProfile()
+	var refSequence align.Sequence
+	var chars align.SeqBag
+	var sb align.SeqBag
+	var uniquechars []uint8
+	var length int
+	var s string
+
+	if err = readsequencestream(file, true, func(al align.Alignment) error {
+		seq, _ := al.GetSequenceCharById(0)
+		name, _ := al.GetSequenceNameById(0)
+		length = len(seq)
+		if refname != "none" && refSequence == nil && name == refname {
+			refSequence = align.NewSequence("ref", append([]uint8(nil), seq...), "")
+		}
+		return profile.AddSequence(seq)
+	}); err != nil {
+		return
+	}
+
+	if refname != "none" && refSequence == nil {
+		// The reference sequence is not in the alignment: We open the potential file
+		if sb, err = readsequences(refname); err != nil {
+			return
+		}
+		if sb.NbSequences() < 1 {
+			err = fmt.Errorf("the reference sequence file does not contain any sequence")
+			return
+		}
+		s, _ = sb.GetSequenceById(0)
+		refSequence = align.NewSequence("ref", []uint8(s), "")
+	}
+	if countProfile != nil && !countProfile.CheckLength(length) {
+		err = fmt.Errorf("profile does not have same length than alignment")
+		return
+	}
+
+	// Alphabet and characters of the whole alignment
+	chars = align.NewSeqBag(align.UNKNOWN)
+	header := make([]uint8, profile.NbCharacters())
+	for i := range header {
+		header[i], _ = profile.NameAt(i)
+	}
+	chars.AddSequenceChar("chars", header, "")
+	chars.AutoAlphabet()
+	uniquechars = chars.UniqueCharacters()
+
+	printSequenceStatsHeader(uniquechars, refSequence, countProfile)
+	err = readsequencestream(file, true, func(al align.Alignment) (err error) {
+		var sequencemap map[uint8]int
+		seq, _ := al.GetSequenceCharById(0)
+		if sequencemap, err = al.CharStatsSeq(0); err != nil {
+			return
+		}
+		st := sequenceStatsFromProfile(seq, chars.Alphabet(), profile, countProfile)
+		return printSequenceStats(al.Sequences()[0], chars.Alphabet(), st, sequencemap, uniquechars, refSequence, countProfile)
+	})
+	return
+}
+
+// Computes the number of gaps and mutations of the sequence that are unique in their site
+// (count of 1 in the profile of the alignment), and that are not found in the countProfile (if not nil).
+// As in align.NumMutationsUniquePerSequence, 'N'/'X' and gaps are not considered as mutations.
+func sequenceStatsFromProfile(seq []uint8, alphabet int, profile, countProfile *align.CountProfile) (st sequenceStats) {
+	var c int
+	var unique bool
+
+	all := uint8('.')
+	if alphabet == align.AMINOACIDS {
+		all = align.ALL_AMINO
+	} else if alphabet == align.NUCLEOTIDS {
+		all = align.ALL_NUCLE
+	}
+
+	for i, r := range seq {
+		if r == all {
+			continue
+		}
+		c, _ = profile.Count(r, i)
+		unique = c == 1
+		if r == align.GAP && unique {
+			st.gapsuniques++
+		} else if unique {
+			st.mutuniques++
+		}
+		if countProfile == nil {
+			continue
+		}
+		if c, _ = countProfile.Count(r, i); c == 0 {
+			if r == align.GAP {
+				st.gapsnew++
+				if unique {
+					st.gapsboth++
+				}
+			} else {
+				st.mutsnew++
+				if unique {
+					st.mutsboth++
+				}
+			}
+		}
+	}
+	return
+}
+
+func printSequenceStatsHeader(uniquechars []uint8, refSequence align.Sequence, countProfile *align.CountProfile) {
 	fmt.Fprintf(os.Stdout, "sequence")
 	fmt.Fprintf(os.Stdout, "\tgaps")
 	fmt.Fprintf(os.Stdout, "\tgapsstart")
@@ -274,48 +431,46 @@ func printAllSequenceStats(al align.Alignment, refSequence align.Sequence, count
 	}
 
 	fmt.Fprintf(os.Stdout, "\n")
-	for i, s := range al.Sequences() {
-		if sequencemap, err = al.CharStatsSeq(i); err != nil {
+}
+
+func printSequenceStats(s align.Sequence, alphabet int, st sequenceStats, sequencemap map[uint8]int, uniquechars []uint8, refSequence align.Sequence, countProfile *align.CountProfile) (err error) {
+	var nummutations int
+	var gaps int = s.NumGaps()
+
+	fmt.Printf("%s", s.Name())
+	fmt.Printf("\t%d", gaps)
+	fmt.Printf("\t%d", s.NumGapsFromStart())
+	fmt.Printf("\t%d", s.NumGapsFromEnd())
+	fmt.Printf("\t%d", st.gapsuniques)
+	if countProfile != nil {
+		fmt.Printf("\t%d", st.gapsnew)
+		fmt.Printf("\t%d", st.gapsboth)
+	}
+	fmt.Printf("\t%d", s.NumGapsOpenning())
+	fmt.Printf("\t%d", st.mutuniques)
+	if countProfile != nil {
+		fmt.Printf("\t%d", st.mutsnew)
+		fmt.Printf("\t%d", st.mutsboth)
+	}
+	if refSequence != nil {
+		if nummutations, err = s.NumMutationsComparedToReferenceSequence(alphabet, refSequence); err != nil {
+			io.LogError(err)
 			return
 		}
-		name = s.Name()
-		gaps = s.NumGaps()
-		fmt.Printf("%s", name)
-		fmt.Printf("\t%d", gaps)
-		fmt.Printf("\t%d", s.NumGapsFromStart())
-		fmt.Printf("\t%d", s.NumGapsFromEnd())
-		fmt.Printf("\t%d", numgapsuniques[i])
-		if countProfile != nil {
-			fmt.Printf("\t%d", numnewgaps[i])
-			fmt.Printf("\t%d", numgapsboth[i])
-		}
-		fmt.Printf("\t%d", s.NumGapsOpenning())
-		fmt.Printf("\t%d", nummutuniques[i])
-		if countProfile != nil {
-			fmt.Printf("\t%d", numnewmuts[i])
-			fmt.Printf("\t%d", nummutsboth[i])
-		}
-		if refSequence != nil {
-			if nummutations, err = s.NumMutationsComparedToReferenceSequence(al.Alphabet(), refSequence); err != nil {
-				io.LogError(err)
-				return
-			}
-			fmt.Printf("\t%d", nummutations)
-		}
-		fmt.Printf("\t%d", s.Length()-gaps)
-		for _, k := range uniquechars {
-			nb := sequencemap[k]
-			fmt.Printf("\t%d", nb)
-		}
-		fmt.Printf("\n")
+		fmt.Printf("\t%d", nummutations)
 	}
-
+	fmt.Printf("\t%d", s.Length()-gaps)
+	for _, k := range uniquechars {
+		nb := sequencemap[k]
+		fmt.Printf("\t%d", nb)
+	}
+	fmt.Printf("\n")
 	return
 }
 
 // Prints the Character with the most frequency
 // for each site of the alignment
-func printMaxCharStats(align align.Alignment, ignoreGaps, ignoreNs bool) {
+func printMaxCharStats(align align.SiteAlignment, ignoreGaps, ignoreNs bool) {
 	maxchars, occur, _ := align.MaxCharStats(ignoreGaps, ignoreNs)
 
 	fmt.Fprintf(os.Stdout, "site\tchar\tnb\n")
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/io"
+	"github.com/spf13/cobra"
+)
+
+// stockholmCmd : to reformat in stockholm format
+var stockholmCmd = &cobra.Command{
+	Use:   "stockholm",
+	Short: "Reformats an input alignment into Stockholm format",
+	Long: `Reformats an alignment into Stockholm format. 
+It may take a Phylip, Fasta, Nexus, Clustal, or Stockholm input alignment.
+
+Annotations (#=GF, #=GS, #=GC, #=GR) of Stockholm input alignments are kept.
+If the input file contains several alignments, they are all written.
+
+Example of usage:
+
+goalign reformat stockholm -i align.phylip -p
+goalign reformat stockholm -i align.fasta
+goalign reformat fasta -i align.sto --stockholm
+
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var aligns *align.AlignChannel
+		var f *os.File
+
+		if aligns, err = readalign(infile); err != nil {
+			io.LogError(err)
+			return
+		}
+		if f, err = openWriteFile(reformatOutput); err != nil {
+			io.LogError(err)
+			return
+		}
+		defer closeWriteFile(f, reformatOutput)
+
+		for a := range aligns.Achan {
+			if reformatCleanNames {
+				a.CleanNames(nil)
+			}
+			if err = writeAlignStockholm(a, f); err != nil {
+				io.LogError(err)
+				return
+			}
+		}
+
+		if aligns.Err != nil {
+			err = aligns.Err
+			io.LogError(err)
+		}
+		return
+	},
+}
+
+func init() {
+	reformatCmd.AddCommand(stockholmCmd)
+}
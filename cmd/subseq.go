@@ -157,7 +157,10 @@ If several alignments are present in the input file and the output is a file
 						}
 					}
 				}
-				writeAlign(subalign, f)
+				if err = writeAlign(subalign, f); err != nil {
+					io.LogError(err)
+					return
+				}
 				start += subseqstep
 				if subseqstep == 0 || (start+len) > al.Length() {
 					break
@@ -106,20 +106,23 @@ given in the comand line.
 			}
 			var filtered align.SeqBag = nil
 			var i int = 0
-			seqs.Iterate(func(name string, sequence string) bool {
+			// Qualities are kept (--fastq)
+			for _, s := range seqs.Sequences() {
 				if filtered == nil {
 					filtered = align.NewSeqBag(seqs.Alphabet())
 				}
-				ok := matchSeqName(name, i, subset, regexps, regexmatch, indexlist, indices)
+				ok := matchSeqName(s.Name(), i, subset, regexps, regexmatch, indexlist, indices)
 				if !revert && ok {
-					filtered.AddSequence(name, sequence, "")
+					filtered.AddSequenceQuality(s.Name(), s.SequenceChar(), s.Quality(), "")
 				} else if revert && !ok {
-					filtered.AddSequence(name, sequence, "")
+					filtered.AddSequenceQuality(s.Name(), s.SequenceChar(), s.Quality(), "")
 				}
 				i++
-				return false
-			})
-			writeSequences(filtered, f)
+			}
+			if err = writeSequences(filtered, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		} else {
 			if aligns, err = readalign(infile); err != nil {
 				io.LogError(err)
@@ -141,7 +144,10 @@ given in the comand line.
 					i++
 					return false
 				})
-				writeAlign(filtered, f)
+				if err = writeAlign(filtered, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 			if aligns.Err != nil {
 				err = aligns.Err
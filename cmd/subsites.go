@@ -145,7 +145,10 @@ If --reverse is given, then will output all positions but the ones that should b
 				io.LogError(err)
 				return
 			}
-			writeAlign(subalign, f)
+			if err = writeAlign(subalign, f); err != nil {
+				io.LogError(err)
+				return
+			}
 			filenum++
 		}
 
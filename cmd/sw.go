@@ -14,12 +14,20 @@ var swLog string
 var gapopen, gapextend float64
 var match float64
 var mismatch float64
+var swMode string
 
-// translateCmd represents the addid command
+// swCmd represents the sw command
 var swCmd = &cobra.Command{
 	Use:   "sw",
-	Short: "Aligns 2 sequences using Smith&Waterman algorithm",
-	Long: `Aligns 2 sequences using Smith&Waterman algorithm.
+	Short: "Aligns 2 sequences (Smith&Waterman local, Needleman&Wunsch global, or semiglobal alignment)",
+	Long: `Aligns 2 sequences using Smith&Waterman (local), Needleman&Wunsch (global),
+or semiglobal alignment algorithms.
+
+Alignment mode is given by --mode:
+- local (default): Smith&Waterman local alignment;
+- global: Needleman&Wunsch global alignment, end to end;
+- semiglobal: Global alignment in which gaps at the beginning and at the end
+  of the sequences are not penalized (e.g. an amplicon against a reference).
 
 Input : Fasta file
 Output: Aligned file (format depending on format options)
@@ -33,6 +41,11 @@ specified by --gap-extend option (they should be negative).
 
 Input file must be a fasta file containing 2 sequences. Output format may be specified
 by formatting options (-p, -x, etc.)
+
+Examples:
+goalign sw -i seqs.fa                     # Local alignment
+goalign sw -i seqs.fa --mode global       # Global alignment
+goalign sw -i seqs.fa --mode semiglobal   # Semiglobal alignment
 `,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		var seqs align.SeqBag
@@ -41,6 +54,12 @@ by formatting options (-p, -x, etc.)
 		var seq2 align.Sequence
 		var ok bool
 		var f, log *os.File
+		var algo int
+
+		if algo, err = alignAlgo(swMode, align.ALIGN_ALGO_SW); err != nil {
+			io.LogError(err)
+			return
+		}
 
 		if f, err = openWriteFile(swOutput); err != nil {
 			io.LogError(err)
@@ -77,7 +96,7 @@ by formatting options (-p, -x, etc.)
 			return
 		}
 
-		aligner := align.NewPwAligner(seq1, seq2, align.ALIGN_ALGO_SW)
+		aligner := align.NewPwAligner(seq1, seq2, algo)
 		aligner.SetGapOpenScore(gapopen)
 		aligner.SetGapExtendScore(gapextend)
 
@@ -89,7 +108,10 @@ by formatting options (-p, -x, etc.)
 			io.LogError(err)
 			return
 		}
-		writeAlign(al, f)
+		if err = writeAlign(al, f); err != nil {
+			io.LogError(err)
+			return
+		}
 
 		if log != nil {
 			start1, start2 := aligner.AlignStarts()
@@ -116,4 +138,21 @@ func init() {
 	swCmd.PersistentFlags().Float64Var(&gapextend, "gap-extend", -0.5, "Score for extending a gap ")
 	swCmd.PersistentFlags().Float64Var(&match, "match", 1.0, "Score for a match (if omitted, then take substitution matrix)")
 	swCmd.PersistentFlags().Float64Var(&mismatch, "mismatch", -1.0, "Score for a mismatch (if omitted, then take substitution matrix)")
+	swCmd.PersistentFlags().StringVar(&swMode, "mode", "local", "Alignment mode: local, global or semiglobal")
+}
+
+// Returns the pairwise alignment algorithm corresponding to the given mode.
+// local: the local algorithm given in argument
+func alignAlgo(mode string, local int) (algo int, err error) {
+	switch mode {
+	case "local":
+		algo = local
+	case "global":
+		algo = align.ALIGN_ALGO_NW
+	case "semiglobal":
+		algo = align.ALIGN_ALGO_SEMIGLOBAL
+	default:
+		err = fmt.Errorf("unknown alignment mode : %s", mode)
+	}
+	return
 }
@@ -52,7 +52,10 @@ goalign shuffle swap -i align.fasta -r 0.5
 
 		for al := range aligns.Achan {
 			al.Swap(swapRate)
-			writeAlign(al, f)
+			if err = writeAlign(al, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 
 		if aligns.Err != nil {
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/evolbioinfo/goalign/align"
 	"github.com/evolbioinfo/goalign/io"
@@ -29,8 +30,35 @@ from positions 0, 1 and 2. Sequence names will be added the suffix
 _<phase>. At the end, 3x times more sequences will be present in the
 file.
 
-It is possible to specify alternative genetic code with --genetic-code 
-(mitoi, mitov, or standard).
+It is possible to specify alternative genetic code with --genetic-code,
+either by name (standard, mitov or mitoi) or by NCBI translation table id:
+   1: standard (same as "standard")
+   2: vertebrate mitochondrial (same as "mitov")
+   3: yeast mitochondrial
+   4: mold, protozoan, coelenterate mitochondrial and mycoplasma/spiroplasma
+   5: invertebrate mitochondrial (same as "mitoi")
+   6: ciliate, dasycladacean and hexamita nuclear
+   9: echinoderm and flatworm mitochondrial
+  10: euplotid nuclear
+  11: bacterial, archaeal and plant plastid
+  12: alternative yeast nuclear
+  13: ascidian mitochondrial
+  14: alternative flatworm mitochondrial
+  15: blepharisma nuclear
+  16: chlorophycean mitochondrial
+  21: trematode mitochondrial
+  22: scenedesmus obliquus mitochondrial
+  23: thraustochytrium mitochondrial
+  24: rhabdopleuridae mitochondrial
+  25: candidate division SR1 and gracilibacteria
+  26: pachysolen tannophilus nuclear
+  27: karyorelict nuclear
+  28: condylostoma nuclear
+  29: mesodinium nuclear
+  30: peritrich nuclear
+  31: blastocrithidia nuclear
+  32: balanophoraceae plastid
+  33: cephalodiscidae mitochondrial
 
 IUPAC codes are taken into account for the translation. If a codon containing 
 IUPAC code is ambiguous for translation, then a X is added in place of the aminoacid.
@@ -45,20 +73,23 @@ IUPAC code is ambiguous for translation, then a X is added in place of the amino
 		}
 		defer closeWriteFile(f, translateOutput)
 
-		switch translateGeneticCode {
-		case "standard":
-			geneticcode = align.GENETIC_CODE_STANDARD
-		case "mitov":
-			geneticcode = align.GENETIC_CODE_VETEBRATE_MITO
-		case "mitoi":
-			geneticcode = align.GENETIC_CODE_INVETEBRATE_MITO
-
-		default:
-			err = fmt.Errorf("unknown genetic code : %s", translateGeneticCode)
+		if geneticcode, err = geneticCodeFromString(translateGeneticCode); err != nil {
+			io.LogError(err)
 			return
 		}
 
-		if unaligned {
+		if streamable() {
+			err = readsequencestream(infile, !unaligned, func(al align.Alignment) (err error) {
+				if err = al.Translate(translatePhase, geneticcode); err != nil {
+					return
+				}
+				err = writeStreamAlign(al, f)
+				return
+			})
+			if err != nil {
+				io.LogError(err)
+			}
+		} else if unaligned {
 			var seqs align.SeqBag
 
 			if seqs, err = readsequences(infile); err != nil {
@@ -69,7 +100,10 @@ IUPAC code is ambiguous for translation, then a X is added in place of the amino
 				io.LogError(err)
 				return
 			}
-			writeSequences(seqs, f)
+			if err = writeSequences(seqs, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		} else {
 			var aligns *align.AlignChannel
 			var al align.Alignment
@@ -83,7 +117,10 @@ IUPAC code is ambiguous for translation, then a X is added in place of the amino
 					io.LogError(err)
 					return
 				}
-				writeAlign(al, f)
+				if err = writeAlign(al, f); err != nil {
+					io.LogError(err)
+					return
+				}
 			}
 
 			if aligns.Err != nil {
@@ -98,8 +135,32 @@ IUPAC code is ambiguous for translation, then a X is added in place of the amino
 
 func init() {
 	RootCmd.AddCommand(translateCmd)
-	translateCmd.PersistentFlags().StringVar(&translateGeneticCode, "genetic-code", "standard", "Genetic Code: standard, mitoi (invertebrate mitochondrial) or mitov (vertebrate mitochondrial)")
+	translateCmd.PersistentFlags().StringVar(&translateGeneticCode, "genetic-code", "standard", geneticCodeUsage)
 	translateCmd.PersistentFlags().StringVarP(&translateOutput, "output", "o", "stdout", "Output translated alignment file")
 	translateCmd.PersistentFlags().IntVar(&translatePhase, "phase", 0, "Number of characters to drop from the start of the alignment (if -1: Translate in the 3 phases, from positions 0, 1, and 2)")
 	translateCmd.PersistentFlags().BoolVar(&unaligned, "unaligned", false, "Considers sequences as unaligned and format fasta (phylip, nexus,... options are ignored)")
 }
+
+const geneticCodeUsage = "Genetic Code: standard, mitoi (invertebrate mitochondrial), mitov (vertebrate mitochondrial), or an NCBI translation table id (1-6, 9-16, 21-33, see goalign translate -h)"
+
+// Returns the genetic code corresponding to the given string:
+// standard, mitov, mitoi, or a NCBI translation table id
+func geneticCodeFromString(code string) (geneticcode int, err error) {
+	var ncbi int
+
+	switch code {
+	case "standard":
+		geneticcode = align.GENETIC_CODE_STANDARD
+	case "mitov":
+		geneticcode = align.GENETIC_CODE_VETEBRATE_MITO
+	case "mitoi":
+		geneticcode = align.GENETIC_CODE_INVETEBRATE_MITO
+	default:
+		if ncbi, err = strconv.Atoi(code); err != nil {
+			err = fmt.Errorf("unknown genetic code : %s", code)
+			return
+		}
+		geneticcode, err = align.GeneticCodeFromNCBI(ncbi)
+	}
+	return
+}
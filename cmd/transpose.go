@@ -37,7 +37,10 @@ var transposeCmd = &cobra.Command{
 				io.LogError(err)
 				return
 			}
-			writeAlign(tr, f)
+			if err = writeAlign(tr, f); err != nil {
+				io.LogError(err)
+				return
+			}
 		}
 
 		if aligns.Err != nil {
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var treeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "Builds phylogenetic trees from an input alignment",
+	Long: `Builds phylogenetic trees from an input alignment
+
+1. goalign tree nj : Builds NJ, BIONJ or UPGMA trees from distance matrices computed on the input alignment
+`,
+}
+
+func init() {
+	RootCmd.AddCommand(treeCmd)
+}
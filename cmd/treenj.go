@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	goio "io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/distance"
+	"github.com/evolbioinfo/goalign/distance/dna"
+	"github.com/evolbioinfo/goalign/distance/protein"
+	"github.com/evolbioinfo/goalign/io"
+	"github.com/evolbioinfo/goalign/io/distmatrix"
+	"github.com/evolbioinfo/goalign/io/newick"
+	"github.com/evolbioinfo/goalign/io/utils"
+	"github.com/evolbioinfo/goalign/tree"
+	"gonum.org/v1/gonum/mat"
+)
+
+var treenjOutput string
+var treenjBootOutput string
+var treenjModel string
+var treenjAlgo string
+var treenjRemoveGaps bool
+var treenjAlpha float64
+var treenjNboot int
+var treenjFrac float64
+var treenjMatrix string
+var treenjBootMatrix string
+
+// treenjCmd represents the tree nj command
+var treenjCmd = &cobra.Command{
+	Use:   "nj",
+	Short: "Builds distance based trees (NJ, BIONJ, UPGMA)",
+	Long: `Builds distance based trees (NJ, BIONJ, UPGMA)
+
+It computes a distance matrix from the input alignment (same models
+as goalign compute distance), builds a tree from it, and writes it
+in Newick format.
+
+If the input alignment contains several alignments, will build a tree
+for all of them.
+
+Available algorithms (--algo):
+- nj    : Neighbor-Joining (Saitou & Nei 1987)
+- bionj : BIONJ (Gascuel 1997)
+- upgma : UPGMA (rooted tree)
+
+NJ and BIONJ trees are unrooted, and may have negative branch lengths.
+
+Available Distances:
+
+Nucleotides:
+- pdist
+- rawdist : raw distance (like pdist, without normalization by length)
+- jc      : Juke-Cantor
+- k2p     : Kimura 2 Parameters
+- f81     : Felsenstein 81
+- f84     : Felsenstein 84
+- tn84    : Tajima and Nei 1984 (tn82 is accepted as an alias)
+- tn93    : Tamura and Nei 1993
+- logdet  : LogDet/paralinear (Lockhart et al. 1994, Lake 1994)
+Proteins:
+- DAYHOFF
+- JTT
+- MtRev 
+- LG
+- WAG
+- HIVb
+- HIVw
+- cpREV
+- rtREV
+- VT
+- Blosum62
+- FLU
+- mtMAM
+- mtART
+- Any model given as a PAML .dat file (-m model.dat)
+- logdet  : LogDet/paralinear
+
+If -n > 0, it builds n bootstrap alignments (like goalign build distboot,
+including partial bootstraps with --frac), computes a tree for each of them,
+and annotates the internal branches of the tree with their bootstrap supports
+(proportion of bootstrap trees containing the same bipartition). Bootstrap
+trees may be written in the --boot-output file.
+
+Instead of an input alignment, distance matrices in PHYLIP format (as written
+by goalign compute distance) may be given with --matrix. A tree is built for
+each matrix of the file, and alignment options (-i, -m, -r, --alpha, -n, --frac)
+are ignored. In the PHYLIP format, the first line gives the number of taxa,
+followed by one row per taxon: its name (without spaces) and its distances.
+
+Bootstrap distance matrices (as written by goalign build distboot) may be given
+with --boot-matrix (instead of -n): a tree is built for each of them, and supports
+are computed as with -n. Taxa names must be the same as in the input alignment
+or matrix.
+
+For example:
+
+goalign tree nj -m k2p -i align.fa -o tree.nw
+goalign tree nj -m k2p --algo bionj -n 100 -i align.fa -o tree.nw --seed 10
+goalign compute distance -m k2p -i align.fa -o dist.txt
+goalign build distboot -m k2p -n 100 -i align.fa -o boot.txt
+goalign tree nj --matrix dist.txt --boot-matrix boot.txt -o tree.nw
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var f, bf *os.File
+		var aligns *align.AlignChannel
+		var algo int
+		var distfunc func(al align.Alignment) ([][]float64, error)
+		var t *tree.Tree
+		var boots []*tree.Tree
+		var matrices []distmatrix.Matrix
+
+		if treenjBootMatrix != "none" && treenjNboot > 0 {
+			err = errors.New("bootstrap matrices (--boot-matrix) and bootstrap replicates (-n) can not be given together")
+			io.LogError(err)
+			return
+		}
+		if algo, err = distance.TreeAlgorithm(treenjAlgo); err != nil {
+			io.LogError(err)
+			return
+		}
+		if f, err = openWriteFile(treenjOutput); err != nil {
+			io.LogError(err)
+			return
+		}
+		defer closeWriteFile(f, treenjOutput)
+
+		if treenjBootOutput != "none" {
+			if bf, err = openWriteFile(treenjBootOutput); err != nil {
+				io.LogError(err)
+				return
+			}
+			defer closeWriteFile(bf, treenjBootOutput)
+		}
+
+		// Bootstrap trees from bootstrap matrices: the same for all input trees
+		if treenjBootMatrix != "none" {
+			if matrices, err = readDistMatrices(treenjBootMatrix); err != nil {
+				io.LogError(err)
+				return
+			}
+			boots = make([]*tree.Tree, len(matrices))
+			for i, m := range matrices {
+				if boots[i], err = distance.BuildTree(m.Names, m.Dist, algo); err != nil {
+					io.LogError(err)
+					return
+				}
+				if bf != nil {
+					bf.WriteString(newick.WriteTree(boots[i]))
+				}
+			}
+		}
+
+		if treenjMatrix != "none" {
+			if matrices, err = readDistMatrices(treenjMatrix); err != nil {
+				io.LogError(err)
+				return
+			}
+			for _, m := range matrices {
+				if t, err = distance.BuildTree(m.Names, m.Dist, algo); err != nil {
+					io.LogError(err)
+					return
+				}
+				if boots != nil {
+					if err = distance.Support(t, boots); err != nil {
+						io.LogError(err)
+						return
+					}
+				}
+				f.WriteString(newick.WriteTree(t))
+			}
+			return
+		}
+
+		if distfunc, err = treeDistFunc(treenjModel, treenjRemoveGaps, cmd.Flags().Changed("alpha"), treenjAlpha); err != nil {
+			io.LogError(err)
+			return
+		}
+		if aligns, err = readalign(infile); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		for al := range aligns.Achan {
+			if t, err = buildDistTree(al, distfunc, algo); err != nil {
+				io.LogError(err)
+				return
+			}
+			if treenjNboot > 0 {
+				boots = make([]*tree.Tree, treenjNboot)
+				for i := range boots {
+					if boots[i], err = buildDistTree(al.BuildBootstrap(treenjFrac), distfunc, algo); err != nil {
+						io.LogError(err)
+						return
+					}
+					if bf != nil {
+						bf.WriteString(newick.WriteTree(boots[i]))
+					}
+				}
+			}
+			if boots != nil {
+				if err = distance.Support(t, boots); err != nil {
+					io.LogError(err)
+					return
+				}
+			}
+			f.WriteString(newick.WriteTree(t))
+		}
+
+		if aligns.Err != nil {
+			err = aligns.Err
+			io.LogError(err)
+		}
+		return
+	},
+}
+
+func init() {
+	treeCmd.AddCommand(treenjCmd)
+	treenjCmd.PersistentFlags().StringVarP(&treenjOutput, "output", "o", "stdout", "Output tree file")
+	treenjCmd.PersistentFlags().StringVarP(&treenjModel, "model", "m", "k2p", "Model for distance computation")
+	treenjCmd.PersistentFlags().StringVar(&treenjAlgo, "algo", "nj", "Tree building algorithm: nj, bionj or upgma")
+	treenjCmd.PersistentFlags().BoolVarP(&treenjRemoveGaps, "rm-gaps", "r", false, "Do not take into account positions containing >=1 gaps")
+	treenjCmd.PersistentFlags().Float64Var(&treenjAlpha, "alpha", 0.0, "Gamma alpha parameter, if not given : no gamma")
+	treenjCmd.PersistentFlags().IntVarP(&treenjNboot, "nboot", "n", 0, "Number of bootstrap replicates used to compute supports (0: no support)")
+	treenjCmd.PersistentFlags().Float64VarP(&treenjFrac, "frac", "f", 1.0, "Fraction of sites to sample for bootstrap replicates (if < 1.0: Partial bootstrap as in phylip seqboot)")
+	treenjCmd.PersistentFlags().StringVar(&treenjBootOutput, "boot-output", "none", "Bootstrap trees output file")
+	treenjCmd.PersistentFlags().StringVar(&treenjMatrix, "matrix", "none", "Input distance matrix file in PHYLIP format, instead of an alignment")
+	treenjCmd.PersistentFlags().StringVar(&treenjBootMatrix, "boot-matrix", "none", "Bootstrap distance matrices file in PHYLIP format (e.g. from goalign build distboot), used to compute supports")
+}
+
+// treeDistFunc returns a function computing distance matrices with the given
+// nucleotide or protein model
+func treeDistFunc(model string, rmgaps, gamma bool, alpha float64) (distfunc func(al align.Alignment) ([][]float64, error), err error) {
+	var dnamodel dna.DistModel
+
+	var protmodel *protein.ProtDistModel
+
+	if protmodel, err = protDistModel(model, gamma, alpha, rmgaps); err != nil {
+		return
+	}
+	if protmodel != nil {
+		protmodel.InitModel(nil, nil)
+		distfunc = func(al align.Alignment) (d [][]float64, err error) {
+			var dense *mat.Dense
+			if _, _, dense, err = protmodel.MLDist(al, nil); err != nil {
+				return
+			}
+			d = denseToSlice(dense)
+			return
+		}
+		return
+	}
+
+	if dnamodel, err = dna.Model(model, rmgaps); err != nil {
+		return
+	}
+	distfunc = func(al align.Alignment) ([][]float64, error) {
+		return dna.DistMatrix(al, nil, alignDistModel(dnamodel, rmgaps, al), -1, -1, -1, -1, gamma, alpha, rootcpus)
+	}
+	return
+}
+
+// buildDistTree computes the distance matrix of the alignment and builds a tree from it
+func buildDistTree(al align.Alignment, distfunc func(al align.Alignment) ([][]float64, error), algo int) (t *tree.Tree, err error) {
+	var d [][]float64
+
+	if d, err = distfunc(al); err != nil {
+		return
+	}
+	names := make([]string, al.NbSequences())
+	for i := range names {
+		names[i], _ = al.GetSequenceNameById(i)
+	}
+	return distance.BuildTree(names, d, algo)
+}
+
+// readDistMatrices reads all the PHYLIP distance matrices of the given file
+func readDistMatrices(file string) (matrices []distmatrix.Matrix, err error) {
+	var fi goio.Closer
+	var r *bufio.Reader
+
+	if fi, r, err = utils.GetReader(file); err != nil {
+		return
+	}
+	defer fi.Close()
+
+	return distmatrix.Parse(r)
+}
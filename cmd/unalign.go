@@ -55,7 +55,10 @@ If align contains 3 alignments, this will generate 3 files:
 				io.LogError(err)
 				return
 			}
-			writeSequences(al.Unalign(), f)
+			if err = writeSequences(al.Unalign(), f); err != nil {
+				io.LogError(err)
+				return
+			}
 			closeWriteFile(f, filename)
 			i++
 		}
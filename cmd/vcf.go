@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/io"
+	"github.com/evolbioinfo/goalign/io/vcf"
+	"github.com/spf13/cobra"
+)
+
+var vcfOutput string
+var vcfRef string
+
+// vcfCmd represents the vcf command
+var vcfCmd = &cobra.Command{
+	Use:   "vcf",
+	Short: "Writes differences with a reference sequence in VCF format",
+	Long: `Writes differences with a reference sequence in VCF format.
+
+Takes an input nucleotide alignment, and compares all the sequences to a reference sequence
+of the alignment (given with --ref-sequence, first sequence by default).
+Differences are written in VCF (v4.2) format, in the coordinates of the reference sequence
+(without gaps). The reference sequence is the only CHROM of the VCF file, and each other
+sequence of the alignment is a sample.
+
+- SNPs are written at their reference position;
+- Insertions (gaps in the reference sequence) are attached to the previous reference base;
+- Deletions (gaps in a sequence) are anchored on the previous reference base
+  (or the next one if the deletion starts at the first position);
+- Ambiguous nucleotides (IUPAC) are written as genotypes containing all the possible alleles.
+  For example, an R in front of a reference A gives the genotype 0/1, with G as alternative
+  allele. Sequences having only Ns in front of a variant have a missing genotype (.);
+- Ambiguous nucleotides (IUPAC) of the reference sequence are written as N in the REF column;
+
+If several alignments are present in the input file, only the first one is considered.
+
+Example:
+goalign vcf -i align.fa --ref-sequence ref > variants.vcf
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var aligns *align.AlignChannel
+		var f *os.File
+		var variants []align.Variant
+		var refname string
+
+		if aligns, err = readalign(infile); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		al, ok := <-aligns.Achan
+		if aligns.Err != nil {
+			err = aligns.Err
+			io.LogError(err)
+			return
+		}
+		if !ok || al.NbSequences() < 1 {
+			err = fmt.Errorf("no sequence in the input alignment")
+			io.LogError(err)
+			return
+		}
+
+		refname = vcfRef
+		if refname == "none" {
+			refname = al.Sequences()[0].Name()
+		}
+
+		if variants, err = al.Variants(refname); err != nil {
+			io.LogError(err)
+			return
+		}
+
+		if f, err = openWriteFile(vcfOutput); err != nil {
+			io.LogError(err)
+			return
+		}
+		defer closeWriteFile(f, vcfOutput)
+
+		f.WriteString(vcf.WriteVariants(al, refname, variants))
+
+		return
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(vcfCmd)
+	vcfCmd.PersistentFlags().StringVarP(&vcfOutput, "output", "o", "stdout", "VCF output file")
+	vcfCmd.PersistentFlags().StringVar(&vcfRef, "ref-sequence", "none", "Name of the reference sequence in the alignment (none: first sequence)")
+}
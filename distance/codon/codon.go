@@ -0,0 +1,249 @@
+package codon
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// Codons are encoded as integers: 16*n1 + 4*n2 + n3, with A=0, C=1, G=2, T=3.
+// Codons containing gaps or ambiguous nucleotides, and stop codons, are encoded
+// as NOT_CODON, and are not taken into account.
+const NOT_CODON = -1
+
+const nucleotides = "ACGT"
+
+// codeTable gives the amino acids of the 64 codons of a genetic code,
+// and precomputes the mutational pathways between all pairs of codons
+type codeTable struct {
+	aa       [64]uint8
+	paths    [64][64][][]step
+	ng86syn  [64]float64 // Number of synonymous sites of each codon (Nei & Gojobori 1986)
+	degclass [64][3]int  // Degeneracy class of each position of each codon: 0, 2 or 4 fold
+}
+
+// step is a single nucleotide change between two codons of a pathway
+type step struct {
+	from, to int
+	ts       bool // Transition
+	syn      bool // Synonymous
+}
+
+// diffs are the numbers of synonymous and non synonymous transitions
+// and transversions between two sequences
+type diffs struct {
+	sts, stv, nts, ntv float64
+}
+
+func newCodeTable(geneticcode int) (ct *codeTable, err error) {
+	var gc map[string]uint8
+	var ok bool
+
+	if gc, err = align.GeneticCode(geneticcode); err != nil {
+		return
+	}
+	ct = &codeTable{}
+	for c := 0; c < 64; c++ {
+		if ct.aa[c], ok = gc[codonString(c)]; !ok {
+			err = fmt.Errorf("codon %s is not defined in the genetic code", codonString(c))
+			return
+		}
+	}
+	for c1 := 0; c1 < 64; c1++ {
+		for c2 := 0; c2 < 64; c2++ {
+			if !ct.isStop(c1) && !ct.isStop(c2) {
+				ct.paths[c1][c2] = ct.pathways(c1, c2)
+			}
+		}
+		if ct.isStop(c1) {
+			continue
+		}
+		for pos := 0; pos < 3; pos++ {
+			syn, nonstop := 0, 0
+			for b := 0; b < 4; b++ {
+				c2 := mutate(c1, pos, b)
+				if c2 == c1 || ct.isStop(c2) {
+					continue
+				}
+				nonstop++
+				if ct.aa[c2] == ct.aa[c1] {
+					syn++
+				}
+			}
+			if nonstop > 0 {
+				ct.ng86syn[c1] += float64(syn) / float64(nonstop)
+			}
+			// Changes to stop codons are considered non synonymous
+			switch syn {
+			case 0:
+				ct.degclass[c1][pos] = 0
+			case 3:
+				ct.degclass[c1][pos] = 4
+			default:
+				ct.degclass[c1][pos] = 2
+			}
+		}
+	}
+	return
+}
+
+func (ct *codeTable) isStop(c int) bool {
+	return ct.aa[c] == '*'
+}
+
+// encode returns the codons of the given nucleotide sequence
+func (ct *codeTable) encode(seq []uint8) (codons []int) {
+	codons = make([]int, len(seq)/3)
+	for i := range codons {
+		codons[i] = 0
+		for pos := 0; pos < 3; pos++ {
+			b := indexOf(seq[3*i+pos])
+			if b < 0 {
+				codons[i] = NOT_CODON
+				break
+			}
+			codons[i] = codons[i]<<2 | b
+		}
+		if codons[i] != NOT_CODON && ct.isStop(codons[i]) {
+			codons[i] = NOT_CODON
+		}
+	}
+	return
+}
+
+// pathways returns the mutational pathways between codons c1 and c2:
+// one per order of the differing positions. Pathways going through
+// stop codons are excluded, unless all of them do.
+func (ct *codeTable) pathways(c1, c2 int) (paths [][]step) {
+	var all [][]step
+
+	positions := make([]int, 0, 3)
+	for pos := 0; pos < 3; pos++ {
+		if base(c1, pos) != base(c2, pos) {
+			positions = append(positions, pos)
+		}
+	}
+	for _, order := range permutations(positions) {
+		path := make([]step, 0, len(order))
+		stop := false
+		c := c1
+		for _, pos := range order {
+			next := mutate(c, pos, base(c2, pos))
+			path = append(path, step{c, next, isTransition(base(c, pos), base(next, pos)), ct.aa[c] == ct.aa[next]})
+			stop = stop || ct.isStop(next)
+			c = next
+		}
+		all = append(all, path)
+		if !stop {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		paths = all
+	}
+	return
+}
+
+// countDiffs counts the synonymous and non synonymous differences between
+// codons c1 and c2, averaged over their pathways. Pathways are weighted by the
+// product of the weights of their steps (if weight is nil, all pathways have the
+// same weight). If all pathways have a null weight, they are given the same weight.
+func (ct *codeTable) countDiffs(c1, c2 int, weight func(s step) float64) (d diffs) {
+	if c1 == c2 {
+		return
+	}
+	total := 0.0
+	for _, path := range ct.paths[c1][c2] {
+		var pd diffs
+		w := 1.0
+		for _, s := range path {
+			if weight != nil {
+				w *= weight(s)
+			}
+			switch {
+			case s.syn && s.ts:
+				pd.sts++
+			case s.syn:
+				pd.stv++
+			case s.ts:
+				pd.nts++
+			default:
+				pd.ntv++
+			}
+		}
+		d.sts += w * pd.sts
+		d.stv += w * pd.stv
+		d.nts += w * pd.nts
+		d.ntv += w * pd.ntv
+		total += w
+	}
+	if total == 0 && weight != nil {
+		return ct.countDiffs(c1, c2, nil)
+	}
+	if total > 0 {
+		d.sts /= total
+		d.stv /= total
+		d.nts /= total
+		d.ntv /= total
+	}
+	return
+}
+
+func (d *diffs) add(o diffs) {
+	d.sts += o.sts
+	d.stv += o.stv
+	d.nts += o.nts
+	d.ntv += o.ntv
+}
+
+func codonString(c int) string {
+	return string([]byte{nucleotides[base(c, 0)], nucleotides[base(c, 1)], nucleotides[base(c, 2)]})
+}
+
+// indexOf returns the index of the nucleotide (A=0, C=1, G=2, T=3),
+// or -1 if it is not one of them
+func indexOf(nt uint8) int {
+	switch unicode.ToUpper(rune(nt)) {
+	case 'A':
+		return 0
+	case 'C':
+		return 1
+	case 'G':
+		return 2
+	case 'T':
+		return 3
+	}
+	return -1
+}
+
+// base returns the nucleotide at position pos (0, 1 or 2) of codon c
+func base(c, pos int) int {
+	return (c >> uint(2*(2-pos))) & 3
+}
+
+// mutate returns codon c with nucleotide b at position pos
+func mutate(c, pos, b int) int {
+	shift := uint(2 * (2 - pos))
+	return c&^(3<<shift) | b<<shift
+}
+
+// A<->G and C<->T
+func isTransition(b1, b2 int) bool {
+	return b1 != b2 && (b1+b2)%2 == 0
+}
+
+func permutations(s []int) (perms [][]int) {
+	if len(s) <= 1 {
+		return [][]int{append([]int{}, s...)}
+	}
+	for i := range s {
+		rest := make([]int, 0, len(s)-1)
+		rest = append(rest, s[:i]...)
+		rest = append(rest, s[i+1:]...)
+		for _, p := range permutations(rest) {
+			perms = append(perms, append([]int{s[i]}, p...))
+		}
+	}
+	return
+}
@@ -0,0 +1,113 @@
+package codon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// dN/dS estimation methods
+const (
+	DNDS_NG86  = iota // Nei & Gojobori 1986
+	DNDS_LWL85        // Li, Wu & Luo 1985
+	DNDS_YN00         // Yang & Nielsen 2000
+)
+
+// Method returns the dN/dS estimation method corresponding
+// to the given name (ng86, lwl85, yn00), or an error if it does not exist
+func Method(name string) (method int, err error) {
+	switch strings.ToLower(name) {
+	case "ng86":
+		method = DNDS_NG86
+	case "lwl85":
+		method = DNDS_LWL85
+	case "yn00":
+		method = DNDS_YN00
+	default:
+		err = fmt.Errorf("unknown dN/dS method: %s", name)
+	}
+	return
+}
+
+// DnDsMatrix computes the pairwise dN, dS and omega=dN/dS matrices of the given
+// codon alignment, using the given method (DNDS_* constants) and genetic code
+// (align.GENETIC_CODE_* constants).
+//
+// Codons containing gaps or ambiguous nucleotides, and stop codons are not taken
+// into account (pairwise deletion). Distances that can not be computed (saturation,
+// no synonymous site, etc.) are NaN or +Inf.
+func DnDsMatrix(al align.Alignment, method, geneticcode, cpus int) (dn, ds, omega [][]float64, err error) {
+	var ct *codeTable
+	var pair func(codons1, codons2 []int) (float64, float64)
+
+	if al.Alphabet() != align.NUCLEOTIDS {
+		err = errors.New("the alignment is not nucleotidic")
+		return
+	}
+	if al.Length()%3 != 0 {
+		err = fmt.Errorf("the alignment length (%d) is not a multiple of 3", al.Length())
+		return
+	}
+	if ct, err = newCodeTable(geneticcode); err != nil {
+		return
+	}
+	switch method {
+	case DNDS_NG86:
+		pair = ct.ng86
+	case DNDS_LWL85:
+		pair = ct.lwl85
+	case DNDS_YN00:
+		pair = ct.yn00
+	default:
+		err = fmt.Errorf("unknown dN/dS method: %d", method)
+		return
+	}
+
+	n := al.NbSequences()
+	codons := make([][]int, 0, n)
+	al.IterateChar(func(name string, seq []uint8) bool {
+		codons = append(codons, ct.encode(seq))
+		return false
+	})
+
+	dn = make([][]float64, n)
+	ds = make([][]float64, n)
+	omega = make([][]float64, n)
+	for i := 0; i < n; i++ {
+		dn[i] = make([]float64, n)
+		ds[i] = make([]float64, n)
+		omega[i] = make([]float64, n)
+	}
+
+	pairs := make(chan [2]int, 100)
+	go func() {
+		defer close(pairs)
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				pairs <- [2]int{i, j}
+			}
+		}
+	}()
+
+	if cpus < 1 {
+		cpus = 1
+	}
+	var wg sync.WaitGroup
+	for cpu := 0; cpu < cpus; cpu++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range pairs {
+				i, j := p[0], p[1]
+				dn[i][j], ds[i][j] = pair(codons[i], codons[j])
+				omega[i][j] = dn[i][j] / ds[i][j]
+				dn[j][i], ds[j][i], omega[j][i] = dn[i][j], ds[i][j], omega[i][j]
+			}
+		}()
+	}
+	wg.Wait()
+	return
+}
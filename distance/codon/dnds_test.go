@@ -0,0 +1,107 @@
+package codon
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// 10 GGG (1 synonymous site), 10 TTT (1/3 synonymous site), one synonymous
+// transition GGG->GGA (4-fold site), one non synonymous transition GGG->AGG
+// (0-fold site in GGG, 2-fold site in AGG)
+var (
+	testSeq1 = strings.Repeat("GGG", 10) + strings.Repeat("TTT", 10)
+	testSeq2 = "GGAAGG" + strings.Repeat("GGG", 8) + strings.Repeat("TTT", 10)
+)
+
+func testDnDs(t *testing.T, seq1, seq2 string, method, geneticcode int) (dn, ds, omega float64) {
+	al := align.NewAlign(align.NUCLEOTIDS)
+	al.AddSequence("s1", seq1, "")
+	al.AddSequence("s2", seq2, "")
+	dnm, dsm, om, err := DnDsMatrix(al, method, geneticcode, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dnm[0][1] != dnm[1][0] || dsm[0][1] != dsm[1][0] || dnm[0][0] != 0 || dsm[1][1] != 0 {
+		t.Errorf("dN and dS matrices should be symmetric with null diagonal: %v, %v", dnm, dsm)
+	}
+	if !math.IsNaN(om[0][1]) && math.Abs(om[0][1]-dnm[0][1]/dsm[0][1]) > 1e-10 {
+		t.Errorf("omega should be dN/dS")
+	}
+	return dnm[0][1], dsm[0][1], om[0][1]
+}
+
+func checkValue(t *testing.T, name string, v, exp float64) {
+	if math.Abs(v-exp) > 1e-10 {
+		t.Errorf("%s should be %f, not %f", name, exp, v)
+	}
+}
+
+func TestNG86(t *testing.T) {
+	dn, ds, _ := testDnDs(t, testSeq1, testSeq2, DNDS_NG86, align.GENETIC_CODE_STANDARD)
+	// S = 8 + 1 + (1 + 2/3)/2 + 10/3 = 79/6, N = 60 - S = 281/6
+	checkValue(t, "dS", ds, -0.75*math.Log(1-4./3.*6./79.))
+	checkValue(t, "dN", dn, -0.75*math.Log(1-4./3.*6./281.))
+
+	dn, ds, omega := testDnDs(t, testSeq1, testSeq1, DNDS_NG86, align.GENETIC_CODE_STANDARD)
+	if dn != 0 || ds != 0 || !math.IsNaN(omega) {
+		t.Errorf("Identical sequences should have dN=dS=0 and omega=NaN, not %f, %f, %f", dn, ds, omega)
+	}
+}
+
+func TestLWL85(t *testing.T) {
+	dn, ds, _ := testDnDs(t, testSeq1, testSeq2, DNDS_LWL85, align.GENETIC_CODE_STANDARD)
+	l0, l2, l4 := 39.5, 11., 9.5
+	a0 := 0.5 * math.Log(1/(1-2*0.5/l0))
+	a2 := 0.5 * math.Log(1/(1-2*0.5/l2))
+	a4 := 0.5 * math.Log(1/(1-2*1/l4))
+	checkValue(t, "dS", ds, (l2*a2+l4*a4)/(l2/3+l4))
+	checkValue(t, "dN", dn, l0*a0/(2*l2/3+l0))
+}
+
+func TestYN00(t *testing.T) {
+	dn, ds, omega := testDnDs(t, testSeq1, testSeq2, DNDS_YN00, align.GENETIC_CODE_STANDARD)
+	if !(dn > 0 && ds > 0 && omega > 0 && omega < 1) {
+		t.Errorf("Wrong yn00 estimates: dN=%f dS=%f omega=%f", dn, ds, omega)
+	}
+	dn, ds, _ = testDnDs(t, testSeq1, testSeq1, DNDS_YN00, align.GENETIC_CODE_STANDARD)
+	if dn != 0 || ds != 0 {
+		t.Errorf("Identical sequences should have dN=dS=0, not %f, %f", dn, ds)
+	}
+}
+
+func TestCountDiffs(t *testing.T) {
+	ct, err := newCodeTable(align.GENETIC_CODE_STANDARD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := ct.encode([]uint8("TGGTATTAA"))
+	if c[2] != NOT_CODON {
+		t.Errorf("Stop codons should not be taken into account")
+	}
+	// TGG->TAG->TAT goes through a stop codon: only TGG->TGT->TAT remains
+	d := ct.countDiffs(c[0], c[1], nil)
+	if d != (diffs{0, 0, 1, 1}) {
+		t.Errorf("Differences between TGG and TAT should be 1 non synonymous transition and 1 transversion, not %v", d)
+	}
+
+	// TGA is a stop codon in the standard code, and codes for W in vertebrate mitochondria
+	ct, _ = newCodeTable(align.GENETIC_CODE_VETEBRATE_MITO)
+	c = ct.encode([]uint8("TGGTGA"))
+	if d := ct.countDiffs(c[0], c[1], nil); d != (diffs{1, 0, 0, 0}) {
+		t.Errorf("Difference between TGG and TGA should be synonymous in vertebrate mitochondria, not %v", d)
+	}
+}
+
+func TestDnDsErrors(t *testing.T) {
+	al := align.NewAlign(align.NUCLEOTIDS)
+	al.AddSequence("s1", "ACGTA", "")
+	if _, _, _, err := DnDsMatrix(al, DNDS_NG86, align.GENETIC_CODE_STANDARD, 1); err == nil {
+		t.Errorf("Alignment length not multiple of 3 should return an error")
+	}
+	if _, err := Method("ml"); err == nil {
+		t.Errorf("Unknown method should return an error")
+	}
+}
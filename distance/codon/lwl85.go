@@ -0,0 +1,77 @@
+package codon
+
+import "math"
+
+// lwlCounts are the numbers of 0-fold, 2-fold and 4-fold degenerate sites
+// (l), and the numbers of transitions (p) and transversions (q) at these
+// sites, between two codon sequences. Index 0, 1 and 2 of each array
+// correspond to 0-fold, 2-fold and 4-fold degenerate sites.
+type lwlCounts struct {
+	l, p, q [3]float64
+}
+
+// classIndex returns the index of the degeneracy class (0, 2 or 4 fold) in lwlCounts arrays
+func classIndex(fold int) int {
+	return fold / 2
+}
+
+// countLWL classifies the sites of the two codon sequences according to their
+// degeneracy, and counts transitions and transversions in each class.
+// Sites and differences are counted half in each sequence.
+func (ct *codeTable) countLWL(codons1, codons2 []int) (counts lwlCounts) {
+	for i, c1 := range codons1 {
+		c2 := codons2[i]
+		if c1 == NOT_CODON || c2 == NOT_CODON {
+			continue
+		}
+		for pos := 0; pos < 3; pos++ {
+			k1 := classIndex(ct.degclass[c1][pos])
+			k2 := classIndex(ct.degclass[c2][pos])
+			counts.l[k1] += 0.5
+			counts.l[k2] += 0.5
+			b1, b2 := base(c1, pos), base(c2, pos)
+			if b1 == b2 {
+				continue
+			}
+			if isTransition(b1, b2) {
+				counts.p[k1] += 0.5
+				counts.p[k2] += 0.5
+			} else {
+				counts.q[k1] += 0.5
+				counts.q[k2] += 0.5
+			}
+		}
+	}
+	return
+}
+
+// k80 returns the transitional (a) and transversional (b) components of the
+// Kimura 2 parameters distance, for each class of sites. Classes without
+// sites have null components.
+func (counts lwlCounts) k80() (a, b [3]float64) {
+	for k := 0; k < 3; k++ {
+		if counts.l[k] == 0 {
+			continue
+		}
+		p := counts.p[k] / counts.l[k]
+		q := counts.q[k] / counts.l[k]
+		a[k] = 0.5*math.Log(1./(1.-2.*p-q)) - 0.25*math.Log(1./(1.-2.*q))
+		b[k] = 0.5 * math.Log(1./(1.-2.*q))
+	}
+	return
+}
+
+// lwl85 computes dN and dS between two codon sequences with the method of
+// Li, Wu & Luo (1985): sites are classified as 0-fold, 2-fold or 4-fold
+// degenerate, and transitions and transversions in each class are corrected
+// with the Kimura 2 parameters formula. Transitions at 2-fold degenerate
+// sites are considered synonymous, and transversions non synonymous.
+func (ct *codeTable) lwl85(codons1, codons2 []int) (dn, ds float64) {
+	counts := ct.countLWL(codons1, codons2)
+	a, b := counts.k80()
+	l0, l2, l4 := counts.l[0], counts.l[1], counts.l[2]
+
+	ds = (l2*a[1] + l4*(a[2]+b[2])) / (l2/3. + l4)
+	dn = (l2*b[1] + l0*(a[0]+b[0])) / (2.*l2/3. + l0)
+	return
+}
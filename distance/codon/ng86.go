@@ -0,0 +1,35 @@
+package codon
+
+import "math"
+
+// ng86 computes dN and dS between two codon sequences with the method of
+// Nei & Gojobori (1986): synonymous and non synonymous sites and differences are
+// counted assuming equal substitution rates between nucleotides (all pathways
+// between codons have the same weight), and proportions of differences are
+// corrected with the Jukes-Cantor formula.
+func (ct *codeTable) ng86(codons1, codons2 []int) (dn, ds float64) {
+	var d diffs
+
+	s, n := 0.0, 0.0
+	for i, c1 := range codons1 {
+		c2 := codons2[i]
+		if c1 == NOT_CODON || c2 == NOT_CODON {
+			continue
+		}
+		syn := (ct.ng86syn[c1] + ct.ng86syn[c2]) / 2.0
+		s += syn
+		n += 3.0 - syn
+		d.add(ct.countDiffs(c1, c2, nil))
+	}
+	ds = jc69((d.sts + d.stv) / s)
+	dn = jc69((d.nts + d.ntv) / n)
+	return
+}
+
+// jc69 corrects the proportion of differences p for multiple substitutions
+func jc69(p float64) float64 {
+	if p == 0 {
+		return 0
+	}
+	return -3. / 4. * math.Log(1.-4./3.*p)
+}
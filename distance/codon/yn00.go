@@ -0,0 +1,151 @@
+package codon
+
+import "math"
+
+const (
+	yn00MaxIter = 20
+	yn00Epsilon = 1e-6
+)
+
+// yn00 computes dN and dS between two codon sequences following the counting
+// method of Yang & Nielsen (2000), which accounts for transition/transversion
+// rate bias and for codon usage:
+//  1. kappa is estimated from the transitions and transversions at 0-fold and
+//     4-fold degenerate sites (Kimura 2 parameters components, as in lwl85);
+//  2. synonymous and non synonymous sites are counted by weighting each
+//     nucleotide change by kappa (transitions) and by the frequency of the
+//     target codon (F3x4 codon frequencies of the two sequences);
+//  3. differences are counted by weighting the pathways between codons by the
+//     same rates, multiplied by omega for non synonymous changes. Omega is
+//     updated until convergence;
+//  4. synonymous and non synonymous transitions and transversions are corrected
+//     with the Kimura 2 parameters formula.
+//
+// As opposed to PAML yn00, the correction does not account for the nucleotide
+// frequencies at synonymous and non synonymous sites, so values may differ slightly.
+func (ct *codeTable) yn00(codons1, codons2 []int) (dn, ds float64) {
+	pi := ct.f3x4(codons1, codons2)
+	kappa := ct.yn00Kappa(codons1, codons2)
+
+	// Synonymous sites of each codon
+	var syn [64]float64
+	for c := 0; c < 64; c++ {
+		if ct.isStop(c) {
+			continue
+		}
+		s, total := 0.0, 0.0
+		for pos := 0; pos < 3; pos++ {
+			for b := 0; b < 4; b++ {
+				c2 := mutate(c, pos, b)
+				if c2 == c || ct.isStop(c2) {
+					continue
+				}
+				r := pi[c2]
+				if isTransition(base(c, pos), b) {
+					r *= kappa
+				}
+				total += r
+				if ct.aa[c2] == ct.aa[c] {
+					s += r
+				}
+			}
+		}
+		if total > 0 {
+			syn[c] = 3. * s / total
+		}
+	}
+	s, n := 0.0, 0.0
+	for i, c1 := range codons1 {
+		c2 := codons2[i]
+		if c1 != NOT_CODON && c2 != NOT_CODON {
+			s += (syn[c1] + syn[c2]) / 2.
+			n += 3. - (syn[c1]+syn[c2])/2.
+		}
+	}
+
+	omega := 1.0
+	for iter := 0; iter < yn00MaxIter; iter++ {
+		var d diffs
+		weight := func(st step) float64 {
+			r := pi[st.to]
+			if st.ts {
+				r *= kappa
+			}
+			if !st.syn {
+				r *= omega
+			}
+			return r
+		}
+		for i, c1 := range codons1 {
+			c2 := codons2[i]
+			if c1 != NOT_CODON && c2 != NOT_CODON {
+				d.add(ct.countDiffs(c1, c2, weight))
+			}
+		}
+		ds = k80(d.sts/s, d.stv/s)
+		dn = k80(d.nts/n, d.ntv/n)
+
+		neww := dn / ds
+		if math.IsNaN(neww) || math.IsInf(neww, 0) || neww <= 0 || math.Abs(neww-omega) < yn00Epsilon {
+			break
+		}
+		omega = math.Min(math.Max(neww, 1e-3), 1e3)
+	}
+	return
+}
+
+// yn00Kappa estimates the transition/transversion rate ratio from
+// 0-fold and 4-fold degenerate sites. Returns 1 if it can not be estimated.
+func (ct *codeTable) yn00Kappa(codons1, codons2 []int) (kappa float64) {
+	counts := ct.countLWL(codons1, codons2)
+	a, b := counts.k80()
+	ts := counts.l[0]*a[0] + counts.l[2]*a[2]
+	tv := counts.l[0]*b[0] + counts.l[2]*b[2]
+	kappa = 2. * ts / tv
+	if math.IsNaN(kappa) || math.IsInf(kappa, 0) || kappa <= 0 {
+		kappa = 1.0
+	}
+	return
+}
+
+// f3x4 returns the codon frequencies of the two sequences, computed from the
+// nucleotide frequencies at each codon position. Stop codons have a null frequency.
+func (ct *codeTable) f3x4(codons1, codons2 []int) (pi [64]float64) {
+	var freqs [3][4]float64
+
+	for _, codons := range [][]int{codons1, codons2} {
+		for i, c := range codons {
+			if codons1[i] == NOT_CODON || codons2[i] == NOT_CODON {
+				continue
+			}
+			for pos := 0; pos < 3; pos++ {
+				freqs[pos][base(c, pos)]++
+			}
+		}
+	}
+	total := 0.0
+	for c := 0; c < 64; c++ {
+		if ct.isStop(c) {
+			continue
+		}
+		pi[c] = freqs[0][base(c, 0)] * freqs[1][base(c, 1)] * freqs[2][base(c, 2)]
+		total += pi[c]
+	}
+	for c := range pi {
+		if total > 0 {
+			pi[c] /= total
+		} else if !ct.isStop(c) {
+			pi[c] = 1.
+		}
+	}
+	return
+}
+
+// k80 computes the Kimura 2 parameters distance from the proportions
+// of transitions (p) and transversions (q)
+func k80(p, q float64) float64 {
+	if p == 0 && q == 0 {
+		return 0
+	}
+	return -0.5*math.Log(1.-2.*p-q) - 0.25*math.Log(1.-2.*q)
+}
@@ -1 +1,93 @@
 package distance
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/evolbioinfo/goalign/tree"
+)
+
+// Distance based tree building algorithms
+const (
+	TREE_NJ    = iota // Neighbor-Joining (Saitou & Nei 1987)
+	TREE_BIONJ        // BIONJ (Gascuel 1997)
+	TREE_UPGMA        // UPGMA
+)
+
+// TreeAlgorithm returns the tree building algorithm corresponding
+// to the given name (nj, bionj, upgma), or an error if it does not exist
+func TreeAlgorithm(name string) (algo int, err error) {
+	switch strings.ToLower(name) {
+	case "nj":
+		algo = TREE_NJ
+	case "bionj":
+		algo = TREE_BIONJ
+	case "upgma":
+		algo = TREE_UPGMA
+	default:
+		err = fmt.Errorf("unknown tree building algorithm: %s", name)
+	}
+	return
+}
+
+// BuildTree builds a tree from the given distance matrix using the given algorithm
+// (TREE_NJ, TREE_BIONJ or TREE_UPGMA). names are the names of the
+// tips, in the same order as the rows of the matrix.
+//
+// NJ and BIONJ trees are unrooted (trifurcation at the root), and may have
+// negative branch lengths. UPGMA trees are rooted.
+func BuildTree(names []string, d [][]float64, algo int) (t *tree.Tree, err error) {
+	switch algo {
+	case TREE_NJ:
+		return NJ(names, d)
+	case TREE_BIONJ:
+		return BioNJ(names, d)
+	case TREE_UPGMA:
+		return UPGMA(names, d)
+	default:
+		err = fmt.Errorf("unknown tree building algorithm: %d", algo)
+	}
+	return
+}
+
+// checkMatrix checks that the matrix is square, finite, and that
+// there are as many names as rows. It returns a copy of the matrix.
+func checkMatrix(names []string, d [][]float64) (c [][]float64, err error) {
+	if len(names) == 0 {
+		err = fmt.Errorf("cannot build a tree from an empty distance matrix")
+		return
+	}
+	if len(d) != len(names) {
+		err = fmt.Errorf("distance matrix has %d rows, but there are %d names", len(d), len(names))
+		return
+	}
+	c = make([][]float64, len(d))
+	for i, row := range d {
+		if len(row) != len(d) {
+			err = fmt.Errorf("distance matrix is not square: row %d has %d columns", i, len(row))
+			return
+		}
+		for j, v := range row {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				err = fmt.Errorf("distance between %s and %s is not finite (%f)", names[i], names[j], v)
+				return
+			}
+		}
+		c[i] = make([]float64, len(row))
+		copy(c[i], row)
+	}
+	return
+}
+
+// smallTree builds the tree when there are less than 3 taxa
+func smallTree(names []string, d [][]float64) *tree.Tree {
+	root := tree.NewNode("")
+	if len(names) == 1 {
+		root.SetName(names[0])
+		return tree.NewTree(root)
+	}
+	root.AddChild(tree.NewNode(names[0]), d[0][1]/2.0)
+	root.AddChild(tree.NewNode(names[1]), d[0][1]/2.0)
+	return tree.NewTree(root)
+}
@@ -0,0 +1,152 @@
+package distance
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/tree"
+)
+
+// Distances between all pairs of tips, following the branches of the tree
+func patristic(t *tree.Tree, names []string) [][]float64 {
+	index := make(map[string]int)
+	for i, n := range names {
+		index[n] = i
+	}
+	d := make([][]float64, len(names))
+	for i := range d {
+		d[i] = make([]float64, len(names))
+	}
+	// Distance from each node to each tip under it
+	var rec func(n *tree.Node) map[int]float64
+	rec = func(n *tree.Node) map[int]float64 {
+		under := make(map[int]float64)
+		if n.Tip() {
+			under[index[n.Name()]] = 0
+		}
+		for _, c := range n.Children() {
+			cunder := rec(c)
+			for i, di := range cunder {
+				for j, dj := range under {
+					d[i][j] = di + c.Length() + dj
+					d[j][i] = d[i][j]
+				}
+			}
+			for i, di := range cunder {
+				under[i] = di + c.Length()
+			}
+		}
+		return under
+	}
+	rec(t.Root())
+	return d
+}
+
+func checkPatristic(t *testing.T, tr *tree.Tree, names []string, exp [][]float64) {
+	d := patristic(tr, names)
+	for i := range exp {
+		for j := range exp {
+			if math.Abs(d[i][j]-exp[i][j]) > 1e-10 {
+				t.Errorf("Distance between %s and %s in the tree should be %f, not %f", names[i], names[j], exp[i][j], d[i][j])
+			}
+		}
+	}
+}
+
+func TestNJ(t *testing.T) {
+	// Additive matrix: NJ and BIONJ must recover the tree
+	names := []string{"a", "b", "c", "d", "e"}
+	d := [][]float64{
+		{0, 5, 9, 9, 8},
+		{5, 0, 10, 10, 9},
+		{9, 10, 0, 8, 7},
+		{9, 10, 8, 0, 3},
+		{8, 9, 7, 3, 0},
+	}
+	for _, algo := range []int{TREE_NJ, TREE_BIONJ} {
+		tr, err := BuildTree(names, d, algo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tr.Tips()) != 5 {
+			t.Errorf("Tree should have 5 tips, not %d", len(tr.Tips()))
+		}
+		if len(tr.Root().Children()) != 3 {
+			t.Errorf("Tree should be unrooted")
+		}
+		checkPatristic(t, tr, names, d)
+	}
+}
+
+func TestUPGMA(t *testing.T) {
+	// Ultrametric matrix: UPGMA must recover the tree
+	names := []string{"a", "b", "c", "d"}
+	d := [][]float64{
+		{0, 2, 6, 10},
+		{2, 0, 6, 10},
+		{6, 6, 0, 10},
+		{10, 10, 10, 0},
+	}
+	tr, err := UPGMA(names, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.Root().Children()) != 2 {
+		t.Errorf("UPGMA tree should be rooted")
+	}
+	checkPatristic(t, tr, names, d)
+}
+
+func TestBuildTreeErrors(t *testing.T) {
+	if _, err := NJ([]string{"a", "b"}, [][]float64{{0, 1}}); err == nil {
+		t.Errorf("Wrong number of names should return an error")
+	}
+	if _, err := BioNJ([]string{"a", "b"}, [][]float64{{0, math.NaN()}, {math.NaN(), 0}}); err == nil {
+		t.Errorf("NaN distances should return an error")
+	}
+	if _, err := TreeAlgorithm("ml"); err == nil {
+		t.Errorf("Unknown algorithm should return an error")
+	}
+}
+
+func TestSupport(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e"}
+	d1 := [][]float64{
+		{0, 5, 9, 9, 8},
+		{5, 0, 10, 10, 9},
+		{9, 10, 0, 8, 7},
+		{9, 10, 8, 0, 3},
+		{8, 9, 7, 3, 0},
+	}
+	// (a,b) is still there, but d is closer to c than to e
+	d2 := [][]float64{
+		{0, 2, 9, 9, 20},
+		{2, 0, 9, 9, 20},
+		{9, 9, 0, 2, 20},
+		{9, 9, 2, 0, 20},
+		{20, 20, 20, 20, 0},
+	}
+	ref, _ := NJ(names, d1)
+	b1, _ := NJ(names, d1)
+	b2, _ := UPGMA(names, d2)
+
+	if err := Support(ref, []*tree.Tree{b1, b2}); err != nil {
+		t.Fatal(err)
+	}
+	supports := make(map[string]bool)
+	ref.PreOrder(func(n *tree.Node) bool {
+		if !n.Tip() && n.Parent() != nil {
+			supports[n.Name()] = true
+		}
+		return true
+	})
+	// One internal branch supported by both trees, and one by the first only
+	if len(supports) != 2 || !supports["1"] || !supports["0.5"] {
+		t.Errorf("Supports should be 1 and 0.5, not %v", supports)
+	}
+
+	other, _ := NJ([]string{"a", "b", "c", "d", "f"}, d1)
+	if err := Support(ref, []*tree.Tree{other}); err == nil {
+		t.Errorf("Trees with different tips should return an error")
+	}
+}
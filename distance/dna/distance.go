@@ -41,6 +41,12 @@ type DistModel interface {
 	Sequence(i int) ([]uint8, error)
 }
 
+// alphabetModel is implemented by distance models working
+// on non nucleotidic alignments (see protein.LogDetModel)
+type alphabetModel interface {
+	Alphabet() int
+}
+
 type seqpairdist struct {
 	i, j       int
 	seq1, seq2 []uint8 // Sequences encoded with align.Nt2IndexIUPAC
@@ -69,12 +75,14 @@ func Model(modelType string, removegaps bool) (model DistModel, err error) {
 		model = NewRawDistModel(removegaps)
 	case "f81":
 		model = NewF81Model(removegaps)
-	// case "tn82":
-	// 	model = NewTN82Model(removegaps)
+	case "tn84", "tn82":
+		model = NewTN82Model(removegaps)
 	case "tn93":
 		model = NewTN93Model(removegaps)
 	case "f84":
 		model = NewF84Model(removegaps)
+	case "logdet":
+		model = NewLogDetModel(removegaps)
 	default:
 		err = errors.New("This model is not implemented : " + modelType)
 	}
@@ -120,11 +128,20 @@ func BuildWeightsDirichlet(al align.Alignment) []float64 {
 // If weights == nil, then all weights are considered 1
 // range1, range2: To restrict the computation to the distances between these ranges of sequence IDS, based [range1Min,range1Max] vs. [range2Min, range2Max]
 // If range1Min, range1Max, range2Min or range2Max are -1, then computes the usual half matrix
+// The alignment must be nucleotidic, unless the model works on another alphabet (ex: protein.LogDetModel)
 func DistMatrix(al align.Alignment, weights []float64, model DistModel, range1Min, range1Max, range2Min, range2Max int,
 	gamma bool, alpha float64, cpus int) (outmatrix [][]float64, err error) {
 
-	if al.Alphabet() != align.NUCLEOTIDS {
-		err = errors.New("The alignment is not nucleotidic")
+	alphabet := align.NUCLEOTIDS
+	if m, ok := model.(alphabetModel); ok {
+		alphabet = m.Alphabet()
+	}
+	if al.Alphabet() != alphabet {
+		if alphabet == align.NUCLEOTIDS {
+			err = errors.New("The alignment is not nucleotidic")
+		} else {
+			err = errors.New("The alignment is not amino acid")
+		}
 		return
 	}
 	if err = model.InitModel(al, weights, gamma, alpha); err != nil {
@@ -187,3 +187,158 @@ func Test_countDiffsWithInternalGaps(t *testing.T) {
 		})
 	}
 }
+
+func Test_countPairs(t *testing.T) {
+	var s1, s2 []uint8
+	var err error
+	seq1, seq2 := []uint8("ACGTAC-NR"), []uint8("ACGAACGTA")
+	selected := []bool{true, true, true, true, true, true, true, true, true}
+
+	s1 = make([]uint8, len(seq1))
+	s2 = make([]uint8, len(seq2))
+	for l := range seq1 {
+		if s1[l], err = align.Nt2IndexIUPAC(seq1[l]); err != nil {
+			t.Error(err)
+			return
+		}
+		if s2[l], err = align.Nt2IndexIUPAC(seq2[l]); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	counts := countPairs(s1, s2, selected, nil)
+	// A-A: 2, C-C: 2, G-G: 1, T-A: 1, others (gap, ambiguous): not counted
+	exp := map[int]float64{0: 2, 5: 2, 10: 1, 12: 1}
+	for i, c := range counts {
+		if c != exp[i] {
+			t.Errorf("Count of nucleotide pair %d should be %f, not %f", i, exp[i], c)
+		}
+	}
+}
+
+func Test_samplePairs(t *testing.T) {
+	if pairs := samplePairs(5, 0); len(pairs) != 10 {
+		t.Errorf("There should be 10 pairs, not %d", len(pairs))
+	}
+	pairs := samplePairs(50, 100)
+	if len(pairs) != 100 {
+		t.Errorf("There should be 100 pairs, not %d", len(pairs))
+	}
+	seen := make(map[[2]int]bool)
+	for _, p := range pairs {
+		if p[0] >= p[1] || seen[p] {
+			t.Errorf("Pair %v is not valid", p)
+		}
+		seen[p] = true
+	}
+}
+
+func Test_TN82(t *testing.T) {
+	tests := []struct {
+		name       string
+		seq1, seq2 string
+		removegaps bool
+		want       float64
+	}{
+		// Equal nucleotide frequencies and equal frequencies of all pairs: same as JC
+		{name: "jc", seq1: "AAACCGACGT", seq2: "CGTGTTACGT", want: -0.75 * math.Log(1-4./3.*0.6)},
+		// pi={0.6,0.1,0.2,0.1}, p=0.4, x_AG=0.4: b=0.5*(0.58+0.16/(0.16/0.24))=0.41
+		{name: "unequal", seq1: "AAAAAAGGCT", seq2: "AAAAGGAACT", want: 1.5225645473487677},
+		{name: "rmgaps", seq1: "AAAAAAGGCT--", seq2: "AAAAGGAACTAA", removegaps: true, want: 1.5225645473487677},
+		{name: "identical", seq1: "ACGTACGT", seq2: "ACGTACGT", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			al := align.NewAlign(align.NUCLEOTIDS)
+			al.AddSequence("s1", tt.seq1, "")
+			al.AddSequence("s2", tt.seq2, "")
+			// tn82 is an alias of tn84
+			for _, name := range []string{"tn84", "tn82"} {
+				m, err := Model(name, tt.removegaps)
+				if err != nil {
+					t.Fatal(err)
+				}
+				d, err := DistMatrix(al, nil, m, -1, -1, -1, -1, false, 0, 1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if math.Abs(d[0][1]-tt.want) > 1e-10 || d[0][1] != d[1][0] {
+					t.Errorf("%s distance should be %f, not %f", name, tt.want, d[0][1])
+				}
+			}
+		})
+	}
+}
+
+func Test_TN82Gamma(t *testing.T) {
+	al := align.NewAlign(align.NUCLEOTIDS)
+	al.AddSequence("s1", "AAACCGACGT", "")
+	al.AddSequence("s2", "CGTGTTACGT", "")
+	tn82 := NewTN82Model(false)
+	jc := NewJCModel(false)
+	dtn82, err := DistMatrix(al, nil, tn82, -1, -1, -1, -1, true, 0.5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	djc, err := DistMatrix(al, nil, jc, -1, -1, -1, -1, true, 0.5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(dtn82[0][1]-djc[0][1]) > 1e-10 {
+		t.Errorf("TN82+G distance should be %f, not %f", djc[0][1], dtn82[0][1])
+	}
+}
+
+func Test_LogDet(t *testing.T) {
+	tests := []struct {
+		name       string
+		seq1, seq2 string
+		removegaps bool
+		weights    []float64
+		want       float64
+	}{
+		// Stationary and symmetric divergence matrix: same as JC
+		{name: "jc", seq1: "ACGTACGTACGTAAAACCCCGGGGTTTT", seq2: "ACGTACGTACGTACGTACGTACGTACGT", want: -0.75 * math.Log(3./7.)},
+		// Triangular divergence matrix: det F=0.1^4, det PI1=det PI2=0.4*0.3*0.2*0.1
+		{name: "nonstationary", seq1: "AAACCGACGT", seq2: "CGTGTTACGT", want: 0.25 * math.Log(24)},
+		{name: "rmgaps", seq1: "AAACCGACGT--", seq2: "CGTGTTACGTAC", removegaps: true, want: 0.25 * math.Log(24)},
+		{name: "weights", seq1: "AAACCGACGT", seq2: "CGTGTTACGT", weights: []float64{2, 2, 2, 2, 2, 2, 2, 2, 2, 2}, want: 0.25 * math.Log(24)},
+		{name: "identical", seq1: "ACGTACGT", seq2: "ACGTACGT", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			al := align.NewAlign(align.NUCLEOTIDS)
+			al.AddSequence("s1", tt.seq1, "")
+			al.AddSequence("s2", tt.seq2, "")
+			m, err := Model("logdet", tt.removegaps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d, err := DistMatrix(al, tt.weights, m, -1, -1, -1, -1, false, 0, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(d[0][1]-tt.want) > 1e-10 || d[0][1] != d[1][0] {
+				t.Errorf("LogDet distance should be %f, not %f", tt.want, d[0][1])
+			}
+		})
+	}
+}
+
+func Test_Paralinear(t *testing.T) {
+	// All pairs equally frequent: saturation
+	f := [][]float64{{1, 1, 1, 1}, {1, 1, 1, 1}, {1, 1, 1, 1}, {1, 1, 1, 1}}
+	if d := Paralinear(f); !math.IsInf(d, 1) {
+		t.Errorf("Paralinear distance of a singular matrix should be +Inf, not %f", d)
+	}
+	// Absent states are not taken into account: same as JC with 2 states
+	f = [][]float64{{3, 1, 0, 0}, {1, 3, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}}
+	if d, want := Paralinear(f), -0.5*math.Log(1-2*0.25); math.Abs(d-want) > 1e-10 {
+		t.Errorf("Paralinear distance should be %f, not %f", want, d)
+	}
+	al := align.NewAlign(align.NUCLEOTIDS)
+	al.AddSequence("s1", "ACGT", "")
+	if err := NewLogDetModel(false).InitModel(al, nil, true, 0.5); err == nil {
+		t.Errorf("LogDet distance with gamma should return an error")
+	}
+}
@@ -0,0 +1,110 @@
+package dna
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/evolbioinfo/goalign/align"
+	mdna "github.com/evolbioinfo/goalign/models/dna"
+)
+
+// EstimateGTR estimates the parameters of a GTR model from the alignment,
+// with a discrete gamma rate heterogeneity if ncat > 1.
+//
+// Base frequencies are the empirical frequencies of the alignment. Rates (and gamma
+// shape) are estimated by maximizing the pairwise composite likelihood over pairs of
+// sequences (see models/dna.EstimateGTR).
+// If maxpairs > 0 and the alignment has more pairs of sequences than maxpairs,
+// then maxpairs random pairs are used.
+//
+// Only sites with unambiguous nucleotides in both sequences of a pair are taken into
+// account. If removegaps is true, sites having at least one gap are removed.
+func EstimateGTR(al align.Alignment, weights []float64, removegaps bool, ncat, maxpairs int) (params mdna.GTRParams, err error) {
+	var selected []bool
+	var codes [][]uint8
+	var pi []float64
+	var pairs []mdna.PairCounts
+	var sum float64
+
+	if al.Alphabet() != align.NUCLEOTIDS {
+		err = fmt.Errorf("model parameters can only be estimated from nucleotide alignments")
+		return
+	}
+	if al.NbSequences() < 2 {
+		err = fmt.Errorf("at least 2 sequences are needed to estimate model parameters")
+		return
+	}
+
+	_, selected = selectedSites(al, weights, removegaps)
+	if codes, err = alignmentToCodes(al); err != nil {
+		return
+	}
+	if pi, err = probaNt(codes, selected, weights); err != nil {
+		return
+	}
+	for _, p := range pi {
+		sum += p
+	}
+	if sum == 0 {
+		err = fmt.Errorf("no nucleotide in the alignment")
+		return
+	}
+	for i := range pi {
+		pi[i] /= sum
+	}
+
+	for _, pair := range samplePairs(len(codes), maxpairs) {
+		pairs = append(pairs, countPairs(codes[pair[0]], codes[pair[1]], selected, weights))
+	}
+
+	params, _, err = mdna.EstimateGTR(pairs, pi, ncat)
+	return
+}
+
+// Returns all pairs of sequences among nseqs sequences, or maxpairs random pairs if
+// maxpairs > 0 and there are more pairs than maxpairs.
+func samplePairs(nseqs, maxpairs int) (pairs [][2]int) {
+	var npairs int = nseqs * (nseqs - 1) / 2
+	var i, j int
+	var chosen map[[2]int]bool
+
+	if maxpairs <= 0 || npairs <= maxpairs {
+		for i = 0; i < nseqs; i++ {
+			for j = i + 1; j < nseqs; j++ {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+		return
+	}
+
+	chosen = make(map[[2]int]bool)
+	for len(pairs) < maxpairs {
+		i, j = rand.Intn(nseqs), rand.Intn(nseqs)
+		if i == j {
+			continue
+		}
+		if i > j {
+			i, j = j, i
+		}
+		if !chosen[[2]int{i, j}] {
+			chosen[[2]int{i, j}] = true
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+	return
+}
+
+// Counts the (weighted) pairs of unambiguous nucleotides between both sequences
+// (encoded with align.Nt2IndexIUPAC), on the selected sites
+func countPairs(seq1, seq2 []uint8, selectedSites []bool, weights []float64) (counts mdna.PairCounts) {
+	w := 1.0
+	for pos := range seq1 {
+		if weights != nil {
+			w = weights[pos]
+		}
+		if selectedSites[pos] && isNucStrict(seq1[pos]) && isNucStrict(seq2[pos]) {
+			counts[4*ntByteToId[seq1[pos]]+ntByteToId[seq2[pos]]] += w
+		}
+	}
+	return
+}
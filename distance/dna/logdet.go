@@ -0,0 +1,152 @@
+package dna
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/evolbioinfo/goalign/align"
+	"gonum.org/v1/gonum/mat"
+)
+
+// LogDetModel is the LogDet/paralinear distance (Lockhart et al. 1994, Lake 1994).
+// It does not assume stationary base composition, and is therefore robust
+// to compositional heterogeneity between lineages:
+// d = -1/4 * (ln det F - 1/2 * (ln det PI1 + ln det PI2)),
+// F being the 4x4 divergence matrix between the two sequences, and
+// PI1 and PI2 the diagonal matrices of their nucleotide frequencies.
+type LogDetModel struct {
+	numSites      float64   // Number of selected sites (no gaps)
+	selectedSites []bool    // true for selected sites
+	removegaps    bool      // If true, we will remove posision with >=1 gaps
+	sequenceCodes [][]uint8 // Sequences converted into int codes
+}
+
+func NewLogDetModel(removegaps bool) *LogDetModel {
+	return &LogDetModel{
+		0,
+		nil,
+		removegaps,
+		nil,
+	}
+}
+
+// Distance computes LogDet distance between 2 sequences
+func (m *LogDetModel) Distance(seq1 []uint8, seq2 []uint8, weights []float64) (float64, error) {
+	f := init2DFloat(4, 4)
+	w := 1.0
+	for pos, char1 := range seq1 {
+		if weights != nil {
+			w = weights[pos]
+		}
+		if m.selectedSites[pos] && isNuc(char1) && isNuc(seq2[pos]) {
+			id1, err := align.PossibleNtIUPAC(char1)
+			if err != nil {
+				return 0.0, err
+			}
+			id2, err := align.PossibleNtIUPAC(seq2[pos])
+			if err != nil {
+				return 0.0, err
+			}
+			// Ambiguous pairs are spread over all possible pairs
+			nb := float64(len(id1) * len(id2))
+			for _, i1 := range id1 {
+				for _, i2 := range id2 {
+					f[ntByteToId[i1]][ntByteToId[i2]] += w / nb
+				}
+			}
+		}
+	}
+	return Paralinear(f), nil
+}
+
+// Paralinear computes the LogDet/paralinear distance from the divergence matrix f:
+// f[i][j] being the (weighted) number of sites having state i in the first
+// sequence and state j in the second sequence.
+//
+// The divergence matrix is restricted to the states present in both sequences:
+// sites having a state absent from one of the sequences are not taken into
+// account (otherwise, the matrix would be singular). If no site remains, or if
+// the restricted matrix is singular (saturation), then returns +Inf.
+func Paralinear(f [][]float64) float64 {
+	var logdet, sign float64
+
+	total := 0.0
+	rows := make([]float64, len(f))
+	cols := make([]float64, len(f))
+	for i := range f {
+		for j := range f[i] {
+			rows[i] += f[i][j]
+			cols[j] += f[i][j]
+			total += f[i][j]
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	states := make([]int, 0, len(f))
+	for i := range f {
+		if rows[i] > 0 && cols[i] > 0 {
+			states = append(states, i)
+		}
+	}
+	k := len(states)
+
+	// Frequencies of the restricted matrix
+	total = 0
+	rowsk := make([]float64, k)
+	colsk := make([]float64, k)
+	for i, si := range states {
+		for j, sj := range states {
+			rowsk[i] += f[si][sj]
+			colsk[j] += f[si][sj]
+			total += f[si][sj]
+		}
+	}
+	if total == 0 {
+		return math.Inf(1)
+	}
+
+	logpi := 0.0
+	fk := mat.NewDense(k, k, nil)
+	for i, si := range states {
+		if rowsk[i] == 0 || colsk[i] == 0 {
+			return math.Inf(1)
+		}
+		logpi += math.Log(rowsk[i]/total) + math.Log(colsk[i]/total)
+		for j, sj := range states {
+			fk.Set(i, j, f[si][sj]/total)
+		}
+	}
+	if logdet, sign = mat.LogDet(fk); sign <= 0 || math.IsInf(logdet, 0) {
+		return math.Inf(1)
+	}
+
+	dist := -1. / float64(k) * (logdet - 0.5*logpi)
+	if dist > 0 {
+		return dist
+	}
+	return 0
+}
+
+func (m *LogDetModel) InitModel(al align.Alignment, weights []float64, gamma bool, alpha float64) (err error) {
+	if gamma {
+		err = errors.New("gamma is not available for the logdet distance")
+		return
+	}
+	m.numSites, m.selectedSites = selectedSites(al, weights, m.removegaps)
+	m.sequenceCodes, err = alignmentToCodes(al)
+	return
+}
+
+// Sequence returns the ith sequence of the alignment
+// encoded in int
+func (m *LogDetModel) Sequence(i int) (seq []uint8, err error) {
+	if i < 0 || i >= len(m.sequenceCodes) {
+		err = fmt.Errorf("This sequence does not exist: %d", i)
+		return
+	}
+	seq = m.sequenceCodes[i]
+	return
+}
@@ -1,5 +1,3 @@
-//+ build ignore
-
 package dna
 
 import (
@@ -9,12 +7,18 @@ import (
 	"github.com/evolbioinfo/goalign/align"
 )
 
+// TN82Model is the Tajima & Nei (1984) distance model (named tn84, and tn82 for
+// compatibility with previous versions, see Model),
+// b = 0.5 * (1 - sum(pi_i^2) + p^2/h), with h = sum_{i<j} x_ij^2/(2 * pi_i * pi_j),
+// x_ij being the frequency of the pair of nucleotides i/j between the two sequences.
 type TN82Model struct {
-	/* Vector of nt proba */
-	pi            []float64 // proba of each nt
+	pi            []float64 // Vector of nt stationary proba
+	b1            float64   // 1 - sum(pi_i^2)
 	numSites      float64   // Number of selected sites (no gaps)
 	selectedSites []bool    // true for selected sites
 	removegaps    bool      // If true, we will remove posision with >=1 gaps
+	gamma         bool
+	alpha         float64
 	sequenceCodes [][]uint8 // Sequences converted into int codes
 }
 
@@ -22,48 +26,64 @@ func NewTN82Model(removegaps bool) *TN82Model {
 	return &TN82Model{
 		nil,
 		0,
+		0,
 		nil,
 		removegaps,
+		false,
+		0.,
 		nil,
 	}
 }
 
-// Distance computes TN82 distance between 2 sequences
+// Distance computes Tajima & Nei (1984) distance between 2 sequences
 func (m *TN82Model) Distance(seq1 []uint8, seq2 []uint8, weights []float64) (float64, error) {
+	var dist float64
+
 	diff, total := countDiffs(seq1, seq2, m.selectedSites, weights, false)
 	diff = diff / total
+	if diff == 0 {
+		return 0, nil
+	}
 
 	psi := init2DFloat(4, 4)
-	totalPairs, err := countNtPairs2Seq(seq1, seq2, m.selectedSites, weights, psi)
-	if err != nil {
+	if _, err := countNtPairs2Seq(seq1, seq2, m.selectedSites, weights, psi); err != nil {
 		return 0.0, err
 	}
-	for i := 0; i < 4; i++ {
-		for j := 0; j < 4; j++ {
-			psi[i][j] = psi[i][j] / totalPairs
-		}
-	}
-	denom := 0.0
+	h := 0.0
 	for i := 0; i < 4; i++ {
 		for j := i + 1; j < 4; j++ {
-			denom += psi[i][j] * psi[i][j] / (2 * m.pi[i] * m.pi[j])
+			x := psi[i][j] / total
+			h += x * x / (2 * m.pi[i] * m.pi[j])
 		}
 	}
-	b1 := diff * diff / denom
-	dist := -1.0 * b1 * math.Log(1.0-diff/b1)
+	b := 0.5 * (m.b1 + diff*diff/h)
+
+	if m.gamma {
+		dist = b * m.alpha * (math.Pow(1.-diff/b, -1./m.alpha) - 1.)
+	} else {
+		dist = -1. * b * math.Log(1.-diff/b)
+	}
 	if dist > 0 {
 		return dist, nil
-	} else {
-		return 0, nil
 	}
+	return 0, nil
 }
 
 func (m *TN82Model) InitModel(al align.Alignment, weights []float64, gamma bool, alpha float64) (err error) {
+	m.gamma = gamma
+	m.alpha = alpha
 	m.numSites, m.selectedSites = selectedSites(al, weights, m.removegaps)
 	if m.sequenceCodes, err = alignmentToCodes(al); err != nil {
 		return
 	}
+	m.b1 = 0.0
 	m.pi, err = probaNt(m.sequenceCodes, m.selectedSites, weights)
+	if err == nil {
+		for i := range m.pi {
+			m.b1 += m.pi[i] * m.pi[i]
+		}
+		m.b1 = 1 - m.b1
+	}
 	return
 }
 
@@ -0,0 +1,184 @@
+package distance
+
+import (
+	"math"
+
+	"github.com/evolbioinfo/goalign/tree"
+)
+
+// NJ builds a Neighbor-Joining tree (Saitou & Nei 1987, Studier & Keppler 1988)
+// from the given distance matrix. names are the names of the tips, in the same
+// order as the rows of the matrix. The output tree is unrooted.
+func NJ(names []string, d [][]float64) (t *tree.Tree, err error) {
+	return nj(names, d, false)
+}
+
+// BioNJ builds a BIONJ tree (Gascuel 1997) from the given distance matrix.
+// It is like NJ, but distances of new nodes take into account the variance
+// of the distance estimates. The output tree is unrooted.
+func BioNJ(names []string, d [][]float64) (t *tree.Tree, err error) {
+	return nj(names, d, true)
+}
+
+func nj(names []string, dist [][]float64, bionj bool) (t *tree.Tree, err error) {
+	var d, v [][]float64
+
+	if d, err = checkMatrix(names, dist); err != nil {
+		return
+	}
+	n := len(names)
+	if n < 3 {
+		t = smallTree(names, d)
+		return
+	}
+	if bionj {
+		// Variances are initialized with distances
+		v, _ = checkMatrix(names, dist)
+	}
+
+	nodes := make([]*tree.Node, n)
+	for i, name := range names {
+		nodes[i] = tree.NewNode(name)
+	}
+	r := make([]float64, n)
+
+	for ; n > 3; n-- {
+		for i := 0; i < n; i++ {
+			r[i] = 0
+			for j := 0; j < n; j++ {
+				r[i] += d[i][j]
+			}
+		}
+
+		// Pair minimizing the Q criterion
+		i, j := 0, 1
+		minq := math.Inf(1)
+		for k := 0; k < n; k++ {
+			for l := k + 1; l < n; l++ {
+				if q := float64(n-2)*d[k][l] - r[k] - r[l]; q < minq {
+					minq = q
+					i, j = k, l
+				}
+			}
+		}
+
+		li := d[i][j]/2.0 + (r[i]-r[j])/(2.0*float64(n-2))
+		lj := d[i][j] - li
+		u := tree.NewNode("")
+		u.AddChild(nodes[i], li)
+		u.AddChild(nodes[j], lj)
+
+		// NJ: lambda=0.5
+		lambda := 0.5
+		if bionj && v[i][j] > 0 {
+			s := 0.0
+			for k := 0; k < n; k++ {
+				if k != i && k != j {
+					s += v[j][k] - v[i][k]
+				}
+			}
+			lambda = math.Min(1.0, math.Max(0.0, 0.5+s/(2.0*float64(n-2)*v[i][j])))
+		}
+
+		// New node u takes the place of i
+		for k := 0; k < n; k++ {
+			if k == i || k == j {
+				continue
+			}
+			d[i][k] = lambda*(d[i][k]-li) + (1.0-lambda)*(d[j][k]-lj)
+			d[k][i] = d[i][k]
+			if bionj {
+				v[i][k] = lambda*v[i][k] + (1.0-lambda)*v[j][k] - lambda*(1.0-lambda)*v[i][j]
+				v[k][i] = v[i][k]
+			}
+		}
+		nodes[i] = u
+
+		// Last node takes the place of j
+		nodes[j] = nodes[n-1]
+		removeRow(d, j, n)
+		if bionj {
+			removeRow(v, j, n)
+		}
+	}
+
+	root := tree.NewNode("")
+	root.AddChild(nodes[0], (d[0][1]+d[0][2]-d[1][2])/2.0)
+	root.AddChild(nodes[1], (d[0][1]+d[1][2]-d[0][2])/2.0)
+	root.AddChild(nodes[2], (d[0][2]+d[1][2]-d[0][1])/2.0)
+	t = tree.NewTree(root)
+	return
+}
+
+// UPGMA builds a rooted UPGMA tree from the given distance matrix.
+// names are the names of the tips, in the same order as the rows of the matrix.
+func UPGMA(names []string, dist [][]float64) (t *tree.Tree, err error) {
+	var d [][]float64
+
+	if d, err = checkMatrix(names, dist); err != nil {
+		return
+	}
+	n := len(names)
+	if n < 2 {
+		t = smallTree(names, d)
+		return
+	}
+
+	nodes := make([]*tree.Node, n)
+	sizes := make([]float64, n)
+	heights := make([]float64, n)
+	for i, name := range names {
+		nodes[i] = tree.NewNode(name)
+		sizes[i] = 1
+	}
+
+	for ; n > 1; n-- {
+		i, j := 0, 1
+		for k := 0; k < n; k++ {
+			for l := k + 1; l < n; l++ {
+				if d[k][l] < d[i][j] {
+					i, j = k, l
+				}
+			}
+		}
+
+		h := d[i][j] / 2.0
+		u := tree.NewNode("")
+		u.AddChild(nodes[i], h-heights[i])
+		u.AddChild(nodes[j], h-heights[j])
+
+		// New node u takes the place of i
+		for k := 0; k < n; k++ {
+			if k == i || k == j {
+				continue
+			}
+			d[i][k] = (sizes[i]*d[i][k] + sizes[j]*d[j][k]) / (sizes[i] + sizes[j])
+			d[k][i] = d[i][k]
+		}
+		nodes[i] = u
+		sizes[i] += sizes[j]
+		heights[i] = h
+
+		// Last node takes the place of j
+		nodes[j] = nodes[n-1]
+		sizes[j] = sizes[n-1]
+		heights[j] = heights[n-1]
+		removeRow(d, j, n)
+	}
+
+	t = tree.NewTree(nodes[0])
+	return
+}
+
+// removeRow replaces row and column j of the n*n matrix m by its
+// last row and column
+func removeRow(m [][]float64, j, n int) {
+	last := n - 1
+	for k := 0; k < n; k++ {
+		m[j][k] = m[last][k]
+	}
+	for k := 0; k < n; k++ {
+		m[k][j] = m[k][last]
+	}
+	m[j][j] = 0
+}
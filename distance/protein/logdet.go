@@ -0,0 +1,89 @@
+package protein
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/distance/dna"
+)
+
+// Code of amino acids that are not taken into account (gaps, ambiguities, stops)
+const aaUnknown = 255
+
+// LogDetModel is the LogDet/paralinear distance for amino acids: same as
+// dna.LogDetModel, computed on the 20x20 divergence matrix. Only amino acids
+// present in both sequences are taken into account (see dna.Paralinear).
+//
+// It implements the dna.DistModel interface, and can be given to dna.DistMatrix.
+type LogDetModel struct {
+	numSites      float64   // Number of selected sites (no gaps)
+	selectedSites []bool    // true for selected sites
+	removegaps    bool      // If true, we will remove posision with >=1 gaps
+	sequenceCodes [][]uint8 // Sequences converted into amino acid indices
+}
+
+func NewLogDetModel(removegaps bool) *LogDetModel {
+	return &LogDetModel{
+		0,
+		nil,
+		removegaps,
+		nil,
+	}
+}
+
+// Alphabet returns align.AMINOACIDS: the model works on amino acid alignments
+func (m *LogDetModel) Alphabet() int {
+	return align.AMINOACIDS
+}
+
+// Distance computes LogDet distance between 2 sequences
+func (m *LogDetModel) Distance(seq1 []uint8, seq2 []uint8, weights []float64) (float64, error) {
+	f := make([][]float64, 20)
+	for i := range f {
+		f[i] = make([]float64, 20)
+	}
+	w := 1.0
+	for pos, c1 := range seq1 {
+		if weights != nil {
+			w = weights[pos]
+		}
+		if m.selectedSites[pos] && c1 != aaUnknown && seq2[pos] != aaUnknown {
+			f[c1][seq2[pos]] += w
+		}
+	}
+	return dna.Paralinear(f), nil
+}
+
+func (m *LogDetModel) InitModel(al align.Alignment, weights []float64, gamma bool, alpha float64) (err error) {
+	if gamma {
+		err = errors.New("gamma is not available for the logdet distance")
+		return
+	}
+	m.numSites, m.selectedSites = selectedSites(al, weights, m.removegaps)
+	m.sequenceCodes = make([][]uint8, 0, al.NbSequences())
+	al.IterateChar(func(name string, seq []uint8) bool {
+		codes := make([]uint8, len(seq))
+		for l, c := range seq {
+			if idx, err := align.AA2Index(c); err != nil {
+				codes[l] = aaUnknown
+			} else {
+				codes[l] = uint8(idx)
+			}
+		}
+		m.sequenceCodes = append(m.sequenceCodes, codes)
+		return false
+	})
+	return
+}
+
+// Sequence returns the ith sequence of the alignment
+// encoded in int
+func (m *LogDetModel) Sequence(i int) (seq []uint8, err error) {
+	if i < 0 || i >= len(m.sequenceCodes) {
+		err = fmt.Errorf("This sequence does not exist: %d", i)
+		return
+	}
+	seq = m.sequenceCodes[i]
+	return
+}
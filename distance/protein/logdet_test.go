@@ -0,0 +1,48 @@
+package protein
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/distance/dna"
+)
+
+func logDetMatrix(t *testing.T, seqs ...string) [][]float64 {
+	al := align.NewAlign(align.AMINOACIDS)
+	for i, s := range seqs {
+		if err := al.AddSequence(string(rune('a'+i)), s, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d, err := dna.DistMatrix(al, nil, NewLogDetModel(false), -1, -1, -1, -1, false, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// L is only present in the first sequence and C only in the second one:
+// the L/C site is not taken into account, instead of giving an infinite distance
+func TestLogDet_AbsentStates(t *testing.T) {
+	d := logDetMatrix(t,
+		"ARNDQEGHIKMFPSTWYVAL",
+		"RRNDQEGHIKMFPSTWYVAC",
+		"ARNDQEGHIKMFPSTWYVAC")
+	// Distance between the first two sequences, without the L/C site
+	want := logDetMatrix(t,
+		"ARNDQEGHIKMFPSTWYVA",
+		"RRNDQEGHIKMFPSTWYVA")[0][1]
+
+	if math.IsInf(d[0][1], 0) || math.Abs(d[0][1]-want) > 1e-10 || want <= 0 {
+		t.Errorf("LogDet distance should be %f, not %f", want, d[0][1])
+	}
+	// Pair differing only at the L/C site
+	if d[0][2] != 0 {
+		t.Errorf("LogDet distance between sequences differing only by absent states should be 0, not %f", d[0][2])
+	}
+	// C is present in both sequences: the last site is taken into account
+	if d[1][2] <= 0 || d[1][2] >= d[0][1] {
+		t.Errorf("LogDet distance should be > 0 and < %f, not %f", d[0][1], d[1][2])
+	}
+}
@@ -22,7 +22,8 @@ type ProtDistModel struct {
 }
 
 // Initialize a new protein model, given the name of the model as const int:
-// MODEL_DAYHOFF, MODEL_JTT, MODEL_MTREV, MODEL_LG or MODEL_WAG
+// MODEL_DAYHOFF, MODEL_JTT, MODEL_MTREV, MODEL_LG, MODEL_WAG, MODEL_HIVB, MODEL_HIVW,
+// MODEL_CPREV, MODEL_RTREV, MODEL_VT, MODEL_BLOSUM62, MODEL_FLU, MODEL_MTMAM or MODEL_MTART
 func NewProtDistModel(model int, modelfreqs bool, usegamma bool, alpha float64, removegaps bool) (*ProtDistModel, error) {
 	m, err := protein.NewProtModel(model, usegamma, alpha)
 	if err != nil {
@@ -37,6 +38,18 @@ func NewProtDistModel(model int, modelfreqs bool, usegamma bool, alpha float64,
 	}, nil
 }
 
+// Initialize a new protein distance model from a protein model, for example
+// read from a PAML file (see protein.ReadPAMLFile)
+func NewProtDistModelFromProtModel(m *protein.ProtModel, modelfreqs bool, removegaps bool) *ProtDistModel {
+	return &ProtDistModel{
+		m,
+		modelfreqs,
+		removegaps,
+		nil,
+		1,
+	}
+}
+
 func (model *ProtDistModel) InitModel(a align.Alignment, weights []float64) (err error) {
 	var pi []float64
 
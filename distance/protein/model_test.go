@@ -0,0 +1,66 @@
+package protein
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/models/protein"
+)
+
+// Builds a pair of sequences whose site patterns are the ones expected
+// under the model after a distance of dist: the number of sites having
+// amino acid i in the first sequence and j in the second one is
+// nsites * pi[i] * Pij(dist)
+func expectedPair(t *testing.T, model *ProtDistModel, dist float64, nsites int) align.Alignment {
+	var s1, s2 strings.Builder
+	aas := "ARNDCQEGHILKMFPSTWYV"
+
+	model.pMat(dist)
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 20; j++ {
+			n := int(math.Round(float64(nsites) * model.model.Pi(i) * model.pij.At(i, j)))
+			s1.WriteString(strings.Repeat(string(aas[i]), n))
+			s2.WriteString(strings.Repeat(string(aas[j]), n))
+		}
+	}
+	al := align.NewAlign(align.AMINOACIDS)
+	if err := al.AddSequence("s1", s1.String(), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := al.AddSequence("s2", s2.String(), ""); err != nil {
+		t.Fatal(err)
+	}
+	return al
+}
+
+// For each built-in model, the distance between sequences having
+// the expected site patterns after a known distance is this distance
+func TestMLDist_KnownDistance(t *testing.T) {
+	for _, name := range []string{"dayoff", "jtt", "mtrev", "lg", "wag", "hivb", "hivw",
+		"cprev", "rtrev", "vt", "blosum62", "flu", "mtmam", "mtart"} {
+		code := protein.ModelStringToInt(name)
+		if code == -1 {
+			t.Errorf("Model %s should be built in", name)
+			continue
+		}
+		for _, exp := range []float64{0.1, 0.5} {
+			model, err := NewProtDistModel(code, true, false, 0, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = model.InitModel(nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			al := expectedPair(t, model, exp, 100000)
+			_, _, dist, err := model.MLDist(al, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := dist.At(0, 1); math.Abs(d-exp) > 0.005 {
+				t.Errorf("Model %s: distance should be %f, not %f", name, exp, d)
+			}
+		}
+	}
+}
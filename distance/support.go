@@ -0,0 +1,126 @@
+package distance
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/evolbioinfo/goalign/tree"
+)
+
+// Support computes bootstrap supports of the internal branches of the tree t:
+// For each internal node, the proportion of trees of boots containing the
+// same bipartition. Trees are considered unrooted, and must have the same tips.
+//
+// Supports are stored as the names of internal nodes, which is the way
+// they are written in Newick format.
+func Support(t *tree.Tree, boots []*tree.Tree) (err error) {
+	var index map[string]int
+	var refbips, bootbips map[*tree.Node]string
+
+	if len(boots) == 0 {
+		err = fmt.Errorf("no bootstrap tree to compute supports")
+		return
+	}
+
+	index = make(map[string]int)
+	for i, tip := range t.Tips() {
+		if _, ok := index[tip.Name()]; ok {
+			err = fmt.Errorf("tip %s is present several times in the tree", tip.Name())
+			return
+		}
+		index[tip.Name()] = i
+	}
+
+	if refbips, err = bipartitions(t, index); err != nil {
+		return
+	}
+	counts := make(map[string]int)
+	for _, b := range refbips {
+		counts[b] = 0
+	}
+
+	for _, boot := range boots {
+		if bootbips, err = bipartitions(boot, index); err != nil {
+			return
+		}
+		// The same bipartition may be defined by two nodes of a rooted tree
+		seen := make(map[string]bool)
+		for _, b := range bootbips {
+			if _, ok := counts[b]; ok && !seen[b] {
+				counts[b]++
+				seen[b] = true
+			}
+		}
+	}
+
+	for n, b := range refbips {
+		n.SetName(strconv.FormatFloat(float64(counts[b])/float64(len(boots)), 'f', -1, 64))
+	}
+	return
+}
+
+// bipartitions returns the non trivial bipartitions defined by the internal
+// branches of the tree. Bipartitions are encoded as bitsets of tip indices
+// (index), such that the first tip is never in the set.
+func bipartitions(t *tree.Tree, index map[string]int) (bips map[*tree.Node]string, err error) {
+	nbtips := len(index)
+	bips = make(map[*tree.Node]string)
+	tips := t.Tips()
+	if len(tips) != nbtips {
+		err = fmt.Errorf("trees do not have the same number of tips (%d vs. %d)", len(tips), nbtips)
+		return
+	}
+	seen := make([]bool, nbtips)
+	for _, tip := range tips {
+		i, ok := index[tip.Name()]
+		if !ok || seen[i] {
+			err = fmt.Errorf("trees do not have the same tips (%s)", tip.Name())
+			return
+		}
+		seen[i] = true
+	}
+	nodeBipartition(t.Root(), index, bips)
+	return
+}
+
+// nodeBipartition returns the set of tips under n, and adds the
+// bipartitions of the internal branches under n to bips
+func nodeBipartition(n *tree.Node, index map[string]int, bips map[*tree.Node]string) (set []uint64) {
+	nbtips := len(index)
+	set = make([]uint64, (nbtips+63)/64)
+	if n.Tip() {
+		i := index[n.Name()]
+		set[i/64] |= 1 << uint(i%64)
+		return
+	}
+	size := 0
+	for _, c := range n.Children() {
+		for k, v := range nodeBipartition(c, index, bips) {
+			set[k] |= v
+		}
+	}
+	for i := 0; i < nbtips; i++ {
+		if set[i/64]&(1<<uint(i%64)) != 0 {
+			size++
+		}
+	}
+	if n.Parent() == nil || size <= 1 || size >= nbtips-1 {
+		return
+	}
+
+	key := make([]byte, 8*len(set))
+	complement := set[0]&1 != 0
+	for k, v := range set {
+		if complement {
+			v = ^v
+			if k == len(set)-1 && nbtips%64 != 0 {
+				v &= (1 << uint(nbtips%64)) - 1
+			}
+		}
+		for b := 0; b < 8; b++ {
+			key[8*k+b] = byte(v >> uint(8*b))
+		}
+	}
+	bips[n] = string(key)
+	return
+}
@@ -36,7 +36,7 @@ func (layout *bioJSLayout) DrawAlign(a align.Alignment) error {
 <body>
   <pre id="fasta" style="display: none;">
 `)
-	layout.writer.WriteString(fasta.WriteAlignment(a))
+	fasta.NewWriter(layout.writer).WriteAlignment(a)
 	_, err = layout.writer.WriteString(`
   </pre>
   <div id="align" style="width:100%;height:100%;">
@@ -0,0 +1,104 @@
+package draw
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// Color schemes
+const (
+	SCHEME_AUTO       = "auto"       // nucleotide for nucleotide alignments, clustal for amino acids
+	SCHEME_CLUSTAL    = "clustal"    // Clustal X residue colors
+	SCHEME_ZAPPO      = "zappo"      // Zappo (physico-chemical properties)
+	SCHEME_NUCLEOTIDE = "nucleotide" // A, C, G and T/U colors
+	SCHEME_NONE       = "none"       // No color
+)
+
+// A ColorScheme gives the background color of each character.
+// Characters absent from the scheme are not colored.
+type ColorScheme map[uint8]color.RGBA
+
+func rgb(r, g, b uint8) color.RGBA {
+	return color.RGBA{r, g, b, 255}
+}
+
+func newScheme(groups map[string]color.RGBA) (scheme ColorScheme) {
+	scheme = make(ColorScheme)
+	for chars, c := range groups {
+		for _, r := range chars {
+			scheme[uint8(r)] = c
+			scheme[uint8(r)-'A'+'a'] = c
+		}
+	}
+	return
+}
+
+var clustalScheme = newScheme(map[string]color.RGBA{
+	"AILMFWV": rgb(0x80, 0xa0, 0xf0), // Hydrophobic
+	"KR":      rgb(0xf0, 0x15, 0x05), // Positive
+	"ED":      rgb(0xc0, 0x48, 0xc0), // Negative
+	"NQST":    rgb(0x15, 0xc0, 0x15), // Polar
+	"C":       rgb(0xf0, 0x80, 0x80), // Cysteine
+	"G":       rgb(0xf0, 0x90, 0x48), // Glycine
+	"P":       rgb(0xc0, 0xc0, 0x00), // Proline
+	"HY":      rgb(0x15, 0xa4, 0xa4), // Aromatic
+})
+
+var zappoScheme = newScheme(map[string]color.RGBA{
+	"ILVAM": rgb(0xff, 0xaf, 0xaf), // Aliphatic/hydrophobic
+	"FWY":   rgb(0xff, 0xc8, 0x00), // Aromatic
+	"KRH":   rgb(0x64, 0x64, 0xff), // Positive
+	"DE":    rgb(0xff, 0x00, 0x00), // Negative
+	"STNQ":  rgb(0x00, 0xff, 0x00), // Hydrophilic
+	"PG":    rgb(0xff, 0x00, 0xff), // Conformationally special
+	"C":     rgb(0xff, 0xff, 0x00), // Cysteine
+})
+
+var nucleotideScheme = newScheme(map[string]color.RGBA{
+	"A":  rgb(0x64, 0xf7, 0x3f),
+	"C":  rgb(0xff, 0xb3, 0x40),
+	"G":  rgb(0xeb, 0x41, 0x3c),
+	"TU": rgb(0x3c, 0x88, 0xee),
+})
+
+// GetColorScheme returns the color scheme having the given name
+// (see SCHEME_* constants) for the given alphabet (align.NUCLEOTIDS
+// or align.AMINOACIDS), or an error if it does not exist.
+func GetColorScheme(name string, alphabet int) (scheme ColorScheme, err error) {
+	switch strings.ToLower(name) {
+	case SCHEME_AUTO:
+		if alphabet == align.NUCLEOTIDS {
+			scheme = nucleotideScheme
+		} else {
+			scheme = clustalScheme
+		}
+	case SCHEME_CLUSTAL:
+		scheme = clustalScheme
+	case SCHEME_ZAPPO:
+		scheme = zappoScheme
+	case SCHEME_NUCLEOTIDE:
+		scheme = nucleotideScheme
+	case SCHEME_NONE:
+		scheme = make(ColorScheme)
+	default:
+		err = fmt.Errorf("unknown color scheme: %s", name)
+	}
+	return
+}
+
+// Color of the conservation track (darker is more conserved)
+func conservationColor(conservation int) color.RGBA {
+	switch conservation {
+	case align.POSITION_IDENTICAL:
+		return rgb(0x33, 0x33, 0x33)
+	case align.POSITION_CONSERVED:
+		return rgb(0x66, 0x66, 0x66)
+	case align.POSITION_SEMI_CONSERVED:
+		return rgb(0x99, 0x99, 0x99)
+	default:
+		return rgb(0xcc, 0xcc, 0xcc)
+	}
+}
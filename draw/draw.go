@@ -2,6 +2,7 @@
 Package intended to draw alignmentd on different devices :
  - Terminal,
  - Html file
+ - Svg and png images
  - ...
 */
 package draw
@@ -0,0 +1,188 @@
+package draw
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+func testAlign() align.Alignment {
+	al := align.NewAlign(align.NUCLEOTIDS)
+	al.AddSequence("s1", "ACGTACGTAC-T", "")
+	al.AddSequence("s2", "ACGTACCTAC-A", "")
+	al.AddSequence("s3", "ACGAACGTAC-A", "")
+	return al
+}
+
+func drawString(t *testing.T, newLayout func(*bufio.Writer, Options) AlignLayout, options Options) string {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	if err := newLayout(w, options).DrawAlign(testAlign()); err != nil {
+		t.Fatal(err)
+	}
+	w.Flush()
+	return b.String()
+}
+
+func TestTerminalLayout(t *testing.T) {
+	options := DefaultOptions()
+	options.Scheme = SCHEME_NONE
+	options.Start = 2
+	options.Length = 10
+	options.Width = 8
+
+	exp := `                    10
+              --+----|
+s1            GTACGTAC 10
+s2            GTACCTAC 10
+s3            GAACGTAC 10
+Consensus     GTACGTAC
+Conservation  * ** ***
+
+
+              --
+s1            -T 12
+s2            -A 12
+s3            -A 12
+Consensus     -A
+Conservation
+`
+	if out := drawString(t, NewTerminalLayout, options); out != exp {
+		t.Errorf("Terminal layout should be:\n%s\nand not:\n%s", exp, out)
+	}
+
+	options.Scheme = SCHEME_NUCLEOTIDE
+	options.Tracks = false
+	out := drawString(t, NewTerminalLayout, options)
+	if strings.Contains(out, "Consensus") {
+		t.Errorf("Tracks should not be drawn")
+	}
+	// T and A have different colors, -T: 1 color, 1 reset
+	if !strings.Contains(out, "s1  -\033[38;2;0;0;0;48;2;60;136;238mT\033[0m 12") {
+		t.Errorf("Colors are not correctly written:\n%s", out)
+	}
+}
+
+func TestSVGLayout(t *testing.T) {
+	options := DefaultOptions()
+	out := drawString(t, NewSVGLayout, options)
+	dec := xml.NewDecoder(strings.NewReader(out))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SVG output should be valid xml: %v", err)
+		}
+	}
+	if !strings.Contains(out, ">Consensus</text>") || !strings.Contains(out, `fill="#64f73f"`) {
+		t.Errorf("SVG output should contain the consensus track and colored nucleotides")
+	}
+}
+
+func TestPNGLayout(t *testing.T) {
+	options := DefaultOptions()
+	options.Width = 5
+	options.PixelSize = 2
+	out := drawString(t, NewPNGLayout, options)
+	img, err := png.Decode(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3 blocks of (ruler, 3 sequences, consensus, 3 conservation rows, blank row)
+	if b := img.Bounds(); b.Dx() != 10 || b.Dy() != 3*9*2 {
+		t.Errorf("PNG image should be 10x54, not %dx%d", b.Dx(), b.Dy())
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, io.ErrShortWrite
+}
+
+func TestWriteError(t *testing.T) {
+	options := DefaultOptions()
+	for _, newLayout := range []func(*bufio.Writer, Options) AlignLayout{NewTerminalLayout, NewSVGLayout, NewPNGLayout} {
+		w := bufio.NewWriterSize(failWriter{}, 16)
+		if err := newLayout(w, options).DrawAlign(testAlign()); err == nil {
+			t.Errorf("A write error should be returned")
+		}
+	}
+}
+
+func TestWrongOptions(t *testing.T) {
+	options := DefaultOptions()
+	options.Scheme = "unknown"
+	if err := NewTerminalLayout(bufio.NewWriter(&bytes.Buffer{}), options).DrawAlign(testAlign()); err == nil {
+		t.Errorf("Unknown color scheme should give an error")
+	}
+	options = DefaultOptions()
+	options.Start = 5
+	options.Length = 10
+	if err := NewSVGLayout(bufio.NewWriter(&bytes.Buffer{}), options).DrawAlign(testAlign()); err == nil {
+		t.Errorf("Region outside the alignment should give an error")
+	}
+}
+
+func TestLogoColumns(t *testing.T) {
+	al := align.NewAlign(align.NUCLEOTIDS)
+	al.AddSequence("s1", "AAAA-", "")
+	al.AddSequence("s2", "AAAA-", "")
+	al.AddSequence("s3", "ACCG-", "")
+	al.AddSequence("s4", "ACCT-", "")
+	columns, err := logoColumns(al)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := 3 / (2 * math.Ln2 * 4)
+	if c := columns[0]; c.nb != 4 || len(c.chars) != 1 || c.chars[0] != 'A' || math.Abs(c.info-(2-e)) > 1e-10 {
+		t.Errorf("Wrong logo column for site 0: %v", c)
+	}
+	if c := columns[1]; math.Abs(c.info-(1-e)) > 1e-10 || math.Abs(c.heights[0]-c.info/2) > 1e-10 {
+		t.Errorf("Wrong logo column for site 1: %v", c)
+	}
+	// A: 1/2, G and T: 1/4 => H=1.5, R < 0 => 0
+	if c := columns[3]; c.info != 0 || len(c.chars) != 3 {
+		t.Errorf("Wrong logo column for site 3: %v", c)
+	}
+	if c := columns[4]; c.nb != 0 || c.info != 0 || len(c.chars) != 0 {
+		t.Errorf("Wrong logo column for site 4 (only gaps): %v", c)
+	}
+	if columns[0].stddev <= 0 {
+		t.Errorf("Standard deviation should be positive for small samples")
+	}
+}
+
+func TestLogoRefCoordinates(t *testing.T) {
+	options := DefaultOptions()
+	options.RefSeq = "s2"
+	options.Start = 4
+	options.Length = 2
+	r, err := newRegion(testAlign(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// s2: ACGTACCTAC-A => ref positions 4 and 5 are alignment sites 4 and 5
+	if r.start != 4 || r.length != 2 || r.refsites[0] != 4 || r.refsites[1] != 5 {
+		t.Errorf("Wrong region: start=%d, length=%d, refsites=%v", r.start, r.length, r.refsites)
+	}
+	options.Start = 9
+	options.Length = -1
+	if r, err = newRegion(testAlign(), options); err != nil {
+		t.Fatal(err)
+	}
+	if r.start != 9 || r.length != 3 || r.refsites[1] != -1 || r.refsites[2] != 10 {
+		t.Errorf("Wrong region: start=%d, length=%d, refsites=%v", r.start, r.length, r.refsites)
+	}
+	out := drawString(t, NewLogoLayout, options)
+	if !strings.Contains(out, ">10</text>") || !strings.Contains(out, ">-</text>") || !strings.Contains(out, ">11</text>") {
+		t.Errorf("Logo should be labelled with reference coordinates")
+	}
+}
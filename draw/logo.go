@@ -0,0 +1,208 @@
+package draw
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// Sizes of the logo layout, in pixels
+const (
+	logoColumnWidth = 20
+	logoHeight      = 120 // Height of a stack of maximum information content
+	logoLeftMargin  = 50
+	logoTopMargin   = 10
+	logoLabelHeight = 35
+	logoFontSize    = 100
+	logoCapHeight   = 0.72 // Approximate height of capital letters, relative to the font size
+	logoCharWidth   = 0.70 // Approximate width of capital letters, relative to the font size
+)
+
+// logoColumn is the logo of a single site: letters are stacked
+// by increasing height, the highest on top
+type logoColumn struct {
+	chars   []uint8   // Characters, by increasing height
+	heights []float64 // Height of each character, in bits
+	nb      int       // Number of characters at the site (gaps and ambiguous characters excluded)
+	info    float64   // Information content, in bits
+	stddev  float64   // Standard deviation of the information content
+}
+
+type logoLayout struct {
+	writer  *bufio.Writer
+	options Options
+}
+
+// NewLogoLayout returns a layout drawing the sequence logo of the alignment
+// (Schneider & Stephens 1990) in a static svg image. At each site, the height of
+// the stack is the information content of the site, in bits:
+//
+//	R = log2(s) - (H + e(n))
+//
+// with s the alphabet size (4 or 20), H the Shannon entropy (log2) of the site,
+// and e(n) = (s-1)/(2 ln(2) n) the small sample correction, n being the number of
+// characters at the site, gaps and ambiguous characters excluded.
+// The height of each letter is its frequency times R.
+//
+// If options.ErrorBars is true, error bars of +/- 2 standard deviations of R are
+// drawn on top of each stack, with Var(R) ~ (sum(q log2(q)^2) - (sum(q log2(q)))^2)/n,
+// q being the frequencies with one pseudo count per character. They are mostly
+// visible for small sample sizes.
+//
+// The logo is split in lines of options.Width sites. If options.RefSeq is given,
+// positions are labelled with their coordinates on this reference sequence
+// ("-" if it has a gap).
+func NewLogoLayout(writer *bufio.Writer, options Options) AlignLayout {
+	return &logoLayout{writer, options}
+}
+
+/*
+Draw the alignment logo on the writer. Does not close the file. The caller must do it.
+*/
+func (layout *logoLayout) DrawAlign(a align.Alignment) (err error) {
+	var r *region
+	var columns []logoColumn
+
+	options := layout.options
+	options.Tracks = false
+	if r, err = newRegion(a, options); err != nil {
+		return
+	}
+	if columns, err = logoColumns(r.al); err != nil {
+		return
+	}
+	maxinfo := math.Log2(float64(len(r.al.AlphabetCharacters())))
+	scale := logoHeight / maxinfo // Pixels per bit
+
+	blocks := r.blocks(options.Width)
+	blockwidth := 0
+	for _, b := range blocks {
+		if b[1]-b[0] > blockwidth {
+			blockwidth = b[1] - b[0]
+		}
+	}
+	rowheight := logoTopMargin + logoHeight + logoLabelHeight
+	width := logoLeftMargin + blockwidth*logoColumnWidth + logoColumnWidth
+	height := len(blocks)*rowheight + logoTopMargin
+
+	w := layout.writer
+	w.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"Arial, Helvetica, sans-serif\">\n", width, height))
+	w.WriteString(fmt.Sprintf("<rect x=\"0\" y=\"0\" width=\"%d\" height=\"%d\" fill=\"white\"/>\n", width, height))
+
+	for bi, b := range blocks {
+		bottom := float64(bi*rowheight + logoTopMargin + logoHeight)
+		layout.drawAxis(maxinfo, scale, bottom)
+		for i := b[0]; i < b[1]; i++ {
+			x := float64(logoLeftMargin + (i-b[0])*logoColumnWidth)
+			cx := x + logoColumnWidth/2.0
+			y := bottom
+			col := columns[i]
+			for k, c := range col.chars {
+				h := col.heights[k] * scale
+				if h >= 0.1 {
+					fill := "black"
+					if color, ok := r.scheme[c]; ok {
+						fill = svgColor(color)
+					}
+					w.WriteString(fmt.Sprintf("<text transform=\"translate(%.2f,%.2f) scale(%.4f,%.4f)\" text-anchor=\"middle\" font-size=\"%d\" font-weight=\"bold\" fill=\"%s\">%c</text>\n",
+						cx, y, logoColumnWidth*0.95/(logoCharWidth*logoFontSize), h/(logoCapHeight*logoFontSize), logoFontSize, fill, c))
+				}
+				y -= h
+			}
+			if options.ErrorBars && col.nb > 0 {
+				top := math.Min(col.info+2*col.stddev, maxinfo)
+				low := math.Max(col.info-2*col.stddev, 0)
+				if top-low > 0 {
+					y1, y2 := bottom-top*scale, bottom-low*scale
+					w.WriteString(fmt.Sprintf("<path d=\"M%.2f %.2fH%.2fM%.2f %.2fV%.2fM%.2f %.2fH%.2f\" stroke=\"black\" fill=\"none\"/>\n",
+						cx-3, y1, cx+3, cx, y1, y2, cx-3, y2, cx+3))
+				}
+			}
+			label := fmt.Sprintf("%d", r.start+i+1)
+			if r.refsites != nil {
+				label = "-"
+				if r.refsites[i] >= 0 {
+					label = fmt.Sprintf("%d", r.refsites[i]+1)
+				}
+			}
+			w.WriteString(fmt.Sprintf("<text transform=\"translate(%.2f,%.2f) rotate(-90)\" text-anchor=\"end\" font-size=\"10\">%s</text>\n",
+				cx+3, bottom+4, html.EscapeString(label)))
+		}
+	}
+	w.WriteString("</svg>\n")
+	return
+}
+
+// Draws the y axis (bits) of a logo line whose bottom is at y=bottom
+func (layout *logoLayout) drawAxis(maxinfo, scale, bottom float64) {
+	w := layout.writer
+	x := float64(logoLeftMargin - 5)
+	w.WriteString(fmt.Sprintf("<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"black\"/>\n", x, bottom, x, bottom-maxinfo*scale))
+	for bit := 0; float64(bit) <= maxinfo; bit++ {
+		y := bottom - float64(bit)*scale
+		w.WriteString(fmt.Sprintf("<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"black\"/>\n", x-4, y, x, y))
+		w.WriteString(fmt.Sprintf("<text x=\"%.2f\" y=\"%.2f\" text-anchor=\"end\" font-size=\"10\">%d</text>\n", x-6, y+3, bit))
+	}
+	w.WriteString(fmt.Sprintf("<text transform=\"translate(%.2f,%.2f) rotate(-90)\" text-anchor=\"middle\" font-size=\"12\">bits</text>\n", x-22, bottom-maxinfo*scale/2))
+}
+
+// logoColumns computes the logo of each site of the alignment,
+// from the counts of the characters given by Pssm
+func logoColumns(al align.Alignment) (columns []logoColumn, err error) {
+	var counts map[uint8][]float64
+
+	if counts, err = al.Pssm(false, 0, align.PSSM_NORM_NONE); err != nil {
+		return
+	}
+	alphabet := make([]uint8, 0, len(counts))
+	for c := range counts {
+		alphabet = append(alphabet, c)
+	}
+	sort.Slice(alphabet, func(i, j int) bool { return alphabet[i] < alphabet[j] })
+	s := float64(len(alphabet))
+
+	columns = make([]logoColumn, al.Length())
+	for site := range columns {
+		col := &columns[site]
+		n := 0.0
+		for _, c := range alphabet {
+			n += counts[c][site]
+		}
+		col.nb = int(n)
+		if n == 0 {
+			continue
+		}
+		// Variance with one pseudo count per character, so that it
+		// is not null for small samples of identical characters
+		entropy, pentropy, pentropy2 := 0.0, 0.0, 0.0
+		for _, c := range alphabet {
+			if p := counts[c][site] / n; p > 0 {
+				entropy -= p * math.Log2(p)
+			}
+			q := (counts[c][site] + 1) / (n + s)
+			pentropy -= q * math.Log2(q)
+			pentropy2 += q * math.Log2(q) * math.Log2(q)
+		}
+		col.info = math.Max(0, math.Log2(s)-entropy-(s-1)/(2*math.Ln2*n))
+		col.stddev = math.Sqrt(math.Max(0, pentropy2-pentropy*pentropy) / n)
+		for _, c := range alphabet {
+			if counts[c][site] > 0 {
+				col.chars = append(col.chars, c)
+				col.heights = append(col.heights, counts[c][site]/n*col.info)
+			}
+		}
+		sort.Stable(col)
+	}
+	return
+}
+
+func (col *logoColumn) Len() int           { return len(col.chars) }
+func (col *logoColumn) Less(i, j int) bool { return col.heights[i] < col.heights[j] }
+func (col *logoColumn) Swap(i, j int) {
+	col.chars[i], col.chars[j] = col.chars[j], col.chars[i]
+	col.heights[i], col.heights[j] = col.heights[j], col.heights[i]
+}
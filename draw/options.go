@@ -0,0 +1,188 @@
+package draw
+
+import (
+	"fmt"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// Options of the terminal, svg, png and logo layouts
+type Options struct {
+	Scheme    string // Color scheme (see SCHEME_* constants)
+	RefSeq    string // Reference sequence giving the coordinates of Start and Length ("": alignment)
+	Start     int    // First site to draw (0-based)
+	Length    int    // Number of sites to draw (-1: until the end of the alignment)
+	Width     int    // Number of sites per block (0: one single block)
+	Tracks    bool   // Draws consensus and conservation tracks
+	PixelSize int    // Size of a site, in pixels (png only)
+	ErrorBars bool   // Draws error bars (logo only)
+}
+
+// DefaultOptions returns the default drawing options:
+// whole alignment, automatic color scheme, blocks of 60 sites,
+// with consensus and conservation tracks
+func DefaultOptions() Options {
+	return Options{
+		Scheme:    SCHEME_AUTO,
+		RefSeq:    "",
+		Start:     0,
+		Length:    -1,
+		Width:     60,
+		Tracks:    true,
+		PixelSize: 4,
+		ErrorBars: true,
+	}
+}
+
+// alignment region to draw, with its tracks
+type region struct {
+	al           align.Alignment // Sub-alignment corresponding to the region
+	names        []string
+	seqs         [][]uint8
+	start        int       // Coordinate of the first site on the full alignment
+	refsites     []int     // Coordinate of each site on the reference sequence (-1: gap), if any
+	length       int       // Number of sites
+	maxname      int       // Length of the longest name (including track names)
+	consensus    []uint8   // Majority character of each site (gaps excluded)
+	conservation []int     // align.POSITION_* conservation class of each site
+	identity     []float64 // Proportion of sequences having the consensus character
+	scheme       ColorScheme
+}
+
+const (
+	consensusName    = "Consensus"
+	conservationName = "Conservation"
+)
+
+func newRegion(a align.Alignment, opt Options) (r *region, err error) {
+	var sub align.Alignment
+
+	r = &region{start: opt.Start, length: opt.Length}
+	if r.scheme, err = GetColorScheme(opt.Scheme, a.Alphabet()); err != nil {
+		return
+	}
+	if opt.RefSeq != "" {
+		if err = r.refCoordinates(a, opt.RefSeq); err != nil {
+			return
+		}
+	} else if r.length < 0 {
+		r.length = a.Length() - r.start
+	}
+	if sub, err = a.SubAlign(r.start, r.length); err != nil {
+		err = fmt.Errorf("wrong region to draw: %v", err)
+		return
+	}
+
+	r.al = sub
+	sub.IterateChar(func(name string, seq []uint8) bool {
+		r.names = append(r.names, name)
+		r.seqs = append(r.seqs, seq)
+		if len(name) > r.maxname {
+			r.maxname = len(name)
+		}
+		return false
+	})
+
+	if opt.Tracks {
+		r.consensus = make([]uint8, r.length)
+		r.identity = make([]float64, r.length)
+		r.conservation = make([]int, r.length)
+		for site := 0; site < r.length; site++ {
+			var counts [256]int
+			max := 0
+			r.consensus[site] = align.GAP
+			for _, seq := range r.seqs {
+				if c := seq[site]; c != align.GAP {
+					if c >= 'a' && c <= 'z' {
+						c -= 'a' - 'A'
+					}
+					counts[c]++
+				}
+			}
+			// Ties: first character in ASCII order
+			for c, nb := range counts {
+				if nb > max {
+					max = nb
+					r.consensus[site] = uint8(c)
+				}
+			}
+			r.identity[site] = float64(max) / float64(len(r.seqs))
+		}
+		for _, s := range sub.SiteScores() {
+			r.conservation[s.Site] = s.Conservation
+		}
+		if len(conservationName) > r.maxname {
+			r.maxname = len(conservationName)
+		}
+	}
+	return
+}
+
+// refCoordinates converts the region coordinates, given on the reference
+// sequence refseq (without gaps), into alignment coordinates, and computes
+// the reference coordinates of each site of the region
+func (r *region) refCoordinates(a align.Alignment, refseq string) (err error) {
+	var seq []uint8
+	var exists bool
+
+	if seq, exists = a.GetSequenceChar(refseq); !exists {
+		err = fmt.Errorf("reference sequence %s does not exist in the alignment", refseq)
+		return
+	}
+	if r.length < 0 {
+		r.length = len(seq) - r.start
+		for _, c := range seq {
+			if c == align.GAP {
+				r.length--
+			}
+		}
+	}
+	if r.start, r.length, err = a.RefCoordinates(refseq, r.start, r.length); err != nil {
+		err = fmt.Errorf("wrong region to draw: %v", err)
+		return
+	}
+	r.refsites = make([]int, r.length)
+	pos := 0
+	for i, c := range seq[:r.start+r.length] {
+		if c == align.GAP {
+			if i >= r.start {
+				r.refsites[i-r.start] = -1
+			}
+			continue
+		}
+		if i >= r.start {
+			r.refsites[i-r.start] = pos
+		}
+		pos++
+	}
+	return
+}
+
+// blocks returns the [start,end[ bounds of each block
+// of width sites (relative to the region)
+func (r *region) blocks(width int) (blocks [][2]int) {
+	if width <= 0 {
+		width = r.length
+	}
+	for s := 0; s < r.length; s += width {
+		e := s + width
+		if e > r.length {
+			e = r.length
+		}
+		blocks = append(blocks, [2]int{s, e})
+	}
+	return
+}
+
+func conservationSymbol(conservation int) uint8 {
+	switch conservation {
+	case align.POSITION_IDENTICAL:
+		return '*'
+	case align.POSITION_CONSERVED:
+		return ':'
+	case align.POSITION_SEMI_CONSERVED:
+		return '.'
+	default:
+		return ' '
+	}
+}
@@ -0,0 +1,105 @@
+package draw
+
+import (
+	"bufio"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+type pngLayout struct {
+	writer  *bufio.Writer
+	options Options
+}
+
+// NewPNGLayout returns a layout drawing alignments in a png image. Each site
+// of each sequence is a square of options.PixelSize pixels, colored according
+// to the color scheme (characters absent from the scheme are light gray, and
+// gaps are white). No text is written (names, characters, coordinates): the
+// ruler is a line of ticks every 5 (light) and 10 (dark) sites. The alignment is
+// split in blocks of options.Width sites, and the conservation track is the
+// same bar plot as in the svg layout.
+func NewPNGLayout(writer *bufio.Writer, options Options) AlignLayout {
+	return &pngLayout{writer, options}
+}
+
+/*
+Draw the alignment on the writer. Does not close the file. The caller must do it.
+*/
+func (layout *pngLayout) DrawAlign(a align.Alignment) (err error) {
+	var r *region
+
+	if r, err = newRegion(a, layout.options); err != nil {
+		return
+	}
+	px := layout.options.PixelSize
+	if px < 1 {
+		px = 1
+	}
+	blocks := r.blocks(layout.options.Width)
+	blockwidth := 0
+	for _, b := range blocks {
+		if b[1]-b[0] > blockwidth {
+			blockwidth = b[1] - b[0]
+		}
+	}
+	// Rows of a block: ruler, sequences, consensus, conservation (3 rows), blank row
+	rows := len(r.seqs) + 2
+	if r.consensus != nil {
+		rows += 4
+	}
+	width, height := blockwidth*px, len(blocks)*rows*px
+	if width == 0 || height == 0 {
+		width, height = 1, 1
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fill(img, 0, 0, width, height, color.RGBA{255, 255, 255, 255})
+	other := color.RGBA{0xd0, 0xd0, 0xd0, 255}
+
+	y := 0
+	for _, b := range blocks {
+		for i := b[0]; i < b[1]; i++ {
+			pos := r.start + i + 1
+			if pos%10 == 0 {
+				fill(img, (i-b[0])*px, y, px, px, color.RGBA{0, 0, 0, 255})
+			} else if pos%5 == 0 {
+				fill(img, (i-b[0])*px, y+px/2, px, px-px/2, color.RGBA{0x80, 0x80, 0x80, 255})
+			}
+		}
+		y += px
+		seqs := r.seqs
+		if r.consensus != nil {
+			seqs = append(seqs[:len(seqs):len(seqs)], r.consensus)
+		}
+		for _, seq := range seqs {
+			for i, c := range seq[b[0]:b[1]] {
+				if col, ok := r.scheme[c]; ok {
+					fill(img, i*px, y, px, px, col)
+				} else if c != align.GAP {
+					fill(img, i*px, y, px, px, other)
+				}
+			}
+			y += px
+		}
+		if r.consensus != nil {
+			for i := b[0]; i < b[1]; i++ {
+				h := int(r.identity[i]*float64(3*px) + 0.5)
+				fill(img, (i-b[0])*px, y+3*px-h, px, h, conservationColor(r.conservation[i]))
+			}
+			y += 3 * px
+		}
+		y += px
+	}
+	return png.Encode(layout.writer, img)
+}
+
+func fill(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	for i := x; i < x+w; i++ {
+		for j := y; j < y+h; j++ {
+			img.SetRGBA(i, j, c)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package draw
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// Size of a site in the svg layout, in pixels
+const (
+	svgCellWidth  = 10
+	svgCellHeight = 14
+	svgCharWidth  = 7 // Approximate width of a character of the names
+	svgFontSize   = 11
+)
+
+type svgLayout struct {
+	writer  *bufio.Writer
+	options Options
+}
+
+// NewSVGLayout returns a layout drawing alignments in a static svg image.
+// The alignment is split in blocks of options.Width sites, each block
+// starting with a ruler (1-based coordinates). Consensus and conservation
+// tracks are drawn below each block: the conservation track is a bar plot of
+// the proportion of sequences having the consensus character, colored according
+// to the clustal conservation class of the site (darker is more conserved).
+func NewSVGLayout(writer *bufio.Writer, options Options) AlignLayout {
+	return &svgLayout{writer, options}
+}
+
+/*
+Draw the alignment on the writer. Does not close the file. The caller must do it.
+*/
+func (layout *svgLayout) DrawAlign(a align.Alignment) (err error) {
+	var r *region
+
+	if r, err = newRegion(a, layout.options); err != nil {
+		return
+	}
+	blocks := r.blocks(layout.options.Width)
+	blockwidth := 0
+	for _, b := range blocks {
+		if b[1]-b[0] > blockwidth {
+			blockwidth = b[1] - b[0]
+		}
+	}
+	// Rows of a block: ruler, sequences, consensus, conservation (2 rows), blank row
+	rows := len(r.seqs) + 2
+	if r.consensus != nil {
+		rows += 3
+	}
+	namewidth := (r.maxname + 2) * svgCharWidth
+	width := namewidth + blockwidth*svgCellWidth + 8*svgCharWidth
+	height := len(blocks) * rows * svgCellHeight
+
+	w := layout.writer
+	w.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"monospace\" font-size=\"%d\">\n", width, height, svgFontSize))
+	w.WriteString(fmt.Sprintf("<rect x=\"0\" y=\"0\" width=\"%d\" height=\"%d\" fill=\"white\"/>\n", width, height))
+
+	y := 0
+	for _, b := range blocks {
+		layout.drawRuler(r, b[0], b[1], namewidth, y)
+		y += svgCellHeight
+		for s, seq := range r.seqs {
+			layout.drawName(r.names[s], y)
+			layout.drawChars(r, seq[b[0]:b[1]], namewidth, y)
+			w.WriteString(fmt.Sprintf("<text x=\"%d\" y=\"%d\">%d</text>\n",
+				namewidth+(b[1]-b[0])*svgCellWidth+svgCharWidth, y+svgCellHeight-3, r.start+b[1]))
+			y += svgCellHeight
+		}
+		if r.consensus != nil {
+			layout.drawName(consensusName, y)
+			layout.drawChars(r, r.consensus[b[0]:b[1]], namewidth, y)
+			y += svgCellHeight
+			layout.drawName(conservationName, y+svgCellHeight/2)
+			for i := b[0]; i < b[1]; i++ {
+				h := int(r.identity[i]*2*svgCellHeight + 0.5)
+				w.WriteString(fmt.Sprintf("<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+					namewidth+(i-b[0])*svgCellWidth, y+2*svgCellHeight-h, svgCellWidth-1, h, svgColor(conservationColor(r.conservation[i]))))
+			}
+			y += 2 * svgCellHeight
+		}
+		y += svgCellHeight
+	}
+	// bufio.Writer errors are sticky: the last write reports any previous failure
+	_, err = w.WriteString("</svg>\n")
+	return
+}
+
+func (layout *svgLayout) drawName(name string, y int) {
+	layout.writer.WriteString(fmt.Sprintf("<text x=\"%d\" y=\"%d\">%s</text>\n", svgCharWidth/2, y+svgCellHeight-3, html.EscapeString(name)))
+}
+
+// Draws the ruler of the sites [start,end[ of the region: coordinates every
+// 10 sites, and ticks every 5 sites
+func (layout *svgLayout) drawRuler(r *region, start, end, x, y int) {
+	for i := start; i < end; i++ {
+		pos := r.start + i + 1
+		cx := x + (i-start)*svgCellWidth + svgCellWidth/2
+		if pos%10 == 0 {
+			layout.writer.WriteString(fmt.Sprintf("<text x=\"%d\" y=\"%d\" text-anchor=\"middle\" font-size=\"%d\">%d</text>\n", cx, y+svgCellHeight-5, svgFontSize-2, pos))
+		}
+		if pos%5 == 0 {
+			layout.writer.WriteString(fmt.Sprintf("<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"black\"/>\n", cx, y+svgCellHeight-3, cx, y+svgCellHeight))
+		}
+	}
+}
+
+func (layout *svgLayout) drawChars(r *region, chars []uint8, x, y int) {
+	for i, c := range chars {
+		if col, ok := r.scheme[c]; ok {
+			layout.writer.WriteString(fmt.Sprintf("<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+				x+i*svgCellWidth, y, svgCellWidth, svgCellHeight, svgColor(col)))
+		}
+		layout.writer.WriteString(fmt.Sprintf("<text x=\"%d\" y=\"%d\" text-anchor=\"middle\">%s</text>\n",
+			x+i*svgCellWidth+svgCellWidth/2, y+svgCellHeight-3, html.EscapeString(string(c))))
+	}
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,125 @@
+package draw
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+type terminalLayout struct {
+	writer  *bufio.Writer
+	options Options
+}
+
+// NewTerminalLayout returns a layout drawing alignments in a terminal, with
+// ANSI (24 bits) colors. The alignment is split in pages (blocks) of
+// options.Width sites, each page starting with a column ruler (1-based
+// coordinates). With the "none" color scheme, no escape code is written.
+func NewTerminalLayout(writer *bufio.Writer, options Options) AlignLayout {
+	return &terminalLayout{writer, options}
+}
+
+/*
+Draw the alignment on the writer. Does not close the file. The caller must do it.
+*/
+func (layout *terminalLayout) DrawAlign(a align.Alignment) (err error) {
+	var r *region
+
+	if r, err = newRegion(a, layout.options); err != nil {
+		return
+	}
+	// bufio.Writer errors are sticky: the last write of each line
+	// reports any previous failure
+	for i, b := range r.blocks(layout.options.Width) {
+		if i > 0 {
+			layout.writer.WriteString("\n")
+		}
+		if err = layout.drawRuler(r, b[0], b[1]); err != nil {
+			return
+		}
+		for s, seq := range r.seqs {
+			layout.drawName(r, r.names[s])
+			layout.drawChars(r, seq[b[0]:b[1]])
+			if _, err = layout.writer.WriteString(fmt.Sprintf(" %d\n", r.start+b[1])); err != nil {
+				return
+			}
+		}
+		if r.consensus != nil {
+			layout.drawName(r, consensusName)
+			layout.drawChars(r, r.consensus[b[0]:b[1]])
+			layout.writer.WriteString("\n")
+			symbols := make([]byte, 0, b[1]-b[0])
+			for _, c := range r.conservation[b[0]:b[1]] {
+				symbols = append(symbols, conservationSymbol(c))
+			}
+			if err = layout.drawTrimmedLine(r, conservationName, symbols); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (layout *terminalLayout) drawName(r *region, name string) {
+	layout.writer.WriteString(name)
+	layout.writer.WriteString(strings.Repeat(" ", r.maxname-len(name)+2))
+}
+
+// Draws a line without color, and without trailing spaces
+func (layout *terminalLayout) drawTrimmedLine(r *region, name string, content []byte) (err error) {
+	line := name + strings.Repeat(" ", r.maxname-len(name)+2) + string(content)
+	layout.writer.WriteString(strings.TrimRight(line, " "))
+	_, err = layout.writer.WriteString("\n")
+	return
+}
+
+// Draws the ruler of the sites [start,end[ of the region: a line of
+// coordinates (every 10 sites), and a line of ticks
+func (layout *terminalLayout) drawRuler(r *region, start, end int) (err error) {
+	numbers := []byte(strings.Repeat(" ", end-start))
+	ticks := make([]byte, end-start)
+	for i := start; i < end; i++ {
+		pos := r.start + i + 1
+		switch {
+		case pos%10 == 0:
+			ticks[i-start] = '|'
+			label := fmt.Sprintf("%d", pos)
+			// Number ends at the tick
+			if s := i - start - len(label) + 1; s >= 0 {
+				copy(numbers[s:], label)
+			}
+		case pos%5 == 0:
+			ticks[i-start] = '+'
+		default:
+			ticks[i-start] = '-'
+		}
+	}
+	layout.drawTrimmedLine(r, "", numbers)
+	layout.drawName(r, "")
+	layout.writer.Write(ticks)
+	_, err = layout.writer.WriteString("\n")
+	return
+}
+
+// Writes the characters with their background color. Escape codes
+// are only written when the color changes.
+func (layout *terminalLayout) drawChars(r *region, chars []uint8) {
+	var cur color.RGBA
+	colored := false
+	for _, c := range chars {
+		col, ok := r.scheme[c]
+		if ok && (!colored || col != cur) {
+			layout.writer.WriteString(fmt.Sprintf("\033[38;2;0;0;0;48;2;%d;%d;%dm", col.R, col.G, col.B))
+		} else if !ok && colored {
+			layout.writer.WriteString("\033[0m")
+		}
+		colored, cur = ok, col
+		layout.writer.WriteByte(c)
+	}
+	if colored {
+		layout.writer.WriteString("\033[0m")
+	}
+}
@@ -1,6 +1,7 @@
 package clustal
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 	"testing"
@@ -177,3 +178,27 @@ func TestParseError2(t *testing.T) {
 		t.Error("There should be an error while reading this alignment")
 	}
 }
+
+func TestWriterLineWidth(t *testing.T) {
+	al, err := NewParser(strings.NewReader(clustalstring2)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.LineWidth = 12
+	if err = w.WriteAlignment(al); err != nil {
+		t.Fatal(err)
+	}
+	// 30 sites: 3 blocks of 12, 12 and 6 sites
+	if n := strings.Count(buf.String(), " 12\n"); n != 6 {
+		t.Errorf("There should be 6 lines ending at site 12 (%d)", n)
+	}
+	al2, err := NewParser(strings.NewReader(buf.String())).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !al.Identical(al2) {
+		t.Errorf("Alignment is different after round trip")
+	}
+}
@@ -1,8 +1,10 @@
 package clustal
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/evolbioinfo/goalign/align"
 	"github.com/evolbioinfo/goalign/version"
@@ -19,10 +21,29 @@ func min_int(a int, b int) int {
 	return b
 }
 
-func WriteAlignment(al align.Alignment) string {
-	var buf bytes.Buffer
+// Writer writes alignments in clustal format to an io.Writer.
+// Sequences are written as they are iterated, the whole
+// output is never stored in memory.
+type Writer struct {
+	w         *bufio.Writer
+	LineWidth int // Number of sites per block (<=0: a single block)
+}
+
+// NewWriter returns a clustal writer to w, with blocks of CLUSTAL_LINE sites.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w), LineWidth: CLUSTAL_LINE}
+}
+
+// WriteAlignment writes the alignment, with a conservation line
+// after each block, and flushes the writer.
+func (w *Writer) WriteAlignment(al align.Alignment) (err error) {
+	var line_length = w.LineWidth
 	cursize := 0
 
+	if line_length <= 0 {
+		line_length = al.Length()
+	}
+
 	// Get length of the longest name
 	maxnamelength := 0
 	al.IterateChar(func(name string, seq []uint8) bool {
@@ -32,49 +53,58 @@ func WriteAlignment(al align.Alignment) string {
 		return false
 	})
 
-	buf.WriteString(fmt.Sprintf("CLUSTAL W (goalign version %s)\n\n", version.Version))
-	for cursize < al.Length() {
+	w.w.WriteString(fmt.Sprintf("CLUSTAL W (goalign version %s)\n\n", version.Version))
+	for cursize < al.Length() && err == nil {
 		if cursize > 0 {
-			buf.WriteRune('\n')
+			w.w.WriteRune('\n')
 		}
 		end := 0
 		al.IterateChar(func(name string, seq []uint8) bool {
-			buf.WriteString(name)
+			w.w.WriteString(name)
 			for i := len(name); i < maxnamelength+3; i++ {
-				buf.WriteRune(' ')
+				w.w.WriteRune(' ')
 			}
 
-			end = min_int(cursize+CLUSTAL_LINE, len(seq))
-			for j := cursize; j < end; j++ {
-				buf.WriteByte(seq[j])
-			}
-			buf.WriteRune(' ')
-			buf.WriteString(fmt.Sprintf("%d", end))
-			buf.WriteRune('\n')
-			return false
+			end = min_int(cursize+line_length, len(seq))
+			w.w.Write(seq[cursize:end])
+			w.w.WriteRune(' ')
+			w.w.WriteString(fmt.Sprintf("%d", end))
+			_, err = w.w.WriteRune('\n')
+			return err != nil
 		})
 		// Conservation line
 		// White spaces
 		for i := 0; i < maxnamelength+3; i++ {
-			buf.WriteRune(' ')
+			w.w.WriteRune(' ')
 		}
 		// Each position in the line
 		for pos := cursize; pos < end; pos++ {
 			conservation, _ := al.SiteConservation(pos)
 			switch conservation {
 			case align.POSITION_IDENTICAL:
-				buf.WriteRune('*')
+				w.w.WriteRune('*')
 			case align.POSITION_CONSERVED:
-				buf.WriteRune(':')
+				w.w.WriteRune(':')
 			case align.POSITION_SEMI_CONSERVED:
-				buf.WriteRune('.')
+				w.w.WriteRune('.')
 			default:
-				buf.WriteRune(' ')
+				w.w.WriteRune(' ')
 			}
 		}
 
-		buf.WriteRune('\n')
-		cursize += CLUSTAL_LINE
+		w.w.WriteRune('\n')
+		cursize += line_length
 	}
+	if err != nil {
+		return
+	}
+	return w.w.Flush()
+}
+
+// WriteAlignment returns the alignment in clustal format. For large alignments,
+// prefer Writer, which does not store the whole output in memory.
+func WriteAlignment(al align.Alignment) string {
+	var buf bytes.Buffer
+	NewWriter(&buf).WriteAlignment(al)
 	return buf.String()
 }
@@ -0,0 +1,79 @@
+// Package distmatrix parses distance matrices in PHYLIP format, such as
+// the ones written by goalign compute distance and goalign build distboot.
+package distmatrix
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Matrix is a square distance matrix, with the names of its taxa,
+// in the same order as its rows
+type Matrix struct {
+	Names []string
+	Dist  [][]float64
+}
+
+// Parse parses all the distance matrices of the input, one after the other.
+// Each matrix is in (square) PHYLIP format:
+//
+//	3
+//	taxon1 0 0.1 0.2
+//	taxon2 0.1 0 0.3
+//	taxon3 0.2 0.3 0
+//
+// The first line gives the number of taxa, and each row starts with the
+// name of the taxon followed by its distances. Fields are separated by
+// spaces or tabs, and rows may span several lines. Names can not contain spaces.
+func Parse(r io.Reader) (matrices []Matrix, err error) {
+	var n int
+	var word string
+	var ok bool
+	var m Matrix
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024*1024)
+	scanner.Split(bufio.ScanWords)
+
+	next := func() (string, bool) {
+		if scanner.Scan() {
+			return scanner.Text(), true
+		}
+		return "", false
+	}
+
+	for word, ok = next(); ok; word, ok = next() {
+		if n, err = strconv.Atoi(word); err != nil || n <= 0 {
+			err = fmt.Errorf("matrix %d: expecting the number of taxa, got %q", len(matrices)+1, word)
+			return
+		}
+		m = Matrix{Names: make([]string, n), Dist: make([][]float64, n)}
+		for i := 0; i < n; i++ {
+			if m.Names[i], ok = next(); !ok {
+				err = fmt.Errorf("matrix %d: end of input, expecting the name of taxon %d", len(matrices)+1, i+1)
+				return
+			}
+			m.Dist[i] = make([]float64, n)
+			for j := 0; j < n; j++ {
+				if word, ok = next(); !ok {
+					err = fmt.Errorf("matrix %d: end of input, expecting %d distances for taxon %s", len(matrices)+1, n, m.Names[i])
+					return
+				}
+				if m.Dist[i][j], err = strconv.ParseFloat(word, 64); err != nil {
+					err = fmt.Errorf("matrix %d: wrong distance %q for taxon %s", len(matrices)+1, word, m.Names[i])
+					return
+				}
+			}
+		}
+		matrices = append(matrices, m)
+	}
+	if err = scanner.Err(); err != nil {
+		return
+	}
+	if len(matrices) == 0 {
+		err = fmt.Errorf("no distance matrix in the input")
+	}
+	return
+}
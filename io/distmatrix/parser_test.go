@@ -0,0 +1,45 @@
+package distmatrix
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := `3
+A	0.000000000000	0.100000000000	0.200000000000
+B	0.100000000000	0.000000000000	0.300000000000
+C	0.200000000000	0.300000000000	0.000000000000
+2
+A 0 0.5
+B
+0.5 0
+`
+	matrices, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	exp := []Matrix{
+		{[]string{"A", "B", "C"}, [][]float64{{0, 0.1, 0.2}, {0.1, 0, 0.3}, {0.2, 0.3, 0}}},
+		{[]string{"A", "B"}, [][]float64{{0, 0.5}, {0.5, 0}}},
+	}
+	if !reflect.DeepEqual(matrices, exp) {
+		t.Errorf("Parsed matrices are not the expected ones: %v vs. %v", matrices, exp)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, bad := range []string{
+		"",
+		"A 0 1\n",
+		"2\nA 0 1\nB 1\n",
+		"2\nA 0 x\nB 1 0\n",
+		"2\nA 0 1\nB 1 0\n3\n",
+	} {
+		if _, err := Parse(strings.NewReader(bad)); err == nil {
+			t.Errorf("Parsing %q should return an error", bad)
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 type Parser struct {
 	s               *Scanner
 	ignoreidentical int
+	started         bool // true if the first ">" has been parsed
 	buf             struct {
 		tok Token  // last read token
 		lit string // last read literal
@@ -25,6 +26,8 @@ type Parser struct {
 	}
 }
 
+var firstSpaces = regexp.MustCompile("^( +)")
+
 // NewParser returns a new instance of Parser.
 func NewParser(r io.Reader) *Parser {
 	return &Parser{s: NewScanner(r), ignoreidentical: align.IGNORE_NONE}
@@ -82,45 +85,75 @@ func (p *Parser) ParseUnalign() (sb align.SeqBag, err error) {
 }
 
 func (p *Parser) parseGeneric(sb align.SeqBag) (err error) {
-	// The first token should be a ">"
-	tok, lit := p.scanIgnoreEndOfLine()
-	if tok != STARTIDENT {
-		err = errors.New("Fasta file should start with a > ")
+	var s align.Sequence
+
+	for {
+		if s, err = p.Next(); err == io.EOF {
+			err = nil
+			break
+		} else if err != nil {
+			return
+		}
+		if err = sb.AddSequenceChar(s.Name(), s.SequenceChar(), s.Comment()); err != nil {
+			return
+		}
+	}
+	sb.AutoAlphabet()
+	return
+}
+
+// Next parses the next sequence of the FASTA file, without
+// storing the previous ones: It allows to process large files
+// one sequence at a time.
+//
+// It returns io.EOF when there is no more sequence in the file.
+//
+// Contrary to Parse and ParseUnalign, duplicate sequence names are
+// not handled (IgnoreIdentical), and sequence lengths are not checked.
+func (p *Parser) Next() (s align.Sequence, err error) {
+	var tok Token
+	var lit string
+	var name string
+	var seq bytes.Buffer
+
+	tok, lit = p.scanIgnoreEndOfLine()
+	if !p.started {
+		// The first token should be a ">"
+		if tok != STARTIDENT {
+			err = errors.New("Fasta file should start with a > ")
+			return
+		}
+		p.started = true
+	}
+	if tok == EOF {
+		err = io.EOF
 		return
 	}
-	p.unscan()
-	var curseq bytes.Buffer
-	curname := ""
-	firstSpaces := regexp.MustCompile("^( +)")
-	for tok != EOF {
+
+	if tok, lit = p.scan(); tok != IDENTIFIER {
+		err = errors.New("> should be followed by a sequence identifier")
+		return
+	}
+	name = firstSpaces.ReplaceAllString(lit, "")
+
+	for {
 		tok, lit = p.scanIgnoreEndOfLine()
-		switch tok {
-		case STARTIDENT:
-			tok, lit = p.scan()
-			if tok != IDENTIFIER {
-				err = errors.New("> should be followed by a sequence identifier")
-				return
-			}
-			if curseq.Len() > 0 {
-				if err = sb.AddSequence(curname, curseq.String(), ""); err != nil {
-					return
-				}
-				curseq.Reset()
-			} else if curname != "" {
-				err = errors.New("A Fasta entry has a name but no sequence (" + curname + ")")
-				return
-			}
-			curname = firstSpaces.ReplaceAllString(lit, "")
-		case IDENTIFIER:
-			curseq.WriteString(strings.Replace(lit, " ", "", -1))
-		case EOF:
-			if curseq.Len() > 0 {
-				if err = sb.AddSequence(curname, curseq.String(), ""); err != nil {
-					return
-				}
-			}
+		if tok == IDENTIFIER {
+			seq.WriteString(strings.Replace(lit, " ", "", -1))
+			continue
 		}
+		p.unscan()
+		break
 	}
-	sb.AutoAlphabet()
+
+	if seq.Len() == 0 {
+		if tok == EOF {
+			err = io.EOF
+			return
+		}
+		err = errors.New("A Fasta entry has a name but no sequence (" + name + ")")
+		return
+	}
+	s = align.NewSequence(name, []uint8(seq.String()), "")
 	return
 }
@@ -3,6 +3,7 @@ package fasta
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 )
@@ -60,3 +61,68 @@ func TestParse(t *testing.T) {
 		t.Errorf("Alignment has not 1000 sequences : %d", align5.NbSequences())
 	}
 }
+
+func TestNext(t *testing.T) {
+	var names []string
+	var lengths []int
+
+	p := NewParser(strings.NewReader(fastastring4 + ">s1\nAC GT\n\n"))
+	for {
+		s, err := p.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Error(err)
+			return
+		}
+		names = append(names, s.Name())
+		lengths = append(lengths, s.Length())
+	}
+	expnames := []string{"s1", "s2", "s1"}
+	explengths := []int{34, 33, 4}
+	if len(names) != len(expnames) {
+		t.Errorf("There should be %d sequences, not %d", len(expnames), len(names))
+		return
+	}
+	for i, n := range expnames {
+		if names[i] != n || lengths[i] != explengths[i] {
+			t.Errorf("Sequence %d should be %s (length %d), not %s (length %d)", i, n, explengths[i], names[i], lengths[i])
+		}
+	}
+
+	if _, err := NewParser(strings.NewReader(fastastring2)).Next(); err == nil {
+		t.Errorf("There should be an error while parsing fastastring2")
+	}
+	p = NewParser(strings.NewReader(">s1\n>s2\nACGT\n"))
+	if _, err := p.Next(); err == nil {
+		t.Errorf("There should be an error while parsing a sequence without characters")
+	}
+}
+
+func TestWriterLineWidth(t *testing.T) {
+	al, err := NewParser(strings.NewReader(fastastring3)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.LineWidth = 15
+	if err = w.WriteAlignment(al); err != nil {
+		t.Fatal(err)
+	}
+	exp := ">s1\nACGATCGATTACTAC\nTGACACGACTGATCG\nATCG\n>s2\nACGATCGATTACTAC\nTGACACGACTGATCG\nATCG\n"
+	if buf.String() != exp {
+		t.Errorf("Fasta output should be:\n%s\nand not:\n%s", exp, buf.String())
+	}
+	buf.Reset()
+	w.LineWidth = 0
+	if err = w.WriteAlignment(al); err != nil {
+		t.Fatal(err)
+	}
+	if exp = ">s1\nACGATCGATTACTACTGACACGACTGATCGATCG\n>s2\nACGATCGATTACTACTGACACGACTGATCGATCG\n"; buf.String() != exp {
+		t.Errorf("Fasta output should be:\n%s\nand not:\n%s", exp, buf.String())
+	}
+	if out := WriteAlignment(al); out != ">s1\nACGATCGATTACTACTGACACGACTGATCGATCG\n>s2\nACGATCGATTACTACTGACACGACTGATCGATCG\n" {
+		t.Errorf("Wrong fasta output: %s", out)
+	}
+}
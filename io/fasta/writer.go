@@ -1,7 +1,9 @@
 package fasta
 
 import (
+	"bufio"
 	"bytes"
+	"io"
 
 	"github.com/evolbioinfo/goalign/align"
 )
@@ -17,48 +19,93 @@ func min_int(a int, b int) int {
 	return b
 }
 
-func WriteAlignment(sb align.SeqBag) string {
-	var buf bytes.Buffer
+// Writer writes sequences in fasta format to an io.Writer.
+// Sequences are written as they are iterated, the whole
+// output is never stored in memory.
+type Writer struct {
+	w         *bufio.Writer
+	LineWidth int // Number of characters per line (<=0: each sequence on a single line)
+}
+
+// Sequences is what a Writer may write: an align.SeqBag, an align.Alignment,
+// or an align.PackedAlign, whose sequences are decoded one at a time.
+type Sequences interface {
+	IterateChar(it func(name string, sequence []uint8) bool)
+}
+
+// NewWriter returns a fasta writer to w, with lines
+// of FASTA_LINE characters.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w), LineWidth: FASTA_LINE}
+}
+
+// WriteAlignment writes the sequences, including gaps, and flushes the writer.
+func (w *Writer) WriteAlignment(sb Sequences) (err error) {
 	sb.IterateChar(func(name string, seq []uint8) bool {
-		buf.WriteString(">")
-		buf.WriteString(name)
-		buf.WriteString("\n")
-		for i := 0; i < len(seq); i++ {
-			if i%FASTA_LINE == 0 && i > 0 {
-				buf.WriteString("\n")
+		w.w.WriteString(">")
+		w.w.WriteString(name)
+		w.w.WriteString("\n")
+		width := w.LineWidth
+		if width <= 0 {
+			width = len(seq)
+		}
+		for i := 0; i < len(seq); i += width {
+			if i > 0 {
+				w.w.WriteString("\n")
 			}
-			buf.WriteByte(seq[i])
+			w.w.Write(seq[i:min_int(i+width, len(seq))])
 		}
-		buf.WriteRune('\n')
-		return false
+		_, err = w.w.WriteString("\n")
+		return err != nil
 	})
-	return buf.String()
+	if err != nil {
+		return
+	}
+	return w.w.Flush()
 }
 
-// Write input alignment as standard fasta sequences
-// It removes "-" characters.
-func WriteSequences(sb align.SeqBag) string {
-	var buf bytes.Buffer
-
+// WriteSequences writes the sequences as standard fasta sequences,
+// removing "-" characters, and flushes the writer.
+func (w *Writer) WriteSequences(sb align.SeqBag) (err error) {
 	sb.IterateChar(func(name string, seq []uint8) bool {
-		buf.WriteString(">")
-		buf.WriteString(name)
-		buf.WriteString("\n")
+		w.w.WriteString(">")
+		w.w.WriteString(name)
+		_, err = w.w.WriteString("\n")
 		nbchar := 0
 		for i := 0; i < len(seq); i++ {
 			if seq[i] != '-' {
-				buf.WriteByte(seq[i])
+				w.w.WriteByte(seq[i])
 				nbchar++
-				if nbchar == FASTA_LINE {
-					buf.WriteString("\n")
+				if nbchar == w.LineWidth {
+					w.w.WriteString("\n")
 					nbchar = 0
 				}
 			}
 		}
 		if nbchar != 0 {
-			buf.WriteString("\n")
+			_, err = w.w.WriteString("\n")
 		}
-		return false
+		return err != nil
 	})
+	if err != nil {
+		return
+	}
+	return w.w.Flush()
+}
+
+// WriteAlignment returns the sequences in fasta format, with lines of
+// FASTA_LINE characters. For large alignments, prefer Writer, which does not
+// store the whole output in memory.
+func WriteAlignment(sb align.SeqBag) string {
+	var buf bytes.Buffer
+	NewWriter(&buf).WriteAlignment(sb)
+	return buf.String()
+}
+
+// Write input alignment as standard fasta sequences
+// It removes "-" characters.
+func WriteSequences(sb align.SeqBag) string {
+	var buf bytes.Buffer
+	NewWriter(&buf).WriteSequences(sb)
 	return buf.String()
 }
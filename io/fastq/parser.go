@@ -0,0 +1,212 @@
+package fastq
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/evolbioinfo/goalign/align"
+	aio "github.com/evolbioinfo/goalign/io"
+)
+
+const (
+	// Offset of Phred quality scores in fastq files (Sanger / Illumina 1.8+)
+	PHRED_OFFSET = 33
+	// Character replacing low quality bases (see Parser.MaskQuality)
+	MASK_CHAR = 'N'
+)
+
+// Parser represents a fastq parser.
+// If ignore is align.IGNORE_NONE: Does not ignore anything
+// If ignore is align.IGNORE_NAME: Ignore sequences having the same name (keep the first one whatever their sequence)
+// If ignore is align.IGNORE_SEQUENCE: Ignore sequences having the same name and the same sequence
+// Otherwise, sets IGNORE_NONE
+//
+// Each fastq record must have 4 lines: "@name", the sequence, "+" (possibly followed
+// by the name again), and the qualities (Phred+33 encoded).
+type Parser struct {
+	r               *bufio.Reader
+	ignoreidentical int
+	trimqual        int // Minimum quality of the ends of the sequences (see TrimQuality)
+	maskqual        int // Minimum quality of the bases (see MaskQuality)
+	line            int // Current line number
+}
+
+// NewParser returns a new instance of Parser.
+func NewParser(r io.Reader) *Parser {
+	return &Parser{r: bufio.NewReader(r), ignoreidentical: align.IGNORE_NONE}
+}
+
+// If sets to true, then will ignore duplicate sequences that have the same name and the same sequence
+// Otherwise, it just renames them just as the sequences that have same name and different sequences
+func (p *Parser) IgnoreIdentical(ignore int) {
+	p.ignoreidentical = ignore
+}
+
+// TrimQuality sets the minimum quality of the ends of the sequences: bases having a
+// quality < minqual are removed from both ends of each sequence.
+// If minqual <= 0, sequences are not trimmed (default).
+func (p *Parser) TrimQuality(minqual int) {
+	p.trimqual = minqual
+}
+
+// MaskQuality sets the minimum quality of the bases: bases having a quality < minqual
+// are replaced by MASK_CHAR. Masking is done after trimming (see TrimQuality).
+// If minqual <= 0, bases are not masked (default).
+func (p *Parser) MaskQuality(minqual int) {
+	p.maskqual = minqual
+}
+
+// ParseUnalign parses an unaligned fastq file
+func (p *Parser) ParseUnalign() (sb align.SeqBag, err error) {
+	var s align.Sequence
+
+	sb = align.NewSeqBag(align.UNKNOWN)
+	sb.IgnoreIdentical(p.ignoreidentical)
+	for {
+		if s, err = p.Next(); err == io.EOF {
+			err = nil
+			break
+		} else if err != nil {
+			return
+		}
+		if err = sb.AddSequenceQuality(s.Name(), s.SequenceChar(), s.Quality(), s.Comment()); err != nil {
+			return
+		}
+	}
+	if sb.NbSequences() == 0 {
+		err = errors.New("no sequences in the fastq file")
+		return
+	}
+	sb.AutoAlphabet()
+	return
+}
+
+// Next parses the next sequence of the fastq file, without
+// storing the previous ones: It allows to process large files
+// one sequence at a time.
+//
+// It returns io.EOF when there is no more sequence in the file.
+//
+// Contrary to ParseUnalign, duplicate sequence names are
+// not handled (IgnoreIdentical).
+//
+// Sequences having no base left after quality trimming (see TrimQuality)
+// are skipped, with a warning.
+func (p *Parser) Next() (s align.Sequence, err error) {
+	for {
+		if s, err = p.nextRecord(); err != nil || s.Length() > 0 {
+			return
+		}
+		aio.PrintMessage(fmt.Sprintf("Fastq sequence %s has no base left after quality trimming, skipping", s.Name()))
+	}
+}
+
+// Parses the next fastq record, and trims/masks its sequence
+func (p *Parser) nextRecord() (s align.Sequence, err error) {
+	var header, sequence, plus, quality string
+	var qual []uint8
+
+	// Empty lines between records are ignored
+	for header == "" {
+		if header, err = p.readLine(); err != nil {
+			return
+		}
+	}
+	if header[0] != '@' {
+		err = fmt.Errorf("line %d: fastq record should start with a @", p.line)
+		return
+	}
+	if header = strings.TrimSpace(header[1:]); header == "" {
+		err = fmt.Errorf("line %d: @ should be followed by a sequence identifier", p.line)
+		return
+	}
+	if sequence, err = p.readRecordLine(header); err != nil {
+		return
+	}
+	if plus, err = p.readRecordLine(header); err != nil {
+		return
+	}
+	if plus[0] != '+' {
+		err = fmt.Errorf("line %d: sequence %s should be followed by a line starting with +", p.line, header)
+		return
+	}
+	if n := strings.TrimSpace(plus[1:]); n != "" && n != header {
+		err = fmt.Errorf("line %d: name after + (%s) is different from the sequence name (%s)", p.line, n, header)
+		return
+	}
+	if quality, err = p.readRecordLine(header); err != nil {
+		return
+	}
+	if len(quality) != len(sequence) {
+		err = fmt.Errorf("line %d: quality length (%d) is different from sequence length (%d) for sequence %s", p.line, len(quality), len(sequence), header)
+		return
+	}
+	qual = make([]uint8, len(quality))
+	for i := 0; i < len(quality); i++ {
+		if quality[i] < PHRED_OFFSET || quality[i] > '~' {
+			err = fmt.Errorf("line %d: wrong quality character %q for sequence %s", p.line, quality[i], header)
+			return
+		}
+		qual[i] = quality[i] - PHRED_OFFSET
+	}
+
+	seq := []uint8(sequence)
+	seq, qual = trimQuality(seq, qual, p.trimqual)
+	maskQuality(seq, qual, p.maskqual)
+
+	s = align.NewSequence(header, seq, "")
+	err = s.SetQuality(qual)
+	return
+}
+
+// Reads the next line, without spaces at the end of the line.
+// Returns io.EOF if there is no more line.
+func (p *Parser) readLine() (line string, err error) {
+	if line, err = p.r.ReadString('\n'); err == io.EOF && line != "" {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+	p.line++
+	line = strings.TrimRight(line, " \t\r\n")
+	return
+}
+
+// Reads the next line of the record of the given sequence, which must not be empty
+func (p *Parser) readRecordLine(name string) (line string, err error) {
+	if line, err = p.readLine(); err == io.EOF {
+		err = fmt.Errorf("fastq record of sequence %s is incomplete", name)
+		return
+	} else if err != nil {
+		return
+	}
+	if line == "" {
+		err = fmt.Errorf("line %d: empty line in fastq record of sequence %s", p.line, name)
+	}
+	return
+}
+
+// Removes the bases having a quality < minqual from both ends of the sequence
+func trimQuality(seq, qual []uint8, minqual int) ([]uint8, []uint8) {
+	start, end := 0, len(seq)
+	for start < end && int(qual[start]) < minqual {
+		start++
+	}
+	for end > start && int(qual[end-1]) < minqual {
+		end--
+	}
+	return seq[start:end], qual[start:end]
+}
+
+// Replaces the bases having a quality < minqual by MASK_CHAR
+func maskQuality(seq, qual []uint8, minqual int) {
+	for i, q := range qual {
+		if int(q) < minqual {
+			seq[i] = MASK_CHAR
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package fastq
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+var fastqstring1 string = `@read1 some description
+ACGTACGTAC
++
+!!IIIII#II
+@read2
+GGGTTTCCAA
++read2
+IIIIIIII!!
+
+`
+
+// Quality length different from sequence length
+var fastqstring2 string = `@read1
+ACGTACGTAC
++
+IIIII
+`
+
+// Missing + line
+var fastqstring3 string = `@read1
+ACGTACGTAC
+IIIIIIIIII
+`
+
+// Incomplete record
+var fastqstring4 string = `@read1
+ACGTACGTAC
++
+`
+
+func TestParse(t *testing.T) {
+	sb, err := NewParser(strings.NewReader(fastqstring1)).ParseUnalign()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sb.NbSequences() != 2 {
+		t.Fatalf("There should be 2 sequences (%d)", sb.NbSequences())
+	}
+	if sb.Alphabet() != align.NUCLEOTIDS {
+		t.Errorf("Alphabet should be nucleotides")
+	}
+	if !sb.HasQualities() {
+		t.Errorf("Sequences should have qualities")
+	}
+	s, _ := sb.Sequence(0)
+	if s.Name() != "read1 some description" || s.Sequence() != "ACGTACGTAC" {
+		t.Errorf("Wrong first sequence: %s %s", s.Name(), s.Sequence())
+	}
+	expqual := []uint8{0, 0, 40, 40, 40, 40, 40, 2, 40, 40}
+	for i, q := range s.Quality() {
+		if q != expqual[i] {
+			t.Errorf("Wrong quality at position %d: %d instead of %d", i, q, expqual[i])
+		}
+	}
+
+	for i, in := range []string{fastqstring2, fastqstring3, fastqstring4, ">s1\nACGT\n"} {
+		if _, err = NewParser(strings.NewReader(in)).ParseUnalign(); err == nil {
+			t.Errorf("Parsing fastq string %d should give an error", i)
+		}
+	}
+}
+
+func TestTrimMask(t *testing.T) {
+	p := NewParser(strings.NewReader(fastqstring1))
+	p.TrimQuality(10)
+	p.MaskQuality(20)
+
+	exp := []struct{ seq, qual string }{
+		{"GTACGNAC", "IIIII#II"},
+		{"GGGTTTCC", "IIIIIIII"},
+	}
+	for i := 0; ; i++ {
+		s, err := p.Next()
+		if err == io.EOF {
+			if i != 2 {
+				t.Errorf("There should be 2 sequences (%d)", i)
+			}
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if s.Sequence() != exp[i].seq {
+			t.Errorf("Sequence %d should be %s and not %s", i, exp[i].seq, s.Sequence())
+		}
+		if len(s.Quality()) != len(exp[i].qual) {
+			t.Errorf("Quality %d should have length %d and not %d", i, len(exp[i].qual), len(s.Quality()))
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	sb, err := NewParser(strings.NewReader(fastqstring1)).ParseUnalign()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "@read1 some description\nACGTACGTAC\n+\n!!IIIII#II\n@read2\nGGGTTTCCAA\n+\nIIIIIIII!!\n"
+	if out := WriteSequences(sb); out != exp {
+		t.Errorf("Fastq output should be:\n%s\nand not:\n%s", exp, out)
+	}
+
+	// Quality follows reverse complement and sequence trimming
+	if err = sb.ReverseComplement(); err != nil {
+		t.Fatal(err)
+	}
+	s, _ := sb.Sequence(1)
+	if s.Sequence() != "TTGGAAACCC" || s.Quality()[0] != 0 || s.Quality()[9] != 40 {
+		t.Errorf("Wrong reverse complemented sequence: %s %v", s.Sequence(), s.Quality())
+	}
+
+	al := align.NewAlign(align.NUCLEOTIDS)
+	al.AddSequenceQuality("s1", []uint8("AC-T"), []uint8{10, 20, 30, 40}, "")
+	al.AddSequenceChar("s2", []uint8("ACGT"), "")
+	if al.HasQualities() {
+		t.Errorf("s2 has no quality")
+	}
+	if err = NewWriter(&strings.Builder{}).WriteSequences(al); err == nil {
+		t.Errorf("Writing sequences without quality should give an error")
+	}
+	if err = al.TrimSequences(1, true); err != nil {
+		t.Fatal(err)
+	}
+	if out := WriteSequences(al); !strings.HasPrefix(out, "@s1\nCT\n+\n5I\n") {
+		t.Errorf("Wrong trimmed fastq output:\n%s", out)
+	}
+	if err = al.AddSequenceQuality("s3", []uint8("ACGT"), []uint8{10}, ""); err == nil {
+		t.Errorf("Quality length different from sequence length should give an error")
+	}
+}
+
+// All the bases of read2 have a quality < 20: it is skipped
+var fastqstring5 string = `@read1
+ACGTACGTAC
++
+IIIIIIIIII
+@read2
+GGGTTTCCAA
++
+!!!!!!!!!!
+@read3
+TTTT
++
+IIII
+`
+
+func TestTrimAll(t *testing.T) {
+	p := NewParser(strings.NewReader(fastqstring5))
+	p.TrimQuality(20)
+	sb, err := p.ParseUnalign()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sb.NbSequences() != 2 {
+		t.Fatalf("There should be 2 sequences (%d)", sb.NbSequences())
+	}
+	if _, ok := sb.GetSequence("read2"); ok {
+		t.Errorf("read2 should have been skipped")
+	}
+	// Output can be read again
+	out := WriteSequences(sb)
+	if sb2, err := NewParser(strings.NewReader(out)).ParseUnalign(); err != nil || sb2.NbSequences() != 2 {
+		t.Errorf("Written fastq sequences should be parsed again: %v\n%s", err, out)
+	}
+
+	// Only empty reads
+	p = NewParser(strings.NewReader("@read2\nGGGT\n+\n!!!!\n"))
+	p.TrimQuality(20)
+	if _, err = p.ParseUnalign(); err == nil {
+		t.Errorf("Parsing fastq with only fully trimmed sequences should give an error")
+	}
+
+	// Empty sequence
+	sb = align.NewSeqBag(align.NUCLEOTIDS)
+	sb.AddSequenceQuality("s1", []uint8("--"), []uint8{10, 20}, "")
+	var buf strings.Builder
+	if err = NewWriter(&buf).WriteSequences(sb); err == nil || buf.Len() > 0 {
+		t.Errorf("Writing an empty fastq sequence should give an error, and write nothing: %q", buf.String())
+	}
+}
@@ -0,0 +1,70 @@
+package fastq
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// Writer writes sequences in fastq format to an io.Writer.
+// Sequences are written as they are iterated, the whole
+// output is never stored in memory.
+type Writer struct {
+	w *bufio.Writer
+}
+
+// NewWriter returns a fastq writer to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// WriteSequences writes the sequences with their qualities (Phred+33 encoded),
+// each record on 4 lines, and flushes the writer. Like fasta.WriteSequences,
+// "-" characters are removed, as well as their qualities.
+//
+// If a sequence has no quality (see align.SeqBag.HasQualities), or is empty
+// once "-" characters are removed, then returns an error: empty fastq records
+// are not valid.
+func (w *Writer) WriteSequences(sb align.SeqBag) (err error) {
+	for _, s := range sb.Sequences() {
+		qual := s.Quality()
+		if qual == nil {
+			w.w.Flush()
+			return fmt.Errorf("sequence %s has no quality, it can not be written in fastq format", s.Name())
+		}
+		if s.NumGaps() == s.Length() {
+			w.w.Flush()
+			return fmt.Errorf("sequence %s is empty, it can not be written in fastq format", s.Name())
+		}
+		w.w.WriteString("@")
+		w.w.WriteString(s.Name())
+		w.w.WriteString("\n")
+		seq := s.SequenceChar()
+		for _, c := range seq {
+			if c != '-' {
+				w.w.WriteByte(c)
+			}
+		}
+		w.w.WriteString("\n+\n")
+		for i, q := range qual {
+			if seq[i] != '-' {
+				w.w.WriteByte(q + PHRED_OFFSET)
+			}
+		}
+		if _, err = w.w.WriteString("\n"); err != nil {
+			return
+		}
+	}
+	return w.w.Flush()
+}
+
+// WriteSequences returns the sequences in fastq format (see Writer.WriteSequences).
+// The output stops at the first sequence without quality, or empty.
+func WriteSequences(sb align.SeqBag) string {
+	var buf bytes.Buffer
+	NewWriter(&buf).WriteSequences(sb)
+	return buf.String()
+}
@@ -0,0 +1,111 @@
+// Package feature parses sequence features (CDS, genes, etc.) from
+// GenBank, EMBL and GFF3 annotation files.
+//
+// Coordinates of the features are converted into 0-based
+// [start,end[ blocks, given in the order of the transcript: blocks
+// on the reverse strand must be reverse complemented, and
+// concatenated in the given order.
+package feature
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Block is an interval of a feature
+type Block struct {
+	Start   int  // 0-based inclusive
+	End     int  // 0-based exclusive
+	Reverse bool // true if the block is on the reverse strand
+}
+
+// Feature is an annotated region of the sequence, made of one or several blocks
+type Feature struct {
+	Type   string  // Feature type (e.g. CDS, gene)
+	Name   string  // Name of the feature, "" if none
+	Blocks []Block // Blocks of the feature, in the order of the transcript
+}
+
+// Parse detects the format of the annotation file (GenBank, EMBL or GFF3),
+// using its first line, and parses its features.
+func Parse(r *bufio.Reader) (features []Feature, err error) {
+	// Beginning of the file, without consuming it
+	b, _ := r.Peek(64)
+	first := strings.TrimLeft(string(b), " \t\r\n")
+
+	switch {
+	case strings.HasPrefix(first, "LOCUS"):
+		return ParseGenBank(r)
+	case strings.HasPrefix(first, "ID   "):
+		return ParseEMBL(r)
+	case strings.HasPrefix(first, "##gff-version"):
+		return ParseGFF3(r)
+	default:
+		err = errors.New("unknown annotation format: expecting GenBank (LOCUS), EMBL (ID) or GFF3 (##gff-version) header")
+	}
+	return
+}
+
+// parseLocation parses a GenBank/EMBL feature location, such as 100..200,
+// complement(join(1..10,20..30)) or join(<1..10,complement(40..>50)).
+// If reverse is true, the location is on the reverse strand.
+func parseLocation(loc string, reverse bool) (blocks []Block, err error) {
+	var start, end int
+	var inner []Block
+
+	switch {
+	case strings.HasPrefix(loc, "complement(") && strings.HasSuffix(loc, ")"):
+		if inner, err = parseLocation(loc[len("complement("):len(loc)-1], !reverse); err != nil {
+			return
+		}
+		for i := len(inner) - 1; i >= 0; i-- {
+			blocks = append(blocks, inner[i])
+		}
+	case (strings.HasPrefix(loc, "join(") || strings.HasPrefix(loc, "order(")) && strings.HasSuffix(loc, ")"):
+		content := loc[strings.Index(loc, "(")+1 : len(loc)-1]
+		depth, prev := 0, 0
+		for i := 0; i <= len(content); i++ {
+			if i < len(content) && content[i] == '(' {
+				depth++
+			} else if i < len(content) && content[i] == ')' {
+				depth--
+			} else if i == len(content) || (content[i] == ',' && depth == 0) {
+				if inner, err = parseLocation(content[prev:i], reverse); err != nil {
+					return
+				}
+				blocks = append(blocks, inner...)
+				prev = i + 1
+			}
+		}
+	case strings.Contains(loc, ":"):
+		err = fmt.Errorf("remote feature location %q is not supported", loc)
+	case strings.ContainsAny(loc, "^(),"):
+		err = fmt.Errorf("unsupported feature location %q", loc)
+	default:
+		bounds := strings.Split(strings.NewReplacer("<", "", ">", "").Replace(loc), "..")
+		if len(bounds) > 2 {
+			err = fmt.Errorf("wrong feature location %q", loc)
+			return
+		}
+		if start, err = strconv.Atoi(bounds[0]); err != nil {
+			err = fmt.Errorf("wrong feature location %q", loc)
+			return
+		}
+		end = start
+		if len(bounds) == 2 {
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				err = fmt.Errorf("wrong feature location %q", loc)
+				return
+			}
+		}
+		if start < 1 || end < start {
+			err = fmt.Errorf("wrong feature location %q", loc)
+			return
+		}
+		blocks = []Block{{Start: start - 1, End: end, Reverse: reverse}}
+	}
+	return
+}
@@ -0,0 +1,114 @@
+package feature
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var genbankstring string = `LOCUS       REF                       60 bp    DNA     linear   UNK 01-JAN-2020
+DEFINITION  test.
+FEATURES             Location/Qualifiers
+     source          1..60
+                     /organism="test"
+     gene            1..30
+                     /gene="g1"
+     CDS             join(1..10,
+                     21..30)
+                     /gene="g1"
+                     /product="a protein
+                     on two lines"
+     CDS             complement(join(31..40,<51..>60))
+                     /locus_tag="T2"
+     misc_feature    41
+ORIGIN
+        1 acgtacgtac gtacgtacgt acgtacgtac gtacgtacgt acgtacgtac gtacgtacgt
+//
+`
+
+var emblstring string = `ID   REF; SV 1; linear; genomic DNA; STD; UNC; 60 BP.
+XX
+FH   Key             Location/Qualifiers
+FH
+FT   CDS             join(complement(51..60),complement(31..40))
+FT                   /protein_id="P2"
+XX
+SQ   Sequence 60 BP;
+     acgtacgtac gtacgtacgt acgtacgtac gtacgtacgt acgtacgtac gtacgtacgt        60
+//
+`
+
+var gffstring string = `##gff-version 3
+##sequence-region REF 1 60
+REF	test	gene	1	30	.	+	.	ID=gene1;Name=g1
+REF	test	CDS	21	30	.	+	0	ID=cds1;Parent=gene1;Name=g1%2Ccds
+REF	test	CDS	1	10	.	+	0	ID=cds1;Parent=gene1;Name=g1%2Ccds
+REF	test	CDS	31	40	.	-	0	ID=cds2;gene=g2
+REF	test	CDS	51	60	.	-	0	ID=cds2;gene=g2
+##FASTA
+>REF
+ACGT
+`
+
+func TestParseGenBank(t *testing.T) {
+	features, err := Parse(bufio.NewReader(strings.NewReader(genbankstring)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Feature{
+		{"source", "", []Block{{0, 60, false}}},
+		{"gene", "g1", []Block{{0, 30, false}}},
+		{"CDS", "g1", []Block{{0, 10, false}, {20, 30, false}}},
+		{"CDS", "T2", []Block{{50, 60, true}, {30, 40, true}}},
+		{"misc_feature", "", []Block{{40, 41, false}}},
+	}
+	if !reflect.DeepEqual(features, exp) {
+		t.Errorf("GenBank features should be:\n%v\nand not:\n%v", exp, features)
+	}
+}
+
+func TestParseEMBL(t *testing.T) {
+	features, err := Parse(bufio.NewReader(strings.NewReader(emblstring)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Feature{
+		{"CDS", "P2", []Block{{50, 60, true}, {30, 40, true}}},
+	}
+	if !reflect.DeepEqual(features, exp) {
+		t.Errorf("EMBL features should be:\n%v\nand not:\n%v", exp, features)
+	}
+}
+
+func TestParseGFF3(t *testing.T) {
+	features, err := Parse(bufio.NewReader(strings.NewReader(gffstring)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Feature{
+		{"gene", "g1", []Block{{0, 30, false}}},
+		{"CDS", "g1,cds", []Block{{0, 10, false}, {20, 30, false}}},
+		{"CDS", "g2", []Block{{50, 60, true}, {30, 40, true}}},
+	}
+	if !reflect.DeepEqual(features, exp) {
+		t.Errorf("GFF3 features should be:\n%v\nand not:\n%v", exp, features)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, loc := range []string{"J00194.1:100..202", "10^11", "20..10", "join(1..10", "a..b"} {
+		if _, err := parseLocation(loc, false); err == nil {
+			t.Errorf("Location %q should give an error", loc)
+		}
+	}
+	for _, in := range []string{
+		">s1\nACGT\n",
+		"##gff-version 3\nREF\ttest\tCDS\t10\t1\t.\t+\t0\tID=c\n",
+		"##gff-version 3\nREF\ttest\tCDS\t1\t10\n",
+	} {
+		if _, err := Parse(bufio.NewReader(strings.NewReader(in))); err == nil {
+			t.Errorf("Annotation %q should give an error", in)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package feature
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Qualifiers giving the name of GenBank/EMBL features, by decreasing priority
+var nameQualifiers = []string{"gene", "locus_tag", "protein_id", "label"}
+
+// Column of the feature locations and qualifiers in GenBank/EMBL feature tables
+const featureColumn = 21
+
+// ParseGenBank parses the features of the FEATURES tables of a GenBank file.
+// If the file contains several records, features of all records are returned.
+func ParseGenBank(r io.Reader) (features []Feature, err error) {
+	return parseFeatureTable(r, false)
+}
+
+// ParseEMBL parses the features of the FT lines of an EMBL file.
+// If the file contains several records, features of all records are returned.
+func ParseEMBL(r io.Reader) (features []Feature, err error) {
+	return parseFeatureTable(r, true)
+}
+
+// genbankFeature is a feature during parsing: its location
+// and qualifiers may span several lines
+type genbankFeature struct {
+	key        string
+	location   strings.Builder
+	qualifiers []string // Qualifiers: /name=value
+	line       int      // Line of the feature key
+}
+
+// parseFeatureTable parses a GenBank (embl=false) or EMBL (embl=true) feature table:
+// The feature key starts at column 5, and its location and qualifiers at column 21.
+func parseFeatureTable(r io.Reader, embl bool) (features []Feature, err error) {
+	var cur *genbankFeature
+	var f Feature
+	var intable bool
+	var nline int
+
+	flush := func() (err error) {
+		if cur == nil {
+			return
+		}
+		if f, err = cur.feature(); err != nil {
+			return fmt.Errorf("line %d: %v", cur.line, err)
+		}
+		features = append(features, f)
+		cur = nil
+		return
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		nline++
+		if embl {
+			// Only FT lines are considered, other lines end the table
+			intable = strings.HasPrefix(line, "FT ")
+			if intable {
+				line = "  " + line[2:]
+			}
+		} else if strings.HasPrefix(line, "FEATURES") {
+			intable = true
+			continue
+		} else if len(line) > 0 && line[0] != ' ' {
+			// ORIGIN, CONTIG, BASE COUNT, //, etc.
+			intable = false
+		}
+		if !intable {
+			if err = flush(); err != nil {
+				return
+			}
+			continue
+		}
+		if len(line) > 5 && line[5] != ' ' {
+			// New feature key
+			if err = flush(); err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			cur = &genbankFeature{key: fields[0], line: nline}
+			if len(line) > featureColumn {
+				cur.location.WriteString(strings.TrimSpace(line[featureColumn:]))
+			}
+		} else if cur != nil && len(line) > featureColumn {
+			content := strings.TrimSpace(line[featureColumn:])
+			if strings.HasPrefix(content, "/") {
+				cur.qualifiers = append(cur.qualifiers, content[1:])
+			} else if len(cur.qualifiers) > 0 {
+				cur.qualifiers[len(cur.qualifiers)-1] += " " + content
+			} else {
+				cur.location.WriteString(content)
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return
+	}
+	err = flush()
+	return
+}
+
+// feature returns the parsed feature, named after the first
+// qualifier of nameQualifiers it has
+func (gf *genbankFeature) feature() (f Feature, err error) {
+	f.Type = gf.key
+	if f.Blocks, err = parseLocation(strings.ReplaceAll(gf.location.String(), " ", ""), false); err != nil {
+		return
+	}
+	for _, n := range nameQualifiers {
+		for _, q := range gf.qualifiers {
+			if strings.HasPrefix(q, n+"=") {
+				f.Name = strings.Trim(q[len(n)+1:], "\"")
+				return
+			}
+		}
+	}
+	return
+}
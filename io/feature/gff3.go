@@ -0,0 +1,96 @@
+package feature
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Attributes giving the name of GFF3 features, by decreasing priority
+var gffNameAttributes = []string{"Name", "gene", "locus_tag", "ID"}
+
+// ParseGFF3 parses the features of a GFF3 file. Lines sharing the same
+// ID (e.g. the several lines of a spliced CDS) are merged into a single
+// feature with several blocks, ordered by increasing coordinates on the
+// forward strand, and by decreasing coordinates on the reverse strand.
+// The ##FASTA section, if any, is ignored.
+func ParseGFF3(r io.Reader) (features []Feature, err error) {
+	var nline int
+	var start, end int
+	var ids map[string]int = make(map[string]int) // Index of the feature having each ID
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		nline++
+		if strings.HasPrefix(line, "##FASTA") {
+			break
+		}
+		if len(strings.TrimSpace(line)) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cols := strings.Split(line, "\t")
+		if len(cols) != 9 {
+			err = fmt.Errorf("line %d: GFF3 lines should have 9 tab separated columns", nline)
+			return
+		}
+		if start, err = strconv.Atoi(cols[3]); err != nil {
+			err = fmt.Errorf("line %d: wrong start coordinate %q", nline, cols[3])
+			return
+		}
+		if end, err = strconv.Atoi(cols[4]); err != nil {
+			err = fmt.Errorf("line %d: wrong end coordinate %q", nline, cols[4])
+			return
+		}
+		if start < 1 || end < start {
+			err = fmt.Errorf("line %d: wrong coordinates %d-%d", nline, start, end)
+			return
+		}
+		block := Block{Start: start - 1, End: end, Reverse: cols[6] == "-"}
+
+		attributes := make(map[string]string)
+		for _, a := range strings.Split(cols[8], ";") {
+			if kv := strings.SplitN(strings.TrimSpace(a), "=", 2); len(kv) == 2 {
+				if attributes[kv[0]], err = url.PathUnescape(kv[1]); err != nil {
+					err = fmt.Errorf("line %d: wrong attribute %q", nline, a)
+					return
+				}
+			}
+		}
+
+		if id, ok := attributes["ID"]; ok {
+			if idx, ok := ids[id]; ok && features[idx].Type == cols[2] {
+				features[idx].Blocks = append(features[idx].Blocks, block)
+				continue
+			}
+			ids[id] = len(features)
+		}
+		f := Feature{Type: cols[2], Blocks: []Block{block}}
+		for _, n := range gffNameAttributes {
+			if v, ok := attributes[n]; ok {
+				f.Name = v
+				break
+			}
+		}
+		features = append(features, f)
+	}
+	if err = scanner.Err(); err != nil {
+		return
+	}
+
+	for _, f := range features {
+		blocks := f.Blocks
+		sort.SliceStable(blocks, func(i, j int) bool {
+			if blocks[i].Reverse {
+				return blocks[i].Start > blocks[j].Start
+			}
+			return blocks[i].Start < blocks[j].Start
+		})
+	}
+	return
+}
@@ -0,0 +1,109 @@
+package newick
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// Scanner represents a lexical scanner.
+type Scanner struct {
+	r *bufio.Reader
+}
+
+// NewScanner returns a new instance of Scanner.
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{r: bufio.NewReader(r)}
+}
+
+// read reads the next rune from the bufferred reader.
+// Returns the rune(0) if an error occurs (or io.EOF is returned).
+func (s *Scanner) read() rune {
+	ch, _, err := s.r.ReadRune()
+	if err != nil {
+		return eof
+	}
+	return ch
+}
+
+// unread places the previously read rune back on the reader.
+func (s *Scanner) unread() {
+	_ = s.r.UnreadRune()
+}
+
+// Scan returns the next token and literal value.
+// Whitespaces and comments ([...]) are skipped.
+func (s *Scanner) Scan() (tok Token, lit string) {
+	ch := s.read()
+
+	for isWhitespace(ch) || ch == '[' {
+		if ch == '[' {
+			// Comment
+			for ch != ']' && ch != eof {
+				ch = s.read()
+			}
+			if ch == eof {
+				return ILLEGAL, "["
+			}
+		}
+		ch = s.read()
+	}
+
+	switch ch {
+	case eof:
+		return EOF, ""
+	case '(':
+		return OPENPAR, string(ch)
+	case ')':
+		return CLOSEPAR, string(ch)
+	case ',':
+		return COMMA, string(ch)
+	case ':':
+		return COLON, string(ch)
+	case ';':
+		return SEMICOLON, string(ch)
+	case '\'':
+		return s.scanQuoted()
+	}
+
+	s.unread()
+	return s.scanLabel()
+}
+
+// scanLabel consumes the current rune and all contiguous label runes.
+func (s *Scanner) scanLabel() (tok Token, lit string) {
+	var buf bytes.Buffer
+	buf.WriteRune(s.read())
+
+	for {
+		if ch := s.read(); ch == eof {
+			break
+		} else if !isLabel(ch) {
+			s.unread()
+			break
+		} else {
+			_, _ = buf.WriteRune(ch)
+		}
+	}
+	return LABEL, buf.String()
+}
+
+// scanQuoted consumes a quoted label: 'label', where ” is a quote.
+func (s *Scanner) scanQuoted() (tok Token, lit string) {
+	var buf bytes.Buffer
+
+	for {
+		ch := s.read()
+		if ch == eof {
+			return ILLEGAL, "'" + buf.String()
+		}
+		if ch == '\'' {
+			if ch = s.read(); ch != '\'' {
+				s.unread()
+				break
+			}
+		}
+		buf.WriteRune(ch)
+	}
+	return LABEL, buf.String()
+}
@@ -0,0 +1,113 @@
+package newick
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/evolbioinfo/goalign/tree"
+)
+
+// Parser represents a parser.
+type Parser struct {
+	s   *Scanner
+	buf struct {
+		tok Token  // last read token
+		lit string // last read literal
+		n   int    // buffer size (max=1)
+	}
+}
+
+// NewParser returns a new instance of Parser.
+func NewParser(r io.Reader) *Parser {
+	return &Parser{s: NewScanner(r)}
+}
+
+// scan returns the next token from the underlying scanner.
+// If a token has been unscanned then read that instead.
+func (p *Parser) scan() (tok Token, lit string) {
+	// If we have a token on the buffer, then return it.
+	if p.buf.n != 0 {
+		p.buf.n = 0
+		return p.buf.tok, p.buf.lit
+	}
+
+	// Otherwise read the next token from the scanner.
+	tok, lit = p.s.Scan()
+
+	// Save it to the buffer in case we unscan later.
+	p.buf.tok, p.buf.lit = tok, lit
+	return
+}
+
+// unscan pushes the previously read token back onto the buffer.
+func (p *Parser) unscan() { p.buf.n = 1 }
+
+// Parse parses a newick tree: ((A:0.1,B:0.2)C:0.3,D:0.4);
+//
+// Comments ([...]) are ignored, and labels may be quoted ('A B').
+func (p *Parser) Parse() (t *tree.Tree, err error) {
+	var root *tree.Node = tree.NewNode("")
+	var tok Token
+	var lit string
+
+	if err = p.parseNode(root); err != nil {
+		return
+	}
+	if tok, lit = p.scan(); tok != SEMICOLON {
+		err = fmt.Errorf("newick tree should end with ';', found %q", lit)
+		return
+	}
+	t = tree.NewTree(root)
+	return
+}
+
+// Parses a node: its subtree, its name and the length of the branch to its parent
+func (p *Parser) parseNode(n *tree.Node) (err error) {
+	var tok Token
+	var lit string
+	var child *tree.Node
+	var length float64
+
+	if tok, lit = p.scan(); tok == OPENPAR {
+		for {
+			child = tree.NewNode("")
+			if err = p.parseNode(child); err != nil {
+				return
+			}
+			n.AddChild(child, child.Length())
+			if tok, lit = p.scan(); tok == CLOSEPAR {
+				break
+			} else if tok != COMMA {
+				err = fmt.Errorf("expecting ',' or ')' in newick tree, found %q", lit)
+				return
+			}
+		}
+		tok, lit = p.scan()
+	}
+
+	if tok == LABEL {
+		n.SetName(lit)
+		tok, lit = p.scan()
+	}
+
+	if tok == COLON {
+		if tok, lit = p.scan(); tok != LABEL {
+			err = fmt.Errorf("expecting a branch length after ':' in newick tree, found %q", lit)
+			return
+		}
+		if length, err = strconv.ParseFloat(lit, 64); err != nil || math.IsNaN(length) {
+			err = fmt.Errorf("branch length is not a number in newick tree: %q", lit)
+			return
+		}
+		n.SetLength(length)
+		return
+	}
+	if tok == ILLEGAL || tok == OPENPAR {
+		err = fmt.Errorf("unexpected %q in newick tree", lit)
+		return
+	}
+	p.unscan()
+	return
+}
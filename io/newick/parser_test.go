@@ -0,0 +1,72 @@
+package newick_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/io/newick"
+)
+
+func TestParseWrite(t *testing.T) {
+	inputs := []string{
+		"((A:0.1,B:0.2)n1:0.05,(C:0.3,D:1e-05):0.2,E:0.5);\n",
+		"((A,B),(C,D),E);\n",
+		"(('A B':0.1,'it''s':0.2):0.3,C:1);\n",
+	}
+	for _, input := range inputs {
+		tr, err := newick.NewParser(strings.NewReader(input)).Parse()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if out := newick.WriteTree(tr); out != input {
+			t.Errorf("Written tree should be %s, not %s", input, out)
+		}
+	}
+}
+
+func TestParseTips(t *testing.T) {
+	input := " ( (A : 0.1 , B[&comment]:0.2 ) : 0.05 ,\n\t(C:0.3,D:0.1):0.2, E:0.5) root;"
+	expnames := []string{"A", "B", "C", "D", "E"}
+	explengths := []float64{0.1, 0.2, 0.3, 0.1, 0.5}
+
+	tr, err := newick.NewParser(strings.NewReader(input)).Parse()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if tr.Root().Name() != "root" {
+		t.Errorf("Root should be named root, not %s", tr.Root().Name())
+	}
+	if len(tr.Root().Children()) != 3 {
+		t.Errorf("Root should have 3 children, not %d", len(tr.Root().Children()))
+	}
+	tips := tr.Tips()
+	if len(tips) != len(expnames) {
+		t.Errorf("There should be %d tips, not %d", len(expnames), len(tips))
+		return
+	}
+	for i, tip := range tips {
+		if tip.Name() != expnames[i] {
+			t.Errorf("Tip %d should be named %s, not %s", i, expnames[i], tip.Name())
+		}
+		if tip.Length() != explengths[i] {
+			t.Errorf("Branch length of tip %s should be %f, not %f", tip.Name(), explengths[i], tip.Length())
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	inputs := []string{
+		"((A,B),C)",
+		"((A,B),C;",
+		"((A,B)(C,D));",
+		"((A:x,B),C);",
+		"((A:,B),C);",
+	}
+	for _, input := range inputs {
+		if _, err := newick.NewParser(strings.NewReader(input)).Parse(); err == nil {
+			t.Errorf("Parsing %s should return an error", input)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package newick
+
+type Token int64
+
+var eof = rune(0)
+
+const (
+	ILLEGAL   Token = iota
+	EOF             // End of file
+	OPENPAR         // (
+	CLOSEPAR        // )
+	COMMA           // ,
+	COLON           // :
+	SEMICOLON       // ;
+	LABEL           // Name of a node or branch length
+)
+
+func isWhitespace(ch rune) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+func isLabel(ch rune) bool {
+	return ch != eof && !isWhitespace(ch) && ch != '(' && ch != ')' && ch != ',' && ch != ':' && ch != ';' && ch != '[' && ch != '\''
+}
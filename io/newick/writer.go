@@ -0,0 +1,45 @@
+package newick
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/evolbioinfo/goalign/tree"
+)
+
+// WriteTree returns the newick representation of the tree
+func WriteTree(t *tree.Tree) string {
+	var buf bytes.Buffer
+	if t.Root() != nil {
+		writeNode(&buf, t.Root())
+	}
+	buf.WriteString(";\n")
+	return buf.String()
+}
+
+func writeNode(buf *bytes.Buffer, n *tree.Node) {
+	if !n.Tip() {
+		buf.WriteString("(")
+		for i, c := range n.Children() {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			writeNode(buf, c)
+		}
+		buf.WriteString(")")
+	}
+	buf.WriteString(label(n.Name()))
+	if n.HasLength() && n.Parent() != nil {
+		buf.WriteString(":")
+		buf.WriteString(strconv.FormatFloat(n.Length(), 'g', -1, 64))
+	}
+}
+
+// Quotes the label if it contains newick special characters
+func label(name string) string {
+	if strings.ContainsAny(name, " \t\n\r()[],:;'") {
+		return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+	}
+	return name
+}
@@ -135,6 +135,10 @@ func (s *Scanner) scanIdent() (tok Token, lit string) {
 			return TREES, buf.String()
 		case "TREE":
 			return TREE, buf.String()
+		case "SETS", "ASSUMPTIONS":
+			return SETS, buf.String()
+		case "CHARSET":
+			return CHARSET, buf.String()
 		case "DIMENSIONS":
 			return DIMENSIONS, buf.String()
 		case "NTAX":
@@ -151,6 +155,8 @@ func (s *Scanner) scanIdent() (tok Token, lit string) {
 			return MATCHCHAR, buf.String()
 		case "GAP":
 			return GAP, buf.String()
+		case "INTERLEAVE":
+			return INTERLEAVE, buf.String()
 		case "MATRIX":
 			return MATRIX, buf.String()
 		case "END":
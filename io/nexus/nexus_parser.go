@@ -8,6 +8,7 @@ import (
 
 	"github.com/evolbioinfo/goalign/align"
 	aio "github.com/evolbioinfo/goalign/io"
+	"github.com/evolbioinfo/goalign/io/partition"
 )
 
 // Parser represents a parser.
@@ -25,6 +26,13 @@ type Parser struct {
 	}
 }
 
+// charset is a CHARSET definition of a SETS or ASSUMPTIONS block.
+// If the end of a range is -1, then the range goes to the end of the alignment ("start-.")
+type charset struct {
+	name   string
+	ranges []partition.SiteRange
+}
+
 // NewParser returns a new instance of Parser.
 func NewParser(r io.Reader) *Parser {
 	return &Parser{s: NewScanner(r), ignoreidentical: align.IGNORE_NONE}
@@ -76,6 +84,7 @@ func (p *Parser) Parse() (al align.Alignment, err error) {
 	var taxlabels map[string]bool = nil
 	var names []string
 	var sequences map[string]string
+	var charsets []charset
 
 	// First token should be a "NEXUS" token.
 	tok, lit := p.scanIgnoreWhitespace()
@@ -126,6 +135,9 @@ func (p *Parser) Parse() (al align.Alignment, err error) {
 			case DATA:
 				// DATA/CHARACTERS BLOCK
 				names, sequences, nchar, ntax, datatype, missing, gap, matchchar, err = p.parseData()
+			case SETS:
+				// SETS/ASSUMPTIONS BLOCK
+				charsets, err = p.parseSets(charsets)
 			default:
 				// If an unsupported block is seen, we just skip it
 				aio.PrintMessage(fmt.Sprintf("Unsupported block %q, skipping", lit2))
@@ -195,6 +207,9 @@ func (p *Parser) Parse() (al align.Alignment, err error) {
 			}
 		}
 		al.ReplaceMatchChars()
+		if len(charsets) > 0 {
+			al.SetPartitions(buildPartitions(charsets, al))
+		}
 	}
 	return
 }
@@ -427,6 +442,19 @@ func (p *Parser) parseData() (names []string, sequences map[string]string, nchar
 							}
 						}
 					}
+				case INTERLEAVE:
+					// interleave or interleave=yes|no
+					// Interleaved blocks are concatenated in the matrix anyway
+					tok3, _ := p.scanIgnoreWhitespace()
+					if tok3 == EQUAL {
+						tok4, lit4 := p.scanIgnoreWhitespace()
+						if tok4 != IDENT {
+							err = fmt.Errorf("Expecting yes or no after 'INTERLEAVE=', got %q", lit4)
+							stopformat = true
+						}
+					} else {
+						p.unscan()
+					}
 				case MATCHCHAR:
 					tok3, lit3 := p.scanIgnoreWhitespace()
 					if tok3 != EQUAL {
@@ -458,7 +486,8 @@ func (p *Parser) parseData() (names []string, sequences map[string]string, nchar
 			}
 		case MATRIX:
 			// Character matrix (Alignmemnt)
-			// So far: Does not handle interleave case...
+			// Interleaved matrices are handled by concatenating
+			// the successive lines of the same sequence
 			stopmatrix := false
 			for !stopmatrix {
 				tok2, lit2 := p.scanIgnoreWhitespace()
@@ -518,6 +547,146 @@ func (p *Parser) parseData() (names []string, sequences map[string]string, nchar
 	return
 }
 
+// SETS / ASSUMPTIONS BLOCK
+//
+// Only CHARSET commands are taken into account, and appended to the given charsets.
+// Other commands (TAXSET, CHARPARTITION, ...) are skipped.
+func (p *Parser) parseSets(charsets []charset) ([]charset, error) {
+	var err error
+	var cs charset
+	stopsets := false
+	for !stopsets {
+		tok, lit := p.scanIgnoreWhitespace()
+		switch tok {
+		case ENDOFLINE:
+			break
+		case ILLEGAL:
+			err = fmt.Errorf("found illegal token %q", lit)
+			stopsets = true
+		case EOF:
+			err = fmt.Errorf("End of file within a SETS block (no END;)")
+			stopsets = true
+		case END:
+			tok2, _ := p.scanIgnoreWhitespace()
+			if tok2 != ENDOFCOMMAND {
+				err = fmt.Errorf("End token without ;")
+			}
+			stopsets = true
+		case CHARSET:
+			if cs, err = p.parseCharset(charsets); err != nil {
+				stopsets = true
+			} else {
+				charsets = append(charsets, cs)
+			}
+		case OPENBRACK:
+			if tok, lit, err = p.consumeComment(tok, lit); err != nil {
+				stopsets = true
+			}
+		default:
+			err = p.parseUnsupportedCommand()
+			aio.PrintMessage(fmt.Sprintf("Unsupported command %q in block SETS, skipping", lit))
+			if err != nil {
+				stopsets = true
+			}
+		}
+	}
+	return charsets, err
+}
+
+// Parses a CHARSET command: charset [*] name = 1-100 101-.\3 othercharset;
+//
+// Site ranges may refer to previously defined charsets.
+func (p *Parser) parseCharset(charsets []charset) (cs charset, err error) {
+	var items []string
+
+	tok, lit := p.scanIgnoreWhitespace()
+	if tok == IDENT && lit == "*" {
+		tok, lit = p.scanIgnoreWhitespace()
+	}
+	if tok != IDENT && tok != NUMERIC {
+		err = fmt.Errorf("Expecting charset name after CHARSET, got %q", lit)
+		return
+	}
+	cs.name = lit
+	if tok, lit = p.scanIgnoreWhitespace(); tok != EQUAL {
+		err = fmt.Errorf("Expecting '=' after CHARSET %s, got %q", cs.name, lit)
+		return
+	}
+
+	for tok != ENDOFCOMMAND {
+		tok, lit = p.scan()
+		switch tok {
+		case IDENT, NUMERIC:
+			items = append(items, lit)
+		case WS, ENDOFLINE:
+			items = append(items, " ")
+		case OPENBRACK:
+			if tok, lit, err = p.consumeComment(tok, lit); err != nil {
+				return
+			}
+		case ENDOFCOMMAND:
+			break
+		default:
+			err = fmt.Errorf("Unexpected token %q in CHARSET %s", lit, cs.name)
+			return
+		}
+	}
+
+	named := make(map[string][]partition.SiteRange)
+	for _, other := range charsets {
+		named[other.name] = other.ranges
+	}
+	if cs.ranges, err = partition.ParseSiteRanges(strings.Join(items, ""), named); err != nil {
+		err = fmt.Errorf("CHARSET %s: %v", cs.name, err)
+	}
+	return
+}
+
+// Builds the partition set of the alignment from the parsed charsets.
+//
+// Charsets having sites outside the alignment, or sites already defined
+// in a previous charset (overlapping charsets), are skipped with a warning.
+// Model names are given by align.DefaultPartitionModel.
+// If no charset is kept, returns nil.
+func buildPartitions(charsets []charset, al align.Alignment) (ps *align.PartitionSet) {
+	var model string = align.DefaultPartitionModel(al.Alphabet())
+	var end int
+	var valid bool
+	var sites map[int]bool
+
+	ps = align.NewPartitionSet(al.Length())
+	for _, cs := range charsets {
+		valid = true
+		sites = make(map[int]bool)
+		for _, r := range cs.ranges {
+			end = r.End
+			if end == -1 {
+				end = al.Length()
+			}
+			for i := r.Start - 1; i < end && valid; i += r.Step {
+				valid = i < al.Length() && ps.Partition(i) == -1 && !sites[i]
+				sites[i] = true
+			}
+		}
+		if !valid {
+			aio.PrintMessage(fmt.Sprintf("CHARSET %s has sites outside the alignment or overlaps another charset, skipping", cs.name))
+			continue
+		}
+		for _, r := range cs.ranges {
+			end = r.End
+			if end == -1 {
+				end = al.Length()
+			}
+			// Errors are not possible here: sites have been checked above
+			ps.AddRange(cs.name, model, r.Start-1, end-1, r.Step)
+		}
+	}
+	if ps.NPartitions() == 0 {
+		return nil
+	}
+	return
+}
+
 // Just skip the current command
 func (p *Parser) parseUnsupportedCommand() (err error) {
 	// Unsupported data command
@@ -1,9 +1,11 @@
 package nexus_test
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
+	"github.com/evolbioinfo/goalign/align"
 	"github.com/evolbioinfo/goalign/io/nexus"
 )
 
@@ -50,3 +52,162 @@ EOF
 		}
 	}
 }
+
+func TestParser_ParseInterleaveCharsets(t *testing.T) {
+	innexus := `#NEXUS
+begin data;
+dimensions ntax=3 nchar=12;
+format datatype=dna interleave gap=- missing=?;
+matrix
+s1 ACGTAC
+s2 ACGTAA
+s3 ACGTTT
+
+s1 GTACGT
+s2 GTACGA
+s3 GT-CG?
+;
+end;
+begin assumptions;
+charset gene1 = 1 - 6;
+charset pos1 = 7-.\3;
+charset pos23 = 8-12\3 9-.\3;
+charset overlap = 5-8;
+charset gene2 = pos1 pos23;
+end;
+`
+	expnexus := `#NEXUS
+begin data;
+dimensions ntax=3 nchar=12;
+format datatype=dna;
+matrix
+s1 ACGTACGTACGT
+s2 ACGTAAGTACGA
+s3 ACGTTTGT-CG*
+;
+end;
+begin sets;
+charset gene1 = 1-6;
+charset pos1 = 7 10;
+charset pos23 = 8-9 11-12;
+end;
+`
+	al, err := nexus.NewParser(strings.NewReader(innexus)).Parse()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if al.NbSequences() != 3 || al.Length() != 12 {
+		t.Errorf("Wrong alignment dimensions (%d,%d)", al.NbSequences(), al.Length())
+	}
+	ps := al.Partitions()
+	if ps == nil {
+		t.Errorf("Partitions should have been parsed")
+		return
+	}
+	// overlap and gene2 overlap other charsets: skipped
+	if ps.NPartitions() != 3 {
+		t.Errorf("There should be 3 partitions (%d)", ps.NPartitions())
+	}
+	exppart := []int{0, 0, 0, 0, 0, 0, 1, 2, 2, 1, 2, 2}
+	for i, p := range exppart {
+		if ps.Partition(i) != p {
+			t.Errorf("Site %d should be in partition %d (%d)", i, p, ps.Partition(i))
+		}
+	}
+
+	out := nexus.WriteAlignment(al)
+	if out != expnexus {
+		t.Errorf("Written alignment is not the expected one:\n%s\nvs.\n%s", out, expnexus)
+	}
+	al2, err := nexus.NewParser(strings.NewReader(out)).Parse()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !al.Identical(al2) {
+		t.Errorf("Alignment is different after round trip")
+	}
+	for i, p := range exppart {
+		if al2.Partitions().Partition(i) != p {
+			t.Errorf("Site %d should be in partition %d after round trip (%d)", i, p, al2.Partitions().Partition(i))
+		}
+	}
+}
+
+func TestWriter_WriteCodonCharsets(t *testing.T) {
+	al := align.NewAlign(align.NUCLEOTIDS)
+	al.AddSequence("s1", "ACGTACGTACGT", "")
+	al.AddSequence("s2", "ACGTAAGTACGA", "")
+	ps := align.NewPartitionSet(12)
+	ps.AddRange("pos12", "DNA", 0, 11, 3)
+	ps.AddRange("pos12", "DNA", 1, 11, 3)
+	ps.AddRange("pos3", "DNA", 2, 11, 3)
+	al.SetPartitions(ps)
+
+	expsets := `begin sets;
+charset pos12 = 1-2 4-5 7-8 10-11;
+charset pos3 = 3-12\3;
+end;
+`
+	if out := nexus.WriteAlignment(al); !strings.HasSuffix(out, expsets) {
+		t.Errorf("Written sets block is not the expected one:\n%s\nvs.\n%s", out, expsets)
+	}
+
+	// Partitions that do not correspond to the alignment are not written
+	al.SetPartitions(align.NewPartitionSet(10))
+	if out := nexus.WriteAlignment(al); strings.Contains(out, "begin sets") {
+		t.Errorf("Sets block should not be written:\n%s", out)
+	}
+}
+
+func TestParser_ParseWrongCharsets(t *testing.T) {
+	for i, sets := range []string{
+		"charset gene1 = 1-x;",
+		"charset gene1 = 6-1;",
+		"charset gene1 = 1-6\\0;",
+		"charset gene1 1-6;",
+	} {
+		innexus := "#NEXUS\nbegin data;\ndimensions ntax=1 nchar=6;\nmatrix\ns1 ACGTAC\n;\nend;\nbegin sets;\n" + sets + "\nend;\n"
+		if _, err := nexus.NewParser(strings.NewReader(innexus)).Parse(); err == nil {
+			t.Errorf("There should be an error while parsing charset %d", i)
+		}
+	}
+}
+
+func TestWriter_WriteInterleaved(t *testing.T) {
+	al := align.NewAlign(align.NUCLEOTIDS)
+	al.AddSequence("s1", "ACGTACGTACGT", "")
+	al.AddSequence("s2", "ACGTAAGTACGA", "")
+
+	var buf bytes.Buffer
+	w := nexus.NewWriter(&buf)
+	w.LineWidth = 8
+	w.BlockSize = 4
+	if err := w.WriteAlignment(al); err != nil {
+		t.Fatal(err)
+	}
+	exp := `#NEXUS
+begin data;
+dimensions ntax=2 nchar=12;
+format datatype=dna interleave;
+matrix
+s1 ACGT ACGT
+s2 ACGT AAGT
+
+s1 ACGT
+s2 ACGA
+;
+end;
+`
+	if buf.String() != exp {
+		t.Errorf("Written alignment is not the expected one:\n%s\nvs.\n%s", buf.String(), exp)
+	}
+	al2, err := nexus.NewParser(strings.NewReader(buf.String())).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !al.Identical(al2) {
+		t.Errorf("Alignment is different after round trip")
+	}
+}
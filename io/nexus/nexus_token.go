@@ -24,16 +24,19 @@ const (
 	TAXLABELS // Begin taxa : list of  taxlabels
 	TREES     // Begin trees -> Definition of trees
 	TREE      // A specific tree in the BEGIN TREES section
+	SETS      // Begin sets / Begin assumptions -> Definition of character sets
+	CHARSET   // Begin sets : charset name = 1-100 101-200\3;
 
 	DIMENSIONS // Dimensions
 	NTAX       // Dimensions : Number of taxa
 	NCHAR      // Dimensions : Length of alignment
 
-	FORMAT    // Format
-	DATATYPE  // Format datatype=dna
-	MISSING   // Format missing=?  missing char
-	GAP       // Format gap=- gap character
-	MATCHCHAR // Format matchchar=.  matching character compared to first seq
+	FORMAT     // Format
+	DATATYPE   // Format datatype=dna
+	MISSING    // Format missing=?  missing char
+	GAP        // Format gap=- gap character
+	MATCHCHAR  // Format matchchar=.  matching character compared to first seq
+	INTERLEAVE // Format interleave or interleave=yes : interleaved matrix
 
 	MATRIX // Matrix
 	END    // End
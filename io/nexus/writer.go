@@ -1,8 +1,10 @@
 package nexus
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/evolbioinfo/goalign/align"
 )
@@ -14,29 +16,136 @@ func min_int(a int, b int) int {
 	return b
 }
 
-func WriteAlignment(al align.Alignment) string {
-	var buf bytes.Buffer
+// Writer writes alignments in nexus format to an io.Writer.
+// Sequences are written as they are iterated, the whole
+// output is never stored in memory.
+type Writer struct {
+	w         *bufio.Writer
+	LineWidth int // Number of sites per line (<=0: each sequence on a single line, >0: interleaved matrix)
+	BlockSize int // Number of sites per space separated block (<=0: no blocks)
+}
 
+// NewWriter returns a nexus writer to w, writing each
+// sequence on a single line, without blocks.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w), LineWidth: 0, BlockSize: 0}
+}
+
+// WriteAlignment writes the alignment in nexus format, and flushes the writer.
+//
+// If the alignment has partitions (see align.Alignment.Partitions()) that correspond
+// to the alignment length, they are written as CHARSETs in a SETS block.
+func (w *Writer) WriteAlignment(al align.Alignment) (err error) {
 	var seqtype string = "dna"
+	var interleave string = ""
+	var line_length = w.LineWidth
+	var block_length = w.BlockSize
 
 	if al.Alphabet() == align.AMINOACIDS {
 		seqtype = "protein"
 	}
+	if line_length > 0 && line_length < al.Length() {
+		interleave = " interleave"
+	} else {
+		line_length = al.Length()
+	}
+	if block_length <= 0 {
+		block_length = line_length
+	}
+
+	w.w.WriteString("#NEXUS\n")
+	w.w.WriteString("begin data;\n")
+	w.w.WriteString(fmt.Sprintf("dimensions ntax=%d nchar=%d;\n", al.NbSequences(), al.Length()))
+	w.w.WriteString(fmt.Sprintf("format datatype=%s%s;\n", seqtype, interleave))
+	w.w.WriteString("matrix\n")
+	// Each sequence is written once, even if the alignment is empty
+	for cursize := 0; ; {
+		if cursize > 0 {
+			w.w.WriteRune('\n')
+		}
+		al.IterateChar(func(name string, seq []uint8) bool {
+			w.w.WriteString(name)
+			w.w.WriteString(" ")
+			for i := cursize; i < cursize+line_length && i < len(seq); i += block_length {
+				if i > cursize {
+					w.w.WriteString(" ")
+				}
+				w.w.Write(seq[i:min_int(min_int(i+block_length, cursize+line_length), len(seq))])
+			}
+			_, err = w.w.WriteRune('\n')
+			return err != nil
+		})
+		if cursize += line_length; err != nil || cursize >= al.Length() {
+			break
+		}
+	}
+	if err != nil {
+		return
+	}
+	w.w.WriteString(";\n")
+	w.w.WriteString("end;\n")
 
-	buf.WriteString("#NEXUS\n")
-	buf.WriteString("begin data;\n")
-	buf.WriteString(fmt.Sprintf("dimensions ntax=%d nchar=%d;\n", al.NbSequences(), al.Length()))
-	buf.WriteString(fmt.Sprintf("format datatype=%s;\n", seqtype))
-	buf.WriteString(fmt.Sprintf("matrix\n"))
-	al.Iterate(func(name string, seq string) bool {
-		buf.WriteString(name)
-		buf.WriteString(" ")
-		buf.WriteString(seq)
-		buf.WriteRune('\n')
-		return false
-	})
-	buf.WriteString(";\n")
-	buf.WriteString("end;\n")
+	if ps := al.Partitions(); ps != nil && ps.AliLength() == al.Length() {
+		return w.WriteSets(ps)
+	}
+	return w.w.Flush()
+}
 
+// WriteAlignment returns the alignment in nexus format (see Writer.WriteAlignment).
+// For large alignments, prefer Writer, which does not store the whole output in memory.
+func WriteAlignment(al align.Alignment) string {
+	var buf bytes.Buffer
+	NewWriter(&buf).WriteAlignment(al)
+	return buf.String()
+}
+
+// WriteSets writes the partitions as a SETS block, one CHARSET per partition,
+// and flushes the writer.
+//
+// Sites of each partition are written as ranges: 1-100 (consecutive sites),
+// 1-100\3 (every 3 sites) or 5 (single site).
+func (w *Writer) WriteSets(ps *align.PartitionSet) (err error) {
+	var buf = w.w
+	var sites []int
+	var i, j, step int
+
+	buf.WriteString("begin sets;\n")
+	for code := 0; code < ps.NPartitions(); code++ {
+		sites = sites[:0]
+		for pos := 0; pos < ps.AliLength(); pos++ {
+			if ps.Partition(pos) == code {
+				sites = append(sites, pos+1)
+			}
+		}
+		buf.WriteString(fmt.Sprintf("charset %s =", ps.PartitionName(code)))
+		for i = 0; i < len(sites); i = j + 1 {
+			j = i
+			if i+1 < len(sites) {
+				step = sites[i+1] - sites[i]
+				for j+1 < len(sites) && sites[j+1]-sites[j] == step {
+					j++
+				}
+			}
+			if j > i && step == 1 {
+				buf.WriteString(fmt.Sprintf(" %d-%d", sites[i], sites[j]))
+			} else if j > i+1 {
+				buf.WriteString(fmt.Sprintf(" %d-%d\\%d", sites[i], sites[j], step))
+			} else {
+				j = i
+				buf.WriteString(fmt.Sprintf(" %d", sites[i]))
+			}
+		}
+		buf.WriteString(";\n")
+	}
+	if _, err = buf.WriteString("end;\n"); err != nil {
+		return
+	}
+	return buf.Flush()
+}
+
+// WriteSets returns the partitions as a SETS block (see Writer.WriteSets).
+func WriteSets(ps *align.PartitionSet) string {
+	var buf bytes.Buffer
+	NewWriter(&buf).WriteSets(ps)
 	return buf.String()
 }
@@ -1,8 +1,10 @@
 package paml
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/evolbioinfo/goalign/align"
 )
@@ -19,34 +21,67 @@ func min_int(a int, b int) int {
 	return b
 }
 
-func WriteAlignment(al align.Alignment) string {
-	var buf bytes.Buffer
+// Writer writes alignments in paml (interleaved) format to an io.Writer.
+// Sequences are written as they are iterated, the whole
+// output is never stored in memory.
+type Writer struct {
+	w         *bufio.Writer
+	LineWidth int // Number of sites per line (<=0: each sequence on a single line)
+	BlockSize int // Number of sites per space separated block (<=0: no blocks)
+}
+
+// NewWriter returns a paml writer to w, with lines of PAML_LINE
+// sites, in blocks of PAML_BLOCK sites.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w), LineWidth: PAML_LINE, BlockSize: PAML_BLOCK}
+}
+
+// WriteAlignment writes the alignment and flushes the writer.
+func (w *Writer) WriteAlignment(al align.Alignment) (err error) {
+	var line_length = w.LineWidth
+	var block_length = w.BlockSize
 
-	buf.WriteString(fmt.Sprintf("  %d %d  I\n", al.NbSequences(), al.Length()))
-	al.Iterate(func(name string, seq string) bool {
-		buf.WriteString(name + "\n")
+	if line_length <= 0 {
+		line_length = al.Length()
+	}
+	if block_length <= 0 {
+		block_length = line_length
+	}
+
+	w.w.WriteString(fmt.Sprintf("  %d %d  I\n", al.NbSequences(), al.Length()))
+	al.IterateChar(func(name string, seq []uint8) bool {
+		w.w.WriteString(name)
+		w.w.WriteString("\n")
 		return false
 	})
-	buf.WriteRune('\n')
+	w.w.WriteRune('\n')
 	cursize := 0
-	for cursize < al.Length() {
+	for cursize < al.Length() && err == nil {
 		if cursize > 0 {
-			buf.WriteString(fmt.Sprintf("%d\n", cursize+1))
+			w.w.WriteString(fmt.Sprintf("%d\n", cursize+1))
 		}
 		al.IterateChar(func(name string, seq []uint8) bool {
-			for i := cursize; i < cursize+PAML_LINE && i < len(seq); i += PAML_BLOCK {
+			for i := cursize; i < cursize+line_length && i < len(seq); i += block_length {
 				if i > cursize {
-					buf.WriteString(" ")
-				}
-				end := min_int(i+PAML_BLOCK, len(seq))
-				for j := i; j < end; j++ {
-					buf.WriteByte(seq[j])
+					w.w.WriteString(" ")
 				}
+				w.w.Write(seq[i:min_int(min_int(i+block_length, cursize+line_length), len(seq))])
 			}
-			buf.WriteString("\n")
-			return false
+			_, err = w.w.WriteString("\n")
+			return err != nil
 		})
-		cursize += PAML_LINE
+		cursize += line_length
 	}
+	if err != nil {
+		return
+	}
+	return w.w.Flush()
+}
+
+// WriteAlignment returns the alignment in paml format. For large alignments,
+// prefer Writer, which does not store the whole output in memory.
+func WriteAlignment(al align.Alignment) string {
+	var buf bytes.Buffer
+	NewWriter(&buf).WriteAlignment(al)
 	return buf.String()
 }
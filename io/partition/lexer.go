@@ -9,12 +9,13 @@ import (
 
 // Scanner represents a lexical scanner.
 type Scanner struct {
-	r *bufio.Reader
+	r    *bufio.Reader
+	line int // Current line (1-based)
 }
 
 // NewScanner returns a new instance of Scanner.
 func NewScanner(r io.Reader) *Scanner {
-	return &Scanner{r: bufio.NewReader(r)}
+	return &Scanner{r: bufio.NewReader(r), line: 1}
 }
 
 // read reads the next rune from the bufferred reader.
@@ -37,6 +38,10 @@ func (s *Scanner) Scan() (tok Token, lit string) {
 	// Read the next rune.
 	ch := s.read()
 
+	for isWhiteSpace(ch) {
+		ch = s.read()
+	}
+
 	if isEndOfLine(ch) {
 		if isCR(ch) {
 			ch := s.read()
@@ -44,13 +49,10 @@ func (s *Scanner) Scan() (tok Token, lit string) {
 				s.unread()
 			}
 		}
+		s.line++
 		return ENDOFLINE, ""
 	}
 
-	for isWhiteSpace(ch) {
-		ch = s.read()
-	}
-
 	switch ch {
 	case eof:
 		return EOF, ""
@@ -60,7 +62,7 @@ func (s *Scanner) Scan() (tok Token, lit string) {
 		return EQUAL, string(ch)
 	case '-':
 		return RANGE, string(ch)
-	case '/':
+	case '/', '\\':
 		return MODULO, string(ch)
 	}
 
@@ -75,21 +77,39 @@ func (s *Scanner) Scan() (tok Token, lit string) {
 	}
 }
 
+// Line returns the current line of the input (1-based).
+func (s *Scanner) Line() int {
+	return s.line
+}
+
 // scanIdent consumes the current rune and all contiguous ident runes.
+//
+// Characters between braces (model parameters, e.g. GTR{1/2/1/1/2/1})
+// are part of the identifier, except end of lines.
 func (s *Scanner) scanIdent() (tok Token, lit string) {
 	// Create a buffer and read the current character into it.
 	var buf bytes.Buffer
-	buf.WriteRune(s.read())
+	var depth int
+	ch := s.read()
+	buf.WriteRune(ch)
+	if ch == '{' {
+		depth++
+	}
 
 	// Read every subsequent ident character into the buffer.
 	// Non-ident characters and EOF will cause the loop to exit.
 	for {
-		if ch := s.read(); ch == eof {
+		if ch = s.read(); ch == eof {
 			break
-		} else if !isIdent(ch) {
+		} else if isEndOfLine(ch) || (depth == 0 && !isIdent(ch)) {
 			s.unread()
 			break
 		} else {
+			if ch == '{' {
+				depth++
+			} else if ch == '}' {
+				depth--
+			}
 			_, _ = buf.WriteRune(ch)
 		}
 	}
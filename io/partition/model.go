@@ -0,0 +1,173 @@
+package partition
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Model is a substitution model as written in RAxML-NG / IQ-TREE
+// partition files, for example GTR+G4+FO, HKY{2.0}+I+G or LG+R4.
+type Model struct {
+	Name      string          // Name of the base model: GTR, HKY, LG, ...
+	Params    []float64       // Parameters of the base model, if given between {}
+	Modifiers []ModelModifier // Modifiers of the model: G4, I, FO, ...
+}
+
+// ModelModifier is a modifier of a substitution model, i.e. what follows a '+':
+// rate heterogeneity (G, G4, R4, I), base frequencies (F, FO, FC, FE, FU), etc.
+type ModelModifier struct {
+	Name   string    // Name of the modifier: G4, I, FO, ...
+	Params []float64 // Parameters of the modifier, if given between {}
+}
+
+var modelComponentRegexp = regexp.MustCompile(`^([^{}+]+)(?:\{([^{}]*)\})?$`)
+var gammaModifierRegexp = regexp.MustCompile(`^G(\d*)[am]?$`)
+
+// ParseModel parses a model string such as GTR+G4+FO or GTR{1/2/1/1/2/1}+FU{0.25/0.25/0.25/0.25}.
+//
+// Parameters between braces may be separated by '/' (RAxML-NG) or ',' (IQ-TREE).
+// Modifier names are not checked, so that any RAxML-NG or IQ-TREE modifier is accepted.
+func ParseModel(model string) (m Model, err error) {
+	var components []string
+	var name string
+	var params []float64
+
+	if components, err = splitModel(model); err != nil {
+		return
+	}
+	for i, c := range components {
+		if name, params, err = parseModelComponent(c); err != nil {
+			err = fmt.Errorf("invalid model %q: %v", model, err)
+			return
+		}
+		if i == 0 {
+			m.Name = name
+			m.Params = params
+		} else {
+			m.Modifiers = append(m.Modifiers, ModelModifier{name, params})
+		}
+	}
+	return
+}
+
+// Splits the model on '+', except between braces
+func splitModel(model string) (components []string, err error) {
+	var depth, start int
+
+	for i, c := range model {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth < 0 {
+				err = fmt.Errorf("invalid model %q: unmatched '}'", model)
+				return
+			}
+		case '+':
+			if depth == 0 {
+				components = append(components, model[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		err = fmt.Errorf("invalid model %q: unmatched '{'", model)
+		return
+	}
+	components = append(components, model[start:])
+	return
+}
+
+// Parses a model component: name or name{p1/p2/...}
+func parseModelComponent(component string) (name string, params []float64, err error) {
+	var match []string
+	var v float64
+
+	if match = modelComponentRegexp.FindStringSubmatch(strings.TrimSpace(component)); match == nil {
+		err = fmt.Errorf("malformed component %q", component)
+		return
+	}
+	name = match[1]
+	if match[2] == "" {
+		return
+	}
+	for _, p := range strings.FieldsFunc(match[2], func(r rune) bool { return r == '/' || r == ',' }) {
+		if v, err = strconv.ParseFloat(strings.TrimSpace(p), 64); err != nil {
+			err = fmt.Errorf("parameter %q of %s is not a number", p, name)
+			return
+		}
+		params = append(params, v)
+	}
+	return
+}
+
+// GammaCategories returns the number of categories of the discrete gamma
+// rate heterogeneity (+G, +G4, +G8m, ...), 0 if the model has no gamma modifier.
+// +G without number means 4 categories.
+func (m Model) GammaCategories() int {
+	var match []string
+	var ncat int
+
+	for _, mod := range m.Modifiers {
+		if match = gammaModifierRegexp.FindStringSubmatch(mod.Name); match != nil {
+			if match[1] == "" {
+				return 4
+			}
+			ncat, _ = strconv.Atoi(match[1])
+			return ncat
+		}
+	}
+	return 0
+}
+
+// Invariant returns true if the model has a proportion of invariant sites (+I, +IO, +IC, +IU)
+func (m Model) Invariant() bool {
+	for _, mod := range m.Modifiers {
+		if mod.Name == "I" || mod.Name == "IO" || mod.Name == "IC" || mod.Name == "IU" {
+			return true
+		}
+	}
+	return false
+}
+
+// Frequencies returns the base frequency modifier of the model (F, FO, FC, FE, FU, FQ),
+// or "" if the model does not define it.
+func (m Model) Frequencies() string {
+	for _, mod := range m.Modifiers {
+		switch mod.Name {
+		case "F", "FO", "FC", "FE", "FU", "FQ":
+			return mod.Name
+		}
+	}
+	return ""
+}
+
+func (m Model) String() string {
+	var buf bytes.Buffer
+
+	writeModelComponent(&buf, m.Name, m.Params)
+	for _, mod := range m.Modifiers {
+		buf.WriteString("+")
+		writeModelComponent(&buf, mod.Name, mod.Params)
+	}
+	return buf.String()
+}
+
+func writeModelComponent(buf *bytes.Buffer, name string, params []float64) {
+	buf.WriteString(name)
+	if len(params) == 0 {
+		return
+	}
+	buf.WriteString("{")
+	for i, p := range params {
+		if i > 0 {
+			buf.WriteString("/")
+		}
+		buf.WriteString(strconv.FormatFloat(p, 'g', -1, 64))
+	}
+	buf.WriteString("}")
+}
@@ -0,0 +1,197 @@
+package partition
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// A CHARSET of a NEXUS partition file
+type nexusCharset struct {
+	name   string
+	ranges []SiteRange
+	line   int
+}
+
+// Parses a NEXUS partition file (IQ-TREE style):
+//
+//	#nexus
+//	begin sets;
+//	  charset part1 = 1-100;
+//	  charset part2 = 101-384\3 102-384\3;
+//	  charpartition mine = HKY+G:part1, GTR+I+G:part2;
+//	end;
+//
+// CHARSETs may also be defined in an ASSUMPTIONS block (older NEXUS files).
+// If a CHARPARTITION is given, partitions are the charsets it lists, with the
+// given models. Otherwise, each charset is a partition with the default model.
+// Only the first CHARPARTITION is taken into account.
+// Charsets referring to alignment files (charset part1 = aln.phy: 1-100;) are not supported.
+func (p *Parser) parseNexus(content string, ps *align.PartitionSet) (err error) {
+	var block string
+	var fields []string
+	var charsets []nexusCharset
+	var named map[string][]SiteRange = make(map[string][]SiteRange)
+	var cs nexusCharset
+	var charpartition *statement
+
+	// Removes the #nexus header, keeping the end of lines
+	if start := strings.Index(strings.ToLower(content), "#nexus"); start != -1 {
+		content = content[:start] + content[start+len("#nexus"):]
+	}
+
+	for _, stmt := range splitStatements(removeNexusComments(content)) {
+		fields = strings.Fields(stmt.text)
+		switch strings.ToLower(fields[0]) {
+		case "begin":
+			if len(fields) > 1 {
+				block = strings.ToLower(fields[1])
+			}
+		case "end", "endblock":
+			block = ""
+		case "charset":
+			if block != "sets" && block != "assumptions" {
+				continue
+			}
+			if cs, err = parseNexusCharset(stmt, named); err != nil {
+				return
+			}
+			charsets = append(charsets, cs)
+			named[cs.name] = cs.ranges
+		case "charpartition":
+			if block != "sets" || charpartition != nil {
+				continue
+			}
+			charpartition = &statement{stmt.text, stmt.line}
+		}
+	}
+
+	if charpartition == nil {
+		for _, cs = range charsets {
+			if err = addSiteRanges(ps, cs.name, p.defaultModel, cs.ranges); err != nil {
+				err = fmt.Errorf("line %d: %v", cs.line, err)
+				return
+			}
+		}
+		return
+	}
+	return parseNexusCharpartition(*charpartition, named, ps)
+}
+
+// Parses a CHARSET command: charset [*] name = 1-100 101-.\3 othercharset
+func parseNexusCharset(stmt statement, named map[string][]SiteRange) (cs nexusCharset, err error) {
+	var def string
+	var eq int
+
+	cs.line = stmt.line
+	if eq = strings.Index(stmt.text, "="); eq == -1 {
+		err = fmt.Errorf("line %d: CHARSET should be of the form 'charset name = ranges': %q", stmt.line, stmt.text)
+		return
+	}
+	cs.name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(stmt.text[len("charset"):eq]), "*"))
+	cs.name = strings.Trim(cs.name, "'\"")
+	def = stmt.text[eq+1:]
+	if colon := strings.Index(def, ":"); colon != -1 {
+		err = fmt.Errorf("line %d: CHARSET %s refers to alignment file %q, which is not supported", stmt.line, cs.name, strings.TrimSpace(def[:colon]))
+		return
+	}
+	if cs.ranges, err = ParseSiteRanges(def, named); err != nil {
+		err = fmt.Errorf("line %d: CHARSET %s: %v", stmt.line, cs.name, err)
+	}
+	return
+}
+
+// Parses a CHARPARTITION command: charpartition name = model1:charset1, model2:charset2
+// and adds the listed charsets to the partition set, with their models
+func parseNexusCharpartition(stmt statement, named map[string][]SiteRange, ps *align.PartitionSet) (err error) {
+	var eq, colon int
+	var model, name string
+	var ranges []SiteRange
+	var ok bool
+	var items []string
+
+	if eq = strings.Index(stmt.text, "="); eq == -1 {
+		err = fmt.Errorf("line %d: CHARPARTITION should be of the form 'charpartition name = model:charset, ...': %q", stmt.line, stmt.text)
+		return
+	}
+	if items, err = splitModelList(stmt.text[eq+1:]); err != nil {
+		err = fmt.Errorf("line %d: %v", stmt.line, err)
+		return
+	}
+	for _, item := range items {
+		model = ""
+		name = item
+		if colon = strings.LastIndex(item, ":"); colon != -1 {
+			model = strings.TrimSpace(item[:colon])
+			name = strings.TrimSpace(item[colon+1:])
+		}
+		if model == "" {
+			err = fmt.Errorf("line %d: CHARPARTITION: no model given for charset %s", stmt.line, name)
+			return
+		}
+		if _, err = ParseModel(model); err != nil {
+			err = fmt.Errorf("line %d: %v", stmt.line, err)
+			return
+		}
+		if ranges, ok = named[name]; !ok {
+			err = fmt.Errorf("line %d: CHARPARTITION: unknown charset %s", stmt.line, name)
+			return
+		}
+		if err = addSiteRanges(ps, name, model, ranges); err != nil {
+			err = fmt.Errorf("line %d: %v", stmt.line, err)
+			return
+		}
+	}
+	return
+}
+
+// Splits the given list on ',', except between braces (model parameters).
+// Empty items are discarded.
+func splitModelList(list string) (items []string, err error) {
+	var depth, start int
+
+	add := func(item string) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	for i, c := range list {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				add(list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		err = fmt.Errorf("unmatched braces in %q", strings.TrimSpace(list))
+		return
+	}
+	add(list[start:])
+	return
+}
+
+// Replaces NEXUS comments [...] with spaces, keeping end of lines
+func removeNexusComments(content string) string {
+	var depth int
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '[':
+			depth++
+			return ' '
+		case r == ']' && depth > 0:
+			depth--
+			return ' '
+		case depth > 0 && r != '\n':
+			return ' '
+		}
+		return r
+	}, content)
+}
@@ -1,18 +1,31 @@
 package partition
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/evolbioinfo/goalign/align"
 )
 
+const (
+	FORMAT_RAXML           = iota // RAxML / RAxML-NG / IQ-TREE partition file: model, name = 1-100, 101-200/3
+	FORMAT_NEXUS                  // NEXUS partition file (IQ-TREE): SETS block with CHARSET / CHARPARTITION
+	FORMAT_PARTITIONFINDER        // PartitionFinder configuration file: [data_blocks] section
+)
+
+var partitionFinderRegexp = regexp.MustCompile(`(?im)^\s*\[data_blocks\]`)
+
 // Parser represents a parser.
 type Parser struct {
-	s   *Scanner
-	buf struct {
+	r            io.Reader
+	s            *Scanner
+	defaultModel string // Model of partitions for which the file does not define any
+	buf          struct {
 		tok Token  // last read token
 		lit string // last read literal
 		n   int    // buffer size (max=1)
@@ -21,7 +34,14 @@ type Parser struct {
 
 // NewParser returns a new instance of Parser.
 func NewParser(r io.Reader) *Parser {
-	return &Parser{s: NewScanner(r)}
+	return &Parser{r: r, defaultModel: "DNA"}
+}
+
+// DefaultModel sets the model given to partitions whose model is not
+// defined in the partition file (NEXUS charsets without CHARPARTITION,
+// PartitionFinder data blocks). Default: "DNA"
+func (p *Parser) DefaultModel(model string) {
+	p.defaultModel = model
 }
 
 // scan returns the next token from the underlying scanner.
@@ -44,53 +64,89 @@ func (p *Parser) scan() (tok Token, lit string) {
 // unscan pushes the previously read token back onto the buffer.
 func (p *Parser) unscan() { p.buf.n = 1 }
 
-// Parse parses a partition file
+// Parse parses a partition file.
+//
+// The format of the file is detected automatically among:
+//   - RAxML / RAxML-NG / IQ-TREE partition files, one partition per line:
+//     GTR+G4+FO, gene1 = 1-100, 250-300/3
+//   - NEXUS partition files (IQ-TREE), made of a SETS block with CHARSET commands and
+//     an optional CHARPARTITION command giving the model of each charset
+//   - PartitionFinder configuration files, whose [data_blocks] section defines the partitions
 func (p *Parser) Parse(alignmentLength int) (partitionSet *align.PartitionSet, err error) {
+	var content []byte
+
+	if content, err = io.ReadAll(p.r); err != nil {
+		return
+	}
 	partitionSet = align.NewPartitionSet(alignmentLength)
-	err = p.parse(partitionSet)
+
+	switch DetectFormat(string(content)) {
+	case FORMAT_NEXUS:
+		err = p.parseNexus(string(content), partitionSet)
+	case FORMAT_PARTITIONFINDER:
+		err = p.parsePartitionFinder(string(content), partitionSet)
+	default:
+		p.s = NewScanner(bytes.NewReader(content))
+		err = p.parse(partitionSet)
+	}
+	if err == nil && partitionSet.NPartitions() == 0 {
+		err = errors.New("no partition defined in partition file")
+	}
 	return
 }
 
-func (p *Parser) parse(ps *align.PartitionSet) (err error) {
-	// The first token should be a ">"
-	tok, lit := p.scan()
-	if tok != IDENTIFIER {
-		err = errors.New("Partition should start with a Model name")
-		return
+// DetectFormat returns the format of the given partition file content:
+// FORMAT_NEXUS, FORMAT_PARTITIONFINDER or FORMAT_RAXML
+func DetectFormat(content string) int {
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(content)), "#nexus") {
+		return FORMAT_NEXUS
 	}
-	p.unscan()
+	if partitionFinderRegexp.MatchString(content) {
+		return FORMAT_PARTITIONFINDER
+	}
+	return FORMAT_RAXML
+}
 
+func (p *Parser) parse(ps *align.PartitionSet) (err error) {
 	var start, end, modulo int64
 	var modeleName, partitionName string
+	var line int
 
+	tok, lit := p.scan()
 	for tok != EOF {
-		tok, lit = p.scan()
 		switch tok {
+		case ENDOFLINE:
+			tok, lit = p.scan()
 		case IDENTIFIER:
+			line = p.s.Line()
 			modeleName = lit
+			if _, err = ParseModel(modeleName); err != nil {
+				err = fmt.Errorf("line %d: %v", line, err)
+				return
+			}
 			tok, lit = p.scan()
 			if tok != SEPARATOR {
-				err = fmt.Errorf("Modele name should be followed by ',' : %s", modeleName)
+				err = fmt.Errorf("line %d: Modele name should be followed by ',' : %s", line, modeleName)
 				return
 			}
 
 			tok, lit = p.scan()
 			partitionName = lit
-			if tok != IDENTIFIER {
-				err = fmt.Errorf("Modele name should be followed by ',' then the name of the partition: %s", lit)
+			if tok != IDENTIFIER && tok != DECIMAL {
+				err = fmt.Errorf("line %d: Modele name should be followed by ',' then the name of the partition: %s", line, lit)
 				return
 			}
 
 			tok, lit = p.scan()
 			if tok != EQUAL {
-				err = fmt.Errorf("Partition name should be followed by '=' : [%s|%s]", partitionName, lit)
+				err = fmt.Errorf("line %d: Partition name should be followed by '=' : [%s|%s]", line, partitionName, lit)
 				return
 			}
 			// Parse intervals
 			tok, lit = p.scan()
 			for tok != ENDOFLINE && tok != EOF {
 				if tok != DECIMAL {
-					err = fmt.Errorf("Interval definition should start with a number : [%s]", lit)
+					err = fmt.Errorf("line %d: Interval definition should start with a number : [%s]", line, lit)
 					return
 				}
 				start, _ = strconv.ParseInt(lit, 10, 64)
@@ -99,7 +155,7 @@ func (p *Parser) parse(ps *align.PartitionSet) (err error) {
 				if tok == RANGE {
 					tok, lit = p.scan()
 					if tok != DECIMAL {
-						err = fmt.Errorf("Interval definition '-' should be followed by an integer value : [%s]", lit)
+						err = fmt.Errorf("line %d: Interval definition '-' should be followed by an integer value : [%s]", line, lit)
 						return
 					}
 					end, _ = strconv.ParseInt(lit, 10, 64)
@@ -108,7 +164,7 @@ func (p *Parser) parse(ps *align.PartitionSet) (err error) {
 				if tok == MODULO {
 					tok, lit = p.scan()
 					if tok != DECIMAL {
-						err = fmt.Errorf("there should be an integer value after '/': [%s]", lit)
+						err = fmt.Errorf("line %d: there should be an integer value after '/': [%s]", line, lit)
 						return
 					}
 					modulo, _ = strconv.ParseInt(lit, 10, 64)
@@ -118,16 +174,20 @@ func (p *Parser) parse(ps *align.PartitionSet) (err error) {
 				}
 
 				if err = ps.AddRange(partitionName, modeleName, int(start)-1, int(end)-1, int(modulo)); err != nil {
+					err = fmt.Errorf("line %d: %v", line, err)
 					return
 				}
 
 				if tok == SEPARATOR {
 					tok, lit = p.scan()
 				} else if tok != ENDOFLINE && tok != EOF {
-					err = fmt.Errorf("there should be a separator (or EOL or EOF) after interval definition : [%s]", lit)
+					err = fmt.Errorf("line %d: there should be a separator (or EOL or EOF) after interval definition : [%s]", line, lit)
 					return
 				}
 			}
+		default:
+			err = fmt.Errorf("line %d: Partition should start with a Model name : [%s]", p.s.Line(), lit)
+			return
 		}
 	}
 	return
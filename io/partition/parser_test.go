@@ -0,0 +1,211 @@
+package partition_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/io/partition"
+)
+
+// Checks the partition (name and model) of each site
+func checkPartitions(t *testing.T, input string, alilength int, names, models []string, sites []int) {
+	ps, err := partition.NewParser(strings.NewReader(input)).Parse(alilength)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if ps.NPartitions() != len(names) {
+		t.Errorf("There should be %d partitions, not %d", len(names), ps.NPartitions())
+		return
+	}
+	for i, n := range names {
+		if ps.PartitionName(i) != n {
+			t.Errorf("Partition %d should be named %s, not %s", i, n, ps.PartitionName(i))
+		}
+		if ps.ModeleName(i) != models[i] {
+			t.Errorf("Model of partition %s should be %s, not %s", n, models[i], ps.ModeleName(i))
+		}
+	}
+	for i, p := range sites {
+		if ps.Partition(i) != p {
+			t.Errorf("Site %d should be in partition %d, not %d", i+1, p, ps.Partition(i))
+		}
+	}
+}
+
+func TestParseRAxML(t *testing.T) {
+	input := `GTR+G4+FO, p1 = 1-4, 9
+GTR{1/2/1/1/2/1}+FU{0.25/0.25/0.25/0.25}, p2 = 5-8/2
+  HKY+I+G,	p3 = 6-8\2, 10
+`
+	checkPartitions(t, input, 10,
+		[]string{"p1", "p2", "p3"},
+		[]string{"GTR+G4+FO", "GTR{1/2/1/1/2/1}+FU{0.25/0.25/0.25/0.25}", "HKY+I+G"},
+		[]int{0, 0, 0, 0, 1, 2, 1, 2, 0, 2})
+}
+
+func TestParseNexus(t *testing.T) {
+	input := `#nexus
+begin sets;
+  [ comment ]
+  charset part1 = 1-4;
+  charset part2 = 5-.\2;
+  charset part3 = 6 - 10 \ 2;
+  charpartition mine = HKY+G:part1, GTR{1,2,1,1,2,1}+I+G:part2, LG:part3;
+end;
+`
+	checkPartitions(t, input, 10,
+		[]string{"part1", "part2", "part3"},
+		[]string{"HKY+G", "GTR{1,2,1,1,2,1}+I+G", "LG"},
+		[]int{0, 0, 0, 0, 1, 2, 1, 2, 1, 2})
+
+	// No charpartition: default models
+	input = `#NEXUS
+BEGIN SETS;
+  CHARSET part1 = 1-6;
+  CHARSET part2 = 7-10;
+END;
+`
+	checkPartitions(t, input, 10,
+		[]string{"part1", "part2"},
+		[]string{"DNA", "DNA"},
+		[]int{0, 0, 0, 0, 0, 0, 1, 1, 1, 1})
+
+	// Charsets of an ASSUMPTIONS block, charpartition in a SETS block
+	input = `#NEXUS
+BEGIN ASSUMPTIONS;
+  CHARSET part1 = 1-3;
+  CHARSET part2 = 4-10;
+END;
+BEGIN SETS;
+  CHARPARTITION mine = GTR+G:part2, HKY:part1;
+END;
+`
+	checkPartitions(t, input, 10,
+		[]string{"part2", "part1"},
+		[]string{"GTR+G", "HKY"},
+		[]int{1, 1, 1, 0, 0, 0, 0, 0, 0, 0})
+
+	// Charsets of an ASSUMPTIONS block only
+	input = `#nexus
+begin assumptions;
+  charset part1 = 1-10\2;
+  charset part2 = 2-10\2;
+end;
+`
+	checkPartitions(t, input, 10,
+		[]string{"part1", "part2"},
+		[]string{"DNA", "DNA"},
+		[]int{0, 1, 0, 1, 0, 1, 0, 1, 0, 1})
+}
+
+func TestParsePartitionFinder(t *testing.T) {
+	input := `## ALIGNMENT FILE ##
+alignment = test.phy;
+
+## MODELS OF EVOLUTION ##
+models = all;
+model_selection = aicc;
+
+## DATA BLOCKS ##
+[data_blocks]
+Gene1_pos1 = 1-9\3;
+Gene1_pos2 = 2-9\3;
+Gene1_pos3 = 3-9\3; # third positions
+Gene2 = 10;
+
+[schemes]
+search = greedy;
+`
+	checkPartitions(t, input, 10,
+		[]string{"Gene1_pos1", "Gene1_pos2", "Gene1_pos3", "Gene2"},
+		[]string{"DNA", "DNA", "DNA", "DNA"},
+		[]int{0, 1, 2, 0, 1, 2, 0, 1, 2, 3})
+}
+
+func TestParseErrors(t *testing.T) {
+	bad := []struct {
+		input string
+		err   string
+	}{
+		{"DNA, p1 = 1-5\nDNA, p2 = 4-10\n", "line 2: partition p2: site 4 is already in partition p1"},
+		{"DNA, p1 = 1-5\nDNA, p2 = 6-12\n", "line 2: partition p2: end of range (12) is outside of alignment (length 10)"},
+		{"DNA, p1 = 5-1\n", "line 1: partition p1: start of range (5) is after its end (1)"},
+		{"GTR+G{0.5, p1 = 1-10\n", "line 1: invalid model"},
+		{"#nexus\nbegin sets;\ncharset p1 = 1-5;\n\ncharset p2 = 3-10;\nend;\n", "line 5: partition p2: site 3 is already in partition p1"},
+		{"#nexus\nbegin sets;\ncharset p1 = aln.phy: 1-10;\nend;\n", "line 3: CHARSET p1 refers to alignment file"},
+		{"#nexus\nbegin sets;\ncharset p1 = 1-10;\ncharpartition mine = GTR:p2;\nend;\n", "line 4: CHARPARTITION: unknown charset p2"},
+		{"[data_blocks]\ngene1 = 1-5;\ngene2 = 11-12;\n", "line 3: partition gene2: end of range (12) is outside of alignment (length 10)"},
+		{"\n", "no partition defined"},
+	}
+
+	for _, b := range bad {
+		_, err := partition.NewParser(strings.NewReader(b.input)).Parse(10)
+		if err == nil {
+			t.Errorf("Parsing %q should return an error", b.input)
+		} else if !strings.HasPrefix(err.Error(), b.err) {
+			t.Errorf("Parsing %q should return error %q, got %q", b.input, b.err, err.Error())
+		}
+	}
+}
+
+func TestParseModel(t *testing.T) {
+	m, err := partition.ParseModel("GTR{1/2/1/1/2/1}+G4m+IO+FO")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if m.Name != "GTR" || len(m.Params) != 6 || m.Params[1] != 2 {
+		t.Errorf("Base model is not correctly parsed: %s %v", m.Name, m.Params)
+	}
+	if m.GammaCategories() != 4 {
+		t.Errorf("Model should have 4 gamma categories, not %d", m.GammaCategories())
+	}
+	if !m.Invariant() {
+		t.Errorf("Model should have invariant sites")
+	}
+	if m.Frequencies() != "FO" {
+		t.Errorf("Frequencies should be FO, not %s", m.Frequencies())
+	}
+	if m.String() != "GTR{1/2/1/1/2/1}+G4m+IO+FO" {
+		t.Errorf("Model string should be GTR{1/2/1/1/2/1}+G4m+IO+FO, not %s", m.String())
+	}
+
+	if m, err = partition.ParseModel("HKY+G"); err != nil {
+		t.Error(err)
+	} else if m.GammaCategories() != 4 || m.Invariant() || m.Frequencies() != "" {
+		t.Errorf("HKY+G model is not correctly parsed")
+	}
+
+	for _, bad := range []string{"GTR++G", "GTR+G{0.5", "GTR}+G", "GTR+G{a}", ""} {
+		if _, err = partition.ParseModel(bad); err == nil {
+			t.Errorf("Model %q should not be parsed", bad)
+		}
+	}
+}
+
+func TestParseSiteRanges(t *testing.T) {
+	named := map[string][]partition.SiteRange{"gene1": {{Start: 1, End: 6, Step: 1}}}
+	exp := []partition.SiteRange{{1, 6, 1}, {7, -1, 3}, {8, 12, 3}, {13, 13, 1}}
+
+	ranges, err := partition.ParseSiteRanges("gene1 7 - .\\3, 8-12/3 13", named)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(ranges) != len(exp) {
+		t.Errorf("Expected %d site ranges, got %d", len(exp), len(ranges))
+		return
+	}
+	for i, r := range ranges {
+		if r != exp[i] {
+			t.Errorf("Site range %d should be %v, got %v", i, exp[i], r)
+		}
+	}
+
+	for _, bad := range []string{"1-x", "6-1", "1-6\\0", "0-5", "gene2", ""} {
+		if _, err = partition.ParseSiteRanges(bad, named); err == nil {
+			t.Errorf("Site ranges %q should not be parsed", bad)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package partition
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+var partitionFinderCommentRegexp = regexp.MustCompile(`#[^\n]*`)
+var partitionFinderSectionRegexp = regexp.MustCompile(`\[\s*(\w+)\s*\]`)
+
+// PartitionFinder model keywords that do not correspond to a single model
+var partitionFinderModelKeywords = map[string]bool{
+	"all": true, "allx": true, "all_protein": true, "allx_protein": true,
+	"mrbayes": true, "beast": true, "gamma": true, "gammai": true,
+	"raxml": true, "iqtree": true,
+}
+
+// Parses a PartitionFinder configuration file:
+//
+//	alignment = test.phy;
+//	models = GTR+G;
+//	[data_blocks]
+//	Gene1_pos1 = 1-789\3;
+//	Gene1_pos2 = 2-789\3;
+//	Gene1_pos3 = 3-789\3;
+//	[schemes]
+//	search = greedy;
+//
+// Each data block is a partition. Their model is the one given by the
+// "models" option if it is a single model, the default model otherwise.
+func (p *Parser) parsePartitionFinder(content string, ps *align.PartitionSet) (err error) {
+	var section string
+	var model string = p.defaultModel
+	var eq int
+	var key, value string
+	var ranges []SiteRange
+	var match []int
+
+	content = partitionFinderCommentRegexp.ReplaceAllString(content, "")
+	// Section headers are not followed by ';': we add one
+	content = partitionFinderSectionRegexp.ReplaceAllString(content, "[$1];")
+
+	for _, stmt := range splitStatements(content) {
+		if match = partitionFinderSectionRegexp.FindStringSubmatchIndex(stmt.text); match != nil && match[0] == 0 {
+			section = strings.ToLower(stmt.text[match[2]:match[3]])
+			continue
+		}
+		if eq = strings.Index(stmt.text, "="); eq == -1 {
+			err = fmt.Errorf("line %d: option should be of the form 'name = value': %q", stmt.line, stmt.text)
+			return
+		}
+		key = strings.TrimSpace(stmt.text[:eq])
+		value = strings.TrimSpace(stmt.text[eq+1:])
+
+		switch section {
+		case "":
+			if strings.ToLower(key) == "models" && !strings.Contains(value, ",") && !partitionFinderModelKeywords[strings.ToLower(value)] {
+				if _, err = ParseModel(value); err != nil {
+					err = fmt.Errorf("line %d: %v", stmt.line, err)
+					return
+				}
+				model = value
+			}
+		case "data_blocks":
+			if ranges, err = ParseSiteRanges(value, nil); err != nil {
+				err = fmt.Errorf("line %d: data block %s: %v", stmt.line, key, err)
+				return
+			}
+			if err = addSiteRanges(ps, key, model, ranges); err != nil {
+				err = fmt.Errorf("line %d: %v", stmt.line, err)
+				return
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,116 @@
+package partition
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// SiteRange is a range of sites: start-end\step (1-based).
+// End == -1 means the end of the alignment ('.').
+type SiteRange struct {
+	Start, End, Step int
+}
+
+// statement is a command of a NEXUS or PartitionFinder file,
+// terminated by a ';', with the line at which it starts.
+type statement struct {
+	text string
+	line int
+}
+
+var siteRangeRegexp = regexp.MustCompile(`^(\d+)(?:-(\d+|\.))?(?:[\\/](\d+))?$`)
+var siteRangeSpacesRegexp = regexp.MustCompile(`\s*([-\\/])\s*`)
+
+// Splits the content into statements separated by ';'.
+// Empty statements are discarded.
+func splitStatements(content string) (stmts []statement) {
+	var start int
+	var line, startline int = 1, 1
+
+	add := func(text string) {
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" {
+			return
+		}
+		leading := text[:strings.Index(text, trimmed)]
+		stmts = append(stmts, statement{trimmed, startline + strings.Count(leading, "\n")})
+	}
+
+	for i, c := range content {
+		switch c {
+		case ';':
+			add(content[start:i])
+			start = i + 1
+			startline = line
+		case '\n':
+			line++
+		}
+	}
+	add(content[start:])
+	return
+}
+
+// ParseSiteRanges parses a definition of site ranges, as given in NEXUS
+// CHARSET commands or PartitionFinder data blocks: "1-100 101-200\3, 201-.\3".
+//
+// Ranges are separated by spaces or commas. The step may be given with
+// '\' or '/'. Items that are not ranges are looked for in the given
+// named ranges (e.g. previously defined charsets), which may be nil.
+func ParseSiteRanges(def string, named map[string][]SiteRange) (ranges []SiteRange, err error) {
+	var match []string
+	var start, end, step int
+	var other []SiteRange
+	var ok bool
+
+	// "1 - 100 \ 3" => "1-100\3"
+	def = siteRangeSpacesRegexp.ReplaceAllString(def, "$1")
+	for _, item := range strings.FieldsFunc(def, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r' }) {
+		if match = siteRangeRegexp.FindStringSubmatch(item); match == nil {
+			if other, ok = named[item]; !ok {
+				err = fmt.Errorf("unknown site range %q", item)
+				return
+			}
+			ranges = append(ranges, other...)
+			continue
+		}
+		start, _ = strconv.Atoi(match[1])
+		end, step = start, 1
+		if match[2] == "." {
+			end = -1
+		} else if match[2] != "" {
+			end, _ = strconv.Atoi(match[2])
+		}
+		if match[3] != "" {
+			step, _ = strconv.Atoi(match[3])
+		}
+		if start < 1 || (end != -1 && end < start) || step < 1 {
+			err = fmt.Errorf("wrong site range %q", item)
+			return
+		}
+		ranges = append(ranges, SiteRange{start, end, step})
+	}
+	if len(ranges) == 0 {
+		err = fmt.Errorf("no site range defined")
+	}
+	return
+}
+
+// Adds the given 1-based site ranges to the partition partName of the partition set
+func addSiteRanges(ps *align.PartitionSet, partName, model string, ranges []SiteRange) (err error) {
+	var end int
+
+	for _, r := range ranges {
+		end = r.End
+		if end == -1 {
+			end = ps.AliLength()
+		}
+		if err = ps.AddRange(partName, model, r.Start-1, end-1, r.Step); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -1,6 +1,7 @@
 package phylip
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 	"testing"
@@ -106,3 +107,31 @@ func TestParseStrict(t *testing.T) {
 	// 	t.Error("Alignment has not 1000 sequences : " + fmt.Sprintf("%d", align5.NbSequences()))
 	// }
 }
+
+func TestWriterBlocks(t *testing.T) {
+	al, err := NewParser(strings.NewReader(phylipstring1), false).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.LineWidth = 15
+	w.BlockSize = 4
+	if err = w.WriteAlignment(al); err != nil {
+		t.Fatal(err)
+	}
+	exp := "   2   20\nseq1  ACGA TCGA TCAC GCT\nseq2  AGCT CGTC GAGA TCG\n\n   AGCT A\n   CTAG C\n"
+	if buf.String() != exp {
+		t.Errorf("Phylip output should be:\n%s\nand not:\n%s", exp, buf.String())
+	}
+	if out := WriteAlignment(al, false, true, true); out != "   2   20\nseq1  ACGATCGATCACGCTAGCTA\nseq2  AGCTCGTCGAGATCGCTAGC\n" {
+		t.Errorf("Wrong phylip one line output:\n%s", out)
+	}
+	al2, err := NewParser(strings.NewReader(exp), false).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !al.Identical(al2) {
+		t.Errorf("Written alignment should be identical to the input alignment")
+	}
+}
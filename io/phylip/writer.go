@@ -1,8 +1,10 @@
 package phylip
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/evolbioinfo/goalign/align"
 )
@@ -19,56 +21,92 @@ func min_int(a int, b int) int {
 	return b
 }
 
-func WriteAlignment(al align.Alignment, strict, oneline, noblock bool) string {
-	var buf bytes.Buffer
+// Writer writes alignments in phylip (interleaved) format to an io.Writer.
+// Sequences are written as they are iterated, the whole
+// output is never stored in memory.
+type Writer struct {
+	w         *bufio.Writer
+	Strict    bool // Names are truncated/padded to 10 characters
+	LineWidth int  // Number of sites per line (<=0: each sequence on a single line)
+	BlockSize int  // Number of sites per space separated block (<=0: no blocks)
+}
+
+// NewWriter returns a phylip writer to w, with lines of PHYLIP_LINE
+// sites, in blocks of PHYLIP_BLOCK sites.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w), Strict: false, LineWidth: PHYLIP_LINE, BlockSize: PHYLIP_BLOCK}
+}
+
+// WriteAlignment writes the alignment and flushes the writer.
+func (w *Writer) WriteAlignment(al align.Alignment) (err error) {
 	var header bool = true
-	var line_length = PHYLIP_LINE
-	var block_length = PHYLIP_BLOCK
+	var line_length = w.LineWidth
+	var block_length = w.BlockSize
 
 	cursize := 0
-	buf.WriteString(fmt.Sprintf("   %d   %d\n", al.NbSequences(), al.Length()))
+	w.w.WriteString(fmt.Sprintf("   %d   %d\n", al.NbSequences(), al.Length()))
 
-	if oneline {
+	if line_length <= 0 {
 		line_length = al.Length()
 	}
-	if noblock {
+	if block_length <= 0 {
 		block_length = line_length
 	}
 
-	for cursize < al.Length() {
+	for cursize < al.Length() && err == nil {
 		if cursize > 0 {
-			buf.WriteString("\n")
+			w.w.WriteString("\n")
 		}
 		al.IterateChar(func(name string, seq []uint8) bool {
 			if header {
-				if strict {
-					buf.WriteString(fmt.Sprintf("%-10s", name[:min_int(10, len(name))]))
+				if w.Strict {
+					w.w.WriteString(fmt.Sprintf("%-10s", name[:min_int(10, len(name))]))
 				} else {
-					buf.WriteString(name)
-					buf.WriteString("  ")
+					w.w.WriteString(name)
+					w.w.WriteString("  ")
 				}
 			}
 
 			for i := cursize; i < cursize+line_length && i < len(seq); i += block_length {
 				if i > cursize {
-					buf.WriteString(" ")
+					w.w.WriteString(" ")
 				} else if !header {
-					if strict {
-						buf.WriteString("          ")
+					if w.Strict {
+						w.w.WriteString("          ")
 					} else {
-						buf.WriteString("   ")
+						w.w.WriteString("   ")
 					}
 				}
-				end := min_int(i+block_length, len(seq))
-				for j := i; j < end; j++ {
-					buf.WriteByte(seq[j])
-				}
+				w.w.Write(seq[i:min_int(min_int(i+block_length, cursize+line_length), len(seq))])
 			}
-			buf.WriteString("\n")
-			return false
+			_, err = w.w.WriteString("\n")
+			return err != nil
 		})
 		cursize += line_length
 		header = false
 	}
+	if err != nil {
+		return
+	}
+	return w.w.Flush()
+}
+
+// WriteAlignment returns the alignment in phylip format:
+//   - strict: names are truncated/padded to 10 characters;
+//   - oneline: each sequence is written on a single line;
+//   - noblock: sequences are not split in blocks of PHYLIP_BLOCK sites.
+//
+// For large alignments, prefer Writer, which does not store the whole output in memory.
+func WriteAlignment(al align.Alignment, strict, oneline, noblock bool) string {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Strict = strict
+	if oneline {
+		w.LineWidth = 0
+	}
+	if noblock {
+		w.BlockSize = 0
+	}
+	w.WriteAlignment(al)
 	return buf.String()
 }
@@ -0,0 +1,270 @@
+package stockholm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// Parser represents a parser.
+// If ignore is align.IGNORE_NONE: Does not ignore anything
+// If ignore is align.IGNORE_NAME: Ignore sequences having the same name (keep the first one whatever their sequence)
+// If ignore is align.IGNORE_SEQUENCE: Ignore sequences having the same name and the same sequence
+// Otherwise, sets IGNORE_NONE
+type Parser struct {
+	s               *Scanner
+	ignoreidentical int
+	nbparsed        int // Number of alignments already parsed
+	buf             struct {
+		tok Token  // last read token
+		lit string // last read literal
+		n   int    // buffer size (max=1)
+	}
+}
+
+// NewParser returns a new instance of Parser.
+func NewParser(r io.Reader) *Parser {
+	return &Parser{s: NewScanner(r), ignoreidentical: align.IGNORE_NONE, nbparsed: 0}
+}
+
+// If sets to true, then will ignore duplicate sequences that have the same name and the same sequence
+// Otherwise, it just renames them just as the sequences that have same name and different sequences
+func (p *Parser) IgnoreIdentical(ignore int) {
+	p.ignoreidentical = ignore
+}
+
+// scan returns the next token from the underlying scanner.
+// If a token has been unscanned then read that instead.
+func (p *Parser) scan() (tok Token, lit string) {
+	// If we have a token on the buffer, then return it.
+	if p.buf.n != 0 {
+		p.buf.n = 0
+		return p.buf.tok, p.buf.lit
+	}
+
+	// Otherwise read the next token from the scanner.
+	tok, lit = p.s.Scan()
+
+	// Save it to the buffer in case we unscan later.
+	p.buf.tok, p.buf.lit = tok, lit
+	return
+}
+
+// unscan pushes the previously read token back onto the buffer.
+func (p *Parser) unscan() { p.buf.n = 1 }
+
+// scanIgnoreWhitespace scans the next non-whitespace token.
+func (p *Parser) scanIgnoreWhitespace() (tok Token, lit string) {
+	tok, lit = p.scan()
+	for tok == WS {
+		tok, lit = p.scan()
+	}
+	return
+}
+
+// scanField scans the next non-whitespace field of the current line
+// (feature, name, sequence or annotation).
+func (p *Parser) scanField(what string) (lit string, err error) {
+	var tok Token
+	tok, lit = p.scanIgnoreWhitespace()
+	if tok == ENDOFLINE || tok == EOF {
+		err = fmt.Errorf("we expect a %s here", what)
+	}
+	return
+}
+
+// scanText scans the end of the current line (free text), without
+// leading and trailing whitespaces
+func (p *Parser) scanText() string {
+	var buf bytes.Buffer
+	tok, lit := p.scanIgnoreWhitespace()
+	for tok != ENDOFLINE && tok != EOF {
+		buf.WriteString(lit)
+		tok, lit = p.scan()
+	}
+	p.unscan()
+	return strings.TrimRight(buf.String(), " \t")
+}
+
+// scanEndOfLine checks that there is nothing else on the current line
+func (p *Parser) scanEndOfLine() (err error) {
+	tok, _ := p.scanIgnoreWhitespace()
+	if tok != ENDOFLINE && tok != EOF {
+		err = errors.New("we expect ENDOFLINE after per column data")
+	}
+	p.unscan()
+	return
+}
+
+// Parse parses a stockholm alignment, until the end of alignment "//" line.
+//
+// Sequences and per column annotations (#=GC and #=GR) may be given in several blocks.
+// All annotations (#=GF, #=GS, #=GC and #=GR) are kept in the alignment Annotations().
+// '.' characters of the sequences (insertion gaps) are considered as standard gaps '-'.
+//
+// If there is no more alignment to parse in the input (only after at least one alignment has been
+// parsed), it returns nil, nil.
+func (p *Parser) Parse() (al align.Alignment, err error) {
+	var tok Token
+	var lit string
+	var name, feature, data string
+	var names []string = make([]string, 0)
+	var seqs map[string]*bytes.Buffer = make(map[string]*bytes.Buffer)
+	var gcs, grs map[string]int
+	var an *align.Annotations = align.NewAnnotations()
+	var ok bool
+	var idx int
+
+	gcs = make(map[string]int)
+	grs = make(map[string]int)
+
+	// Skip empty lines
+	tok, lit = p.scanIgnoreWhitespace()
+	for tok == ENDOFLINE {
+		tok, lit = p.scanIgnoreWhitespace()
+	}
+	if tok == EOF && p.nbparsed > 0 {
+		return
+	}
+	if tok != COMMENT || !isHeader(lit) {
+		err = errors.New("stockholm alignment must start with '# STOCKHOLM'")
+		return
+	}
+
+	for tok != END {
+		tok, lit = p.scanIgnoreWhitespace()
+		switch tok {
+		case ENDOFLINE, COMMENT:
+			// Empty line or comment: nothing to do
+		case GF:
+			if feature, err = p.scanField("feature"); err != nil {
+				return
+			}
+			an.GF = append(an.GF, align.Annotation{Feature: feature, Text: p.scanText()})
+		case GS:
+			if name, err = p.scanField("sequence name"); err != nil {
+				return
+			}
+			if feature, err = p.scanField("feature"); err != nil {
+				return
+			}
+			an.GS = append(an.GS, align.Annotation{Name: name, Feature: feature, Text: p.scanText()})
+		case GC:
+			if feature, err = p.scanField("feature"); err != nil {
+				return
+			}
+			if data, err = p.scanField("per column annotation"); err != nil {
+				return
+			}
+			if err = p.scanEndOfLine(); err != nil {
+				return
+			}
+			if idx, ok = gcs[feature]; ok {
+				an.GC[idx].Text += data
+			} else {
+				gcs[feature] = len(an.GC)
+				an.GC = append(an.GC, align.Annotation{Feature: feature, Text: data})
+			}
+		case GR:
+			if name, err = p.scanField("sequence name"); err != nil {
+				return
+			}
+			if feature, err = p.scanField("feature"); err != nil {
+				return
+			}
+			if data, err = p.scanField("per residue annotation"); err != nil {
+				return
+			}
+			if err = p.scanEndOfLine(); err != nil {
+				return
+			}
+			if idx, ok = grs[name+"\t"+feature]; ok {
+				an.GR[idx].Text += data
+			} else {
+				grs[name+"\t"+feature] = len(an.GR)
+				an.GR = append(an.GR, align.Annotation{Name: name, Feature: feature, Text: data})
+			}
+		case IDENTIFIER:
+			name = lit
+			if data, err = p.scanField("sequence"); err != nil {
+				return
+			}
+			if err = p.scanEndOfLine(); err != nil {
+				return
+			}
+			if _, ok = seqs[name]; !ok {
+				names = append(names, name)
+				seqs[name] = new(bytes.Buffer)
+			}
+			seqs[name].WriteString(data)
+		case END:
+			// End of the alignment, we skip the end of the line
+			p.scanText()
+		case EOF:
+			err = errors.New("stockholm alignment must end with '//'")
+			return
+		default:
+			err = fmt.Errorf("unexpected token in stockholm alignment: %s", lit)
+			return
+		}
+	}
+
+	if len(names) == 0 {
+		err = errors.New("no sequences in the alignment")
+		return
+	}
+
+	for _, n := range names {
+		s := strings.ReplaceAll(seqs[n].String(), string(align.POINT), string(align.GAP))
+		if al == nil {
+			al = align.NewAlign(align.DetectAlphabet(s))
+			al.IgnoreIdentical(p.ignoreidentical)
+		}
+		if err = al.AddSequence(n, s, ""); err != nil {
+			return
+		}
+	}
+	for _, a := range an.GC {
+		if len(a.Text) != al.Length() {
+			err = fmt.Errorf("#=GC %s annotation length (%d) is different from alignment length (%d)", a.Feature, len(a.Text), al.Length())
+			return
+		}
+	}
+	for _, a := range an.GR {
+		if len(a.Text) != al.Length() {
+			err = fmt.Errorf("#=GR %s %s annotation length (%d) is different from alignment length (%d)", a.Name, a.Feature, len(a.Text), al.Length())
+			return
+		}
+	}
+	if !an.Empty() {
+		al.SetAnnotations(an)
+	}
+	p.nbparsed++
+
+	return
+}
+
+// ParseMultiple parses all the alignments of the input stockholm file,
+// and sends them to the given channel
+func (p *Parser) ParseMultiple(aligns *align.AlignChannel) {
+	var al align.Alignment
+	var err error
+	al, err = p.Parse()
+	for err == nil && al != nil {
+		aligns.Achan <- al
+		al, err = p.Parse()
+	}
+	aligns.Err = err
+
+	close(aligns.Achan)
+}
+
+// Returns true if the given comment line is a stockholm header: "# STOCKHOLM 1.0"
+func isHeader(comment string) bool {
+	fields := strings.Fields(strings.TrimPrefix(comment, "#"))
+	return len(fields) > 0 && strings.ToUpper(fields[0]) == "STOCKHOLM"
+}
@@ -0,0 +1,220 @@
+package stockholm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+var stockholmstring1 string = `# STOCKHOLM 1.0
+#=GF ID   test_family
+#=GF CC   some comment
+#=GF CC   with two lines
+#=GS s1/1-10 AC P12345.1
+
+s1/1-10    ACDEF.GHIK
+#=GR s1/1-10 SS ---HHH-HH-
+s2/3-12    ACDEFLGHIK
+#=GC SS_cons   <<<...>>>.
+
+s1/1-10    LM
+#=GR s1/1-10 SS EE
+s2/3-12    LN
+#=GC SS_cons   ..
+//
+# STOCKHOLM 1.0
+a ACGT
+b ACGA
+//
+`
+
+var stockholmstring1out string = `# STOCKHOLM 1.0
+#=GF ID test_family
+#=GF CC some comment
+#=GF CC with two lines
+#=GS s1/1-10 AC P12345.1
+
+s1/1-10         ACDEF-GHIKLM
+#=GR s1/1-10 SS ---HHH-HH-EE
+s2/3-12         ACDEFLGHIKLN
+#=GC SS_cons    <<<...>>>...
+//
+`
+
+// Missing "//"
+var stockholmstring2 string = `# STOCKHOLM 1.0
+a ACGT
+b ACGA
+`
+
+// Wrong #=GC length
+var stockholmstring3 string = `# STOCKHOLM 1.0
+a ACGT
+b ACGA
+#=GC SS_cons ...
+//
+`
+
+// No header
+var stockholmstring4 string = `a ACGT
+b ACGA
+//
+`
+
+func TestParse(t *testing.T) {
+	p := NewParser(strings.NewReader(stockholmstring1))
+	al, err := p.Parse()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if al.Length() != 12 {
+		t.Errorf("Alignment length is not 12 (%d)", al.Length())
+	}
+	if al.NbSequences() != 2 {
+		t.Errorf("There are not 2 sequences in the alignment (%d)", al.NbSequences())
+	}
+	an := al.Annotations()
+	if an == nil {
+		t.Errorf("Annotations should have been parsed")
+		return
+	}
+	expgf := []align.Annotation{
+		{Feature: "ID", Text: "test_family"},
+		{Feature: "CC", Text: "some comment"},
+		{Feature: "CC", Text: "with two lines"},
+	}
+	for i, a := range expgf {
+		if i >= len(an.GF) || an.GF[i] != a {
+			t.Errorf("Wrong #=GF annotation %d: %v", i, an.GF)
+		}
+	}
+	if len(an.GS) != 1 || an.GS[0] != (align.Annotation{Name: "s1/1-10", Feature: "AC", Text: "P12345.1"}) {
+		t.Errorf("Wrong #=GS annotations: %v", an.GS)
+	}
+	if len(an.GC) != 1 || an.GC[0] != (align.Annotation{Feature: "SS_cons", Text: "<<<...>>>..."}) {
+		t.Errorf("Wrong #=GC annotations: %v", an.GC)
+	}
+	if len(an.GR) != 1 || an.GR[0] != (align.Annotation{Name: "s1/1-10", Feature: "SS", Text: "---HHH-HH-EE"}) {
+		t.Errorf("Wrong #=GR annotations: %v", an.GR)
+	}
+
+	// Second alignment
+	al, err = p.Parse()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if al.Length() != 4 || al.NbSequences() != 2 {
+		t.Errorf("Wrong second alignment dimensions (%d,%d)", al.NbSequences(), al.Length())
+	}
+	if al.Annotations() != nil {
+		t.Errorf("Second alignment should not have annotations")
+	}
+
+	// No more alignment
+	al, err = p.Parse()
+	if err != nil || al != nil {
+		t.Errorf("There should not be any more alignment: %v", err)
+	}
+}
+
+func TestParseDots(t *testing.T) {
+	al, err := NewParser(strings.NewReader("# STOCKHOLM 1.0\na AC..GT\nb ACTTGT\n//\n")).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := al.GetSequence("a"); s != "AC--GT" {
+		t.Errorf("'.' should be parsed as gaps: %s", s)
+	}
+}
+
+func TestParseMultiple(t *testing.T) {
+	alchan := &align.AlignChannel{}
+	alchan.Achan = make(chan align.Alignment, 15)
+	go NewParser(strings.NewReader(stockholmstring1)).ParseMultiple(alchan)
+	nb := 0
+	for range alchan.Achan {
+		nb++
+	}
+	if alchan.Err != nil {
+		t.Error(alchan.Err)
+	}
+	if nb != 2 {
+		t.Errorf("There should be 2 alignments (%d)", nb)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for i, s := range []string{stockholmstring2, stockholmstring3, stockholmstring4} {
+		if _, err := NewParser(strings.NewReader(s)).Parse(); err == nil {
+			t.Errorf("There should be an error while reading alignment %d", i+2)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	al, err := NewParser(strings.NewReader(stockholmstring1)).Parse()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if out := WriteAlignment(al); out != stockholmstring1out {
+		t.Errorf("Written alignment is not the expected one:\n%s\nvs.\n%s", out, stockholmstring1out)
+	}
+
+	// Round trip
+	al2, err := NewParser(strings.NewReader(WriteAlignment(al))).Parse()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !al.Identical(al2) {
+		t.Errorf("Alignment is different after round trip")
+	}
+	if out := WriteAlignment(al2); out != stockholmstring1out {
+		t.Errorf("Annotations are different after round trip:\n%s", out)
+	}
+}
+
+func TestWriteBlocks(t *testing.T) {
+	al, err := NewParser(strings.NewReader(stockholmstring1)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.LineWidth = 10
+	if err = w.WriteAlignment(al); err != nil {
+		t.Fatal(err)
+	}
+	exp := `# STOCKHOLM 1.0
+#=GF ID test_family
+#=GF CC some comment
+#=GF CC with two lines
+#=GS s1/1-10 AC P12345.1
+
+s1/1-10         ACDEF-GHIK
+#=GR s1/1-10 SS ---HHH-HH-
+s2/3-12         ACDEFLGHIK
+#=GC SS_cons    <<<...>>>.
+
+s1/1-10         LM
+#=GR s1/1-10 SS EE
+s2/3-12         LN
+#=GC SS_cons    ..
+//
+`
+	if buf.String() != exp {
+		t.Errorf("Written alignment is not the expected one:\n%s\nvs.\n%s", buf.String(), exp)
+	}
+	al2, err := NewParser(strings.NewReader(buf.String())).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := WriteAlignment(al2); out != stockholmstring1out {
+		t.Errorf("Alignment is different after round trip:\n%s", out)
+	}
+}
@@ -0,0 +1,39 @@
+package stockholm
+
+type Token int64
+
+var eof = rune(0)
+
+const (
+	ILLEGAL    Token = iota
+	IDENTIFIER       // Sequence name, sequence, feature or annotation text
+	ENDOFLINE        // End of line token
+	COMMENT          // Comment line starting with '#' (including "# STOCKHOLM 1.0" header)
+	GF               // Per file annotation: "#=GF"
+	GS               // Per sequence annotation: "#=GS"
+	GC               // Per column annotation: "#=GC"
+	GR               // Per residue annotation: "#=GR"
+	END              // End of alignment: "//"
+	EOF              // End of File
+	WS               // Whitespace
+)
+
+func isEndOfLine(ch rune) bool {
+	return ch == '\n' || ch == '\r'
+}
+
+func isCR(ch rune) bool {
+	return ch == '\r'
+}
+
+func isNL(ch rune) bool {
+	return ch == '\n'
+}
+
+func isIdent(ch rune) bool {
+	return ch != '\n' && ch != ' ' && ch != '\t' && ch != '\r'
+}
+
+func isWhitespace(ch rune) bool {
+	return ch == ' ' || ch == '\t'
+}
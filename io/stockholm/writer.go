@@ -0,0 +1,153 @@
+package stockholm
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// Writer writes alignments in stockholm format to an io.Writer.
+// Sequences are written as they are iterated, the whole
+// output is never stored in memory.
+type Writer struct {
+	w         *bufio.Writer
+	LineWidth int // Number of sites per block (<=0: each sequence on a single line)
+}
+
+// NewWriter returns a stockholm writer to w, writing each
+// sequence on a single line.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w), LineWidth: 0}
+}
+
+// WriteAlignment writes the alignment in stockholm format, and flushes the writer.
+// Each sequence is followed by its #=GR annotations. If LineWidth>0, the
+// alignment is split in blocks of LineWidth sites, separated by empty lines.
+//
+// Annotations of the alignment (see align.Alignment.Annotations()) are written
+// if they still correspond to the alignment:
+//   - #=GS and #=GR annotations are written only if the sequence is still in the alignment;
+//   - #=GC and #=GR annotations are written only if their length is the alignment length.
+func (w *Writer) WriteAlignment(al align.Alignment) (err error) {
+	var buf = w.w
+	var line_length = w.LineWidth
+	var an *align.Annotations
+	var grs map[string][]align.Annotation
+	var ok bool
+
+	an = al.Annotations()
+	if an == nil {
+		an = align.NewAnnotations()
+	}
+
+	// Per residue annotations, grouped by sequence
+	grs = make(map[string][]align.Annotation)
+	for _, a := range an.GR {
+		if _, ok = al.GetSequenceChar(a.Name); ok && len(a.Text) == al.Length() {
+			grs[a.Name] = append(grs[a.Name], a)
+		}
+	}
+
+	// Get length of the longest name
+	maxnamelength := 0
+	al.IterateChar(func(name string, seq []uint8) bool {
+		if len(name) > maxnamelength {
+			maxnamelength = len(name)
+		}
+		for _, a := range grs[name] {
+			if l := len("#=GR ") + len(name) + 1 + len(a.Feature); l > maxnamelength {
+				maxnamelength = l
+			}
+		}
+		return false
+	})
+	for _, a := range an.GC {
+		if l := len("#=GC ") + len(a.Feature); len(a.Text) == al.Length() && l > maxnamelength {
+			maxnamelength = l
+		}
+	}
+
+	buf.WriteString("# STOCKHOLM 1.0\n")
+	for _, a := range an.GF {
+		buf.WriteString("#=GF ")
+		buf.WriteString(a.Feature)
+		buf.WriteRune(' ')
+		buf.WriteString(a.Text)
+		buf.WriteRune('\n')
+	}
+	for _, a := range an.GS {
+		if _, ok = al.GetSequenceChar(a.Name); !ok {
+			continue
+		}
+		buf.WriteString("#=GS ")
+		buf.WriteString(a.Name)
+		buf.WriteRune(' ')
+		buf.WriteString(a.Feature)
+		buf.WriteRune(' ')
+		buf.WriteString(a.Text)
+		buf.WriteRune('\n')
+	}
+	if len(an.GF) > 0 || len(an.GS) > 0 {
+		buf.WriteRune('\n')
+	}
+
+	if line_length <= 0 || line_length > al.Length() {
+		line_length = al.Length()
+	}
+	// Each sequence is written once, even if the alignment is empty
+	for cursize := 0; ; {
+		end := cursize + line_length
+		if cursize > 0 {
+			buf.WriteRune('\n')
+		}
+		if end > al.Length() {
+			end = al.Length()
+		}
+		al.IterateChar(func(name string, seq []uint8) bool {
+			writePaddedName(buf, name, maxnamelength)
+			buf.Write(seq[cursize:end])
+			_, err = buf.WriteRune('\n')
+			for _, a := range grs[name] {
+				writePaddedName(buf, "#=GR "+name+" "+a.Feature, maxnamelength)
+				buf.WriteString(a.Text[cursize:end])
+				_, err = buf.WriteRune('\n')
+			}
+			return err != nil
+		})
+		for _, a := range an.GC {
+			if len(a.Text) != al.Length() {
+				continue
+			}
+			writePaddedName(buf, "#=GC "+a.Feature, maxnamelength)
+			buf.WriteString(a.Text[cursize:end])
+			buf.WriteRune('\n')
+		}
+		if cursize = end; err != nil || cursize >= al.Length() {
+			break
+		}
+	}
+	if err != nil {
+		return
+	}
+	buf.WriteString("//\n")
+	return buf.Flush()
+}
+
+// WriteAlignment returns the alignment in stockholm format (see Writer.WriteAlignment),
+// each sequence on a single line. For large alignments, prefer Writer, which does not
+// store the whole output in memory.
+func WriteAlignment(al align.Alignment) string {
+	var buf bytes.Buffer
+	NewWriter(&buf).WriteAlignment(al)
+	return buf.String()
+}
+
+// Writes the name followed by enough spaces to align sequences
+func writePaddedName(buf *bufio.Writer, name string, maxnamelength int) {
+	buf.WriteString(name)
+	for i := len(name); i < maxnamelength+1; i++ {
+		buf.WriteRune(' ')
+	}
+}
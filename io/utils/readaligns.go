@@ -3,21 +3,24 @@ package utils
 import (
 	"bufio"
 	"io"
+	"strings"
 
 	"github.com/evolbioinfo/goalign/align"
 	"github.com/evolbioinfo/goalign/io/clustal"
 	"github.com/evolbioinfo/goalign/io/fasta"
 	"github.com/evolbioinfo/goalign/io/nexus"
 	"github.com/evolbioinfo/goalign/io/phylip"
+	"github.com/evolbioinfo/goalign/io/stockholm"
 )
 
 // Parses the input buffer while automatically
-// detecting the format between Newick, Phylip, and Nexus
+// detecting the format between Fasta, Stockholm, Nexus, Clustal and Phylip
 //
 // If several alignments are present in the onput file, only the first will be
 // parsed.
 //
-// Returned format may be align.FORMAT_PHYLIP, align.FORMAT_FASTA, or align.FORMAT_NEXUS
+// Returned format may be align.FORMAT_PHYLIP, align.FORMAT_FASTA, align.FORMAT_NEXUS,
+// align.FORMAT_CLUSTAL, or align.FORMAT_STOCKHOLM
 //
 // rootinpustrict: In the case of phylip detected format: should we consider it as strict or not?
 //
@@ -38,6 +41,11 @@ func ParseAlignmentAuto(r *bufio.Reader, rootinputstrict bool) (al align.Alignme
 	if firstbyte == '>' {
 		format = align.FORMAT_FASTA
 		al, err = fasta.NewParser(r).Parse()
+	} else if firstbyte == '#' && isStockholm(r) {
+		if al, err = stockholm.NewParser(r).Parse(); err != nil {
+			return
+		}
+		format = align.FORMAT_STOCKHOLM
 	} else if firstbyte == '#' {
 		if al, err = nexus.NewParser(r).Parse(); err != nil {
 			return
@@ -57,7 +65,7 @@ func ParseAlignmentAuto(r *bufio.Reader, rootinputstrict bool) (al align.Alignme
 }
 
 // Parses the input buffer while automatically
-// detecting the format between Newick, Phylip, and Nexus
+// detecting the format between Fasta, Stockholm, Nexus, Clustal and Phylip
 //
 // If several alignments are present in the input file, they are queued in the channel
 //
@@ -92,6 +100,15 @@ func ParseMultiAlignmentsAuto(f io.Closer, r *bufio.Reader, rootinputstrict bool
 			f.Close()
 		}
 		close(alchan.Achan)
+	} else if firstbyte == '#' && isStockholm(r) {
+		format = align.FORMAT_STOCKHOLM
+		alchan.Achan = make(chan align.Alignment, 15)
+		go func() {
+			stockholm.NewParser(r).ParseMultiple(alchan)
+			if f != nil {
+				f.Close()
+			}
+		}()
 	} else if firstbyte == '#' {
 		if al, err = nexus.NewParser(r).Parse(); err != nil {
 			return
@@ -135,6 +152,7 @@ func ParseMultiAlignmentsAuto(f io.Closer, r *bufio.Reader, rootinputstrict bool
 // - align.FORMAT_PHYLIP
 // - align.FORMAT_NEXUS
 // - align.FORMAT_CLUSTAL
+// - align.FORMAT_STOCKHOLM
 // - align.FORMAT_FASTA
 // - any other value is interpreted as align.FORMAT_FASTA
 func ReadAlign(file string, format int) (outAlign align.Alignment, err error) {
@@ -156,6 +174,10 @@ func ReadAlign(file string, format int) (outAlign align.Alignment, err error) {
 		cp := clustal.NewParser(r)
 		cp.IgnoreIdentical(align.IGNORE_NONE)
 		outAlign, err = cp.Parse()
+	} else if format == align.FORMAT_STOCKHOLM {
+		sp := stockholm.NewParser(r)
+		sp.IgnoreIdentical(align.IGNORE_NONE)
+		outAlign, err = sp.Parse()
 	} else {
 		// FASTA
 		fp := fasta.NewParser(r)
@@ -166,3 +188,11 @@ func ReadAlign(file string, format int) (outAlign align.Alignment, err error) {
 
 	return
 }
+
+// Returns true if the buffer starts with a stockholm header ("# STOCKHOLM")
+// It does not consume the buffer
+func isStockholm(r *bufio.Reader) bool {
+	var header []byte
+	header, _ = r.Peek(len("# STOCKHOLM"))
+	return strings.EqualFold(strings.Replace(string(header), " ", "", 1), "#STOCKHOLM")
+}
@@ -0,0 +1,75 @@
+package vcf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// WriteVariants writes the given variants in VCF (v4.2) format.
+//
+// Variants are given in the coordinates of the reference sequence refname,
+// which is the only "chromosome" (CHROM column) of the file. Samples are all
+// the sequences of the alignment except the reference, in the alignment order
+// (as given by align.Alignment.Variants()).
+func WriteVariants(al align.Alignment, refname string, variants []align.Variant) string {
+	var buf bytes.Buffer
+	var reflen int
+
+	if ref, ok := al.GetSequenceChar(refname); ok {
+		for _, c := range ref {
+			if c != align.GAP {
+				reflen++
+			}
+		}
+	}
+
+	buf.WriteString("##fileformat=VCFv4.2\n")
+	buf.WriteString("##source=goalign\n")
+	buf.WriteString(fmt.Sprintf("##contig=<ID=%s,length=%d>\n", refname, reflen))
+	buf.WriteString("##FORMAT=<ID=GT,Number=1,Type=String,Description=\"Genotype\">\n")
+	buf.WriteString("#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\tFORMAT")
+	al.IterateChar(func(name string, sequence []uint8) bool {
+		if name != refname {
+			buf.WriteString("\t")
+			buf.WriteString(name)
+		}
+		return false
+	})
+	buf.WriteString("\n")
+
+	for _, v := range variants {
+		buf.WriteString(refname)
+		buf.WriteString("\t")
+		buf.WriteString(strconv.Itoa(v.Pos))
+		buf.WriteString("\t.\t")
+		buf.WriteString(v.Ref)
+		buf.WriteString("\t")
+		if len(v.Alts) == 0 {
+			buf.WriteString(".")
+		}
+		for i, alt := range v.Alts {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(alt)
+		}
+		buf.WriteString("\t.\tPASS\t.\tGT")
+		for _, g := range v.Genotypes {
+			buf.WriteString("\t")
+			if len(g) == 0 {
+				buf.WriteString(".")
+			}
+			for i, a := range g {
+				if i > 0 {
+					buf.WriteString("/")
+				}
+				buf.WriteString(strconv.Itoa(a))
+			}
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
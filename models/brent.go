@@ -0,0 +1,98 @@
+package models
+
+import (
+	"math"
+)
+
+const (
+	BRENT_ITMAX = 1000
+	BRENT_ZEPS  = 1.e-10
+	BRENT_CGOLD = 0.3819660
+)
+
+// BrentMinimize finds the minimum of f in the interval [ax,cx],
+// starting from bx (ax<=bx<=cx), using Brent's method.
+//
+// tol is the relative precision on the position of the minimum.
+// Returns the position of the minimum and the value of f at this position.
+func BrentMinimize(f func(x float64) float64, ax, bx, cx, tol float64) (xmin, fmin float64) {
+	var a, b, d, e, etemp, fu, fv, fw, fx, p, q, r, tol1, tol2, u, v, w, x, xm float64
+
+	a, b = math.Min(ax, cx), math.Max(ax, cx)
+	x, w, v = bx, bx, bx
+	fx = f(x)
+	fw, fv = fx, fx
+
+	for iter := 0; iter < BRENT_ITMAX; iter++ {
+		xm = 0.5 * (a + b)
+		tol1 = tol*math.Abs(x) + BRENT_ZEPS
+		tol2 = 2.0 * tol1
+		if math.Abs(x-xm) <= tol2-0.5*(b-a) {
+			break
+		}
+		if math.Abs(e) > tol1 {
+			// Parabolic step
+			r = (x - w) * (fx - fv)
+			q = (x - v) * (fx - fw)
+			p = (x-v)*q - (x-w)*r
+			q = 2.0 * (q - r)
+			if q > 0.0 {
+				p = -p
+			}
+			q = math.Abs(q)
+			etemp = e
+			e = d
+			if math.Abs(p) >= math.Abs(0.5*q*etemp) || p <= q*(a-x) || p >= q*(b-x) {
+				if x >= xm {
+					e = a - x
+				} else {
+					e = b - x
+				}
+				d = BRENT_CGOLD * e
+			} else {
+				d = p / q
+				u = x + d
+				if u-a < tol2 || b-u < tol2 {
+					d = math.Copysign(tol1, xm-x)
+				}
+			}
+		} else {
+			// Golden section step
+			if x >= xm {
+				e = a - x
+			} else {
+				e = b - x
+			}
+			d = BRENT_CGOLD * e
+		}
+		if math.Abs(d) >= tol1 {
+			u = x + d
+		} else {
+			u = x + math.Copysign(tol1, d)
+		}
+		fu = f(u)
+		if fu <= fx {
+			if u >= x {
+				a = x
+			} else {
+				b = x
+			}
+			v, w, x = w, x, u
+			fv, fw, fx = fw, fx, fu
+		} else {
+			if u < x {
+				a = u
+			} else {
+				b = u
+			}
+			if fu <= fw || w == x {
+				v, w = w, u
+				fv, fw = fw, fu
+			} else if fu <= fv || v == x || v == w {
+				v = u
+				fv = fu
+			}
+		}
+	}
+	return x, fx
+}
@@ -312,3 +312,14 @@ func TestJCPij(t *testing.T) {
 		}
 	}
 }
+
+func TestWrongKappa(t *testing.T) {
+	for _, kappa := range []float64{-1, math.NaN(), math.Inf(1)} {
+		if err := NewK2PModel().InitModel(kappa); err == nil {
+			t.Errorf("K2P model initialization should fail with kappa=%v", kappa)
+		}
+		if err := NewF84Model().InitModel(kappa, 1./4., 1./4., 1./4., 1./4.); err == nil {
+			t.Errorf("F84 model initialization should fail with kappa=%v", kappa)
+		}
+	}
+}
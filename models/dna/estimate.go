@@ -0,0 +1,277 @@
+package dna
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/evolbioinfo/goalign/models"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	EST_BL_MIN     = 1.e-06 // Minimum pairwise distance
+	EST_BL_MAX     = 10.0   // Maximum pairwise distance
+	EST_RATE_MIN   = 1.e-03 // Minimum relative rate
+	EST_RATE_MAX   = 1.e+03 // Maximum relative rate
+	EST_ALPHA_MIN  = 0.02   // Minimum gamma shape parameter
+	EST_ALPHA_MAX  = 100.0  // Maximum gamma shape parameter
+	EST_MAX_ROUNDS = 50     // Maximum number of optimization rounds
+	EST_LNL_EPS    = 1.e-03 // Minimum lnL improvement between two rounds
+	EST_TOL        = 1.e-04 // Relative precision of Brent optimization
+)
+
+// PairCounts contains the (weighted) number of sites of two aligned sequences
+// having each pair of nucleotides: index 4*i+j for nucleotide i in the first sequence and
+// nucleotide j in the second sequence (A=0, C=1, G=2, T=3)
+type PairCounts [16]float64
+
+// GTRParams contains the parameters of a GTR(+G) model
+type GTRParams struct {
+	Rates []float64 // Relative rates AC, AG, AT, CG, CT, GT (GT=1)
+	Pi    []float64 // Base frequencies A, C, G, T
+	Alpha float64   // Gamma shape parameter (0 if no gamma)
+	NCat  int       // Number of gamma categories (0 if no gamma)
+	LnL   float64   // Pairwise composite log-likelihood
+}
+
+// String returns the model in RAxML-NG format: GTR{AC/AG/AT/CG/CT/GT}+FU{A/C/G/T}[+G4{alpha}]
+func (p GTRParams) String() string {
+	s := fmt.Sprintf("GTR{%g/%g/%g/%g/%g/%g}+FU{%g/%g/%g/%g}",
+		round(p.Rates[0]), round(p.Rates[1]), round(p.Rates[2]), round(p.Rates[3]), round(p.Rates[4]), round(p.Rates[5]),
+		round(p.Pi[0]), round(p.Pi[1]), round(p.Pi[2]), round(p.Pi[3]))
+	if p.NCat > 0 {
+		s += fmt.Sprintf("+G%d{%g}", p.NCat, round(p.Alpha))
+	}
+	return s
+}
+
+func round(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
+
+// Pairwise composite likelihood of a GTR model
+type gtrLikelihood struct {
+	pairs []PairCounts
+	dists []float64 // Current distance of each pair
+	pi    []float64
+	ncat  int
+	rates []float64 // Current relative rates
+	alpha float64
+	// Decomposition of P(t): Pij(t) = sum_k coefs[i][j][k]*exp(vals[k]*t)
+	vals     []float64
+	coefs    [4][4][4]float64
+	catrates []float64
+}
+
+// EstimateGTR estimates the parameters of a GTR model, with empirical base
+// frequencies pi, from the nucleotide pair counts of several pairs of sequences.
+//
+// If ncat > 1, a discrete gamma rate heterogeneity with ncat categories is also estimated.
+// Parameters are estimated by maximizing the pairwise composite likelihood: each pair of
+// sequences is considered independently with its own distance, and all pairs share the
+// model parameters. Distances and parameters are optimized alternatively with Brent's method.
+//
+// Returns the estimated parameters and the ML distance of each pair.
+func EstimateGTR(pairs []PairCounts, pi []float64, ncat int) (params GTRParams, dists []float64, err error) {
+	var lnl, prevlnl float64
+	var l *gtrLikelihood
+
+	if len(pi) != 4 {
+		err = fmt.Errorf("there should be 4 base frequencies, not %d", len(pi))
+		return
+	}
+	if len(pairs) == 0 {
+		err = fmt.Errorf("at least one pair of sequences is needed to estimate model parameters")
+		return
+	}
+	if ncat < 2 {
+		ncat = 1
+	}
+
+	l = &gtrLikelihood{
+		pairs: pairs,
+		dists: make([]float64, len(pairs)),
+		pi:    pi,
+		ncat:  ncat,
+		rates: []float64{1, 1, 1, 1, 1, 1},
+		alpha: 1.0,
+	}
+	for i, p := range pairs {
+		l.dists[i] = jcDistance(p)
+	}
+	if err = l.update(); err != nil {
+		return
+	}
+
+	prevlnl = math.Inf(-1)
+	for round := 0; round < EST_MAX_ROUNDS; round++ {
+		l.optimizeDistances()
+		// GT rate is fixed to 1
+		for r := 0; r < 5; r++ {
+			if err = l.optimizeRate(r); err != nil {
+				return
+			}
+		}
+		if ncat > 1 {
+			if err = l.optimizeAlpha(); err != nil {
+				return
+			}
+		}
+		lnl = l.lnL()
+		if lnl-prevlnl < EST_LNL_EPS {
+			break
+		}
+		prevlnl = lnl
+	}
+	l.optimizeDistances()
+
+	params = GTRParams{
+		Rates: append([]float64(nil), l.rates...),
+		Pi:    append([]float64(nil), pi...),
+		LnL:   l.lnL(),
+	}
+	if ncat > 1 {
+		params.Alpha = l.alpha
+		params.NCat = ncat
+	}
+	dists = l.dists
+	return
+}
+
+// Jukes-Cantor distance used as starting distance of the pair
+func jcDistance(p PairCounts) float64 {
+	var diff, total float64
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			total += p[4*i+j]
+			if i != j {
+				diff += p[4*i+j]
+			}
+		}
+	}
+	if total == 0 {
+		return EST_BL_MIN
+	}
+	diff /= total
+	if diff >= 0.74 {
+		return 1.0
+	}
+	return math.Max(EST_BL_MIN, math.Min(EST_BL_MAX, -0.75*math.Log(1.0-4.0/3.0*diff)))
+}
+
+// Updates the eigen decomposition and gamma rates with the current parameters
+func (l *gtrLikelihood) update() (err error) {
+	var vals []float64
+	var left, right *mat.Dense
+
+	m := NewGTRModel()
+	if err = m.InitModel(l.rates[0], l.rates[1], l.rates[2], l.rates[3], l.rates[4], l.rates[5],
+		l.pi[0], l.pi[1], l.pi[2], l.pi[3]); err != nil {
+		return
+	}
+	if vals, left, right, err = m.Eigens(); err != nil {
+		return
+	}
+	l.vals = vals
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			for k := 0; k < 4; k++ {
+				l.coefs[i][j][k] = right.At(i, k) * left.At(k, j)
+			}
+		}
+	}
+	l.catrates = []float64{1.0}
+	if l.ncat > 1 {
+		l.catrates = models.DiscreteGamma(l.alpha, l.ncat)
+	}
+	return
+}
+
+// Log-likelihood of the pair p at distance t
+func (l *gtrLikelihood) pairLnL(p PairCounts, t float64) (lnl float64) {
+	var exps [4]float64
+	var pij [16]float64
+	var v float64
+
+	for _, r := range l.catrates {
+		for k := 0; k < 4; k++ {
+			exps[k] = math.Exp(l.vals[k] * t * r)
+		}
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 4; j++ {
+				v = 0
+				for k := 0; k < 4; k++ {
+					v += l.coefs[i][j][k] * exps[k]
+				}
+				pij[4*i+j] += v / float64(len(l.catrates))
+			}
+		}
+	}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			if p[4*i+j] == 0 {
+				continue
+			}
+			v = l.pi[i] * pij[4*i+j]
+			if v < DBL_MIN {
+				v = DBL_MIN
+			}
+			lnl += p[4*i+j] * math.Log(v)
+		}
+	}
+	return
+}
+
+// Pairwise composite log-likelihood with current distances and parameters
+func (l *gtrLikelihood) lnL() (lnl float64) {
+	for i, p := range l.pairs {
+		lnl += l.pairLnL(p, l.dists[i])
+	}
+	return
+}
+
+// Optimizes the distance of each pair with current parameters
+func (l *gtrLikelihood) optimizeDistances() {
+	for i, p := range l.pairs {
+		p := p
+		l.dists[i], _ = models.BrentMinimize(func(t float64) float64 {
+			return -l.pairLnL(p, t)
+		}, EST_BL_MIN, math.Max(EST_BL_MIN, math.Min(EST_BL_MAX, l.dists[i])), EST_BL_MAX, EST_TOL)
+	}
+}
+
+// Optimizes the given relative rate, on a log scale, with current distances
+func (l *gtrLikelihood) optimizeRate(r int) (err error) {
+	var x float64
+
+	x, _ = models.BrentMinimize(func(x float64) float64 {
+		l.rates[r] = math.Exp(x)
+		if err = l.update(); err != nil {
+			return math.Inf(1)
+		}
+		return -l.lnL()
+	}, math.Log(EST_RATE_MIN), math.Log(l.rates[r]), math.Log(EST_RATE_MAX), EST_TOL)
+	if err != nil {
+		return
+	}
+	l.rates[r] = math.Exp(x)
+	return l.update()
+}
+
+// Optimizes the gamma shape parameter, on a log scale, with current distances
+func (l *gtrLikelihood) optimizeAlpha() (err error) {
+	var x float64
+
+	x, _ = models.BrentMinimize(func(x float64) float64 {
+		l.alpha = math.Exp(x)
+		if err = l.update(); err != nil {
+			return math.Inf(1)
+		}
+		return -l.lnL()
+	}, math.Log(EST_ALPHA_MIN), math.Log(l.alpha), math.Log(EST_ALPHA_MAX), EST_TOL)
+	if err != nil {
+		return
+	}
+	l.alpha = math.Exp(x)
+	return l.update()
+}
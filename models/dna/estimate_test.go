@@ -0,0 +1,80 @@
+package dna
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/models"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Expected pair counts of nsites sites at distance t under the given GTR+G model
+func expectedPairCounts(rates, pi []float64, alpha float64, ncat int, t float64, nsites float64) (p PairCounts, err error) {
+	var catrates []float64 = []float64{1.0}
+	var pij *models.Pij
+	var m *GTRModel = NewGTRModel()
+	var counts *mat.Dense = mat.NewDense(4, 4, nil)
+
+	if err = m.InitModel(rates[0], rates[1], rates[2], rates[3], rates[4], rates[5], pi[0], pi[1], pi[2], pi[3]); err != nil {
+		return
+	}
+	if ncat > 1 {
+		catrates = models.DiscreteGamma(alpha, ncat)
+	}
+	for _, r := range catrates {
+		if pij, err = models.NewPij(m, t*r); err != nil {
+			return
+		}
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 4; j++ {
+				counts.Set(i, j, counts.At(i, j)+nsites*pi[i]*pij.Pij(i, j)/float64(len(catrates)))
+			}
+		}
+	}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			p[4*i+j] = counts.At(i, j)
+		}
+	}
+	return
+}
+
+func TestEstimateGTR(t *testing.T) {
+	rates := []float64{1.5, 4.0, 0.8, 1.2, 6.0, 1.0}
+	pi := []float64{0.3, 0.2, 0.2, 0.3}
+	alpha := 0.5
+	dists := []float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.8}
+
+	for _, ncat := range []int{1, 4} {
+		pairs := make([]PairCounts, len(dists))
+		for i, d := range dists {
+			var err error
+			if pairs[i], err = expectedPairCounts(rates, pi, alpha, ncat, d, 10000); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		params, estdists, err := EstimateGTR(pairs, pi, ncat)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for i, r := range rates {
+			if math.Abs(params.Rates[i]-r)/r > 0.05 {
+				t.Errorf("ncat=%d: rate %d should be %f, not %f", ncat, i, r, params.Rates[i])
+			}
+		}
+		for i, d := range dists {
+			if math.Abs(estdists[i]-d)/d > 0.05 {
+				t.Errorf("ncat=%d: distance %d should be %f, not %f", ncat, i, d, estdists[i])
+			}
+		}
+		if ncat > 1 && math.Abs(params.Alpha-alpha)/alpha > 0.1 {
+			t.Errorf("alpha should be %f, not %f", alpha, params.Alpha)
+		}
+		if ncat == 1 && (params.NCat != 0 || params.Alpha != 0) {
+			t.Errorf("There should be no gamma parameter")
+		}
+	}
+}
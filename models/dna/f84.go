@@ -1,6 +1,9 @@
 package dna
 
 import (
+	"fmt"
+	"math"
+
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -18,7 +21,11 @@ func NewF84Model() *F84Model {
 	}
 }
 
-func (m *F84Model) InitModel(kappa, piA, piC, piG, piT float64) {
+func (m *F84Model) InitModel(kappa, piA, piC, piG, piT float64) (err error) {
+	if kappa < 0 || math.IsNaN(kappa) || math.IsInf(kappa, 0) {
+		err = fmt.Errorf("wrong kappa parameter %v, it must be >= 0", kappa)
+		return
+	}
 	//m.qmatrix = mat.NewDense(4, 4, []float64{
 	//	-(piC + (1+kappa/piR)*piG + piT), piC, (1 + kappa/piR) * piG, piT,
 	//	piA, -(piA + piG + (1+kappa/piY)*piT), piG, (1 + kappa/piY) * piT,
@@ -31,6 +38,7 @@ func (m *F84Model) InitModel(kappa, piA, piC, piG, piT float64) {
 	m.piC = piC
 	m.piG = piG
 	m.piT = piT
+	return
 }
 
 // See http://biopp.univ-montp2.fr/Documents/ClassDocumentation/bpp-phyl/html/F84_8cpp_source.html
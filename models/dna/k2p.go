@@ -1,6 +1,7 @@
 package dna
 
 import (
+	"fmt"
 	"math"
 
 	"gonum.org/v1/gonum/mat"
@@ -21,8 +22,13 @@ func NewK2PModel() *K2PModel {
 
 // For Eigen values/vectors computation
 //
-func (m *K2PModel) InitModel(kappa float64) {
+func (m *K2PModel) InitModel(kappa float64) (err error) {
+	if kappa < 0 || math.IsNaN(kappa) || math.IsInf(kappa, 0) {
+		err = fmt.Errorf("wrong kappa parameter %v, it must be >= 0", kappa)
+		return
+	}
 	m.kappa = kappa
+	return
 }
 
 func (m *K2PModel) Eigens() (val []float64, leftvectors, rightvectors *mat.Dense, err error) {
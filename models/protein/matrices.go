@@ -1413,3 +1413,1894 @@ func HIVBMats() (dmat *mat.Dense, pi []float64) {
 	dmat = mat.NewDense(naa, naa, m)
 	return
 }
+
+/*********************************************************/
+
+// cpREV model data
+// Adachi J, Waddell PJ, Martin W, Hasegawa M.
+// Plastid genome phylogeny and a model of amino acid substitution
+// for proteins encoded by chloroplast DNA.
+// J Mol Evol. 2000;50:348-358.
+func CpREVMats() (dmat *mat.Dense, pi []float64) {
+	var i, j, naa int
+
+	naa = 20
+
+	m := make([]float64, naa*naa)
+	pi = make([]float64, naa)
+
+	m[1*20+0] = 105
+	m[2*20+0] = 227
+	m[2*20+1] = 357
+	m[3*20+0] = 175
+	m[3*20+1] = 43
+	m[3*20+2] = 4435
+	m[4*20+0] = 669
+	m[4*20+1] = 823
+	m[4*20+2] = 538
+	m[4*20+3] = 10
+	m[5*20+0] = 157
+	m[5*20+1] = 1745
+	m[5*20+2] = 768
+	m[5*20+3] = 400
+	m[5*20+4] = 10
+	m[6*20+0] = 499
+	m[6*20+1] = 152
+	m[6*20+2] = 1055
+	m[6*20+3] = 3691
+	m[6*20+4] = 10
+	m[6*20+5] = 3122
+	m[7*20+0] = 665
+	m[7*20+1] = 243
+	m[7*20+2] = 653
+	m[7*20+3] = 431
+	m[7*20+4] = 303
+	m[7*20+5] = 133
+	m[7*20+6] = 379
+	m[8*20+0] = 66
+	m[8*20+1] = 715
+	m[8*20+2] = 1405
+	m[8*20+3] = 331
+	m[8*20+4] = 441
+	m[8*20+5] = 1269
+	m[8*20+6] = 162
+	m[8*20+7] = 19
+	m[9*20+0] = 145
+	m[9*20+1] = 136
+	m[9*20+2] = 168
+	m[9*20+3] = 10
+	m[9*20+4] = 280
+	m[9*20+5] = 92
+	m[9*20+6] = 148
+	m[9*20+7] = 40
+	m[9*20+8] = 29
+	m[10*20+0] = 197
+	m[10*20+1] = 203
+	m[10*20+2] = 113
+	m[10*20+3] = 10
+	m[10*20+4] = 396
+	m[10*20+5] = 286
+	m[10*20+6] = 82
+	m[10*20+7] = 20
+	m[10*20+8] = 66
+	m[10*20+9] = 1745
+	m[11*20+0] = 236
+	m[11*20+1] = 4482
+	m[11*20+2] = 2430
+	m[11*20+3] = 412
+	m[11*20+4] = 48
+	m[11*20+5] = 3313
+	m[11*20+6] = 2629
+	m[11*20+7] = 263
+	m[11*20+8] = 305
+	m[11*20+9] = 345
+	m[11*20+10] = 218
+	m[12*20+0] = 185
+	m[12*20+1] = 125
+	m[12*20+2] = 61
+	m[12*20+3] = 47
+	m[12*20+4] = 159
+	m[12*20+5] = 202
+	m[12*20+6] = 113
+	m[12*20+7] = 21
+	m[12*20+8] = 10
+	m[12*20+9] = 1772
+	m[12*20+10] = 1351
+	m[12*20+11] = 193
+	m[13*20+0] = 68
+	m[13*20+1] = 53
+	m[13*20+2] = 97
+	m[13*20+3] = 22
+	m[13*20+4] = 726
+	m[13*20+5] = 10
+	m[13*20+6] = 145
+	m[13*20+7] = 25
+	m[13*20+8] = 127
+	m[13*20+9] = 454
+	m[13*20+10] = 1268
+	m[13*20+11] = 72
+	m[13*20+12] = 327
+	m[14*20+0] = 490
+	m[14*20+1] = 87
+	m[14*20+2] = 173
+	m[14*20+3] = 170
+	m[14*20+4] = 285
+	m[14*20+5] = 323
+	m[14*20+6] = 185
+	m[14*20+7] = 28
+	m[14*20+8] = 152
+	m[14*20+9] = 117
+	m[14*20+10] = 219
+	m[14*20+11] = 302
+	m[14*20+12] = 100
+	m[14*20+13] = 43
+	m[15*20+0] = 2440
+	m[15*20+1] = 385
+	m[15*20+2] = 2085
+	m[15*20+3] = 590
+	m[15*20+4] = 2331
+	m[15*20+5] = 396
+	m[15*20+6] = 568
+	m[15*20+7] = 691
+	m[15*20+8] = 303
+	m[15*20+9] = 216
+	m[15*20+10] = 516
+	m[15*20+11] = 868
+	m[15*20+12] = 93
+	m[15*20+13] = 487
+	m[15*20+14] = 1202
+	m[16*20+0] = 1340
+	m[16*20+1] = 314
+	m[16*20+2] = 1393
+	m[16*20+3] = 266
+	m[16*20+4] = 576
+	m[16*20+5] = 241
+	m[16*20+6] = 369
+	m[16*20+7] = 92
+	m[16*20+8] = 32
+	m[16*20+9] = 1040
+	m[16*20+10] = 156
+	m[16*20+11] = 918
+	m[16*20+12] = 645
+	m[16*20+13] = 148
+	m[16*20+14] = 260
+	m[16*20+15] = 2151
+	m[17*20+0] = 14
+	m[17*20+1] = 230
+	m[17*20+2] = 40
+	m[17*20+3] = 18
+	m[17*20+4] = 435
+	m[17*20+5] = 53
+	m[17*20+6] = 63
+	m[17*20+7] = 82
+	m[17*20+8] = 69
+	m[17*20+9] = 42
+	m[17*20+10] = 159
+	m[17*20+11] = 10
+	m[17*20+12] = 86
+	m[17*20+13] = 468
+	m[17*20+14] = 49
+	m[17*20+15] = 73
+	m[17*20+16] = 29
+	m[18*20+0] = 56
+	m[18*20+1] = 323
+	m[18*20+2] = 754
+	m[18*20+3] = 281
+	m[18*20+4] = 1466
+	m[18*20+5] = 391
+	m[18*20+6] = 142
+	m[18*20+7] = 10
+	m[18*20+8] = 1971
+	m[18*20+9] = 89
+	m[18*20+10] = 189
+	m[18*20+11] = 247
+	m[18*20+12] = 215
+	m[18*20+13] = 2370
+	m[18*20+14] = 97
+	m[18*20+15] = 522
+	m[18*20+16] = 71
+	m[18*20+17] = 346
+	m[19*20+0] = 968
+	m[19*20+1] = 92
+	m[19*20+2] = 83
+	m[19*20+3] = 75
+	m[19*20+4] = 592
+	m[19*20+5] = 54
+	m[19*20+6] = 200
+	m[19*20+7] = 91
+	m[19*20+8] = 25
+	m[19*20+9] = 4797
+	m[19*20+10] = 865
+	m[19*20+11] = 249
+	m[19*20+12] = 475
+	m[19*20+13] = 317
+	m[19*20+14] = 122
+	m[19*20+15] = 167
+	m[19*20+16] = 760
+	m[19*20+17] = 10
+	m[19*20+18] = 119
+
+	for i = 0; i < naa; i++ {
+		for j = 0; j < i; j++ {
+			m[j*naa+i] = m[i*naa+j]
+		}
+	}
+
+	pi[0] = 0.076
+	pi[1] = 0.062
+	pi[2] = 0.041
+	pi[3] = 0.037
+	pi[4] = 0.009
+	pi[5] = 0.038
+	pi[6] = 0.049
+	pi[7] = 0.084
+	pi[8] = 0.025
+	pi[9] = 0.081
+	pi[10] = 0.101
+	pi[11] = 0.050
+	pi[12] = 0.022
+	pi[13] = 0.051
+	pi[14] = 0.043
+	pi[15] = 0.062
+	pi[16] = 0.054
+	pi[17] = 0.018
+	pi[18] = 0.031
+	pi[19] = 0.066
+
+	dmat = mat.NewDense(naa, naa, m)
+	return
+}
+
+/*********************************************************/
+
+// rtREV model data
+// Dimmic MW, Rest JS, Mindell DP, Goldstein RA.
+// rtREV: an amino acid substitution matrix for inference of retrovirus
+// and reverse transcriptase phylogeny.
+// J Mol Evol. 2002;55:65-73.
+func RtREVMats() (dmat *mat.Dense, pi []float64) {
+	var i, j, naa int
+
+	naa = 20
+
+	m := make([]float64, naa*naa)
+	pi = make([]float64, naa)
+
+	m[1*20+0] = 34
+	m[2*20+0] = 51
+	m[2*20+1] = 35
+	m[3*20+0] = 10
+	m[3*20+1] = 30
+	m[3*20+2] = 384
+	m[4*20+0] = 439
+	m[4*20+1] = 92
+	m[4*20+2] = 128
+	m[4*20+3] = 1
+	m[5*20+0] = 32
+	m[5*20+1] = 221
+	m[5*20+2] = 236
+	m[5*20+3] = 78
+	m[5*20+4] = 70
+	m[6*20+0] = 81
+	m[6*20+1] = 10
+	m[6*20+2] = 79
+	m[6*20+3] = 542
+	m[6*20+4] = 1
+	m[6*20+5] = 372
+	m[7*20+0] = 135
+	m[7*20+1] = 41
+	m[7*20+2] = 94
+	m[7*20+3] = 61
+	m[7*20+4] = 48
+	m[7*20+5] = 18
+	m[7*20+6] = 70
+	m[8*20+0] = 30
+	m[8*20+1] = 90
+	m[8*20+2] = 320
+	m[8*20+3] = 91
+	m[8*20+4] = 124
+	m[8*20+5] = 387
+	m[8*20+6] = 34
+	m[8*20+7] = 68
+	m[9*20+0] = 1
+	m[9*20+1] = 24
+	m[9*20+2] = 35
+	m[9*20+3] = 1
+	m[9*20+4] = 104
+	m[9*20+5] = 33
+	m[9*20+6] = 1
+	m[9*20+7] = 1
+	m[9*20+8] = 34
+	m[10*20+0] = 45
+	m[10*20+1] = 18
+	m[10*20+2] = 15
+	m[10*20+3] = 5
+	m[10*20+4] = 110
+	m[10*20+5] = 54
+	m[10*20+6] = 21
+	m[10*20+7] = 3
+	m[10*20+8] = 51
+	m[10*20+9] = 385
+	m[11*20+0] = 38
+	m[11*20+1] = 593
+	m[11*20+2] = 123
+	m[11*20+3] = 20
+	m[11*20+4] = 16
+	m[11*20+5] = 309
+	m[11*20+6] = 141
+	m[11*20+7] = 30
+	m[11*20+8] = 76
+	m[11*20+9] = 34
+	m[11*20+10] = 23
+	m[12*20+0] = 235
+	m[12*20+1] = 57
+	m[12*20+2] = 1
+	m[12*20+3] = 1
+	m[12*20+4] = 156
+	m[12*20+5] = 158
+	m[12*20+6] = 1
+	m[12*20+7] = 37
+	m[12*20+8] = 116
+	m[12*20+9] = 375
+	m[12*20+10] = 581
+	m[12*20+11] = 134
+	m[13*20+0] = 1
+	m[13*20+1] = 7
+	m[13*20+2] = 49
+	m[13*20+3] = 1
+	m[13*20+4] = 70
+	m[13*20+5] = 1
+	m[13*20+6] = 1
+	m[13*20+7] = 7
+	m[13*20+8] = 141
+	m[13*20+9] = 64
+	m[13*20+10] = 179
+	m[13*20+11] = 14
+	m[13*20+12] = 247
+	m[14*20+0] = 97
+	m[14*20+1] = 24
+	m[14*20+2] = 33
+	m[14*20+3] = 55
+	m[14*20+4] = 1
+	m[14*20+5] = 68
+	m[14*20+6] = 52
+	m[14*20+7] = 17
+	m[14*20+8] = 44
+	m[14*20+9] = 10
+	m[14*20+10] = 22
+	m[14*20+11] = 43
+	m[14*20+12] = 1
+	m[14*20+13] = 11
+	m[15*20+0] = 460
+	m[15*20+1] = 102
+	m[15*20+2] = 294
+	m[15*20+3] = 136
+	m[15*20+4] = 75
+	m[15*20+5] = 225
+	m[15*20+6] = 95
+	m[15*20+7] = 152
+	m[15*20+8] = 183
+	m[15*20+9] = 4
+	m[15*20+10] = 24
+	m[15*20+11] = 77
+	m[15*20+12] = 1
+	m[15*20+13] = 20
+	m[15*20+14] = 134
+	m[16*20+0] = 258
+	m[16*20+1] = 64
+	m[16*20+2] = 148
+	m[16*20+3] = 55
+	m[16*20+4] = 117
+	m[16*20+5] = 146
+	m[16*20+6] = 82
+	m[16*20+7] = 7
+	m[16*20+8] = 49
+	m[16*20+9] = 72
+	m[16*20+10] = 25
+	m[16*20+11] = 110
+	m[16*20+12] = 131
+	m[16*20+13] = 69
+	m[16*20+14] = 62
+	m[16*20+15] = 671
+	m[17*20+0] = 5
+	m[17*20+1] = 13
+	m[17*20+2] = 16
+	m[17*20+3] = 1
+	m[17*20+4] = 55
+	m[17*20+5] = 10
+	m[17*20+6] = 17
+	m[17*20+7] = 23
+	m[17*20+8] = 48
+	m[17*20+9] = 39
+	m[17*20+10] = 47
+	m[17*20+11] = 6
+	m[17*20+12] = 111
+	m[17*20+13] = 182
+	m[17*20+14] = 9
+	m[17*20+15] = 14
+	m[17*20+16] = 1
+	m[18*20+0] = 55
+	m[18*20+1] = 47
+	m[18*20+2] = 28
+	m[18*20+3] = 1
+	m[18*20+4] = 131
+	m[18*20+5] = 45
+	m[18*20+6] = 1
+	m[18*20+7] = 21
+	m[18*20+8] = 307
+	m[18*20+9] = 26
+	m[18*20+10] = 64
+	m[18*20+11] = 1
+	m[18*20+12] = 74
+	m[18*20+13] = 1017
+	m[18*20+14] = 14
+	m[18*20+15] = 31
+	m[18*20+16] = 34
+	m[18*20+17] = 176
+	m[19*20+0] = 197
+	m[19*20+1] = 29
+	m[19*20+2] = 21
+	m[19*20+3] = 6
+	m[19*20+4] = 295
+	m[19*20+5] = 36
+	m[19*20+6] = 35
+	m[19*20+7] = 3
+	m[19*20+8] = 1
+	m[19*20+9] = 1048
+	m[19*20+10] = 112
+	m[19*20+11] = 19
+	m[19*20+12] = 236
+	m[19*20+13] = 92
+	m[19*20+14] = 25
+	m[19*20+15] = 39
+	m[19*20+16] = 196
+	m[19*20+17] = 26
+	m[19*20+18] = 59
+
+	for i = 0; i < naa; i++ {
+		for j = 0; j < i; j++ {
+			m[j*naa+i] = m[i*naa+j]
+		}
+	}
+
+	pi[0] = 0.0646
+	pi[1] = 0.0453
+	pi[2] = 0.0376
+	pi[3] = 0.0422
+	pi[4] = 0.0114
+	pi[5] = 0.0606
+	pi[6] = 0.0607
+	pi[7] = 0.0639
+	pi[8] = 0.0273
+	pi[9] = 0.0679
+	pi[10] = 0.1018
+	pi[11] = 0.0751
+	pi[12] = 0.0150
+	pi[13] = 0.0287
+	pi[14] = 0.0681
+	pi[15] = 0.0488
+	pi[16] = 0.0622
+	pi[17] = 0.0251
+	pi[18] = 0.0318
+	pi[19] = 0.0619
+
+	dmat = mat.NewDense(naa, naa, m)
+	return
+}
+
+/*********************************************************/
+
+// VT model data
+// Muller T, Vingron M.
+// Modeling amino acid replacement.
+// J Comput Biol. 2000;7:761-776.
+func VTMats() (dmat *mat.Dense, pi []float64) {
+	var i, j, naa int
+
+	naa = 20
+
+	m := make([]float64, naa*naa)
+	pi = make([]float64, naa)
+
+	m[1*20+0] = 0.233108
+	m[2*20+0] = 0.199097
+	m[2*20+1] = 0.210797
+	m[3*20+0] = 0.265145
+	m[3*20+1] = 0.105191
+	m[3*20+2] = 0.883422
+	m[4*20+0] = 0.227333
+	m[4*20+1] = 0.031726
+	m[4*20+2] = 0.027495
+	m[4*20+3] = 0.010313
+	m[5*20+0] = 0.310084
+	m[5*20+1] = 0.493763
+	m[5*20+2] = 0.2757
+	m[5*20+3] = 0.205842
+	m[5*20+4] = 0.004315
+	m[6*20+0] = 0.567957
+	m[6*20+1] = 0.25524
+	m[6*20+2] = 0.270417
+	m[6*20+3] = 1.599461
+	m[6*20+4] = 0.005321
+	m[6*20+5] = 0.960976
+	m[7*20+0] = 0.876213
+	m[7*20+1] = 0.156945
+	m[7*20+2] = 0.362028
+	m[7*20+3] = 0.311718
+	m[7*20+4] = 0.050876
+	m[7*20+5] = 0.12866
+	m[7*20+6] = 0.250447
+	m[8*20+0] = 0.078692
+	m[8*20+1] = 0.213164
+	m[8*20+2] = 0.290006
+	m[8*20+3] = 0.134252
+	m[8*20+4] = 0.016695
+	m[8*20+5] = 0.315521
+	m[8*20+6] = 0.104458
+	m[8*20+7] = 0.058131
+	m[9*20+0] = 0.222972
+	m[9*20+1] = 0.08151
+	m[9*20+2] = 0.087225
+	m[9*20+3] = 0.01172
+	m[9*20+4] = 0.046398
+	m[9*20+5] = 0.054602
+	m[9*20+6] = 0.046589
+	m[9*20+7] = 0.051089
+	m[9*20+8] = 0.020039
+	m[10*20+0] = 0.42463
+	m[10*20+1] = 0.192364
+	m[10*20+2] = 0.069245
+	m[10*20+3] = 0.060863
+	m[10*20+4] = 0.091709
+	m[10*20+5] = 0.24353
+	m[10*20+6] = 0.151924
+	m[10*20+7] = 0.087056
+	m[10*20+8] = 0.103552
+	m[10*20+9] = 2.08989
+	m[11*20+0] = 0.393245
+	m[11*20+1] = 1.755838
+	m[11*20+2] = 0.50306
+	m[11*20+3] = 0.261101
+	m[11*20+4] = 0.004067
+	m[11*20+5] = 0.738208
+	m[11*20+6] = 0.88863
+	m[11*20+7] = 0.193243
+	m[11*20+8] = 0.153323
+	m[11*20+9] = 0.093181
+	m[11*20+10] = 0.201204
+	m[12*20+0] = 0.21155
+	m[12*20+1] = 0.08793
+	m[12*20+2] = 0.05742
+	m[12*20+3] = 0.012182
+	m[12*20+4] = 0.02369
+	m[12*20+5] = 0.120801
+	m[12*20+6] = 0.058643
+	m[12*20+7] = 0.04656
+	m[12*20+8] = 0.021157
+	m[12*20+9] = 0.493845
+	m[12*20+10] = 1.105667
+	m[12*20+11] = 0.096474
+	m[13*20+0] = 0.116646
+	m[13*20+1] = 0.042569
+	m[13*20+2] = 0.039769
+	m[13*20+3] = 0.016577
+	m[13*20+4] = 0.051127
+	m[13*20+5] = 0.026235
+	m[13*20+6] = 0.028168
+	m[13*20+7] = 0.050143
+	m[13*20+8] = 0.079807
+	m[13*20+9] = 0.32102
+	m[13*20+10] = 0.946499
+	m[13*20+11] = 0.038261
+	m[13*20+12] = 0.173052
+	m[14*20+0] = 0.399143
+	m[14*20+1] = 0.12848
+	m[14*20+2] = 0.083956
+	m[14*20+3] = 0.160063
+	m[14*20+4] = 0.011137
+	m[14*20+5] = 0.15657
+	m[14*20+6] = 0.205134
+	m[14*20+7] = 0.124492
+	m[14*20+8] = 0.078892
+	m[14*20+9] = 0.054797
+	m[14*20+10] = 0.169784
+	m[14*20+11] = 0.212302
+	m[14*20+12] = 0.010363
+	m[14*20+13] = 0.042564
+	m[15*20+0] = 1.817198
+	m[15*20+1] = 0.292327
+	m[15*20+2] = 0.847049
+	m[15*20+3] = 0.461519
+	m[15*20+4] = 0.17527
+	m[15*20+5] = 0.358017
+	m[15*20+6] = 0.406035
+	m[15*20+7] = 0.612843
+	m[15*20+8] = 0.167406
+	m[15*20+9] = 0.081567
+	m[15*20+10] = 0.214977
+	m[15*20+11] = 0.400072
+	m[15*20+12] = 0.090515
+	m[15*20+13] = 0.138119
+	m[15*20+14] = 0.430431
+	m[16*20+0] = 0.877877
+	m[16*20+1] = 0.204109
+	m[16*20+2] = 0.471268
+	m[16*20+3] = 0.178197
+	m[16*20+4] = 0.079511
+	m[16*20+5] = 0.248992
+	m[16*20+6] = 0.321028
+	m[16*20+7] = 0.136266
+	m[16*20+8] = 0.101117
+	m[16*20+9] = 0.376588
+	m[16*20+10] = 0.243227
+	m[16*20+11] = 0.446646
+	m[16*20+12] = 0.184609
+	m[16*20+13] = 0.08587
+	m[16*20+14] = 0.207143
+	m[16*20+15] = 1.767766
+	m[17*20+0] = 0.030309
+	m[17*20+1] = 0.046417
+	m[17*20+2] = 0.010459
+	m[17*20+3] = 0.011393
+	m[17*20+4] = 0.007732
+	m[17*20+5] = 0.021248
+	m[17*20+6] = 0.018844
+	m[17*20+7] = 0.02399
+	m[17*20+8] = 0.020009
+	m[17*20+9] = 0.034954
+	m[17*20+10] = 0.083439
+	m[17*20+11] = 0.023321
+	m[17*20+12] = 0.022019
+	m[17*20+13] = 0.12805
+	m[17*20+14] = 0.014584
+	m[17*20+15] = 0.035933
+	m[17*20+16] = 0.020437
+	m[18*20+0] = 0.087061
+	m[18*20+1] = 0.09701
+	m[18*20+2] = 0.093268
+	m[18*20+3] = 0.051664
+	m[18*20+4] = 0.042823
+	m[18*20+5] = 0.062544
+	m[18*20+6] = 0.0552
+	m[18*20+7] = 0.037568
+	m[18*20+8] = 0.286027
+	m[18*20+9] = 0.086237
+	m[18*20+10] = 0.189842
+	m[18*20+11] = 0.068689
+	m[18*20+12] = 0.073223
+	m[18*20+13] = 0.898663
+	m[18*20+14] = 0.032043
+	m[18*20+15] = 0.121979
+	m[18*20+16] = 0.094617
+	m[18*20+17] = 0.124746
+	m[19*20+0] = 1.230985
+	m[19*20+1] = 0.113146
+	m[19*20+2] = 0.049824
+	m[19*20+3] = 0.048769
+	m[19*20+4] = 0.163831
+	m[19*20+5] = 0.112027
+	m[19*20+6] = 0.205868
+	m[19*20+7] = 0.082579
+	m[19*20+8] = 0.068575
+	m[19*20+9] = 3.65443
+	m[19*20+10] = 1.337571
+	m[19*20+11] = 0.144587
+	m[19*20+12] = 0.307309
+	m[19*20+13] = 0.247329
+	m[19*20+14] = 0.129315
+	m[19*20+15] = 0.1277
+	m[19*20+16] = 0.740372
+	m[19*20+17] = 0.022134
+	m[19*20+18] = 0.131528
+
+	for i = 0; i < naa; i++ {
+		for j = 0; j < i; j++ {
+			m[j*naa+i] = m[i*naa+j]
+		}
+	}
+
+	pi[0] = 0.078837
+	pi[1] = 0.051238
+	pi[2] = 0.042313
+	pi[3] = 0.053066
+	pi[4] = 0.015175
+	pi[5] = 0.036713
+	pi[6] = 0.061924
+	pi[7] = 0.070852
+	pi[8] = 0.023082
+	pi[9] = 0.062056
+	pi[10] = 0.096371
+	pi[11] = 0.057324
+	pi[12] = 0.023771
+	pi[13] = 0.043296
+	pi[14] = 0.043911
+	pi[15] = 0.063403
+	pi[16] = 0.055897
+	pi[17] = 0.013272
+	pi[18] = 0.034399
+	pi[19] = 0.073101
+
+	dmat = mat.NewDense(naa, naa, m)
+	return
+}
+
+/*********************************************************/
+
+// Blosum62 model data
+// Henikoff S, Henikoff JG.
+// Amino acid substitution matrices from protein blocks.
+// PNAS. 1992;89:10915-10919.
+func Blosum62Mats() (dmat *mat.Dense, pi []float64) {
+	var i, j, naa int
+
+	naa = 20
+
+	m := make([]float64, naa*naa)
+	pi = make([]float64, naa)
+
+	m[1*20+0] = 0.735790389698
+	m[2*20+0] = 0.485391055466
+	m[2*20+1] = 1.297446705134
+	m[3*20+0] = 0.543161820899
+	m[3*20+1] = 0.500964408555
+	m[3*20+2] = 3.180100048216
+	m[4*20+0] = 1.459995310470
+	m[4*20+1] = 0.227826574209
+	m[4*20+2] = 0.397358949897
+	m[4*20+3] = 0.240836614802
+	m[5*20+0] = 1.199705704602
+	m[5*20+1] = 3.020833610064
+	m[5*20+2] = 1.839216146992
+	m[5*20+3] = 1.190945703396
+	m[5*20+4] = 0.329801504630
+	m[6*20+0] = 1.170949042800
+	m[6*20+1] = 1.360574190420
+	m[6*20+2] = 1.240488508640
+	m[6*20+3] = 3.761625208368
+	m[6*20+4] = 0.140748891814
+	m[6*20+5] = 5.528919177928
+	m[7*20+0] = 1.955883574960
+	m[7*20+1] = 0.418763308518
+	m[7*20+2] = 1.355872344485
+	m[7*20+3] = 0.798473248968
+	m[7*20+4] = 0.418203192284
+	m[7*20+5] = 0.609846305383
+	m[7*20+6] = 0.423579992176
+	m[8*20+0] = 0.716241444998
+	m[8*20+1] = 1.456141166336
+	m[8*20+2] = 2.414501434208
+	m[8*20+3] = 0.778142664022
+	m[8*20+4] = 0.354058109831
+	m[8*20+5] = 2.435341131140
+	m[8*20+6] = 1.626891056982
+	m[8*20+7] = 0.539859124954
+	m[9*20+0] = 0.605899003687
+	m[9*20+1] = 0.232036445142
+	m[9*20+2] = 0.283017326278
+	m[9*20+3] = 0.418555732462
+	m[9*20+4] = 0.774894022794
+	m[9*20+5] = 0.236202451204
+	m[9*20+6] = 0.186848046932
+	m[9*20+7] = 0.189296292376
+	m[9*20+8] = 0.252718447885
+	m[10*20+0] = 0.800016530518
+	m[10*20+1] = 0.622711669692
+	m[10*20+2] = 0.211888159615
+	m[10*20+3] = 0.218131577594
+	m[10*20+4] = 0.831842640142
+	m[10*20+5] = 0.580737093181
+	m[10*20+6] = 0.372625175087
+	m[10*20+7] = 0.217721159236
+	m[10*20+8] = 0.348072209797
+	m[10*20+9] = 3.890963773304
+	m[11*20+0] = 1.295201266783
+	m[11*20+1] = 5.411115141489
+	m[11*20+2] = 1.593137043457
+	m[11*20+3] = 1.032447924952
+	m[11*20+4] = 0.285078800906
+	m[11*20+5] = 3.945277674515
+	m[11*20+6] = 2.802427151679
+	m[11*20+7] = 0.752042440303
+	m[11*20+8] = 1.022507035889
+	m[11*20+9] = 0.406193586642
+	m[11*20+10] = 0.445570274261
+	m[12*20+0] = 1.253758266664
+	m[12*20+1] = 0.983692987457
+	m[12*20+2] = 0.648441278787
+	m[12*20+3] = 0.222621897958
+	m[12*20+4] = 0.767688823480
+	m[12*20+5] = 2.494896077113
+	m[12*20+6] = 0.555415397470
+	m[12*20+7] = 0.459436173579
+	m[12*20+8] = 0.984311525359
+	m[12*20+9] = 3.364797763104
+	m[12*20+10] = 6.030559379572
+	m[12*20+11] = 1.073061184332
+	m[13*20+0] = 0.492964679748
+	m[13*20+1] = 0.371644693209
+	m[13*20+2] = 0.354861249223
+	m[13*20+3] = 0.281730694207
+	m[13*20+4] = 0.441337471187
+	m[13*20+5] = 0.144356959750
+	m[13*20+6] = 0.291409084165
+	m[13*20+7] = 0.368166464453
+	m[13*20+8] = 0.714533703928
+	m[13*20+9] = 1.517359325954
+	m[13*20+10] = 2.064839703237
+	m[13*20+11] = 0.266924750511
+	m[13*20+12] = 1.773855168830
+	m[14*20+0] = 1.173275900924
+	m[14*20+1] = 0.448133661718
+	m[14*20+2] = 0.494887043702
+	m[14*20+3] = 0.730628272998
+	m[14*20+4] = 0.356008498769
+	m[14*20+5] = 0.858570575674
+	m[14*20+6] = 0.926563934846
+	m[14*20+7] = 0.504086599527
+	m[14*20+8] = 0.527007339151
+	m[14*20+9] = 0.388355409206
+	m[14*20+10] = 0.374555687471
+	m[14*20+11] = 1.047383450722
+	m[14*20+12] = 0.454123625103
+	m[14*20+13] = 0.233597909629
+	m[15*20+0] = 4.325092687057
+	m[15*20+1] = 1.122783104210
+	m[15*20+2] = 2.904101656456
+	m[15*20+3] = 1.582754142065
+	m[15*20+4] = 1.197188415094
+	m[15*20+5] = 1.934870924596
+	m[15*20+6] = 1.769893238937
+	m[15*20+7] = 1.509326253224
+	m[15*20+8] = 1.117029762910
+	m[15*20+9] = 0.357544412460
+	m[15*20+10] = 0.352969184527
+	m[15*20+11] = 1.752165917819
+	m[15*20+12] = 0.918723415746
+	m[15*20+13] = 0.540027644824
+	m[15*20+14] = 1.169129577716
+	m[16*20+0] = 1.729178019485
+	m[16*20+1] = 0.914665954563
+	m[16*20+2] = 1.898173634533
+	m[16*20+3] = 0.934187509431
+	m[16*20+4] = 1.119831358516
+	m[16*20+5] = 1.277480294596
+	m[16*20+6] = 1.071097236007
+	m[16*20+7] = 0.641436011405
+	m[16*20+8] = 0.585407090225
+	m[16*20+9] = 1.179091197260
+	m[16*20+10] = 0.915259857694
+	m[16*20+11] = 1.303875200799
+	m[16*20+12] = 1.488548053722
+	m[16*20+13] = 0.488206118793
+	m[16*20+14] = 1.005451683149
+	m[16*20+15] = 5.151556292270
+	m[17*20+0] = 0.465839367725
+	m[17*20+1] = 0.426382310122
+	m[17*20+2] = 0.191482046247
+	m[17*20+3] = 0.145345046279
+	m[17*20+4] = 0.527664418872
+	m[17*20+5] = 0.758653808642
+	m[17*20+6] = 0.407635648938
+	m[17*20+7] = 0.508358924638
+	m[17*20+8] = 0.301248600780
+	m[17*20+9] = 0.341985787540
+	m[17*20+10] = 0.691474634600
+	m[17*20+11] = 0.332243040634
+	m[17*20+12] = 0.888101098152
+	m[17*20+13] = 2.074324893497
+	m[17*20+14] = 0.252214830027
+	m[17*20+15] = 0.387925622098
+	m[17*20+16] = 0.513128126891
+	m[18*20+0] = 0.718206697586
+	m[18*20+1] = 0.720517441216
+	m[18*20+2] = 0.538222519037
+	m[18*20+3] = 0.261422208965
+	m[18*20+4] = 0.470237733696
+	m[18*20+5] = 0.958989742850
+	m[18*20+6] = 0.596719300346
+	m[18*20+7] = 0.308055737035
+	m[18*20+8] = 4.218953969389
+	m[18*20+9] = 0.674617093228
+	m[18*20+10] = 0.811245856323
+	m[18*20+11] = 0.717993486900
+	m[18*20+12] = 0.951682162246
+	m[18*20+13] = 6.747260430801
+	m[18*20+14] = 0.369405319355
+	m[18*20+15] = 0.796751520761
+	m[18*20+16] = 0.801010243199
+	m[18*20+17] = 4.054419006558
+	m[19*20+0] = 2.187774522005
+	m[19*20+1] = 0.438388343772
+	m[19*20+2] = 0.312858797993
+	m[19*20+3] = 0.258129289418
+	m[19*20+4] = 1.116352478606
+	m[19*20+5] = 0.530785790125
+	m[19*20+6] = 0.524253846338
+	m[19*20+7] = 0.253340790190
+	m[19*20+8] = 0.201555971750
+	m[19*20+9] = 8.311839405458
+	m[19*20+10] = 2.231405688913
+	m[19*20+11] = 0.498138475304
+	m[19*20+12] = 2.575850755315
+	m[19*20+13] = 0.838119610178
+	m[19*20+14] = 0.496908410676
+	m[19*20+15] = 0.561925457442
+	m[19*20+16] = 2.253074051176
+	m[19*20+17] = 0.266508731426
+	m[19*20+18] = 1.000000000000
+
+	for i = 0; i < naa; i++ {
+		for j = 0; j < i; j++ {
+			m[j*naa+i] = m[i*naa+j]
+		}
+	}
+
+	pi[0] = 0.074
+	pi[1] = 0.052
+	pi[2] = 0.045
+	pi[3] = 0.054
+	pi[4] = 0.025
+	pi[5] = 0.034
+	pi[6] = 0.054
+	pi[7] = 0.074
+	pi[8] = 0.026
+	pi[9] = 0.068
+	pi[10] = 0.099
+	pi[11] = 0.058
+	pi[12] = 0.025
+	pi[13] = 0.047
+	pi[14] = 0.039
+	pi[15] = 0.057
+	pi[16] = 0.051
+	pi[17] = 0.013
+	pi[18] = 0.032
+	pi[19] = 0.073
+
+	dmat = mat.NewDense(naa, naa, m)
+	return
+}
+
+/*********************************************************/
+
+// FLU model data
+// Dang CC, Le QS, Gascuel O, Le VS.
+// FLU, an amino acid substitution model for influenza proteins.
+// BMC Evol Biol. 2010;10:99.
+func FLUMats() (dmat *mat.Dense, pi []float64) {
+	var i, j, naa int
+
+	naa = 20
+
+	m := make([]float64, naa*naa)
+	pi = make([]float64, naa)
+
+	m[1*20+0] = 0.138658765
+	m[2*20+0] = 0.053366579
+	m[2*20+1] = 0.161000889
+	m[3*20+0] = 0.584852306
+	m[3*20+1] = 0.006771843
+	m[3*20+2] = 7.737392871
+	m[4*20+0] = 0.026447095
+	m[4*20+1] = 0.167207008
+	m[4*20+2] = 1.30e-05
+	m[4*20+3] = 1.41e-02
+	m[5*20+0] = 0.353753982
+	m[5*20+1] = 3.292716942
+	m[5*20+2] = 0.530642655
+	m[5*20+3] = 0.145469388
+	m[5*20+4] = 0.002547334
+	m[6*20+0] = 1.484234503
+	m[6*20+1] = 0.124897617
+	m[6*20+2] = 0.061652192
+	m[6*20+3] = 5.370511279
+	m[6*20+4] = 3.91e-11
+	m[6*20+5] = 1.195629122
+	m[7*20+0] = 1.132313122
+	m[7*20+1] = 1.190624465
+	m[7*20+2] = 0.322524648
+	m[7*20+3] = 1.934832784
+	m[7*20+4] = 0.116941459
+	m[7*20+5] = 0.108051341
+	m[7*20+6] = 1.593098825
+	m[8*20+0] = 0.214757862
+	m[8*20+1] = 1.879569938
+	m[8*20+2] = 1.387096032
+	m[8*20+3] = 0.887570549
+	m[8*20+4] = 2.18e-02
+	m[8*20+5] = 5.330313412
+	m[8*20+6] = 0.256491863
+	m[8*20+7] = 0.058774527
+	m[9*20+0] = 0.149926734
+	m[9*20+1] = 0.246117172
+	m[9*20+2] = 0.218571975
+	m[9*20+3] = 0.014085917
+	m[9*20+4] = 0.001112158
+	m[9*20+5] = 0.02883995
+	m[9*20+6] = 0.014969213
+	m[9*20+7] = 1.00e-12
+	m[9*20+8] = 0.070213083
+	m[10*20+0] = 0.395952558
+	m[10*20+1] = 0.307087081
+	m[10*20+2] = 0.081869262
+	m[10*20+3] = 0.017212838
+	m[10*20+4] = 0.052017013
+	m[10*20+5] = 0.255366591
+	m[10*20+6] = 0.014785815
+	m[10*20+7] = 0.009738767
+	m[10*20+8] = 0.142681096
+	m[10*20+9] = 3.226932393
+	m[11*20+0] = 0.216521012
+	m[11*20+1] = 2.736668779
+	m[11*20+2] = 3.245640508
+	m[11*20+3] = 0.119519301
+	m[11*20+4] = 4.30e-07
+	m[11*20+5] = 2.147082919
+	m[11*20+6] = 1.131707018
+	m[11*20+7] = 0.205962929
+	m[11*20+8] = 0.233154262
+	m[11*20+9] = 0.150458009
+	m[11*20+10] = 0.144716733
+	m[12*20+0] = 0.589727008
+	m[12*20+1] = 0.125810003
+	m[12*20+2] = 0.066101574
+	m[12*20+3] = 0.008066447
+	m[12*20+4] = 0.091463498
+	m[12*20+5] = 0.077929007
+	m[12*20+6] = 0.105149212
+	m[12*20+7] = 0.014938919
+	m[12*20+8] = 0.05625335
+	m[12*20+9] = 2.337221624
+	m[12*20+10] = 5.545547564
+	m[12*20+11] = 0.291022613
+	m[13*20+0] = 0.081005003
+	m[13*20+1] = 0.055823373
+	m[13*20+2] = 0.075135007
+	m[13*20+3] = 0.007023765
+	m[13*20+4] = 1.01e-08
+	m[13*20+5] = 0.015853064
+	m[13*20+6] = 0.015279758
+	m[13*20+7] = 0.056155009
+	m[13*20+8] = 0.107316911
+	m[13*20+9] = 1.094466451
+	m[13*20+10] = 3.268727716
+	m[13*20+11] = 0.022000512
+	m[13*20+12] = 0.310466547
+	m[14*20+0] = 1.161150624
+	m[14*20+1] = 0.222633941
+	m[14*20+2] = 0.020641357
+	m[14*20+3] = 0.092919542
+	m[14*20+4] = 0.036567009
+	m[14*20+5] = 0.394101633
+	m[14*20+6] = 0.047437998
+	m[14*20+7] = 0.099733999
+	m[14*20+8] = 0.375101212
+	m[14*20+9] = 0.017279082
+	m[14*20+10] = 0.418683069
+	m[14*20+11] = 0.06453373
+	m[14*20+12] = 0.020227963
+	m[14*20+13] = 0.037079339
+	m[15*20+0] = 2.342024042
+	m[15*20+1] = 0.547047436
+	m[15*20+2] = 1.789919025
+	m[15*20+3] = 0.231662658
+	m[15*20+4] = 0.470426766
+	m[15*20+5] = 0.361564116
+	m[15*20+6] = 0.123054622
+	m[15*20+7] = 0.740215612
+	m[15*20+8] = 0.275612546
+	m[15*20+9] = 0.021633564
+	m[15*20+10] = 0.153325768
+	m[15*20+11] = 0.318946582
+	m[15*20+12] = 0.232232694
+	m[15*20+13] = 0.117924616
+	m[15*20+14] = 1.135289302
+	m[16*20+0] = 2.008811895
+	m[16*20+1] = 0.428919463
+	m[16*20+2] = 1.151963418
+	m[16*20+3] = 0.187098574
+	m[16*20+4] = 0.302419838
+	m[16*20+5] = 0.315883108
+	m[16*20+6] = 0.221346539
+	m[16*20+7] = 0.050693812
+	m[16*20+8] = 0.144151025
+	m[16*20+9] = 1.130849117
+	m[16*20+10] = 0.206097163
+	m[16*20+11] = 0.577059587
+	m[16*20+12] = 1.247718148
+	m[16*20+13] = 0.02567941
+	m[16*20+14] = 0.329045127
+	m[16*20+15] = 2.286762562
+	m[17*20+0] = 0.007296846
+	m[17*20+1] = 0.304802089
+	m[17*20+2] = 0.026082236
+	m[17*20+3] = 5.80e-05
+	m[17*20+4] = 0.168001553
+	m[17*20+5] = 0.009451054
+	m[17*20+6] = 0.013848637
+	m[17*20+7] = 0.061823386
+	m[17*20+8] = 0.038946016
+	m[17*20+9] = 0.023041546
+	m[17*20+10] = 0.095106524
+	m[17*20+11] = 1.45e-11
+	m[17*20+12] = 0.012929032
+	m[17*20+13] = 0.243669232
+	m[17*20+14] = 0.049466478
+	m[17*20+15] = 0.068713883
+	m[17*20+16] = 0.027447018
+	m[18*20+0] = 0.041893716
+	m[18*20+1] = 0.158213745
+	m[18*20+2] = 1.02e-02
+	m[18*20+3] = 0.065669082
+	m[18*20+4] = 0.330138271
+	m[18*20+5] = 0.081046099
+	m[18*20+6] = 2.58e-06
+	m[18*20+7] = 0.004735426
+	m[18*20+8] = 3.019713416
+	m[18*20+9] = 0.036637018
+	m[18*20+10] = 0.120111064
+	m[18*20+11] = 0.035616283
+	m[18*20+12] = 0.038640548
+	m[18*20+13] = 5.128521057
+	m[18*20+14] = 0.01640208
+	m[18*20+15] = 0.213718339
+	m[18*20+16] = 0.053064981
+	m[18*20+17] = 0.314262427
+	m[19*20+0] = 2.537810469
+	m[19*20+1] = 0.213211155
+	m[19*20+2] = 0.056101315
+	m[19*20+3] = 0.106493659
+	m[19*20+4] = 0.338138007
+	m[19*20+5] = 0.029082264
+	m[19*20+6] = 0.253218547
+	m[19*20+7] = 0.087627474
+	m[19*20+8] = 0.018694149
+	m[19*20+9] = 7.800393766
+	m[19*20+10] = 1.050227155
+	m[19*20+11] = 0.066722286
+	m[19*20+12] = 1.398449826
+	m[19*20+13] = 0.372853081
+	m[19*20+14] = 0.064305545
+	m[19*20+15] = 0.110812609
+	m[19*20+16] = 1.004024215
+	m[19*20+17] = 0.080046545
+	m[19*20+18] = 0.223617546
+
+	for i = 0; i < naa; i++ {
+		for j = 0; j < i; j++ {
+			m[j*naa+i] = m[i*naa+j]
+		}
+	}
+
+	pi[0] = 0.0470718
+	pi[1] = 0.0509102
+	pi[2] = 0.0742143
+	pi[3] = 0.0478596
+	pi[4] = 0.0250216
+	pi[5] = 0.0333036
+	pi[6] = 0.0545874
+	pi[7] = 0.0763734
+	pi[8] = 0.0199635
+	pi[9] = 0.0671336
+	pi[10] = 0.0714981
+	pi[11] = 0.0567845
+	pi[12] = 0.0181507
+	pi[13] = 0.0304961
+	pi[14] = 0.0506561
+	pi[15] = 0.0884091
+	pi[16] = 0.0743386
+	pi[17] = 0.0185237
+	pi[18] = 0.0314741
+	pi[19] = 0.0632259
+
+	dmat = mat.NewDense(naa, naa, m)
+	return
+}
+
+/*********************************************************/
+
+// mtMAM model data
+// Yang Z, Nielsen R, Hasegawa M.
+// Models of amino acid substitution and applications to mitochondrial
+// protein evolution.
+// Mol Biol Evol. 1998;15:1600-1611.
+func MtMAMMats() (dmat *mat.Dense, pi []float64) {
+	var i, j, naa int
+
+	naa = 20
+
+	m := make([]float64, naa*naa)
+	pi = make([]float64, naa)
+
+	m[1*20+0] = 32
+	m[2*20+0] = 2
+	m[2*20+1] = 4
+	m[3*20+0] = 11
+	m[3*20+1] = 0
+	m[3*20+2] = 864
+	m[4*20+0] = 0
+	m[4*20+1] = 186
+	m[4*20+2] = 0
+	m[4*20+3] = 0
+	m[5*20+0] = 0
+	m[5*20+1] = 246
+	m[5*20+2] = 8
+	m[5*20+3] = 49
+	m[5*20+4] = 0
+	m[6*20+0] = 0
+	m[6*20+1] = 0
+	m[6*20+2] = 0
+	m[6*20+3] = 569
+	m[6*20+4] = 0
+	m[6*20+5] = 274
+	m[7*20+0] = 78
+	m[7*20+1] = 18
+	m[7*20+2] = 47
+	m[7*20+3] = 79
+	m[7*20+4] = 0
+	m[7*20+5] = 0
+	m[7*20+6] = 22
+	m[8*20+0] = 8
+	m[8*20+1] = 232
+	m[8*20+2] = 458
+	m[8*20+3] = 11
+	m[8*20+4] = 305
+	m[8*20+5] = 550
+	m[8*20+6] = 22
+	m[8*20+7] = 0
+	m[9*20+0] = 75
+	m[9*20+1] = 0
+	m[9*20+2] = 19
+	m[9*20+3] = 0
+	m[9*20+4] = 41
+	m[9*20+5] = 0
+	m[9*20+6] = 0
+	m[9*20+7] = 0
+	m[9*20+8] = 0
+	m[10*20+0] = 21
+	m[10*20+1] = 6
+	m[10*20+2] = 0
+	m[10*20+3] = 0
+	m[10*20+4] = 27
+	m[10*20+5] = 20
+	m[10*20+6] = 0
+	m[10*20+7] = 0
+	m[10*20+8] = 26
+	m[10*20+9] = 232
+	m[11*20+0] = 0
+	m[11*20+1] = 50
+	m[11*20+2] = 408
+	m[11*20+3] = 0
+	m[11*20+4] = 0
+	m[11*20+5] = 242
+	m[11*20+6] = 215
+	m[11*20+7] = 0
+	m[11*20+8] = 0
+	m[11*20+9] = 6
+	m[11*20+10] = 4
+	m[12*20+0] = 76
+	m[12*20+1] = 0
+	m[12*20+2] = 21
+	m[12*20+3] = 0
+	m[12*20+4] = 0
+	m[12*20+5] = 22
+	m[12*20+6] = 0
+	m[12*20+7] = 0
+	m[12*20+8] = 0
+	m[12*20+9] = 378
+	m[12*20+10] = 609
+	m[12*20+11] = 59
+	m[13*20+0] = 0
+	m[13*20+1] = 0
+	m[13*20+2] = 6
+	m[13*20+3] = 5
+	m[13*20+4] = 7
+	m[13*20+5] = 0
+	m[13*20+6] = 0
+	m[13*20+7] = 0
+	m[13*20+8] = 0
+	m[13*20+9] = 57
+	m[13*20+10] = 246
+	m[13*20+11] = 0
+	m[13*20+12] = 11
+	m[14*20+0] = 53
+	m[14*20+1] = 9
+	m[14*20+2] = 33
+	m[14*20+3] = 2
+	m[14*20+4] = 0
+	m[14*20+5] = 51
+	m[14*20+6] = 0
+	m[14*20+7] = 0
+	m[14*20+8] = 53
+	m[14*20+9] = 5
+	m[14*20+10] = 43
+	m[14*20+11] = 18
+	m[14*20+12] = 0
+	m[14*20+13] = 17
+	m[15*20+0] = 342
+	m[15*20+1] = 3
+	m[15*20+2] = 446
+	m[15*20+3] = 16
+	m[15*20+4] = 347
+	m[15*20+5] = 30
+	m[15*20+6] = 21
+	m[15*20+7] = 112
+	m[15*20+8] = 20
+	m[15*20+9] = 0
+	m[15*20+10] = 74
+	m[15*20+11] = 65
+	m[15*20+12] = 47
+	m[15*20+13] = 90
+	m[15*20+14] = 202
+	m[16*20+0] = 681
+	m[16*20+1] = 0
+	m[16*20+2] = 110
+	m[16*20+3] = 0
+	m[16*20+4] = 114
+	m[16*20+5] = 0
+	m[16*20+6] = 4
+	m[16*20+7] = 0
+	m[16*20+8] = 1
+	m[16*20+9] = 360
+	m[16*20+10] = 34
+	m[16*20+11] = 50
+	m[16*20+12] = 691
+	m[16*20+13] = 8
+	m[16*20+14] = 78
+	m[16*20+15] = 614
+	m[17*20+0] = 5
+	m[17*20+1] = 16
+	m[17*20+2] = 6
+	m[17*20+3] = 0
+	m[17*20+4] = 65
+	m[17*20+5] = 0
+	m[17*20+6] = 0
+	m[17*20+7] = 0
+	m[17*20+8] = 0
+	m[17*20+9] = 0
+	m[17*20+10] = 12
+	m[17*20+11] = 0
+	m[17*20+12] = 13
+	m[17*20+13] = 0
+	m[17*20+14] = 7
+	m[17*20+15] = 17
+	m[17*20+16] = 0
+	m[18*20+0] = 0
+	m[18*20+1] = 0
+	m[18*20+2] = 156
+	m[18*20+3] = 0
+	m[18*20+4] = 530
+	m[18*20+5] = 54
+	m[18*20+6] = 0
+	m[18*20+7] = 1
+	m[18*20+8] = 1525
+	m[18*20+9] = 16
+	m[18*20+10] = 25
+	m[18*20+11] = 67
+	m[18*20+12] = 0
+	m[18*20+13] = 682
+	m[18*20+14] = 8
+	m[18*20+15] = 107
+	m[18*20+16] = 0
+	m[18*20+17] = 14
+	m[19*20+0] = 398
+	m[19*20+1] = 0
+	m[19*20+2] = 0
+	m[19*20+3] = 10
+	m[19*20+4] = 0
+	m[19*20+5] = 33
+	m[19*20+6] = 20
+	m[19*20+7] = 5
+	m[19*20+8] = 0
+	m[19*20+9] = 2220
+	m[19*20+10] = 100
+	m[19*20+11] = 0
+	m[19*20+12] = 832
+	m[19*20+13] = 6
+	m[19*20+14] = 0
+	m[19*20+15] = 0
+	m[19*20+16] = 237
+	m[19*20+17] = 0
+	m[19*20+18] = 0
+
+	for i = 0; i < naa; i++ {
+		for j = 0; j < i; j++ {
+			m[j*naa+i] = m[i*naa+j]
+		}
+	}
+
+	pi[0] = 0.0692
+	pi[1] = 0.0184
+	pi[2] = 0.0400
+	pi[3] = 0.0186
+	pi[4] = 0.0065
+	pi[5] = 0.0238
+	pi[6] = 0.0236
+	pi[7] = 0.0557
+	pi[8] = 0.0277
+	pi[9] = 0.0905
+	pi[10] = 0.1675
+	pi[11] = 0.0221
+	pi[12] = 0.0561
+	pi[13] = 0.0611
+	pi[14] = 0.0536
+	pi[15] = 0.0725
+	pi[16] = 0.0870
+	pi[17] = 0.0293
+	pi[18] = 0.0340
+	pi[19] = 0.0428
+
+	dmat = mat.NewDense(naa, naa, m)
+	return
+}
+
+/*********************************************************/
+
+// mtART model data
+// Abascal F, Posada D, Zardoya R.
+// MtArt: a new model of amino acid replacement for Arthropoda.
+// Mol Biol Evol. 2007;24:1-5.
+func MtARTMats() (dmat *mat.Dense, pi []float64) {
+	var i, j, naa int
+
+	naa = 20
+
+	m := make([]float64, naa*naa)
+	pi = make([]float64, naa)
+
+	m[1*20+0] = 0.2
+	m[2*20+0] = 0.2
+	m[2*20+1] = 0.2
+	m[3*20+0] = 1
+	m[3*20+1] = 4
+	m[3*20+2] = 500
+	m[4*20+0] = 254
+	m[4*20+1] = 36
+	m[4*20+2] = 98
+	m[4*20+3] = 11
+	m[5*20+0] = 0.2
+	m[5*20+1] = 154
+	m[5*20+2] = 262
+	m[5*20+3] = 0.2
+	m[5*20+4] = 0.2
+	m[6*20+0] = 0.2
+	m[6*20+1] = 0.2
+	m[6*20+2] = 183
+	m[6*20+3] = 862
+	m[6*20+4] = 0.2
+	m[6*20+5] = 262
+	m[7*20+0] = 200
+	m[7*20+1] = 0.2
+	m[7*20+2] = 121
+	m[7*20+3] = 12
+	m[7*20+4] = 81
+	m[7*20+5] = 3
+	m[7*20+6] = 44
+	m[8*20+0] = 0.2
+	m[8*20+1] = 41
+	m[8*20+2] = 180
+	m[8*20+3] = 0.2
+	m[8*20+4] = 12
+	m[8*20+5] = 314
+	m[8*20+6] = 15
+	m[8*20+7] = 0.2
+	m[9*20+0] = 26
+	m[9*20+1] = 2
+	m[9*20+2] = 21
+	m[9*20+3] = 7
+	m[9*20+4] = 63
+	m[9*20+5] = 11
+	m[9*20+6] = 7
+	m[9*20+7] = 3
+	m[9*20+8] = 0.2
+	m[10*20+0] = 4
+	m[10*20+1] = 2
+	m[10*20+2] = 13
+	m[10*20+3] = 1
+	m[10*20+4] = 79
+	m[10*20+5] = 16
+	m[10*20+6] = 2
+	m[10*20+7] = 1
+	m[10*20+8] = 6
+	m[10*20+9] = 515
+	m[11*20+0] = 0.2
+	m[11*20+1] = 209
+	m[11*20+2] = 467
+	m[11*20+3] = 2
+	m[11*20+4] = 0.2
+	m[11*20+5] = 349
+	m[11*20+6] = 106
+	m[11*20+7] = 0.2
+	m[11*20+8] = 0.2
+	m[11*20+9] = 3
+	m[11*20+10] = 4
+	m[12*20+0] = 121
+	m[12*20+1] = 5
+	m[12*20+2] = 79
+	m[12*20+3] = 0.2
+	m[12*20+4] = 312
+	m[12*20+5] = 67
+	m[12*20+6] = 0.2
+	m[12*20+7] = 56
+	m[12*20+8] = 0.2
+	m[12*20+9] = 515
+	m[12*20+10] = 885
+	m[12*20+11] = 106
+	m[13*20+0] = 13
+	m[13*20+1] = 5
+	m[13*20+2] = 20
+	m[13*20+3] = 0.2
+	m[13*20+4] = 184
+	m[13*20+5] = 0.2
+	m[13*20+6] = 0.2
+	m[13*20+7] = 1
+	m[13*20+8] = 14
+	m[13*20+9] = 118
+	m[13*20+10] = 263
+	m[13*20+11] = 11
+	m[13*20+12] = 322
+	m[14*20+0] = 49
+	m[14*20+1] = 0.2
+	m[14*20+2] = 17
+	m[14*20+3] = 0.2
+	m[14*20+4] = 0.2
+	m[14*20+5] = 39
+	m[14*20+6] = 8
+	m[14*20+7] = 0.2
+	m[14*20+8] = 1
+	m[14*20+9] = 0.2
+	m[14*20+10] = 12
+	m[14*20+11] = 17
+	m[14*20+12] = 5
+	m[14*20+13] = 15
+	m[15*20+0] = 673
+	m[15*20+1] = 3
+	m[15*20+2] = 398
+	m[15*20+3] = 44
+	m[15*20+4] = 664
+	m[15*20+5] = 52
+	m[15*20+6] = 31
+	m[15*20+7] = 226
+	m[15*20+8] = 11
+	m[15*20+9] = 7
+	m[15*20+10] = 8
+	m[15*20+11] = 144
+	m[15*20+12] = 112
+	m[15*20+13] = 36
+	m[15*20+14] = 87
+	m[16*20+0] = 244
+	m[16*20+1] = 0.2
+	m[16*20+2] = 166
+	m[16*20+3] = 0.2
+	m[16*20+4] = 183
+	m[16*20+5] = 44
+	m[16*20+6] = 43
+	m[16*20+7] = 0.2
+	m[16*20+8] = 19
+	m[16*20+9] = 204
+	m[16*20+10] = 48
+	m[16*20+11] = 70
+	m[16*20+12] = 289
+	m[16*20+13] = 14
+	m[16*20+14] = 47
+	m[16*20+15] = 660
+	m[17*20+0] = 0.2
+	m[17*20+1] = 0.2
+	m[17*20+2] = 8
+	m[17*20+3] = 0.2
+	m[17*20+4] = 22
+	m[17*20+5] = 7
+	m[17*20+6] = 11
+	m[17*20+7] = 2
+	m[17*20+8] = 0.2
+	m[17*20+9] = 0.2
+	m[17*20+10] = 21
+	m[17*20+11] = 16
+	m[17*20+12] = 71
+	m[17*20+13] = 54
+	m[17*20+14] = 0.2
+	m[17*20+15] = 2
+	m[17*20+16] = 0.2
+	m[18*20+0] = 1
+	m[18*20+1] = 4
+	m[18*20+2] = 251
+	m[18*20+3] = 0.2
+	m[18*20+4] = 72
+	m[18*20+5] = 87
+	m[18*20+6] = 8
+	m[18*20+7] = 9
+	m[18*20+8] = 191
+	m[18*20+9] = 12
+	m[18*20+10] = 20
+	m[18*20+11] = 117
+	m[18*20+12] = 71
+	m[18*20+13] = 792
+	m[18*20+14] = 18
+	m[18*20+15] = 30
+	m[18*20+16] = 46
+	m[18*20+17] = 38
+	m[19*20+0] = 340
+	m[19*20+1] = 0.2
+	m[19*20+2] = 23
+	m[19*20+3] = 0.2
+	m[19*20+4] = 350
+	m[19*20+5] = 0.2
+	m[19*20+6] = 14
+	m[19*20+7] = 3
+	m[19*20+8] = 0.2
+	m[19*20+9] = 1855
+	m[19*20+10] = 85
+	m[19*20+11] = 26
+	m[19*20+12] = 281
+	m[19*20+13] = 52
+	m[19*20+14] = 32
+	m[19*20+15] = 61
+	m[19*20+16] = 544
+	m[19*20+17] = 0.2
+	m[19*20+18] = 2
+
+	for i = 0; i < naa; i++ {
+		for j = 0; j < i; j++ {
+			m[j*naa+i] = m[i*naa+j]
+		}
+	}
+
+	pi[0] = 0.054116
+	pi[1] = 0.018227
+	pi[2] = 0.039903
+	pi[3] = 0.020160
+	pi[4] = 0.009709
+	pi[5] = 0.018781
+	pi[6] = 0.024289
+	pi[7] = 0.068183
+	pi[8] = 0.024518
+	pi[9] = 0.092638
+	pi[10] = 0.148658
+	pi[11] = 0.021718
+	pi[12] = 0.061453
+	pi[13] = 0.088668
+	pi[14] = 0.041826
+	pi[15] = 0.091030
+	pi[16] = 0.049194
+	pi[17] = 0.029786
+	pi[18] = 0.039443
+	pi[19] = 0.057700
+
+	dmat = mat.NewDense(naa, naa, m)
+	return
+}
+
+/*********************************************************/
+
+// HIVw model data
+// Nickle DC, Heath L, Jensen MA, Gilbert PB, Mullins JI, Kosakovsky Pond SL.
+// HIV-Specific Probabilistic Models of Protein Evolution.
+// PLoS ONE. 2007 Jun 6;2:e503.
+func HIVWMats() (dmat *mat.Dense, pi []float64) {
+	var i, j, naa int
+
+	naa = 20
+
+	m := make([]float64, naa*naa)
+	pi = make([]float64, naa)
+
+	m[1*20+0] = 0.0744808
+	m[2*20+0] = 0.617509
+	m[2*20+1] = 0.16024
+	m[3*20+0] = 4.43521
+	m[3*20+1] = 0.0674539
+	m[3*20+2] = 29.4087
+	m[4*20+0] = 0.167653
+	m[4*20+1] = 2.86364
+	m[4*20+2] = 0.0604932
+	m[4*20+3] = 0.005
+	m[5*20+0] = 0.005
+	m[5*20+1] = 10.6746
+	m[5*20+2] = 0.342068
+	m[5*20+3] = 0.005
+	m[5*20+4] = 0.005
+	m[6*20+0] = 5.56325
+	m[6*20+1] = 0.0251632
+	m[6*20+2] = 0.201526
+	m[6*20+3] = 12.1233
+	m[6*20+4] = 0.005
+	m[6*20+5] = 3.20656
+	m[7*20+0] = 1.8685
+	m[7*20+1] = 13.4379
+	m[7*20+2] = 0.0604932
+	m[7*20+3] = 10.3969
+	m[7*20+4] = 0.0489798
+	m[7*20+5] = 0.0604932
+	m[7*20+6] = 14.7801
+	m[8*20+0] = 0.005
+	m[8*20+1] = 6.84405
+	m[8*20+2] = 8.59876
+	m[8*20+3] = 2.31779
+	m[8*20+4] = 0.005
+	m[8*20+5] = 18.5465
+	m[8*20+6] = 0.005
+	m[8*20+7] = 0.005
+	m[9*20+0] = 0.005
+	m[9*20+1] = 1.34069
+	m[9*20+2] = 0.987028
+	m[9*20+3] = 0.145124
+	m[9*20+4] = 0.005
+	m[9*20+5] = 0.0342252
+	m[9*20+6] = 0.0390512
+	m[9*20+7] = 0.005
+	m[9*20+8] = 0.005
+	m[10*20+0] = 0.16024
+	m[10*20+1] = 0.586757
+	m[10*20+2] = 0.005
+	m[10*20+3] = 0.005
+	m[10*20+4] = 0.005
+	m[10*20+5] = 2.89048
+	m[10*20+6] = 0.129839
+	m[10*20+7] = 0.0489798
+	m[10*20+8] = 1.76382
+	m[10*20+9] = 9.10246
+	m[11*20+0] = 0.592784
+	m[11*20+1] = 39.8897
+	m[11*20+2] = 10.6655
+	m[11*20+3] = 0.894313
+	m[11*20+4] = 0.005
+	m[11*20+5] = 13.0705
+	m[11*20+6] = 23.9626
+	m[11*20+7] = 0.279425
+	m[11*20+8] = 0.22406
+	m[11*20+9] = 0.817481
+	m[11*20+10] = 0.005
+	m[12*20+0] = 0.005
+	m[12*20+1] = 3.28652
+	m[12*20+2] = 0.201526
+	m[12*20+3] = 0.005
+	m[12*20+4] = 0.005
+	m[12*20+5] = 0.005
+	m[12*20+6] = 0.005
+	m[12*20+7] = 0.0489798
+	m[12*20+8] = 0.005
+	m[12*20+9] = 17.3064
+	m[12*20+10] = 11.3839
+	m[12*20+11] = 4.09564
+	m[13*20+0] = 0.597923
+	m[13*20+1] = 0.005
+	m[13*20+2] = 0.005
+	m[13*20+3] = 0.005
+	m[13*20+4] = 0.362959
+	m[13*20+5] = 0.005
+	m[13*20+6] = 0.005
+	m[13*20+7] = 0.005
+	m[13*20+8] = 0.005
+	m[13*20+9] = 1.48288
+	m[13*20+10] = 7.48781
+	m[13*20+11] = 0.005
+	m[13*20+12] = 0.005
+	m[14*20+0] = 1.00981
+	m[14*20+1] = 0.404723
+	m[14*20+2] = 0.344848
+	m[14*20+3] = 0.005
+	m[14*20+4] = 0.005
+	m[14*20+5] = 3.04502
+	m[14*20+6] = 0.005
+	m[14*20+7] = 0.005
+	m[14*20+8] = 13.9444
+	m[14*20+9] = 0.005
+	m[14*20+10] = 9.83095
+	m[14*20+11] = 0.111928
+	m[14*20+12] = 0.005
+	m[14*20+13] = 0.0342252
+	m[15*20+0] = 8.5942
+	m[15*20+1] = 8.35024
+	m[15*20+2] = 14.5699
+	m[15*20+3] = 0.427881
+	m[15*20+4] = 1.12195
+	m[15*20+5] = 0.16024
+	m[15*20+6] = 0.005
+	m[15*20+7] = 6.27966
+	m[15*20+8] = 0.725157
+	m[15*20+9] = 0.740091
+	m[15*20+10] = 6.14396
+	m[15*20+11] = 0.005
+	m[15*20+12] = 0.392575
+	m[15*20+13] = 4.27939
+	m[15*20+14] = 14.249
+	m[16*20+0] = 24.1422
+	m[16*20+1] = 0.928203
+	m[16*20+2] = 4.54206
+	m[16*20+3] = 0.630395
+	m[16*20+4] = 0.005
+	m[16*20+5] = 0.203091
+	m[16*20+6] = 0.458743
+	m[16*20+7] = 0.0489798
+	m[16*20+8] = 0.95956
+	m[16*20+9] = 9.36345
+	m[16*20+10] = 0.005
+	m[16*20+11] = 4.04802
+	m[16*20+12] = 7.41313
+	m[16*20+13] = 0.114512
+	m[16*20+14] = 4.33701
+	m[16*20+15] = 6.34079
+	m[17*20+0] = 0.005
+	m[17*20+1] = 5.96564
+	m[17*20+2] = 0.005
+	m[17*20+3] = 0.005
+	m[17*20+4] = 5.49894
+	m[17*20+5] = 0.0443298
+	m[17*20+6] = 0.005
+	m[17*20+7] = 2.8258
+	m[17*20+8] = 0.005
+	m[17*20+9] = 0.005
+	m[17*20+10] = 1.37031
+	m[17*20+11] = 0.005
+	m[17*20+12] = 0.005
+	m[17*20+13] = 0.005
+	m[17*20+14] = 0.005
+	m[17*20+15] = 1.10156
+	m[17*20+16] = 0.005
+	m[18*20+0] = 0.005
+	m[18*20+1] = 0.005
+	m[18*20+2] = 5.06475
+	m[18*20+3] = 2.28154
+	m[18*20+4] = 8.34835
+	m[18*20+5] = 0.005
+	m[18*20+6] = 0.005
+	m[18*20+7] = 0.005
+	m[18*20+8] = 47.4889
+	m[18*20+9] = 0.114512
+	m[18*20+10] = 0.005
+	m[18*20+11] = 0.005
+	m[18*20+12] = 0.579198
+	m[18*20+13] = 4.12728
+	m[18*20+14] = 0.005
+	m[18*20+15] = 0.933142
+	m[18*20+16] = 0.490608
+	m[18*20+17] = 0.005
+	m[19*20+0] = 24.8094
+	m[19*20+1] = 0.279425
+	m[19*20+2] = 0.0744808
+	m[19*20+3] = 2.91786
+	m[19*20+4] = 0.005
+	m[19*20+5] = 0.005
+	m[19*20+6] = 2.19952
+	m[19*20+7] = 2.79622
+	m[19*20+8] = 0.827479
+	m[19*20+9] = 24.8231
+	m[19*20+10] = 2.95344
+	m[19*20+11] = 0.128065
+	m[19*20+12] = 14.7683
+	m[19*20+13] = 2.28
+	m[19*20+14] = 0.005
+	m[19*20+15] = 0.862637
+	m[19*20+16] = 0.005
+	m[19*20+17] = 0.005
+	m[19*20+18] = 1.35482
+
+	for i = 0; i < naa; i++ {
+		for j = 0; j < i; j++ {
+			m[j*naa+i] = m[i*naa+j]
+		}
+	}
+
+	pi[0] = 0.0377494
+	pi[1] = 0.057321
+	pi[2] = 0.0891129
+	pi[3] = 0.0342034
+	pi[4] = 0.0240105
+	pi[5] = 0.0437824
+	pi[6] = 0.0618606
+	pi[7] = 0.0838496
+	pi[8] = 0.0156076
+	pi[9] = 0.0983641
+	pi[10] = 0.0577867
+	pi[11] = 0.0641682
+	pi[12] = 0.0158419
+	pi[13] = 0.0422741
+	pi[14] = 0.0458601
+	pi[15] = 0.0550846
+	pi[16] = 0.0813774
+	pi[17] = 0.019597
+	pi[18] = 0.0205847
+	pi[19] = 0.0515638
+
+	dmat = mat.NewDense(naa, naa, m)
+	return
+}
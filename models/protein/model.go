@@ -14,6 +14,14 @@ const (
 	MODEL_LG
 	MODEL_WAG
 	MODEL_HIVB
+	MODEL_HIVW
+	MODEL_CPREV
+	MODEL_RTREV
+	MODEL_VT
+	MODEL_BLOSUM62
+	MODEL_FLU
+	MODEL_MTMAM
+	MODEL_MTART
 
 	BL_MIN  = 1.e-08
 	BL_MAX  = 100.0
@@ -35,7 +43,9 @@ type ProtModel struct {
 }
 
 // Initialize a new protein model, given the name of the model as const int:
-// MODEL_DAYHOFF, MODEL_JTT, MODEL_MTREV, MODEL_LG, MODEL_WAG or MODEL_HIVB
+// MODEL_DAYHOFF, MODEL_JTT, MODEL_MTREV, MODEL_LG, MODEL_WAG, MODEL_HIVB,
+// MODEL_HIVW, MODEL_CPREV, MODEL_RTREV, MODEL_VT, MODEL_BLOSUM62, MODEL_FLU,
+// MODEL_MTMAM or MODEL_MTART
 func NewProtModel(model int, usegamma bool, alpha float64) (*ProtModel, error) {
 	var m *mat.Dense
 	var pi []float64
@@ -52,6 +62,22 @@ func NewProtModel(model int, usegamma bool, alpha float64) (*ProtModel, error) {
 		m, pi = WAGMats()
 	case MODEL_HIVB:
 		m, pi = HIVBMats()
+	case MODEL_HIVW:
+		m, pi = HIVWMats()
+	case MODEL_CPREV:
+		m, pi = CpREVMats()
+	case MODEL_RTREV:
+		m, pi = RtREVMats()
+	case MODEL_VT:
+		m, pi = VTMats()
+	case MODEL_BLOSUM62:
+		m, pi = Blosum62Mats()
+	case MODEL_FLU:
+		m, pi = FLUMats()
+	case MODEL_MTMAM:
+		m, pi = MtMAMMats()
+	case MODEL_MTART:
+		m, pi = MtARTMats()
 	default:
 		return nil, fmt.Errorf("This protein model is not implemented")
 	}
@@ -68,6 +94,28 @@ func NewProtModel(model int, usegamma bool, alpha float64) (*ProtModel, error) {
 	}, nil
 }
 
+// Initialize a new protein model from the given exchangeability matrix
+// (20x20, symmetric) and amino acid frequencies (see ReadPAMLMats).
+func NewProtModelFromMats(m *mat.Dense, pi []float64, usegamma bool, alpha float64) (*ProtModel, error) {
+	if r, c := m.Dims(); r != 20 || c != 20 {
+		return nil, fmt.Errorf("Protein substitution matrix must be 20x20")
+	}
+	if len(pi) != 20 {
+		return nil, fmt.Errorf("aa frequency array does not have a length of 20")
+	}
+	return &ProtModel{
+		pi,
+		m,
+		-1.0,
+		nil,
+		nil,
+		nil,
+		nil,
+		alpha,
+		usegamma,
+	}, nil
+}
+
 // Returns code of the model
 // If the model does not exist, returns -1
 func ModelStringToInt(model string) int {
@@ -84,6 +132,22 @@ func ModelStringToInt(model string) int {
 		return MODEL_WAG
 	case "hivb":
 		return MODEL_HIVB
+	case "hivw":
+		return MODEL_HIVW
+	case "cprev":
+		return MODEL_CPREV
+	case "rtrev":
+		return MODEL_RTREV
+	case "vt":
+		return MODEL_VT
+	case "blosum62":
+		return MODEL_BLOSUM62
+	case "flu":
+		return MODEL_FLU
+	case "mtmam":
+		return MODEL_MTMAM
+	case "mtart":
+		return MODEL_MTART
 	default:
 		return -1
 	}
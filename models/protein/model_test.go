@@ -0,0 +1,36 @@
+package protein
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBuiltInModels(t *testing.T) {
+	for _, name := range []string{"dayoff", "jtt", "mtrev", "lg", "wag", "hivb", "hivw",
+		"cprev", "rtrev", "vt", "blosum62", "flu", "mtmam", "mtart"} {
+		code := ModelStringToInt(name)
+		if code == -1 {
+			t.Errorf("Model %s should be built in", name)
+			continue
+		}
+		model, err := NewProtModel(code, false, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := 0.0
+		for i := 0; i < 20; i++ {
+			sum += model.Pi(i)
+			for j := 0; j < 20; j++ {
+				if model.mat.At(i, j) < 0 || model.mat.At(i, j) != model.mat.At(j, i) {
+					t.Errorf("Model %s: wrong exchangeability (%d,%d): %f", name, i, j, model.mat.At(i, j))
+				}
+			}
+		}
+		if math.Abs(sum-1) > 1e-3 {
+			t.Errorf("Model %s: frequencies should sum to 1 (%f)", name, sum)
+		}
+		if err = model.InitModel(nil); err != nil {
+			t.Errorf("Model %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package protein
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ReadPAMLFile reads a protein substitution model from a PAML-style
+// .dat file (see ReadPAMLMats)
+func ReadPAMLFile(file string) (dmat *mat.Dense, pi []float64, err error) {
+	var f *os.File
+
+	if f, err = os.Open(file); err != nil {
+		return
+	}
+	defer f.Close()
+	if dmat, pi, err = ReadPAMLMats(f); err != nil {
+		err = fmt.Errorf("%s: %v", file, err)
+	}
+	return
+}
+
+// ReadPAMLMats reads a protein substitution model in PAML .dat format:
+//   - The lower triangle of the exchangeability matrix (190 values, 19 rows
+//     of 1, 2, ..., 19 values);
+//   - The 20 amino acid equilibrium frequencies.
+//
+// Amino acids are in the order A R N D C Q E G H I L K M F P S T W Y V.
+// Values may be separated by any white space, and anything after the
+// frequencies is ignored (comments, references, etc.). Frequencies are
+// normalized so that they sum to 1.
+func ReadPAMLMats(r io.Reader) (dmat *mat.Dense, pi []float64, err error) {
+	var v float64
+	var i, j int
+
+	naa := 20
+	nexch := naa * (naa - 1) / 2
+	values := make([]float64, 0, nexch+naa)
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	s.Split(bufio.ScanWords)
+	for len(values) < nexch+naa && s.Scan() {
+		if v, err = strconv.ParseFloat(s.Text(), 64); err != nil {
+			err = fmt.Errorf("value %d is not a number: %s", len(values)+1, s.Text())
+			return
+		}
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			err = fmt.Errorf("value %d is not a positive number: %s", len(values)+1, s.Text())
+			return
+		}
+		values = append(values, v)
+	}
+	if err = s.Err(); err != nil {
+		return
+	}
+	if len(values) < nexch+naa {
+		err = fmt.Errorf("PAML model should contain %d exchangeabilities and %d frequencies, only %d values found", nexch, naa, len(values))
+		return
+	}
+
+	m := make([]float64, naa*naa)
+	k := 0
+	for i = 1; i < naa; i++ {
+		for j = 0; j < i; j++ {
+			m[i*naa+j] = values[k]
+			m[j*naa+i] = values[k]
+			k++
+		}
+	}
+
+	pi = make([]float64, naa)
+	sum := 0.0
+	for i = 0; i < naa; i++ {
+		pi[i] = values[nexch+i]
+		sum += pi[i]
+	}
+	if sum == 0 {
+		err = fmt.Errorf("PAML model frequencies are all 0")
+		return
+	}
+	for i = 0; i < naa; i++ {
+		pi[i] /= sum
+	}
+
+	dmat = mat.NewDense(naa, naa, m)
+	return
+}
@@ -0,0 +1,64 @@
+package protein
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestReadPAMLMats(t *testing.T) {
+	var buf bytes.Buffer
+
+	expmat, exppi := LGMats()
+	for i := 1; i < 20; i++ {
+		for j := 0; j < i; j++ {
+			buf.WriteString(fmt.Sprintf("%f ", expmat.At(i, j)))
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+	for i := 0; i < 20; i++ {
+		buf.WriteString(fmt.Sprintf("%f ", exppi[i]))
+	}
+	buf.WriteString("\n\nLG model: Le & Gascuel 2008\n")
+
+	m, pi, err := ReadPAMLMats(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 20; j++ {
+			if math.Abs(m.At(i, j)-expmat.At(i, j)) > 1e-6 {
+				t.Errorf("Exchangeability (%d,%d) should be %f, not %f", i, j, expmat.At(i, j), m.At(i, j))
+			}
+		}
+		if math.Abs(pi[i]-exppi[i]) > 1e-5 {
+			t.Errorf("Frequency %d should be %f, not %f", i, exppi[i], pi[i])
+		}
+	}
+
+	model, err := NewProtModelFromMats(m, pi, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = model.InitModel(nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReadPAMLMatsErrors(t *testing.T) {
+	inputs := []string{
+		"",
+		strings.Repeat("1.0 ", 209),
+		strings.Repeat("1.0 ", 100) + "x " + strings.Repeat("1.0 ", 109),
+		strings.Repeat("1.0 ", 100) + "-1.0 " + strings.Repeat("1.0 ", 109),
+		strings.Repeat("1.0 ", 190) + strings.Repeat("0 ", 20),
+	}
+	for i, input := range inputs {
+		if _, _, err := ReadPAMLMats(strings.NewReader(input)); err == nil {
+			t.Errorf("Reading input %d should return an error", i)
+		}
+	}
+}
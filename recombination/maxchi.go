@@ -0,0 +1,252 @@
+package recombination
+
+import (
+	"sync"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// MaxChi2Result is the result of the maximum chi-squared test
+type MaxChi2Result struct {
+	NbSites int     // Number of polymorphic sites
+	MaxChi2 float64 // Maximum chi2 over all pairs of sequences and breakpoints
+	Seq1    int     // Index of the first sequence of the pair giving the maximum (-1 if none)
+	Seq2    int     // Index of the second sequence of the pair giving the maximum (-1 if none)
+	Site    int     // Alignment site right after the best breakpoint (-1 if none)
+	NbPerms int     // Number of permutations
+	PValue  float64 // Permutation p-value
+}
+
+// Breakpoint is the best breakpoint of a sliding window scan
+type Breakpoint struct {
+	Site int     // Alignment site right after the breakpoint
+	Chi2 float64 // Maximum chi2 over all pairs of sequences
+	Seq1 int     // Index of the first sequence of the pair giving the maximum (-1 if none)
+	Seq2 int     // Index of the second sequence of the pair giving the maximum (-1 if none)
+}
+
+// MaxChi2 computes the maximum chi-squared test (Maynard Smith 1992) on the
+// polymorphic sites of the nucleotide alignment (sites with at least two
+// different nucleotides, gaps and ambiguous nucleotides not taken into account).
+//
+// For each pair of sequences and each breakpoint between two consecutive sites
+// (where both sequences are defined), it computes the chi2 of the 2x2 contingency
+// table comparing the proportions of differences on the left and on the right of
+// the breakpoint. The statistic is the maximum chi2 over all pairs and breakpoints.
+//
+// The p-value is the proportion of random permutations of the polymorphic sites
+// giving a maximum chi2 greater than or equal to the observed one:
+// (nb+1)/(nbperms+1). Permutations are computed in parallel using cpus threads.
+func MaxChi2(al align.Alignment, nbperms, cpus int) (res MaxChi2Result, err error) {
+	var sites [][]int8
+	var index []int
+
+	if sites, index, err = polymorphicSites(al); err != nil {
+		return
+	}
+	n := al.NbSequences()
+	res.NbSites = len(sites)
+	res.NbPerms = nbperms
+	res.Seq1, res.Seq2, res.Site = -1, -1, -1
+
+	stat := func(order []int) float64 {
+		best := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if chi2, _ := maxChi2Pair(sites, order, i, j); chi2 > best {
+					best = chi2
+				}
+			}
+		}
+		return best
+	}
+
+	order := identity(len(sites))
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if chi2, pos := maxChi2Pair(sites, order, i, j); chi2 > res.MaxChi2 {
+				res.MaxChi2 = chi2
+				res.Seq1, res.Seq2 = i, j
+				res.Site = index[pos]
+			}
+		}
+	}
+
+	extreme, _, _ := permutationTest(len(sites), nbperms, cpus, res.MaxChi2, false, stat)
+	res.PValue = float64(extreme+1) / float64(nbperms+1)
+	return
+}
+
+// MaxChi2Scan scans the alignment for recombination breakpoints: for each
+// breakpoint between two consecutive polymorphic sites, it compares the window
+// of window polymorphic sites on its left with the window of window polymorphic
+// sites on its right, and returns the maximum chi2 over all pairs of sequences
+// (see MaxChi2). Only breakpoints having full windows on both sides are returned,
+// in the order of the alignment. Pairs of sequences are processed in parallel
+// using cpus threads.
+func MaxChi2Scan(al align.Alignment, window, cpus int) (breakpoints []Breakpoint, err error) {
+	var sites [][]int8
+	var index []int
+
+	if sites, index, err = polymorphicSites(al); err != nil {
+		return
+	}
+	n := al.NbSequences()
+	nbbp := len(sites) - 2*window + 1
+	if window < 1 || nbbp < 1 {
+		breakpoints = make([]Breakpoint, 0)
+		return
+	}
+
+	type pairBest struct {
+		chi2 float64
+		pair int
+	}
+
+	pairs := make(chan [3]int, 100)
+	go func() {
+		defer close(pairs)
+		p := 0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				pairs <- [3]int{i, j, p}
+				p++
+			}
+		}
+	}()
+
+	if cpus < 1 {
+		cpus = 1
+	}
+	results := make([][]pairBest, cpus)
+	var wg sync.WaitGroup
+	for cpu := 0; cpu < cpus; cpu++ {
+		results[cpu] = make([]pairBest, nbbp)
+		for b := range results[cpu] {
+			results[cpu][b].pair = -1
+		}
+		wg.Add(1)
+		go func(best []pairBest) {
+			defer wg.Done()
+			defined := make([]int, len(sites)+1)
+			diffs := make([]int, len(sites)+1)
+			for p := range pairs {
+				i, j := p[0], p[1]
+				for s, site := range sites {
+					defined[s+1], diffs[s+1] = defined[s], diffs[s]
+					if site[i] >= 0 && site[j] >= 0 {
+						defined[s+1]++
+						if site[i] != site[j] {
+							diffs[s+1]++
+						}
+					}
+				}
+				for b := range best {
+					start, bp, end := b, b+window, b+2*window
+					chi2 := chi2Table(defined[bp]-defined[start], diffs[bp]-diffs[start],
+						defined[end]-defined[bp], diffs[end]-diffs[bp])
+					if best[b].pair < 0 || chi2 > best[b].chi2 || (chi2 == best[b].chi2 && p[2] < best[b].pair) {
+						best[b] = pairBest{chi2, p[2]}
+					}
+				}
+			}
+		}(results[cpu])
+	}
+	wg.Wait()
+
+	// Index of pairs -> sequences
+	pairseqs := make([][2]int, 0, n*(n-1)/2)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			pairseqs = append(pairseqs, [2]int{i, j})
+		}
+	}
+
+	breakpoints = make([]Breakpoint, nbbp)
+	for b := range breakpoints {
+		best := pairBest{0, -1}
+		for _, r := range results {
+			if r[b].pair >= 0 && (best.pair < 0 || r[b].chi2 > best.chi2 || (r[b].chi2 == best.chi2 && r[b].pair < best.pair)) {
+				best = r[b]
+			}
+		}
+		breakpoints[b] = Breakpoint{Site: index[b+window], Chi2: best.chi2, Seq1: -1, Seq2: -1}
+		if best.pair >= 0 {
+			breakpoints[b].Seq1, breakpoints[b].Seq2 = pairseqs[best.pair][0], pairseqs[best.pair][1]
+		}
+	}
+	return
+}
+
+// polymorphicSites returns the encoded polymorphic sites of the alignment
+// and their indices in the alignment
+func polymorphicSites(al align.Alignment) (sites [][]int8, index []int, err error) {
+	var codes [][]int8
+
+	if codes, err = encodeAlignment(al); err != nil {
+		return
+	}
+	sites = make([][]int8, 0)
+	index = make([]int, 0)
+	for i, site := range codes {
+		nb := 0
+		for _, c := range stateCounts(site) {
+			if c > 0 {
+				nb++
+			}
+		}
+		if nb >= 2 {
+			sites = append(sites, site)
+			index = append(index, i)
+		}
+	}
+	return
+}
+
+// maxChi2Pair returns the maximum chi2 over all breakpoints for sequences i and j,
+// the sites being taken in the given order, as well as the position (in order)
+// of the first site after the best breakpoint.
+func maxChi2Pair(sites [][]int8, order []int, i, j int) (best float64, pos int) {
+	nb, d := 0, 0
+	for _, o := range order {
+		if c1, c2 := sites[o][i], sites[o][j]; c1 >= 0 && c2 >= 0 {
+			nb++
+			if c1 != c2 {
+				d++
+			}
+		}
+	}
+	if d == 0 || d == nb {
+		return
+	}
+	n1, d1 := 0, 0
+	for p, o := range order {
+		c1, c2 := sites[o][i], sites[o][j]
+		if c1 < 0 || c2 < 0 {
+			continue
+		}
+		if n1 > 0 {
+			if chi2 := chi2Table(n1, d1, nb-n1, d-d1); chi2 > best {
+				best = chi2
+				pos = p
+			}
+		}
+		n1++
+		if c1 != c2 {
+			d1++
+		}
+	}
+	return
+}
+
+// chi2Table returns the chi2 of the 2x2 contingency table comparing the
+// number of differences d1 over n1 sites with d2 over n2 sites
+func chi2Table(n1, d1, n2, d2 int) float64 {
+	n := n1 + n2
+	d := d1 + d2
+	if n1 == 0 || n2 == 0 || d == 0 || d == n {
+		return 0
+	}
+	num := float64(d1*(n2-d2) - d2*(n1-d1))
+	return float64(n) * num * num / (float64(n1) * float64(n2) * float64(d) * float64(n-d))
+}
@@ -0,0 +1,141 @@
+package recombination
+
+import (
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// PhiResult is the result of the PHI test
+type PhiResult struct {
+	NbSites  int     // Number of informative sites
+	Window   int     // Window size (in informative sites)
+	Phi      float64 // Observed PHI statistic
+	PermMean float64 // Mean of PHI over permutations
+	PermVar  float64 // Variance of PHI over permutations
+	NbPerms  int     // Number of permutations
+	PValue   float64 // Permutation p-value
+}
+
+// Phi computes the pairwise homoplasy index (Bruen, Philippe & Bryant 2006) of
+// the nucleotide alignment: the mean refined incompatibility score between pairs
+// of informative sites separated by at most window informative sites. Informative
+// sites are sites with at least two nucleotides occuring at least twice each
+// (gaps and ambiguous nucleotides are not taken into account).
+//
+// The refined incompatibility between two sites is the cycle rank of the graph
+// connecting the nucleotides observed together at both sites in the same sequence
+// (number of pairs - number of nucleotides + number of connected components), i.e.
+// the minimum number of homoplasies needed to explain both sites.
+//
+// Under recombination, incompatible sites are closer than expected: the p-value is
+// the proportion of random permutations of the informative sites giving a PHI lower
+// than or equal to the observed one: (nb+1)/(nbperms+1). Permutations are computed
+// in parallel using cpus threads.
+//
+// If the alignment has less informative sites than window+1, then all pairs
+// are considered, and the permutation test is not informative (p-value=1).
+func Phi(al align.Alignment, window, nbperms, cpus int) (res PhiResult, err error) {
+	var codes [][]int8
+
+	if codes, err = encodeAlignment(al); err != nil {
+		return
+	}
+	informative := make([][]int8, 0)
+	for _, site := range codes {
+		counts := stateCounts(site)
+		nb := 0
+		for _, c := range counts {
+			if c >= 2 {
+				nb++
+			}
+		}
+		if nb >= 2 {
+			informative = append(informative, site)
+		}
+	}
+	m := len(informative)
+	res.NbSites = m
+	res.Window = window
+	res.NbPerms = nbperms
+
+	// Incompatibilities between all pairs of informative sites (upper triangle)
+	inc := make([]uint8, m*(m-1)/2+1)
+	for i := 0; i < m; i++ {
+		for j := i + 1; j < m; j++ {
+			inc[triIndex(i, j, m)] = incompatibility(informative[i], informative[j])
+		}
+	}
+
+	phi := func(order []int) float64 {
+		sum, nb := 0.0, 0
+		for a := 0; a < m; a++ {
+			for b := a + 1; b < m && b-a <= window; b++ {
+				i, j := order[a], order[b]
+				if i > j {
+					i, j = j, i
+				}
+				sum += float64(inc[triIndex(i, j, m)])
+				nb++
+			}
+		}
+		if nb == 0 {
+			return 0
+		}
+		return sum / float64(nb)
+	}
+
+	res.Phi = phi(identity(m))
+	extreme, mean, variance := permutationTest(m, nbperms, cpus, res.Phi, true, phi)
+	res.PermMean, res.PermVar = mean, variance
+	res.PValue = float64(extreme+1) / float64(nbperms+1)
+	return
+}
+
+// index of pair i<j in a upper triangular matrix of size m stored as a slice
+func triIndex(i, j, m int) int {
+	return i*(2*m-i-1)/2 + (j - i - 1)
+}
+
+// incompatibility returns the refined incompatibility score between two sites:
+// the cycle rank of the bipartite graph whose vertices are the nucleotides of
+// both sites, and whose edges are the pairs of nucleotides observed in the same
+// sequence. Sequences with a gap or an ambiguous nucleotide at one of the sites
+// are not taken into account.
+func incompatibility(site1, site2 []int8) uint8 {
+	var pairs [4][4]bool
+	var parent [8]int
+
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			i = parent[i]
+		}
+		return i
+	}
+
+	edges, vertices, components := 0, 0, 0
+	var seen [8]bool
+	for s, c1 := range site1 {
+		c2 := site2[s]
+		if c1 < 0 || c2 < 0 || pairs[c1][c2] {
+			continue
+		}
+		pairs[c1][c2] = true
+		edges++
+		v1, v2 := int(c1), 4+int(c2)
+		for _, v := range []int{v1, v2} {
+			if !seen[v] {
+				seen[v] = true
+				vertices++
+				components++
+			}
+		}
+		if r1, r2 := find(v1), find(v2); r1 != r2 {
+			parent[r1] = r2
+			components--
+		}
+	}
+	return uint8(edges - vertices + components)
+}
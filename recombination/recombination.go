@@ -0,0 +1,119 @@
+// Package recombination implements tests detecting recombination in
+// nucleotide alignments: the pairwise homoplasy index (PHI, Bruen et al. 2006)
+// and the maximum chi-squared test (Maynard Smith 1992), with permutation p-values.
+package recombination
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+// encodeAlignment returns the nucleotides of the alignment, as a
+// [site][sequence] matrix: A=0, C=1, G=2, T=3, and -1 for other
+// characters (gaps, ambiguous nucleotides, etc.)
+func encodeAlignment(al align.Alignment) (codes [][]int8, err error) {
+	if al.Alphabet() != align.NUCLEOTIDS {
+		err = errors.New("recombination detection is only available for nucleotide alignments")
+		return
+	}
+	codes = make([][]int8, al.Length())
+	for site := range codes {
+		codes[site] = make([]int8, al.NbSequences())
+	}
+	i := 0
+	al.IterateChar(func(name string, seq []uint8) bool {
+		for site, c := range seq {
+			codes[site][i] = ntCode(c)
+		}
+		i++
+		return false
+	})
+	return
+}
+
+func ntCode(c uint8) int8 {
+	switch c {
+	case 'A', 'a':
+		return 0
+	case 'C', 'c':
+		return 1
+	case 'G', 'g':
+		return 2
+	case 'T', 't':
+		return 3
+	}
+	return -1
+}
+
+// stateCounts returns the number of occurences of each nucleotide at the site
+func stateCounts(site []int8) (counts [4]int) {
+	for _, c := range site {
+		if c >= 0 {
+			counts[c]++
+		}
+	}
+	return
+}
+
+// permutationTest computes the statistic of nbperms random permutations of
+// 0..n-1 in parallel (cpus), and returns the number of permutations whose
+// statistic is more extreme than obs (lower if lower is true, greater otherwise),
+// as well as the mean and variance of permuted statistics. Random permutations
+// are generated from seeds drawn from math/rand beforehand, so that the result
+// only depends on the global seed.
+func permutationTest(n, nbperms, cpus int, obs float64, lower bool, stat func(order []int) float64) (extreme int, mean, variance float64) {
+	seeds := make([]int64, nbperms)
+	for i := range seeds {
+		seeds[i] = rand.Int63()
+	}
+	stats := make([]float64, nbperms)
+
+	perms := make(chan int, 100)
+	go func() {
+		defer close(perms)
+		for i := range seeds {
+			perms <- i
+		}
+	}()
+
+	if cpus < 1 {
+		cpus = 1
+	}
+	var wg sync.WaitGroup
+	for cpu := 0; cpu < cpus; cpu++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perms {
+				stats[i] = stat(rand.New(rand.NewSource(seeds[i])).Perm(n))
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, s := range stats {
+		if (lower && s <= obs) || (!lower && s >= obs) {
+			extreme++
+		}
+		mean += s
+	}
+	if nbperms > 0 {
+		mean /= float64(nbperms)
+		for _, s := range stats {
+			variance += (s - mean) * (s - mean)
+		}
+		variance /= float64(nbperms)
+	}
+	return
+}
+
+func identity(n int) (order []int) {
+	order = make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	return
+}
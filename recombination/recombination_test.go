@@ -0,0 +1,149 @@
+package recombination
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/align"
+)
+
+func checkValue(t *testing.T, name string, v, exp float64) {
+	if math.Abs(v-exp) > 1e-10 {
+		t.Errorf("%s should be %f, not %f", name, exp, v)
+	}
+}
+
+func TestIncompatibility(t *testing.T) {
+	tests := []struct {
+		site1, site2 []int8
+		exp          uint8
+	}{
+		{[]int8{0, 0, 1, 1}, []int8{0, 0, 1, 1}, 0},
+		{[]int8{0, 0, 1, 1}, []int8{0, 1, 0, 1}, 1},
+		{[]int8{0, 0, 1, 1}, []int8{0, 1, 0, -1}, 0},
+		{[]int8{0, 1, 2, 3, 0, 1}, []int8{0, 1, 2, 3, 1, 0}, 1},
+		{[]int8{0, 0, 1, 1, 0, 1}, []int8{0, 1, 0, 1, 2, 2}, 2},
+	}
+	for _, test := range tests {
+		if inc := incompatibility(test.site1, test.site2); inc != test.exp {
+			t.Errorf("Incompatibility between %v and %v should be %d, not %d", test.site1, test.site2, test.exp, inc)
+		}
+	}
+}
+
+func TestPhi(t *testing.T) {
+	// Informative sites: 0 (AACC), 1 (ACAC) and 3 (AACC)
+	al := align.NewAlign(align.NUCLEOTIDS)
+	al.AddSequence("s1", "AAAAA", "")
+	al.AddSequence("s2", "ACAA-", "")
+	al.AddSequence("s3", "CAACC", "")
+	al.AddSequence("s4", "CCCCC", "")
+
+	res, err := Phi(al, 100, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.NbSites != 3 {
+		t.Errorf("Number of informative sites should be 3, not %d", res.NbSites)
+	}
+	// All pairs in the window: PHI does not depend on site order
+	checkValue(t, "Phi", res.Phi, 2./3.)
+	checkValue(t, "Phi permutation mean", res.PermMean, 2./3.)
+	checkValue(t, "Phi permutation variance", res.PermVar, 0)
+	checkValue(t, "Phi p-value", res.PValue, 1)
+
+	if res, err = Phi(al, 1, 10, 2); err != nil {
+		t.Fatal(err)
+	}
+	checkValue(t, "Phi (window 1)", res.Phi, 1)
+}
+
+func TestChi2Table(t *testing.T) {
+	checkValue(t, "chi2", chi2Table(10, 0, 10, 10), 20)
+	checkValue(t, "chi2", chi2Table(5, 0, 5, 5), 10)
+	checkValue(t, "chi2", chi2Table(10, 5, 10, 5), 0)
+	checkValue(t, "chi2", chi2Table(0, 0, 10, 5), 0)
+}
+
+func maxChiAlign() align.Alignment {
+	al := align.NewAlign(align.NUCLEOTIDS)
+	al.AddSequence("s1", "G"+strings.Repeat("A", 20), "")
+	al.AddSequence("s2", "G"+strings.Repeat("A", 10)+strings.Repeat("C", 10), "")
+	al.AddSequence("s3", "G"+strings.Repeat("C", 10)+strings.Repeat("A", 10), "")
+	return al
+}
+
+func TestMaxChi2(t *testing.T) {
+	res, err := MaxChi2(maxChiAlign(), 20, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.NbSites != 20 {
+		t.Errorf("Number of polymorphic sites should be 20, not %d", res.NbSites)
+	}
+	checkValue(t, "Max chi2", res.MaxChi2, 20)
+	if res.Seq1 != 0 || res.Seq2 != 1 || res.Site != 11 {
+		t.Errorf("Max chi2 should be between sequences 0 and 1 at site 11, not %d and %d at site %d", res.Seq1, res.Seq2, res.Site)
+	}
+	if res.PValue <= 0 || res.PValue > 1 {
+		t.Errorf("Max chi2 p-value should be in ]0,1], not %f", res.PValue)
+	}
+}
+
+func TestMaxChi2Scan(t *testing.T) {
+	bps, err := MaxChi2Scan(maxChiAlign(), 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bps) != 11 {
+		t.Fatalf("Scan should give 11 breakpoints, not %d", len(bps))
+	}
+	for b, bp := range bps {
+		if bp.Site != b+6 {
+			t.Errorf("Breakpoint %d should be at site %d, not %d", b, b+6, bp.Site)
+		}
+	}
+	checkValue(t, "Scan max chi2", bps[5].Chi2, 10)
+	if bps[5].Seq1 != 0 || bps[5].Seq2 != 1 {
+		t.Errorf("Scan max chi2 should be between sequences 0 and 1, not %d and %d", bps[5].Seq1, bps[5].Seq2)
+	}
+	for b, bp := range bps {
+		if bp.Chi2 > bps[5].Chi2 {
+			t.Errorf("Breakpoint %d should not have a chi2 (%f) greater than the real breakpoint", b, bp.Chi2)
+		}
+	}
+}
+
+func TestPermutationReproducibility(t *testing.T) {
+	al, err := align.RandomAlignment(align.NUCLEOTIDS, 300, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rand.Seed(10)
+	res1, err := Phi(al, 100, 50, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rand.Seed(10)
+	res2, err := Phi(al, 100, 50, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res1 != res2 {
+		t.Errorf("PHI results should not depend on the number of threads: %v vs. %v", res1, res2)
+	}
+}
+
+func TestProtein(t *testing.T) {
+	al := align.NewAlign(align.AMINOACIDS)
+	al.AddSequence("s1", "EFLP", "")
+	al.AddSequence("s2", "EFLP", "")
+	if _, err := Phi(al, 100, 10, 1); err == nil {
+		t.Errorf("PHI test should not be available for amino acid alignments")
+	}
+	if _, err := MaxChi2(al, 10, 1); err == nil {
+		t.Errorf("Max chi2 test should not be available for amino acid alignments")
+	}
+}
@@ -0,0 +1,355 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/models"
+	"github.com/evolbioinfo/goalign/tree"
+)
+
+// Simulator simulates the evolution of sequences along a tree,
+// under a substitution model, with optional gamma rate heterogeneity,
+// invariant sites and indels.
+type Simulator struct {
+	model    models.Model
+	pi       []float64 // Stationary frequencies of the model (root sequence)
+	alphabet int       // align.NUCLEOTIDS or align.AMINOACIDS
+	alpha    float64   // Gamma shape parameter
+	ncat     int       // Number of gamma categories (0: no gamma)
+	pinv     float64   // Proportion of invariant sites
+	indel    float64   // Indel rate, relative to the substitution rate
+	indelLen float64   // Mean indel length (geometric distribution)
+}
+
+// A residue of a simulated sequence
+type residue struct {
+	column int   // Index of the column of the true alignment
+	cat    int   // Rate category (-1: invariant)
+	state  uint8 // Index of the character in the alphabet
+}
+
+// NewSimulator returns a simulator with the given substitution model, stationary
+// frequencies of the model, and alphabet (align.NUCLEOTIDS or align.AMINOACIDS).
+//
+// The model must be initialized and normalized (1 substitution per site per unit of branch length).
+func NewSimulator(model models.Model, pi []float64, alphabet int) (s *Simulator, err error) {
+	var sum float64
+
+	if len(pi) != model.NState() {
+		err = fmt.Errorf("there should be %d frequencies, not %d", model.NState(), len(pi))
+		return
+	}
+	if (alphabet == align.NUCLEOTIDS && model.NState() != 4) || (alphabet == align.AMINOACIDS && model.NState() != 20) {
+		err = fmt.Errorf("model states do not correspond to the alphabet")
+		return
+	}
+	for _, p := range pi {
+		if p < 0 {
+			err = fmt.Errorf("frequencies must be positive")
+			return
+		}
+		sum += p
+	}
+	if math.Abs(sum-1.0) > 1.e-3 {
+		err = fmt.Errorf("frequencies must sum to 1 (%f)", sum)
+		return
+	}
+	s = &Simulator{
+		model:    model,
+		pi:       pi,
+		alphabet: alphabet,
+	}
+	return
+}
+
+// SetGamma sets a discrete gamma rate heterogeneity across sites,
+// with the given shape parameter and number of categories.
+// If ncat < 2, the rate heterogeneity is removed.
+func (s *Simulator) SetGamma(alpha float64, ncat int) (err error) {
+	if ncat < 2 {
+		s.ncat = 0
+		return
+	}
+	if alpha <= 0 {
+		err = fmt.Errorf("gamma shape parameter must be > 0")
+		return
+	}
+	s.alpha = alpha
+	s.ncat = ncat
+	return
+}
+
+// SetInvariant sets the proportion of invariant sites
+func (s *Simulator) SetInvariant(pinv float64) (err error) {
+	if pinv < 0 || pinv >= 1 {
+		err = fmt.Errorf("proportion of invariant sites must be in [0,1[")
+		return
+	}
+	s.pinv = pinv
+	return
+}
+
+// SetIndels sets the indel rate (number of insertions+deletions per site per unit of
+// branch length, relative to the substitution rate), and the mean length of indels
+// (geometric distribution).
+func (s *Simulator) SetIndels(rate, meanlength float64) (err error) {
+	if rate < 0 {
+		err = fmt.Errorf("indel rate must be >= 0")
+		return
+	}
+	if rate > 0 && meanlength < 1 {
+		err = fmt.Errorf("mean indel length must be >= 1")
+		return
+	}
+	s.indel = rate
+	s.indelLen = meanlength
+	return
+}
+
+// Simulate simulates an alignment of the given root length along the given tree.
+//
+// The root sequence is drawn from the stationary frequencies, and each site is given
+// a rate category (gamma category, or invariant), such that the mean rate is 1.
+// Then sequences evolve along each branch: substitutions follow the transition
+// probabilities of the model, and indels (if any) occur afterwards, at uniformly
+// chosen positions, insertions being drawn from the stationary frequencies.
+//
+// The returned alignment contains tip sequences, named after tip names,
+// and inserted/deleted positions are aligned (true alignment).
+func (s *Simulator) Simulate(t *tree.Tree, length int) (al align.Alignment, err error) {
+	var rates []float64
+	var seqs map[*tree.Node][]residue = make(map[*tree.Node][]residue)
+	var columns []int // order of the columns in the true alignment
+	var ncolumns int
+	var root []residue
+	var tips []*tree.Node
+
+	if length <= 0 {
+		err = fmt.Errorf("sequence length must be > 0")
+		return
+	}
+	if t.Root() == nil {
+		err = fmt.Errorf("tree is empty")
+		return
+	}
+
+	rates = s.categoryRates()
+	root = make([]residue, length)
+	for i := range root {
+		root[i] = residue{i, s.drawCategory(), drawState(s.pi)}
+		columns = append(columns, i)
+	}
+	ncolumns = length
+	seqs[t.Root()] = root
+
+	t.PreOrder(func(n *tree.Node) bool {
+		var seq []residue
+		if n == t.Root() {
+			return true
+		}
+		if !n.HasLength() || n.Length() < 0 {
+			err = fmt.Errorf("all branches of the tree must have a positive length")
+			return false
+		}
+		if seq, err = s.evolve(seqs[n.Parent()], n.Length(), rates); err != nil {
+			return false
+		}
+		if s.indel > 0 {
+			seq = s.indels(seq, n.Length(), &columns, &ncolumns)
+		}
+		seqs[n] = seq
+		return true
+	})
+	if err != nil {
+		return
+	}
+
+	tips = t.Tips()
+	al = align.NewAlign(s.alphabet)
+	chars := al.AlphabetCharacters()
+	colindex := make([]int, ncolumns)
+	for i, c := range columns {
+		colindex[c] = i
+	}
+	for i, tip := range tips {
+		seq := make([]uint8, len(columns))
+		for j := range seq {
+			seq[j] = align.GAP
+		}
+		for _, r := range seqs[tip] {
+			seq[colindex[r.column]] = chars[r.state]
+		}
+		name := tip.Name()
+		if name == "" {
+			name = fmt.Sprintf("Tip%d", i)
+		}
+		if err = al.AddSequenceChar(name, seq, ""); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Rates of the gamma categories, normalized such that the mean rate over
+// all sites (including invariant sites) is 1
+func (s *Simulator) categoryRates() (rates []float64) {
+	rates = []float64{1.0}
+	if s.ncat > 1 {
+		rates = models.DiscreteGamma(s.alpha, s.ncat)
+	}
+	for i := range rates {
+		rates[i] /= (1.0 - s.pinv)
+	}
+	return
+}
+
+// Draws the rate category of a site: -1 if invariant
+func (s *Simulator) drawCategory() int {
+	if s.pinv > 0 && rand.Float64() < s.pinv {
+		return -1
+	}
+	if s.ncat > 1 {
+		return rand.Intn(s.ncat)
+	}
+	return 0
+}
+
+// Draws a state given the probability of each state
+func drawState(probas []float64) uint8 {
+	var cumul float64
+	r := rand.Float64()
+	for i, p := range probas {
+		cumul += p
+		if r < cumul {
+			return uint8(i)
+		}
+	}
+	return uint8(len(probas) - 1)
+}
+
+// Evolves the sequence along a branch of the given length (substitutions only)
+func (s *Simulator) evolve(parent []residue, length float64, rates []float64) (child []residue, err error) {
+	var pij *models.Pij
+	var probas [][][]float64 = make([][][]float64, len(rates))
+	var ns int = s.model.NState()
+
+	// Transition probabilities of each rate category
+	for c, r := range rates {
+		if pij, err = models.NewPij(s.model, length*r); err != nil {
+			return
+		}
+		probas[c] = make([][]float64, ns)
+		for i := 0; i < ns; i++ {
+			probas[c][i] = make([]float64, ns)
+			for j := 0; j < ns; j++ {
+				probas[c][i][j] = pij.Pij(i, j)
+			}
+		}
+	}
+
+	child = make([]residue, len(parent))
+	for i, r := range parent {
+		child[i] = r
+		if r.cat >= 0 {
+			child[i].state = drawState(probas[r.cat][r.state])
+		}
+	}
+	return
+}
+
+// Adds insertions and deletions to the sequence, evolving along a branch of the given length.
+//
+// The number of indels is drawn from a poisson distribution of mean indel rate * length * sequence length,
+// half of them being insertions and half deletions. New columns are added to the
+// true alignment columns, just after the column of the preceding residue.
+func (s *Simulator) indels(seq []residue, length float64, columns *[]int, ncolumns *int) []residue {
+	var nevents int = poisson(s.indel * length * float64(len(seq)+1))
+	var pos, l int
+
+	for e := 0; e < nevents; e++ {
+		l = geometric(s.indelLen)
+		if rand.Intn(2) == 0 {
+			// Insertion after position pos-1
+			pos = rand.Intn(len(seq) + 1)
+			prev := -1
+			if pos > 0 {
+				prev = seq[pos-1].column
+			}
+			ins := make([]residue, l)
+			for i := range ins {
+				ins[i] = residue{*ncolumns, s.drawCategory(), drawState(s.pi)}
+				*ncolumns++
+			}
+			insertColumns(columns, prev, ins)
+			seq = append(seq[:pos], append(ins, seq[pos:]...)...)
+		} else if len(seq) > 0 {
+			// Deletion starting at position pos
+			pos = rand.Intn(len(seq))
+			if pos+l > len(seq) {
+				l = len(seq) - pos
+			}
+			seq = append(seq[:pos:pos], seq[pos+l:]...)
+		}
+	}
+	return seq
+}
+
+// Inserts the columns of the inserted residues just after the column prev (-1: at the beginning)
+func insertColumns(columns *[]int, prev int, ins []residue) {
+	var idx int
+	if prev >= 0 {
+		for i, c := range *columns {
+			if c == prev {
+				idx = i + 1
+				break
+			}
+		}
+	}
+	newcols := make([]int, 0, len(*columns)+len(ins))
+	newcols = append(newcols, (*columns)[:idx]...)
+	for _, r := range ins {
+		newcols = append(newcols, r.column)
+	}
+	newcols = append(newcols, (*columns)[idx:]...)
+	*columns = newcols
+}
+
+// Draws a number from a poisson distribution of mean lambda
+func poisson(lambda float64) (k int) {
+	if lambda <= 0 {
+		return 0
+	}
+	if lambda > 30 {
+		// Normal approximation
+		k = int(math.Round(rand.NormFloat64()*math.Sqrt(lambda) + lambda))
+		if k < 0 {
+			k = 0
+		}
+		return
+	}
+	l := math.Exp(-lambda)
+	p := 1.0
+	for {
+		p *= rand.Float64()
+		if p <= l {
+			return
+		}
+		k++
+	}
+}
+
+// Draws a length >= 1 from a geometric distribution of the given mean
+func geometric(mean float64) (l int) {
+	l = 1
+	if mean <= 1 {
+		return
+	}
+	p := 1.0 / mean
+	for rand.Float64() > p {
+		l++
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package simulation
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/evolbioinfo/goalign/align"
+	"github.com/evolbioinfo/goalign/io/newick"
+	"github.com/evolbioinfo/goalign/models/dna"
+	"github.com/evolbioinfo/goalign/tree"
+)
+
+func parseTree(t *testing.T, nw string) *tree.Tree {
+	tr, err := newick.NewParser(strings.NewReader(nw)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func gtrSimulator(t *testing.T) *Simulator {
+	pi := []float64{0.1, 0.2, 0.3, 0.4}
+	m := dna.NewGTRModel()
+	if err := m.InitModel(1, 2, 1, 1, 2, 1, pi[0], pi[1], pi[2], pi[3]); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSimulator(m, pi, align.NUCLEOTIDS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSimulate(t *testing.T) {
+	rand.Seed(10)
+	s := gtrSimulator(t)
+	if err := s.SetGamma(0.5, 4); err != nil {
+		t.Fatal(err)
+	}
+	tr := parseTree(t, "((A:0.1,B:0.2):0.05,(C:0.3,D:0.1):0.2,E:0.5);")
+
+	al, err := s.Simulate(tr, 5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if al.NbSequences() != 5 {
+		t.Errorf("There should be 5 sequences, not %d", al.NbSequences())
+	}
+	if al.Length() != 5000 {
+		t.Errorf("Alignment length should be 5000, not %d", al.Length())
+	}
+	for i, n := range []string{"A", "B", "C", "D", "E"} {
+		if name, _ := al.GetSequenceNameById(i); name != n {
+			t.Errorf("Sequence %d should be named %s, not %s", i, n, name)
+		}
+	}
+	// Frequencies should be close to stationary frequencies
+	freqs := al.CharStats()
+	for i, c := range []uint8{'A', 'C', 'G', 'T'} {
+		f := float64(freqs[c]) / float64(5*5000)
+		if f < s.pi[i]-0.02 || f > s.pi[i]+0.02 {
+			t.Errorf("Frequency of %c should be close to %f, not %f", c, s.pi[i], f)
+		}
+	}
+}
+
+func TestSimulateNullBranches(t *testing.T) {
+	rand.Seed(10)
+	s := gtrSimulator(t)
+	if err := s.SetInvariant(0.5); err != nil {
+		t.Fatal(err)
+	}
+	tr := parseTree(t, "((A:0,B:0):0,C:0,D:0);")
+
+	al, err := s.Simulate(tr, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, _ := al.GetSequenceById(0)
+	al.IterateChar(func(name string, seq []uint8) bool {
+		if string(seq) != ref {
+			t.Errorf("Sequence %s should be identical to the root sequence", name)
+		}
+		return false
+	})
+}
+
+func TestSimulateIndels(t *testing.T) {
+	rand.Seed(10)
+	s := gtrSimulator(t)
+	if err := s.SetIndels(0.2, 3); err != nil {
+		t.Fatal(err)
+	}
+	tr := parseTree(t, "((A:0.1,B:0.2):0.05,(C:0.3,D:0.1):0.2,E:0.5);")
+
+	al, err := s.Simulate(tr, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if al.NbSequences() != 5 {
+		t.Errorf("There should be 5 sequences, not %d", al.NbSequences())
+	}
+	if al.Length() <= 300 {
+		t.Errorf("Alignment with insertions should be longer than the root sequence, length=%d", al.Length())
+	}
+	if gaps := al.CharStats()[align.GAP]; gaps == 0 {
+		t.Errorf("Alignment with indels should contain gaps")
+	}
+}
+
+func TestSimulateErrors(t *testing.T) {
+	s := gtrSimulator(t)
+	if _, err := s.Simulate(parseTree(t, "((A:0.1,B):0.05,C:0.2);"), 100); err == nil {
+		t.Errorf("Simulation should fail with undefined branch lengths")
+	}
+	if _, err := s.Simulate(parseTree(t, "((A:0.1,B:0.1):0.05,C:0.2);"), 0); err == nil {
+		t.Errorf("Simulation should fail with a null length")
+	}
+	if err := s.SetInvariant(1.0); err == nil {
+		t.Errorf("Proportion of invariant sites of 1 should return an error")
+	}
+	if _, err := NewSimulator(dna.NewJCModel(), []float64{0.5, 0.5}, align.NUCLEOTIDS); err == nil {
+		t.Errorf("Wrong number of frequencies should return an error")
+	}
+}
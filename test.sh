@@ -144,6 +144,26 @@ diff -q -b log expectedlog2
 rm -f expected result mapfile log expectedlog2
 
 
+echo "->goalign clean sites --stockholm ('.' gaps)"
+cat > input <<EOF
+# STOCKHOLM 1.0
+a AC..GT
+b ACT.GT
+c AC-AGT
+//
+EOF
+cat > expected <<EOF
+# STOCKHOLM 1.0
+a ACGT
+b ACGT
+c ACGT
+//
+EOF
+${GOALIGN} clean sites --stockholm -c 0.5 -q -i input > result
+diff -q -b result expected
+rm -f input expected result
+
+
 echo "->goalign clean sites --ends"
 cat > input <<EOF
 >Seq0000
@@ -359,9 +379,9 @@ ACCTACGGCTCTAGACAGCTGAAGTCCGGTTCCGAGCACTGTACGGAAACTTGAAAAGGCTCGACGGAGGCTTGTTCCGC
 AGAGTGGGACTATAACATAC
 EOF
 cat > expectedlog <<EOF
-[Warning] in cmd/cleanseqs.go (line 61), message: Alignment (0) #seqs before cleaning=10
-[Warning] in cmd/cleanseqs.go (line 62), message: Alignment (0) #seqs after cleaning=5
-[Warning] in cmd/cleanseqs.go (line 63), message: Alignment (0) removed sequences=5
+[Warning] in cmd/cleanseqs.go (line 64), message: Alignment (0) #seqs before cleaning=10
+[Warning] in cmd/cleanseqs.go (line 65), message: Alignment (0) #seqs after cleaning=5
+[Warning] in cmd/cleanseqs.go (line 66), message: Alignment (0) removed sequences=5
 EOF
 ${GOALIGN} clean seqs -i input > result 2>log
 diff -q -b result expected
@@ -438,9 +458,9 @@ ACCTACGGCTCTAGACAGCTGAAGTCCGGTTCCGAGCACTGTACGGAAACTTGAAAAGGCTCGACGGAGGCTTGTTCCGC
 AGAGTGGGACTATAACATAC
 EOF
 cat > expectedlog <<EOF
-[Warning] in cmd/cleanseqs.go (line 61), message: Alignment (0) #seqs before cleaning=10
-[Warning] in cmd/cleanseqs.go (line 62), message: Alignment (0) #seqs after cleaning=9
-[Warning] in cmd/cleanseqs.go (line 63), message: Alignment (0) removed sequences=1
+[Warning] in cmd/cleanseqs.go (line 64), message: Alignment (0) #seqs before cleaning=10
+[Warning] in cmd/cleanseqs.go (line 65), message: Alignment (0) #seqs after cleaning=9
+[Warning] in cmd/cleanseqs.go (line 66), message: Alignment (0) removed sequences=1
 EOF
 
 ${GOALIGN} clean seqs --char N --ignore-gaps -c 0.2 -i input > result 2>log
@@ -513,9 +533,9 @@ ACCTACGGCTCTAGACAGCTGAAGTCCGGTTCCGAGCACTGTACGGAAACTTGAAAAGGCTCGACGGAGGCTTGTTCCGC
 AGAGTGGGACTATAACATAC
 EOF
 cat > expectedlog <<EOF
-[Warning] in cmd/cleanseqs.go (line 61), message: Alignment (0) #seqs before cleaning=10
-[Warning] in cmd/cleanseqs.go (line 62), message: Alignment (0) #seqs after cleaning=9
-[Warning] in cmd/cleanseqs.go (line 63), message: Alignment (0) removed sequences=1
+[Warning] in cmd/cleanseqs.go (line 64), message: Alignment (0) #seqs before cleaning=10
+[Warning] in cmd/cleanseqs.go (line 65), message: Alignment (0) #seqs after cleaning=9
+[Warning] in cmd/cleanseqs.go (line 66), message: Alignment (0) removed sequences=1
 EOF
 ${GOALIGN} clean seqs --char GAP --ignore-n -c 0.2 -i input > result 2>log
 diff -q -b result expected
@@ -543,9 +563,9 @@ ACGT-
 ACGT-
 EOF
 cat > expectedlog <<EOF
-[Warning] in cmd/cleanseqs.go (line 61), message: Alignment (0) #seqs before cleaning=4
-[Warning] in cmd/cleanseqs.go (line 62), message: Alignment (0) #seqs after cleaning=3
-[Warning] in cmd/cleanseqs.go (line 63), message: Alignment (0) removed sequences=1
+[Warning] in cmd/cleanseqs.go (line 64), message: Alignment (0) #seqs before cleaning=4
+[Warning] in cmd/cleanseqs.go (line 65), message: Alignment (0) #seqs after cleaning=3
+[Warning] in cmd/cleanseqs.go (line 66), message: Alignment (0) removed sequences=1
 EOF
 
 ${GOALIGN} clean seqs --char A --ignore-n -c 0.5 -i input > result 2>log
@@ -574,9 +594,9 @@ ACGT-
 ACGT-
 EOF
 cat > expectedlog <<EOF
-[Warning] in cmd/cleanseqs.go (line 61), message: Alignment (0) #seqs before cleaning=4
-[Warning] in cmd/cleanseqs.go (line 62), message: Alignment (0) #seqs after cleaning=3
-[Warning] in cmd/cleanseqs.go (line 63), message: Alignment (0) removed sequences=1
+[Warning] in cmd/cleanseqs.go (line 64), message: Alignment (0) #seqs before cleaning=4
+[Warning] in cmd/cleanseqs.go (line 65), message: Alignment (0) #seqs after cleaning=3
+[Warning] in cmd/cleanseqs.go (line 66), message: Alignment (0) removed sequences=1
 EOF
 
 ${GOALIGN} clean seqs --char A --ignore-n --ignore-case -c 0.9 -i input > result 2>log
@@ -769,6 +789,38 @@ ${GOALIGN} random -n 5 -l 75 --seed 10 | ${GOALIGN} reformat phylip --no-block >
 diff -q -b result expected
 rm -f expected result mapfile
 
+echo "->goalign reformat phylip --line-width --block-size"
+cat > expected <<EOF
+   5   75
+Seq0000  GATTAATTTGCCGTAGGCCA GAATCTGAAGATCGAACACT
+Seq0001  TACTTTTTAAACACTTTTAC ATCGATGTCGGACCTAAGTA
+Seq0002  CTATTTTTCCGGTTGAAGGA CTCTAGAGCTGTAAAGGGTA
+Seq0003  AGCAAGGTTAAATACTCGGC AATGCCCCATGATCCCCCAA
+Seq0004  GAGTGGAGGCTTTATGGCAC AAGGTATTAGAGACTGAGGG
+
+   TTAAGTTTTCACTTCTAATG GAGAGGACTAGTTCA
+   TTGAGTACAACGGTGTATTC CAGCGGTGGAGAGGT
+   TGGCCATGTGCTAAGCGCGG GCGGATTGCTGTTGG
+   GGACAATAAGAGCGAAGTTA GAACAAATGAACCCC
+   GCACCCCGGCATGGTAAGCA GGAGCCATCGCGAAG
+EOF
+${GOALIGN} random -n 5 -l 75 --seed 10 | ${GOALIGN} reformat phylip --line-width 40 --block-size 20 > result
+diff -q -b result expected
+rm -f expected result mapfile
+
+echo "->goalign reformat fasta --line-width"
+cat > expected <<EOF
+>Seq0000
+GATTAATTTGCCGTAGGCCAGAATCTGAAGATCGAACACT
+TTAAGTTTTCACTTCTAATGGAGAGGACTAGTTCA
+>Seq0001
+TACTTTTTAAACACTTTTACATCGATGTCGGACCTAAGTA
+TTGAGTACAACGGTGTATTCCAGCGGTGGAGAGGT
+EOF
+${GOALIGN} random -n 2 -l 75 --seed 10 --line-width 40 > result
+diff -q -b result expected
+rm -f expected result
+
 
 echo "->goalign reformat phylip spaces tabs"
 cat > expected <<EOF
@@ -1127,6 +1179,92 @@ ${GOALIGN} reformat paml -i input.test -p > output.paml
 diff -q -b output.paml expected
 rm -f expected output.paml input.test
 
+echo "->goalign tree nj"
+cat > input <<EOF
+>A
+AAAAAAAAAA
+>B
+AAAAAAAACC
+>C
+CCAAAAAAAA
+>D
+CCCCAAAAAA
+EOF
+cat > expected <<EOF
+((A:0.1,B:0.1):0.1,(D:0.1,C:0.1):0.1);
+EOF
+cat > expected2 <<EOF
+((A,B)1,D,C);
+((A,B)1,D,C);
+EOF
+${GOALIGN} tree nj -m pdist --algo upgma -i input > result
+diff -q -b expected result
+${GOALIGN} tree nj -m pdist --algo nj -n 10 --seed 10 -i input | sed 's/:[-0-9.e]*//g' > result
+${GOALIGN} tree nj -m pdist --algo bionj -n 10 --seed 10 -i input | sed 's/:[-0-9.e]*//g' >> result
+diff -q -b expected2 result
+rm -f input expected expected2 result
+
+echo "->goalign tree nj --matrix"
+cat > input <<EOF
+4
+A	0	0.2	0.4	0.4
+B	0.2	0	0.4	0.4
+C	0.4	0.4	0	0.2
+D	0.4	0.4	0.2	0
+EOF
+cat > boot <<EOF
+4
+A	0	0.2	0.4	0.4
+B	0.2	0	0.4	0.4
+C	0.4	0.4	0	0.2
+D	0.4	0.4	0.2	0
+4
+A 0 0.1 0.3 0.3
+B 0.1 0 0.3 0.3
+C 0.3 0.3 0 0.1
+D 0.3 0.3 0.1 0
+4
+B	0	0.2	0.4	0.4
+A	0.2	0	0.4	0.4
+D	0.4	0.4	0	0.2
+C	0.4	0.4	0.2	0
+4
+A	0	0.4	0.2	0.4
+B	0.4	0	0.4	0.2
+C	0.2	0.4	0	0.4
+D	0.4	0.2	0.4	0
+EOF
+cat > expected <<EOF
+((A:0.1,B:0.1):0.1,(D:0.1,C:0.1):0.1);
+EOF
+cat > expected2 <<EOF
+((A,B)0.75,D,C);
+((A,B)0.75,D,C);
+EOF
+cat > expected3 <<EOF
+((A,B),D,C);
+((A,B),D,C);
+((B,A),C,D);
+((A,C),B,D);
+EOF
+${GOALIGN} tree nj --matrix input --algo upgma > result
+diff -q -b expected result
+${GOALIGN} tree nj --matrix input --boot-matrix boot --algo nj --boot-output bootres | sed 's/:[-0-9.e]*//g' > result
+${GOALIGN} tree nj --matrix input --boot-matrix boot --algo bionj | sed 's/:[-0-9.e]*//g' >> result
+diff -q -b expected2 result
+sed 's/:[-0-9.e]*//g' bootres > result
+diff -q -b expected3 result
+# Bootstrap matrices from build distboot give the same supports as tree nj -n
+cat > input.nw <<EOF
+(((A:0.05,B:0.05):0.02,(C:0.05,D:0.05):0.01):0.01,(E:0.05,F:0.05):0.02);
+EOF
+${GOALIGN} simulate --tree input.nw -m "K80{2}" -l 100 --seed 10 > input
+${GOALIGN} tree nj -m k2p -n 20 --seed 10 -i input > expected
+${GOALIGN} build distboot -m k2p -n 20 --seed 10 -i input | ${GOALIGN} tree nj -m k2p -i input --boot-matrix - > result
+diff -q -b expected result
+rm -f input input.nw boot bootres expected expected2 expected3 result
+
+
 echo "->goalign compute distance -m f81"
 cat > expected <<EOF
 5
@@ -1141,6 +1279,296 @@ diff -q -b result expected
 rm -f expected result mapfile
 
 
+echo "->goalign compute distance -m tn84"
+cat > expected <<EOF
+5
+Tip4	0.000000000000	0.175934352466	0.194557221041	0.235391100034	0.237284012581
+Tip0	0.175934352466	0.000000000000	0.082706044713	0.129163643650	0.143920946605
+Tip3	0.194557221041	0.082706044713	0.000000000000	0.071490454040	0.087358528569
+Tip2	0.235391100034	0.129163643650	0.071490454040	0.000000000000	0.112365684729
+Tip1	0.237284012581	0.143920946605	0.087358528569	0.112365684729	0.000000000000
+EOF
+${GOALIGN} compute distance -m tn84 -i ${TESTDATA}/test_distance.phy.gz -p > result
+diff -q -b result expected
+${GOALIGN} compute distance -m tn82 -i ${TESTDATA}/test_distance.phy.gz -p > result
+diff -q -b result expected
+rm -f expected result
+
+
+echo "->goalign compute distance -m logdet"
+cat > expected <<EOF
+5
+Tip4	0.000000000000	0.178497398664	0.193033262631	0.234636442323	0.235954237405
+Tip0	0.178497398664	0.000000000000	0.082081828519	0.128693618834	0.143401463527
+Tip3	0.193033262631	0.082081828519	0.000000000000	0.070784926483	0.087436647726
+Tip2	0.234636442323	0.128693618834	0.070784926483	0.000000000000	0.111936600219
+Tip1	0.235954237405	0.143401463527	0.087436647726	0.111936600219	0.000000000000
+EOF
+${GOALIGN} compute distance -m logdet -i ${TESTDATA}/test_distance.phy.gz -p > result
+diff -q -b result expected
+rm -f expected result
+
+
+echo "->goalign compute distance -m logdet (aa)"
+cat > input <<EOF
+>s1
+EEEFFLEFLP
+>s2
+FLPLPPEFLP
+>s3
+EEEFFLEFLP
+EOF
+cat > expected <<EOF
+3
+s1	0.000000000000	0.794513457587	0.000000000000
+s2	0.794513457587	0.000000000000	0.000000000000
+s3	0.000000000000	0.000000000000	0.000000000000
+EOF
+${GOALIGN} compute distance -m logdet -i input --range1 0:0 --range2 1:2 > result
+diff -q -b result expected
+rm -f input expected result
+
+
+echo "->goalign compute distance -m cprev,rtrev,vt,blosum62,flu,mtmam,mtart,hivw"
+cat > input.nw <<EOF
+((A:0.1,B:0.2):0.05,(C:0.1,D:0.3):0.1,E:0.2);
+EOF
+for m in cprev rtrev vt blosum62 flu mtmam mtart hivw
+do
+    # Alignments simulated with the model: distances are finite and > 0
+    echo "${m} ok" > expected
+    ${GOALIGN} simulate --tree input.nw -m "$(echo ${m} | tr a-z A-Z)" -l 500 --seed 10 \
+        | ${GOALIGN} compute distance -m ${m} \
+        | awk -v m=${m} 'NR>1{for(i=2;i<=NF;i++){if((i-1!=NR-1 && !($i>0 && $i<10)) || (i-1==NR-1 && $i!=0)){ko=1}}}END{print m, (NR==6 && !ko)?"ok":"ko"}' > result
+    diff -q -b result expected
+done
+rm -f input.nw expected result
+
+
+echo "->goalign compute dnds"
+cat > input <<EOF
+>s1
+GGGGGGGGGGGGGGGGGGGGGGGGGGGGGGTTTTTTTTTTTTTTTTTTTTTTTTTTTTTT
+>s2
+GGAAGGGGGGGGGGGGGGGGGGGGGGGGGGTTTTTTTTTTTTTTTTTTTTTTTTTTTTTT
+>s3
+GGGGGGGGGGGGGGGGGGGGGGGGGGGGGGTTTTTTTTTTTTTTTTTTTTTTTTTTTTTT
+EOF
+cat > expected <<EOF
+3
+s1	0.000000000000	0.270520013456	NaN
+s2	0.270520013456	0.000000000000	0.270520013456
+s3	NaN	0.270520013456	0.000000000000
+EOF
+cat > expected.dn <<EOF
+3
+s1	0.000000000000	0.021662155612	0.000000000000
+s2	0.021662155612	0.000000000000	0.021662155612
+s3	0.000000000000	0.021662155612	0.000000000000
+EOF
+cat > expected.ds <<EOF
+3
+s1	0.000000000000	0.080075981569	0.000000000000
+s2	0.080075981569	0.000000000000	0.080075981569
+s3	0.000000000000	0.080075981569	0.000000000000
+EOF
+${GOALIGN} compute dnds -m ng86 -i input --dn-output result.dn --ds-output result.ds > result
+diff -q -b result expected
+diff -q -b result.dn expected.dn
+diff -q -b result.ds expected.ds
+rm -f input expected expected.dn expected.ds result result.dn result.ds
+
+
+echo "->goalign compute sitescores"
+cat > input <<EOF
+>s1
+AAC-T-
+>s2
+AGT-TA
+>s3
+ACG-TA
+>s4
+A-A-TA
+EOF
+cat > expected <<EOF
+Alignment	Site	RefSite	Entropy	JSD	GapFraction	Informative	Conservation	CodonPos
+0	0	0	0.000	0.311	0.000	0	identical	1
+0	1	1	1.099	0.176	0.250	0	not-conserved	2
+0	2	2	1.386	0.071	0.000	0	not-conserved	3
+0	3	NA	NaN	0.000	1.000	0	not-conserved	1
+0	4	3	0.000	0.517	0.000	0	identical	2
+0	5	NA	0.000	0.233	0.250	0	not-conserved	3
+EOF
+cat > expected.bed <<EOF
+#Chrom	Start	End	Entropy	JSD	GapFraction	Informative	Conservation
+s1	0	1	0.000	0.311	0.000	0	identical
+s1	1	2	1.099	0.176	0.250	0	not-conserved
+s1	2	3	1.386	0.071	0.000	0	not-conserved
+s1	3	4	0.000	0.517	0.000	0	identical
+EOF
+${GOALIGN} compute sitescores -i input --ref-seq s1 --codon > result
+diff -q -b result expected
+${GOALIGN} compute sitescores -i input --ref-seq s1 --bed > result
+diff -q -b result expected.bed
+rm -f input expected expected.bed result
+
+
+echo "->goalign recombdetect"
+cat > input <<EOF
+>s1
+GAAAAAAAAAAAAAAAAAAAA
+>s2
+GAAAAAAAAAACCCCCCCCCC
+>s3
+GCCCCCCCCCCAAAAAAAAAA
+>s4
+GCCCCCCCCCCCCCCCCCCCC
+EOF
+cat > expected <<EOF
+Alignment	InformativeSites	Phi	PhiMean	PhiVar	PhiPValue	MaxChi2	MaxChi2Seq1	MaxChi2Seq2	MaxChi2Site	MaxChi2PValue
+0	20	0.0811	0.5338	0.003059	0.04762	20.0000	s1	s2	11	0.09091
+EOF
+cat > expected.scan <<EOF
+Alignment	Site	MaxChi2	Seq1	Seq2
+0	6	0.0000	s1	s2
+0	7	1.1111	s1	s2
+0	8	2.5000	s1	s2
+0	9	4.2857	s1	s2
+0	10	6.6667	s1	s2
+0	11	10.0000	s1	s2
+0	12	6.6667	s1	s2
+0	13	4.2857	s1	s2
+0	14	2.5000	s1	s2
+0	15	1.1111	s1	s2
+0	16	0.0000	s1	s2
+EOF
+${GOALIGN} recombdetect -i input --perms 20 --maxchi-perms 10 --phi-window 2 --scan-window 5 --scan-output result.scan --seed 10 -t 4 > result
+diff -q -b result expected
+diff -q -b result.scan expected.scan
+rm -f input expected expected.scan result result.scan
+
+
+echo "->goalign draw terminal"
+cat > input <<EOF
+>s1
+ACGTACGTAC-T
+>s2
+ACGTACCTAC-A
+>s3
+ACGAACGTAC-A
+EOF
+cat > expected <<EOF
+                    10
+              --+----|
+s1            GTACGTAC 10
+s2            GTACCTAC 10
+s3            GAACGTAC 10
+Consensus     GTACGTAC
+Conservation  * ** ***
+
+
+              --
+s1            -T 12
+s2            -A 12
+s3            -A 12
+Consensus     -A
+Conservation
+EOF
+${GOALIGN} draw terminal -i input --scheme none --width 8 -s 2 > result
+diff -q -b result expected
+${GOALIGN} draw svg -i input -o result.svg
+${GOALIGN} draw png -i input -o result.png
+rm -f input expected result result.svg result.png
+
+
+echo "->goalign draw logo"
+cat > input <<EOF
+>s1
+ACGTACGTAC-T
+>s2
+ACGTACCTAC-A
+>s3
+ACGAACGTAC-A
+EOF
+${GOALIGN} draw logo -i input --ref-seq s1 -s 8 -o result.svg
+grep -c "rotate(-90)\" text-anchor=\"end\" font-size=\"10\">\(9\|10\|-\|11\)<" result.svg > result
+echo "4" > expected
+diff -q -b result expected
+rm -f input expected result result.svg
+
+
+echo "->goalign dedup --fastq"
+cat > input <<EOF
+@r1
+ACGTACGTACGGA
++
+!!IIIII#IIII!
+@r2
+GGGTTTCCAAATG
++
+IIIIIIIIIII!!
+@r3
+ACGTACGTACGGA
++
+IIIIIIIIIIIII
+EOF
+cat > expected <<EOF
+@r1
+ACGTACGTACGGA
++
+!!IIIII#IIII!
+@r2
+GGGTTTCCAAATG
++
+IIIIIIIIIII!!
+EOF
+cat > expected.id <<EOF
+r1,r3
+r2
+EOF
+cat > expected.rev <<EOF
+@r1
+CCGTNCGTAC
++
+IIII#IIIII
+@r2
+TTTGGAAACCC
++
+IIIIIIIIIII
+@r3
+TCCGTACGTACGT
++
+IIIIIIIIIIIII
+EOF
+${GOALIGN} dedup --unaligned --fastq -i input -l result.id > result
+${GOALIGN} revcomp --unaligned --fastq --fastq-trim-qual 10 --fastq-mask-qual 20 -i input > result.rev
+diff -q -b result expected
+diff -q -b result.id expected.id
+diff -q -b result.rev expected.rev
+rm -f input expected expected.id expected.rev result result.id result.rev
+
+echo "->goalign revcomp --fastq --fastq-trim-qual (fully trimmed reads)"
+cat > input <<EOF
+@r1
+ACGTACGTAC
++
+IIIIIIIIII
+@r2
+GGGTTTCCAA
++
+!!!!!!!!!!
+EOF
+cat > expected <<EOF
+@r1
+ACGTACGTAC
++
+IIIIIIIIII
+EOF
+${GOALIGN} revcomp --unaligned --fastq --fastq-trim-qual 20 -i input 2>/dev/null | ${GOALIGN} revcomp --unaligned --fastq > result
+diff -q -b result expected
+rm -f input expected result
+
+
 echo "->goalign compute distance -m rawdist"
 cat > expected <<EOF
 5
@@ -1642,6 +2070,45 @@ diff -q -b result expected
 rm -f expected result input1 input2
 
 
+echo "->goalign concat --out-partition --out-charsets --fill-char"
+cat > gene1.fa <<EOF
+>s1
+ACGTAC
+>s2
+ACGTAA
+EOF
+cat > gene2.fa <<EOF
+>s2
+GGGC
+>s3
+GGGT
+EOF
+cat > expected <<EOF
+>s1
+ACGTAC????
+>s2
+ACGTAAGGGC
+>s3
+??????GGGT
+EOF
+cat > expected.part <<EOF
+GTR+G,gene1=1-6
+HKY,gene2=7-10
+EOF
+cat > expected.nex <<EOF
+#NEXUS
+begin sets;
+charset gene1 = 1-6;
+charset gene2 = 7-10;
+end;
+EOF
+${GOALIGN} concat -i none gene1.fa gene2.fa --fill-char '?' --model GTR+G,HKY --out-partition result.part --out-charsets result.nex > result
+diff -q -b result expected
+diff -q -b result.part expected.part
+diff -q -b result.nex expected.nex
+rm -f expected expected.part expected.nex result result.part result.nex gene1.fa gene2.fa
+
+
 echo "->goalign divide"
 cat > expected <<EOF
 >Seq0000
@@ -3117,6 +3584,19 @@ ${GOALIGN} translate -i input --phase -1 --unaligned --genetic-code mitov -o res
 diff -q -b expected result
 rm -f input expected result
 
+echo "->goalign translate (ncbi code 4)"
+cat > input <<EOF
+>s1
+ATGTGGTGATAA
+EOF
+cat > expected <<EOF
+>s1
+MWW*
+EOF
+${GOALIGN} translate -i input --phase 0 --unaligned --genetic-code 4 -o result
+diff -q -b expected result
+rm -f input expected result
+
 echo "->goalign translate IUPAC"
 cat > input <<EOF
 >allcodons
@@ -3899,6 +4379,273 @@ diff -q -b expected result
 rm -f input expected result
 
 
+echo "->goalign sw global / 4"
+cat > input <<EOF
+>ref
+GGGGGACGTACGTTTGACCATTTTT
+>amp
+ACGTACGTTTGACCA
+EOF
+cat > expected <<EOF
+>ref
+GGGGGACGTACGTTTGACCATTTTT
+>amp
+-----ACGTACGTTTGACCA-----
+EOF
+
+${GOALIGN} sw -i input -o result --mode global
+diff -q -b expected result
+${GOALIGN} sw -i input -o result --mode semiglobal
+diff -q -b expected result
+rm -f input expected result
+
+
+echo "->goalign align"
+cat > input <<EOF
+>s1
+ACGTACGTTTGACCATTGACAGT
+>s2
+ACGTCGTTTGACCATTGACAGT
+>s3
+ACGTACGTTTGACCATGACAGT
+>s4
+GGACGTACGTTTGACCATTGACAGTAA
+>s5
+ACGTACGTTTAACCATTGACAGT
+EOF
+cat > expected <<EOF
+>s1
+--ACGTACGTTTGACCATTGACAGT--
+>s2
+--ACGT-CGTTTGACCATTGACAGT--
+>s3
+--ACGTACGTTTGACCA-TGACAGT--
+>s4
+GGACGTACGTTTGACCATTGACAGTAA
+>s5
+--ACGTACGTTTAACCATTGACAGT--
+EOF
+
+${GOALIGN} align -i input -o result
+diff -q -b expected result
+${GOALIGN} align -i input -o result --guide pairwise -t 2
+diff -q -b expected result
+rm -f input expected result
+
+
+echo "->goalign add"
+cat > input <<EOF
+>r1
+ACGTACGTTTGACC-ATTGACAGT
+>r2
+ACGT-CGTTTGACC-ATTGACAGT
+>r3
+ACGTACGTTTGACCAATTGACAGT
+EOF
+cat > input2 <<EOF
+>n1
+ACGTACGTTTGGGGGGACCATTGACAGT
+>n2
+CGTTTGACCATTGA
+EOF
+cat > expected <<EOF
+>r1
+ACGTACGTTT-----GACC-ATTGACAGT
+>r2
+ACGT-CGTTT-----GACC-ATTGACAGT
+>r3
+ACGTACGTTT-----GACCAATTGACAGT
+>n1
+ACGTACGTTTGGGGGGACC-ATTGACAGT
+>n2
+-----CGTTT-----GACC-ATTGA----
+EOF
+cat > expected2 <<EOF
+>r1
+ACGTACGTTTGACC-ATTGACAGT
+>r2
+ACGT-CGTTTGACC-ATTGACAGT
+>r3
+ACGTACGTTTGACCAATTGACAGT
+>n1
+ACGTACGTTTGACC-ATTGACAGT
+>n2
+-----CGTTTGACC-ATTGA----
+EOF
+
+${GOALIGN} add -i input -s input2 -o result
+diff -q -b expected result
+${GOALIGN} add -i input -s input2 -o result --keep-length
+diff -q -b expected2 result
+rm -f input input2 expected expected2 result
+
+
+echo "->goalign vcf"
+cat > input <<EOF
+>ref
+ACGT--ACGTACGT
+>s1
+ACGTTTACGTACGT
+>s2
+ACRT--AC--ACGT
+>s3
+-CGT--ACGTNCGA
+>s4
+ACGT--ACGTACGY
+EOF
+cat > expected <<EOF
+##fileformat=VCFv4.2
+##source=goalign
+##contig=<ID=ref,length=12>
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	s1	s2	s3	s4
+ref	1	.	AC	C	.	PASS	.	GT	0	0	1	0
+ref	3	.	G	A	.	PASS	.	GT	0	0/1	0	0
+ref	4	.	T	TTT	.	PASS	.	GT	1	0	0	0
+ref	6	.	CGT	C	.	PASS	.	GT	0	1	0	0
+ref	12	.	T	A,C	.	PASS	.	GT	0	0	1	0/2
+EOF
+${GOALIGN} vcf -i input --ref-sequence ref -o result
+diff -q -b expected result
+rm -f input expected result
+
+
+echo "->goalign vcf (ambiguous reference)"
+cat > input <<EOF
+>ref
+ACRTACGT
+>s1
+ACATACGT
+>s2
+ACNTACGA
+EOF
+cat > expected <<EOF
+##fileformat=VCFv4.2
+##source=goalign
+##contig=<ID=ref,length=8>
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	s1	s2
+ref	3	.	N	A	.	PASS	.	GT	1	.
+ref	8	.	T	A	.	PASS	.	GT	0	1
+EOF
+${GOALIGN} vcf -i input --ref-sequence ref -o result
+diff -q -b expected result
+rm -f input expected result
+
+
+echo "->goalign reformat stockholm"
+cat > input <<EOF
+# STOCKHOLM 1.0
+#=GF ID   test_family
+#=GS s1/1-10 AC P12345.1
+
+s1/1-10    ACDEF.GHIK
+#=GR s1/1-10 SS ---HHH-HH-
+s2/3-12    ACDEFLGHIK
+#=GC SS_cons   <<<...>>>.
+
+s1/1-10    LM
+#=GR s1/1-10 SS EE
+s2/3-12    LN
+#=GC SS_cons   ..
+//
+# STOCKHOLM 1.0
+a ACGT
+b ACGA
+//
+EOF
+cat > expected <<EOF
+# STOCKHOLM 1.0
+#=GF ID test_family
+#=GS s1/1-10 AC P12345.1
+
+s1/1-10         ACDEF-GHIKLM
+#=GR s1/1-10 SS ---HHH-HH-EE
+s2/3-12         ACDEFLGHIKLN
+#=GC SS_cons    <<<...>>>...
+//
+# STOCKHOLM 1.0
+a ACGT
+b ACGA
+//
+EOF
+cat > expected2 <<EOF
+>s1/1-10
+ACDEF-GHIKLM
+>s2/3-12
+ACDEFLGHIKLN
+EOF
+${GOALIGN} reformat stockholm -i input --stockholm -o result
+diff -q -b expected result
+${GOALIGN} reformat fasta -i input --auto-detect -o result
+diff -q -b expected2 result
+rm -f input expected expected2 result
+
+
+echo "->goalign split nexus charsets"
+cat > input <<EOF
+#NEXUS
+begin data;
+dimensions ntax=2 nchar=8;
+format datatype=dna interleave=yes;
+matrix
+s1 AAAA
+s2 AAAA
+
+s1 CCCC
+s2 CCCG
+;
+end;
+begin sets;
+charset p1 = 1-4;
+charset p2 = 5-8;
+end;
+EOF
+cat > expected <<EOF
+#NEXUS
+begin data;
+dimensions ntax=2 nchar=8;
+format datatype=dna;
+matrix
+s1 AAAACCCC
+s2 AAAACCCG
+;
+end;
+begin sets;
+charset p1 = 1-4;
+charset p2 = 5-8;
+end;
+EOF
+cat > expected.1 <<EOF
+#NEXUS
+begin data;
+dimensions ntax=2 nchar=4;
+format datatype=dna;
+matrix
+s1 AAAA
+s2 AAAA
+;
+end;
+EOF
+cat > expected.2 <<EOF
+#NEXUS
+begin data;
+dimensions ntax=2 nchar=4;
+format datatype=dna;
+matrix
+s1 CCCC
+s2 CCCG
+;
+end;
+EOF
+${GOALIGN} reformat nexus -x -i input -o result
+diff -q -b expected result
+${GOALIGN} split -x -i input -o result_
+diff -q -b expected.1 result_p1.nx
+diff -q -b expected.2 result_p2.nx
+rm -f input expected expected.1 expected.2 result result_p1.nx result_p2.nx
+
+
 echo "->goalign orf"
 cat > input <<EOF
 >allcodons
@@ -3940,6 +4687,27 @@ ${GOALIGN} orf -i input -o result --reverse
 diff -q -b expected result
 rm -f input expected result
 
+echo "->goalign orf alt-starts"
+cat > input <<EOF
+>s
+CCTTGAAACCCGGGTAAATGAAATAA
+EOF
+
+cat > expected <<EOF
+>s
+TTGAAACCCGGGTAA
+EOF
+cat > expected2 <<EOF
+>s
+ATGAAATAA
+EOF
+
+${GOALIGN} orf -i input -o result --alt-starts
+diff -q -b expected result
+${GOALIGN} orf -i input -o result
+diff -q -b expected2 result
+rm -f input expected expected2 result
+
 echo "->goalign mask / prot"
 cat > input <<EOF
    10   20
@@ -4573,6 +5341,56 @@ diff -q -b  wres wexp
 
 rm -f input expected result wexp wres
 
+echo "->goalign compress / clean sites (fasta, packed)"
+
+cat > input <<EOF
+>1
+GG-GNNRRAA
+>2
+TTCTAA--AA
+>3
+GG-GNNRRAC
+EOF
+
+cat > expected <<EOF
+>1
+-AAGNR
+>2
+CAATA-
+>3
+-ACGNR
+EOF
+
+cat > wexp <<EOF
+1
+1
+1
+3
+2
+2
+EOF
+
+cat > expected2 <<EOF
+>1
+GGGNNAA
+>2
+TTTAAAA
+>3
+GGGNNAC
+EOF
+
+${GOALIGN} compress -i input -o result --weight-out wres
+diff -q -b  expected result
+diff -q -b  wres wexp
+${GOALIGN} clean sites -c 0.3 -i input -o result -q
+diff -q -b  expected2 result
+# Lower case characters are not packed: same result with the row-major alignment
+sed 's/GG-GNNRRAC/gg-gnnrrac/' input | ${GOALIGN} compress -o result --weight-out wres
+sed 's/^-ACGNR/-acgnr/' expected | diff -q -b - result
+diff -q -b  wres wexp
+
+rm -f input expected expected2 result wexp wres
+
 
 
 echo "->goalign split"
@@ -4624,6 +5442,63 @@ diff -q -b exp_p1 p1.fa
 diff -q -b exp_p2 p2.fa
 rm -f input exp_p1 exp_p2 partitions p1.fa p2.fa
 
+echo "->goalign split nexus/partitionfinder partition files"
+cat > input <<EOF
+>s1
+AAAACCCCCGG
+>s2
+AAAACCCCCGG
+EOF
+cat > partitions.nex <<EOF
+#nexus
+begin sets;
+  charset p1 = 1-4 10-.;
+  charset p2 = 5-9;
+  charpartition mine = GTR+G4+FO:p1, HKY:p2;
+end;
+EOF
+cat > partitions.cfg <<EOF
+## ALIGNMENT FILE ##
+alignment = input;
+models = all;
+[data_blocks]
+p1 = 1-4 10-11;
+p2 = 5-9;
+[schemes]
+search = greedy;
+EOF
+cat > exp_p1 <<EOF
+>s1
+AAAAGG
+>s2
+AAAAGG
+EOF
+cat > exp_p2 <<EOF
+>s1
+CCCCC
+>s2
+CCCCC
+EOF
+${GOALIGN} split -i input --partition partitions.nex --out-prefix ./
+diff -q -b exp_p1 p1.fa
+diff -q -b exp_p2 p2.fa
+rm -f p1.fa p2.fa
+${GOALIGN} split -i input --partition partitions.cfg --out-prefix ./
+diff -q -b exp_p1 p1.fa
+diff -q -b exp_p2 p2.fa
+rm -f p1.fa p2.fa
+cat > partitions.nex <<EOF
+#nexus
+begin assumptions;
+  charset p1 = 1-4 10-.;
+  charset p2 = 5-9;
+end;
+EOF
+${GOALIGN} split -i input --partition partitions.nex --out-prefix ./
+diff -q -b exp_p1 p1.fa
+diff -q -b exp_p2 p2.fa
+rm -f input exp_p1 exp_p2 partitions.nex partitions.cfg p1.fa p2.fa
+
 echo "->goalign split codons/2"
 cat > input <<EOF
 >s1
@@ -5369,6 +6244,58 @@ diff -q -b expected2 extract.tmp/output.2.fa
 rm -rf input.align input.coordinates extract.tmp expected1 expected2 expected1.2 expected2.2
 
 
+echo "->goalign extract --features"
+cat > input.align <<EOF
+>ref
+ATG-AAACCCTAA
+>s2
+ATGGAAACCCTGA
+EOF
+
+cat > input.gb <<EOF
+LOCUS       ref                       12 bp    DNA     linear   UNK 01-JAN-2020
+FEATURES             Location/Qualifiers
+     gene            1..12
+                     /gene="a"
+     CDS             1..6
+                     /gene="a"
+     CDS             complement(join(4..6,10..12))
+                     /gene="b"
+     CDS             7..9
+ORIGIN
+//
+EOF
+
+cat > expected1 <<EOF
+>ref
+ATG-AAA
+>s2
+ATGGAAA
+EOF
+
+cat > expected2 <<EOF
+>ref
+TTATTT
+>s2
+TCATTT
+EOF
+
+cat > expected3 <<EOF
+>ref
+CCC
+>s2
+CCC
+EOF
+
+mkdir -p extract.tmp
+${GOALIGN} extract -i input.align --features input.gb --ref-seq ref -o extract.tmp
+diff -q -b expected1 extract.tmp/a.fa
+diff -q -b expected2 extract.tmp/b.fa
+diff -q -b expected3 extract.tmp/CDS_3.fa
+
+rm -rf input.align input.gb extract.tmp expected1 expected2 expected3
+
+
 
 echo "->goalign transpose"
 cat > input <<EOF
@@ -5470,3 +6397,87 @@ EOF
 ${GOALIGN} revcomp -i input --unaligned > output
 diff -q -b expected output
 rm -rf input output expected
+
+
+echo "->goalign simulate"
+cat > input.nw <<EOF
+((A:0,B:0):0,(C:0,'D':0):0,E:0);
+EOF
+cat > expected.nb <<EOF
+5
+5
+EOF
+cat > expected.len <<EOF
+200
+EOF
+cat > expected.dist <<EOF
+0.000000000000
+EOF
+${GOALIGN} simulate --tree input.nw -m "GTR{1/2/1/1/2/1}+FU{0.1/0.2/0.3/0.4}+G4{0.5}+I{0.2}" -l 200 -n 2 -p --seed 10 > output
+${GOALIGN} stats nseq -p -i output > result.nb
+${GOALIGN} stats length -p -i output | sort -u > result.len
+${GOALIGN} compute distance -m pdist -p -i output | awk 'NF>1{for(i=2;i<=NF;i++){print $i}}' | sort -u > result.dist
+diff -q -b result.nb expected.nb
+diff -q -b result.len expected.len
+diff -q -b result.dist expected.dist
+rm -f input.nw output expected.nb expected.len expected.dist result.nb result.len result.dist
+
+
+echo "->goalign compute model"
+cat > input.nw <<EOF
+((A:0.1,B:0.2):0.05,(C:0.1,D:0.3):0.1,E:0.2);
+EOF
+cat > expected <<EOF
+Alignment	lnL	AC	AG	AT	CG	CT	GT	piA	piC	piG	piT	alpha	model
+0	-108618.2084	0.785118	4.444754	1.004890	1.062690	3.915158	1.000000	0.100880	0.202640	0.295200	0.401280	NA	GTR{0.785118/4.444754/1.00489/1.06269/3.915158/1}+FU{0.10088/0.20264/0.2952/0.40128}
+EOF
+cat > expected.nb <<EOF
+5
+EOF
+${GOALIGN} simulate --tree input.nw -m "GTR{1/4/1/1/4/1}+FU{0.1/0.2/0.3/0.4}" -l 5000 --seed 10 | ${GOALIGN} compute model > result
+# Estimated values are compared with a relative tolerance, the model column must be a GTR model
+paste result expected | awk -F'\t' 'BEGIN{ok=1}
+NR==1{for(i=1;i<=14;i++){if($i!=$(i+14)){ok=0}}; next}
+{for(i=1;i<=13;i++){if($(i+14)=="NA"){if($i!="NA"){ok=0}; continue}; d=$i-$(i+14); if(d<0){d=-d}; e=$(i+14); if(e<0){e=-e}; if(d>1e-4*e+1e-6){ok=0}}; if($14!~/^GTR\{/){ok=0}}
+END{if(NR!=2){ok=0}; exit(!ok)}' || { echo "Tolerance comparison failed"; exit 1; }
+# The estimated model is accepted by goalign simulate
+${GOALIGN} simulate --tree input.nw -m "$(tail -n 1 result | cut -f 14)" -l 100 --seed 10 | ${GOALIGN} stats nseq > result.nb
+diff -q -b result.nb expected.nb
+rm -f input.nw expected result expected.nb result.nb
+
+
+echo "->goalign stats --per-sequences (stream)"
+${GOALIGN} random -n 20 -l 200 --seed 10 | ${GOALIGN} mutate gaps -n 0.5 -r 0.1 --seed 10 > input
+${GOALIGN} random -n 1 -l 200 --seed 11 > ref
+${GOALIGN} stats char --per-sites -i input > profile
+cat input | ${GOALIGN} stats --per-sequences --ref-sequence ref --count-profile profile > expected
+${GOALIGN} stats --per-sequences -i input --ref-sequence ref --count-profile profile > result
+diff -q -b expected result
+cat input | ${GOALIGN} stats --per-sequences --ref-sequence Seq0003 > expected
+${GOALIGN} stats --per-sequences -i input --ref-sequence Seq0003 > result
+diff -q -b expected result
+rm -f input ref profile expected result
+
+
+echo "->goalign addid (stream, duplicate names)"
+cat > input <<EOF
+>s1
+ACGT
+>s1
+ACGT
+>s1
+ACGA
+>s2
+ACGC
+EOF
+cat > expected <<EOF
+>x_s1
+ACGT
+>x_s1_0001
+ACGA
+>x_s2
+ACGC
+EOF
+${GOALIGN} addid -n x_ -i input --ignore-identical 2 > result 2>/dev/null
+diff -q -b expected result
+rm -f input expected result
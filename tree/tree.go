@@ -0,0 +1,115 @@
+package tree
+
+import "math"
+
+// Node of a rooted phylogenetic tree
+type Node struct {
+	name     string
+	length   float64 // Length of the branch to the parent (NaN if not defined)
+	parent   *Node
+	children []*Node
+}
+
+// Tree is a rooted phylogenetic tree.
+// Unrooted trees are represented with a multifurcating root.
+type Tree struct {
+	root *Node
+}
+
+// NewTree returns a tree with the given root node
+func NewTree(root *Node) *Tree {
+	return &Tree{root: root}
+}
+
+// NewNode returns a new node with the given name and no branch length
+func NewNode(name string) *Node {
+	return &Node{
+		name:     name,
+		length:   math.NaN(),
+		parent:   nil,
+		children: make([]*Node, 0),
+	}
+}
+
+// Root returns the root of the tree
+func (t *Tree) Root() *Node {
+	return t.root
+}
+
+// Tips returns the tips of the tree, in pre-order
+func (t *Tree) Tips() (tips []*Node) {
+	t.PreOrder(func(n *Node) bool {
+		if n.Tip() {
+			tips = append(tips, n)
+		}
+		return true
+	})
+	return
+}
+
+// PreOrder applies f to all the nodes of the tree, parents before children.
+// If f returns false, the traversal stops.
+func (t *Tree) PreOrder(f func(n *Node) bool) {
+	if t.root != nil {
+		t.root.preOrder(f)
+	}
+}
+
+func (n *Node) preOrder(f func(n *Node) bool) bool {
+	if !f(n) {
+		return false
+	}
+	for _, c := range n.children {
+		if !c.preOrder(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddChild adds the child c to the node n, with the given branch length (NaN if none)
+func (n *Node) AddChild(c *Node, length float64) {
+	c.parent = n
+	c.length = length
+	n.children = append(n.children, c)
+}
+
+// Name returns the name of the node ("" if none)
+func (n *Node) Name() string {
+	return n.name
+}
+
+// SetName sets the name of the node
+func (n *Node) SetName(name string) {
+	n.name = name
+}
+
+// Length returns the length of the branch to the parent (NaN if not defined)
+func (n *Node) Length() float64 {
+	return n.length
+}
+
+// SetLength sets the length of the branch to the parent
+func (n *Node) SetLength(length float64) {
+	n.length = length
+}
+
+// HasLength returns true if the length of the branch to the parent is defined
+func (n *Node) HasLength() bool {
+	return !math.IsNaN(n.length)
+}
+
+// Parent returns the parent of the node (nil for the root)
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Children returns the children of the node
+func (n *Node) Children() []*Node {
+	return n.children
+}
+
+// Tip returns true if the node has no child
+func (n *Node) Tip() bool {
+	return len(n.children) == 0
+}